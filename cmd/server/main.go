@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,6 +17,7 @@ import (
 	"github.com/jhonVitor-rs/url-shortener/internal/api/worker"
 	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
 	"github.com/jhonVitor-rs/url-shortener/internal/data/db/rdstore"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
 )
@@ -48,7 +50,7 @@ func main() {
 	rdb := setupRedisConnection(ctx)
 	defer rdb.Close()
 
-	handler := api.NewApiHandler(pgstore.New(pool), rdb)
+	handler := api.NewApiHandler(pgstore.New(pool), rdb, infra.NewMailerFromEnv(), net.DefaultResolver)
 
 	server := &http.Server{
 		Addr:    "0.0.0.0:8080",
@@ -67,6 +69,10 @@ func main() {
 	}()
 
 	worker.StartHourlyAccessSyncWorker(pgstore.New(pool), rdb)
+	worker.StartDailyPurgeDeletedWorker(pgstore.New(pool))
+	worker.StartMetadataWorker(pgstore.New(pool))
+	worker.StartHealthCheckWorker(pgstore.New(pool))
+	worker.StartBulkJobWorker(pgstore.New(pool))
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
@@ -94,6 +94,381 @@ func Config(ctx context.Context) (*pgxpool.Pool, *redis.Client) {
 			DELETE
 				CASCADE
 		);
+
+		ALTER TABLE users
+			ADD COLUMN IF NOT EXISTS "totp_secret" TEXT,
+			ADD COLUMN IF NOT EXISTS "totp_enabled" BOOLEAN NOT NULL DEFAULT FALSE;
+
+		CREATE TABLE IF NOT EXISTS user_recovery_codes (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"user_id" uuid NOT NULL,
+			"code_hash" TEXT NOT NULL,
+			"used_at" TIMESTAMP WITH TIME ZONE,
+			"created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON
+			DELETE
+				CASCADE
+		);
+
+		ALTER TABLE users
+			ADD COLUMN IF NOT EXISTS "email_verified_at" TIMESTAMP WITH TIME ZONE,
+			ADD COLUMN IF NOT EXISTS "password_hash" TEXT;
+
+		CREATE TABLE IF NOT EXISTS user_tokens (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"user_id" uuid NOT NULL,
+			"purpose" TEXT NOT NULL,
+			"email" VARCHAR(100) NOT NULL,
+			"expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+			"used_at" TIMESTAMP WITH TIME ZONE,
+			"created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON
+			DELETE
+				CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS workspaces (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"name" VARCHAR(100) NOT NULL,
+			"created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		);
+
+		CREATE TABLE IF NOT EXISTS workspace_members (
+			"workspace_id" uuid NOT NULL,
+			"user_id" uuid NOT NULL,
+			"role" TEXT NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+			"created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			PRIMARY KEY (workspace_id, user_id),
+			FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS workspace_invitations (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"workspace_id" uuid NOT NULL,
+			"email" VARCHAR(100) NOT NULL,
+			"role" TEXT NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+			"invited_by" uuid,
+			"expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+			"accepted_at" TIMESTAMP WITH TIME ZONE,
+			"created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+			FOREIGN KEY (invited_by) REFERENCES users(id) ON DELETE SET NULL
+		);
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "workspace_id" uuid REFERENCES workspaces(id) ON DELETE RESTRICT,
+			ALTER COLUMN "user_id" DROP NOT NULL,
+			DROP CONSTRAINT IF EXISTS short_urls_user_id_fkey,
+			ADD CONSTRAINT short_urls_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+			DROP CONSTRAINT IF EXISTS short_urls_owner_check,
+			ADD CONSTRAINT short_urls_owner_check CHECK (user_id IS NOT NULL OR workspace_id IS NOT NULL);
+
+		CREATE OR REPLACE FUNCTION delete_personal_short_urls() RETURNS TRIGGER AS $$
+		BEGIN
+			DELETE FROM short_urls WHERE user_id = OLD.id AND workspace_id IS NULL;
+			RETURN OLD;
+		END;
+		$$ LANGUAGE plpgsql;
+
+		DROP TRIGGER IF EXISTS users_delete_personal_short_urls ON users;
+		CREATE TRIGGER users_delete_personal_short_urls BEFORE DELETE ON users
+			FOR EACH ROW EXECUTE FUNCTION delete_personal_short_urls();
+
+		ALTER TABLE users
+			ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMP WITH TIME ZONE;
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMP WITH TIME ZONE;
+
+		CREATE TABLE IF NOT EXISTS audit_logs (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"actor_id" uuid,
+			"entity_type" TEXT NOT NULL,
+			"entity_id" uuid NOT NULL,
+			"action" TEXT NOT NULL,
+			"changes" JSONB NOT NULL DEFAULT '{}',
+			"request_id" TEXT,
+			"ip_address" TEXT,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+		);
+
+		CREATE INDEX IF NOT EXISTS audit_logs_entity_idx ON audit_logs (entity_type, entity_id, created_at DESC);
+		CREATE INDEX IF NOT EXISTS audit_logs_actor_id_idx ON audit_logs (actor_id, created_at DESC);
+
+		CREATE OR REPLACE FUNCTION prevent_audit_log_changes() RETURNS TRIGGER AS $$
+		BEGIN
+			RAISE EXCEPTION 'audit_logs is append-only';
+		END;
+		$$ LANGUAGE plpgsql;
+
+		DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+		CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+			FOR EACH ROW EXECUTE FUNCTION prevent_audit_log_changes();
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "password_hash" TEXT;
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "max_clicks" INTEGER CHECK (max_clicks > 0);
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "starts_at" TIMESTAMP WITH TIME ZONE,
+			ADD COLUMN IF NOT EXISTS "inactive_fallback" TEXT NOT NULL DEFAULT 'not_found' CHECK (
+				inactive_fallback IN ('not_found', 'holding_page', 'countdown')
+			),
+			ADD COLUMN IF NOT EXISTS "inactive_url" TEXT;
+
+		CREATE TABLE IF NOT EXISTS redirect_rules (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"short_url_id" uuid NOT NULL,
+			"position" INTEGER NOT NULL DEFAULT 0,
+			"conditions" JSONB NOT NULL DEFAULT '{}',
+			"target_url" TEXT NOT NULL,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS redirect_rules_short_url_id_idx ON redirect_rules (short_url_id, position);
+
+		CREATE TABLE IF NOT EXISTS short_url_variants (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"short_url_id" uuid NOT NULL,
+			"url" TEXT NOT NULL,
+			"weight" INTEGER NOT NULL CHECK (weight > 0 AND weight <= 1000),
+			"access_count" INTEGER NOT NULL DEFAULT 0,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS short_url_variants_short_url_id_idx ON short_url_variants (short_url_id);
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "sticky_variants" BOOLEAN NOT NULL DEFAULT FALSE;
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "redirect_status" INTEGER NOT NULL DEFAULT 302 CHECK (redirect_status IN (301, 302, 307, 308)),
+			ADD COLUMN IF NOT EXISTS "forward_query" BOOLEAN NOT NULL DEFAULT FALSE,
+			ADD COLUMN IF NOT EXISTS "query_merge" TEXT NOT NULL DEFAULT 'keep_destination' CHECK (
+				query_merge IN ('keep_destination', 'override', 'append')
+			);
+
+		CREATE TABLE IF NOT EXISTS campaigns (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"user_id" uuid NOT NULL,
+			"name" TEXT NOT NULL,
+			"utm_source" TEXT,
+			"utm_medium" TEXT,
+			"utm_campaign" TEXT,
+			"utm_term" TEXT,
+			"utm_content" TEXT,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (user_id, name),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "campaign_id" uuid REFERENCES campaigns(id) ON DELETE SET NULL;
+
+		CREATE INDEX IF NOT EXISTS short_urls_campaign_id_idx ON short_urls (campaign_id);
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "scan_count" INTEGER NOT NULL DEFAULT 0;
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "interstitial" BOOLEAN NOT NULL DEFAULT FALSE;
+
+		CREATE TABLE IF NOT EXISTS short_url_metadata (
+			"short_url_id" uuid PRIMARY KEY NOT NULL,
+			"url" TEXT NOT NULL,
+			"status" TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'fetched', 'failed')),
+			"title" TEXT,
+			"description" TEXT,
+			"image_url" TEXT,
+			"favicon_url" TEXT,
+			"attempts" INTEGER NOT NULL DEFAULT 0,
+			"last_error" TEXT,
+			"next_attempt_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			"fetched_at" TIMESTAMP WITH TIME ZONE,
+			FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS short_url_metadata_pending_idx ON short_url_metadata (next_attempt_at) WHERE status = 'pending';
+
+		CREATE TABLE IF NOT EXISTS short_url_health (
+			"short_url_id" uuid PRIMARY KEY NOT NULL,
+			"url" TEXT NOT NULL,
+			"healthy" BOOLEAN NOT NULL,
+			"status_code" INTEGER,
+			"final_url" TEXT,
+			"latency_ms" INTEGER,
+			"last_error" TEXT,
+			"consecutive_failures" INTEGER NOT NULL DEFAULT 0,
+			"checked_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			"last_success_at" TIMESTAMP WITH TIME ZONE,
+			"next_check_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+			"notified_at" TIMESTAMP WITH TIME ZONE,
+			FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS short_url_health_next_check_idx ON short_url_health (next_check_at);
+
+		CREATE TABLE IF NOT EXISTS webhooks (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"user_id" uuid NOT NULL,
+			"url" TEXT NOT NULL,
+			"secret" TEXT NOT NULL,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS webhooks_user_id_idx ON webhooks (user_id);
+
+		CREATE TABLE IF NOT EXISTS tags (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"user_id" uuid NOT NULL,
+			"name" TEXT NOT NULL,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (user_id, name),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS short_url_tags (
+			"short_url_id" uuid NOT NULL,
+			"tag_id" uuid NOT NULL,
+			PRIMARY KEY (short_url_id, tag_id),
+			FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON DELETE CASCADE,
+			FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS short_url_tags_tag_id_idx ON short_url_tags (tag_id);
+
+		CREATE TABLE IF NOT EXISTS folders (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"user_id" uuid NOT NULL,
+			"parent_id" uuid REFERENCES folders(id) ON DELETE CASCADE,
+			"name" TEXT NOT NULL,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE NULLS NOT DISTINCT (user_id, parent_id, name),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "folder_id" uuid REFERENCES folders(id) ON DELETE SET NULL;
+
+		CREATE INDEX IF NOT EXISTS short_urls_folder_id_idx ON short_urls (folder_id);
+
+		CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+		CREATE INDEX IF NOT EXISTS short_urls_user_id_created_at_idx ON short_urls (user_id, created_at, id) WHERE deleted_at IS NULL;
+
+		CREATE INDEX IF NOT EXISTS short_urls_workspace_id_created_at_idx ON short_urls (workspace_id, created_at, id) WHERE deleted_at IS NULL;
+
+		CREATE INDEX IF NOT EXISTS short_urls_slug_trgm_idx ON short_urls USING gin (slug gin_trgm_ops);
+
+		CREATE INDEX IF NOT EXISTS short_urls_original_url_trgm_idx ON short_urls USING gin (original_url gin_trgm_ops);
+
+		CREATE INDEX IF NOT EXISTS short_url_metadata_title_trgm_idx ON short_url_metadata USING gin (title gin_trgm_ops);
+
+		CREATE INDEX IF NOT EXISTS users_created_at_idx ON users (created_at, id) WHERE deleted_at IS NULL;
+
+		CREATE TABLE IF NOT EXISTS bulk_jobs (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"user_id" uuid NOT NULL,
+			"status" TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+			"total_rows" INTEGER NOT NULL,
+			"processed_rows" INTEGER NOT NULL DEFAULT 0,
+			"created_rows" INTEGER NOT NULL DEFAULT 0,
+			"failed_rows" INTEGER NOT NULL DEFAULT 0,
+			"error" TEXT,
+			"locked_until" TIMESTAMP WITH TIME ZONE,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			"started_at" TIMESTAMP WITH TIME ZONE,
+			"finished_at" TIMESTAMP WITH TIME ZONE,
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+
+		CREATE INDEX IF NOT EXISTS bulk_jobs_unfinished_idx ON bulk_jobs (created_at) WHERE status IN ('pending', 'running');
+
+		CREATE TABLE IF NOT EXISTS bulk_job_rows (
+			"job_id" uuid NOT NULL,
+			"row_number" INTEGER NOT NULL,
+			"input" JSONB NOT NULL,
+			"short_url_id" uuid,
+			"slug" TEXT,
+			"errors" JSONB,
+			PRIMARY KEY (job_id, row_number),
+			FOREIGN KEY (job_id) REFERENCES bulk_jobs(id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS short_url_daily_stats (
+			"short_url_id" uuid NOT NULL,
+			"day" DATE NOT NULL,
+			"clicks" INTEGER NOT NULL DEFAULT 0,
+			"scans" INTEGER NOT NULL DEFAULT 0,
+			PRIMARY KEY (short_url_id, day),
+			FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS short_url_versions (
+			"short_url_id" uuid NOT NULL,
+			"version" INTEGER NOT NULL,
+			"slug" TEXT NOT NULL,
+			"original_url" TEXT NOT NULL,
+			"expires_at" TIMESTAMP WITH TIME ZONE,
+			"password_hash" TEXT,
+			"max_clicks" INTEGER,
+			"starts_at" TIMESTAMP WITH TIME ZONE,
+			"inactive_fallback" TEXT NOT NULL,
+			"inactive_url" TEXT,
+			"sticky_variants" BOOLEAN NOT NULL,
+			"redirect_status" INTEGER NOT NULL,
+			"forward_query" BOOLEAN NOT NULL,
+			"query_merge" TEXT NOT NULL,
+			"campaign_id" uuid REFERENCES campaigns(id) ON DELETE SET NULL,
+			"interstitial" BOOLEAN NOT NULL,
+			"folder_id" uuid REFERENCES folders(id) ON DELETE SET NULL,
+			"author_id" uuid REFERENCES users(id) ON DELETE SET NULL,
+			"restored_from" INTEGER,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (short_url_id, version),
+			FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON DELETE CASCADE
+		);
+
+		CREATE TABLE IF NOT EXISTS domains (
+			"id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+			"user_id" uuid NOT NULL,
+			"host" TEXT NOT NULL,
+			"verification_token" TEXT NOT NULL,
+			"verified_at" TIMESTAMP WITH TIME ZONE,
+			"not_found_url" TEXT,
+			"created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+			UNIQUE (user_id, host),
+			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+		);
+
+		CREATE UNIQUE INDEX IF NOT EXISTS domains_verified_host_key ON domains (host) WHERE verified_at IS NOT NULL;
+
+		ALTER TABLE short_urls
+			ADD COLUMN IF NOT EXISTS "domain_id" uuid REFERENCES domains(id) ON DELETE CASCADE;
+
+		ALTER TABLE short_urls
+			DROP CONSTRAINT IF EXISTS short_urls_slug_key;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS short_urls_slug_key ON short_urls (slug) WHERE domain_id IS NULL;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS short_urls_domain_id_slug_key ON short_urls (domain_id, slug) WHERE domain_id IS NOT NULL;
+
+		CREATE INDEX IF NOT EXISTS short_urls_slug_idx ON short_urls (slug);
+
+		ALTER TABLE users
+			DROP CONSTRAINT IF EXISTS users_email_key;
+
+		CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (email) WHERE deleted_at IS NULL;
+
+		ALTER TABLE short_urls
+			DROP CONSTRAINT IF EXISTS short_urls_domain_id_fkey,
+			ADD CONSTRAINT short_urls_domain_id_fkey FOREIGN KEY (domain_id) REFERENCES domains(id) ON DELETE RESTRICT;
 	`)
 	if err != nil {
 		panic(err)
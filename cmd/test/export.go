@@ -3,12 +3,21 @@ package test
 import (
 	"log/slog"
 	"net/http"
+	"regexp"
 	"sync"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
 )
 
+// UserPassword is the password of the users created by the test suites
+const UserPassword = "correct-horse-battery"
+
 var (
 	handler     http.Handler
 	handlerLock sync.RWMutex
+	mailer      = infra.NewMemoryMailer()
+	resolver    = infra.NewMemoryTxtResolver()
+	tokenRegexp = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
 )
 
 func SetHandler(h http.Handler) {
@@ -34,3 +43,24 @@ func Handler() http.Handler {
 
 	return handler
 }
+
+// Mailer returns the in-memory mailer shared by the test handlers
+func Mailer() *infra.MemoryMailer {
+	return mailer
+}
+
+// Resolver returns the in-memory TXT resolver used to verify custom domains
+func Resolver() *infra.MemoryTxtResolver {
+	return resolver
+}
+
+// LastEmailToken extracts the signed token from the latest email sent to the address
+func LastEmailToken(address string) (string, bool) {
+	email, ok := mailer.LastMessageTo(address)
+	if !ok {
+		return "", false
+	}
+
+	token := tokenRegexp.FindString(email.Body)
+	return token, token != ""
+}
@@ -0,0 +1,110 @@
+package shorturltest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationScheduledShortUrl(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	t.Run("Scheduled link is not found before starts_at", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/launch",
+			StartsAt:    &future,
+		})
+		assert.Equal(t, models.InactiveFallbackNotFound, shortUrl.InactiveFallback)
+
+		assert.Equal(t, http.StatusNotFound, redirectTestSlug(shortUrl.Slug))
+		// A second visit must not be served from the cache either
+		assert.Equal(t, http.StatusNotFound, redirectTestSlug(shortUrl.Slug))
+	})
+
+	t.Run("Holding page fallback redirects to the holding URL", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl:      "https://example.com/launch",
+			StartsAt:         &future,
+			InactiveFallback: models.InactiveFallbackHoldingPage,
+			InactiveUrl:      ptr("https://example.com/coming-soon"),
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/"+shortUrl.Slug, nil)
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "https://example.com/coming-soon", recorder.Header().Get("Location"))
+	})
+
+	t.Run("Countdown fallback renders a page", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl:      "https://example.com/launch",
+			StartsAt:         &future,
+			InactiveFallback: models.InactiveFallbackCountdown,
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/"+shortUrl.Slug, nil)
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+		assert.Contains(t, recorder.Body.String(), future)
+	})
+
+	t.Run("Link redirects once starts_at has passed", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		past := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+		payload, err := json.Marshal(models.UpdateShortUrlInput{StartsAt: &past})
+		require.NoError(t, err)
+		shortUrl = updateTestShortUrl(t, token, shortUrl.ID, payload)
+		require.NotNil(t, shortUrl.StartsAt)
+
+		assert.Equal(t, http.StatusFound, redirectTestSlug(shortUrl.Slug))
+	})
+
+	t.Run("Failed to use the holding page without a URL", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+
+		payload, err := json.Marshal(models.CreateShortUrlInput{
+			OriginalUrl:      "https://example.com/launch",
+			StartsAt:         &future,
+			InactiveFallback: models.InactiveFallbackHoldingPage,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/short_url", bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("Failed to update with a malformed holding URL", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		payload, err := json.Marshal(models.UpdateShortUrlInput{InactiveUrl: ptr("not a url")})
+		require.NoError(t, err)
+		recorder := sendAuthorizedRequest(token, http.MethodPatch, "/api/short_url/"+shortUrl.ID, payload)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var body models.Response
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+		require.NotNil(t, body.Error)
+		require.Len(t, body.Error.Errors, 1)
+		assert.Equal(t, "InactiveUrl", body.Error.Errors[0].Field)
+	})
+}
@@ -0,0 +1,149 @@
+package shorturltest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	api "github.com/jhonVitor-rs/url-shortener/internal/api/server"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationShortUrlAudit(t *testing.T) {
+	t.Run("Create, update and delete are recorded with a diff", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		originalUrl := "https://www.youtube.com/watch?v=g5ZUG1gKZpE"
+		payload, err := json.Marshal(models.UpdateShortUrlInput{OriginalUrl: ptr(originalUrl)})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/short_url/%s", shortUrl.ID), bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/short_url/%s", shortUrl.ID), nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		page := getAuditPage(t, token, fmt.Sprintf("/api/short_url/%s/audit", shortUrl.ID))
+		require.Len(t, page.Entries, 3)
+		assert.False(t, page.HasMore)
+
+		assert.Equal(t, models.AuditActionDelete, page.Entries[0].Action)
+		assert.Equal(t, models.AuditActionUpdate, page.Entries[1].Action)
+		assert.Equal(t, models.AuditActionCreate, page.Entries[2].Action)
+
+		update := page.Entries[1]
+		assert.Equal(t, shortUrl.UserID, update.ActorID)
+		assert.NotEmpty(t, update.RequestID)
+		assert.NotEmpty(t, update.IPAddress)
+
+		var changes map[string]models.AuditChange
+		require.NoError(t, json.Unmarshal(update.Changes, &changes))
+		require.Contains(t, changes, "original_url")
+		assert.Equal(t, shortUrl.OriginalUrl, changes["original_url"].Before)
+		assert.Equal(t, originalUrl, changes["original_url"].After)
+		assert.NotContains(t, changes, "created_at")
+	})
+
+	t.Run("Audit log is paginated", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/short_url/%s", shortUrl.ID), nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		page := getAuditPage(t, token, fmt.Sprintf("/api/short_url/%s/audit?limit=1", shortUrl.ID))
+		require.Len(t, page.Entries, 1)
+		assert.True(t, page.HasMore)
+		assert.Equal(t, models.AuditActionDelete, page.Entries[0].Action)
+
+		page = getAuditPage(t, token, fmt.Sprintf("/api/short_url/%s/audit?limit=1&offset=1", shortUrl.ID))
+		require.Len(t, page.Entries, 1)
+		assert.False(t, page.HasMore)
+		assert.Equal(t, models.AuditActionCreate, page.Entries[0].Action)
+	})
+
+	t.Run("Failed to read another user's link audit log", func(t *testing.T) {
+		_, shortUrl := setupTestShortUrl(t)
+		otherToken, _ := setupTestShortUrl(t)
+
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short_url/%s/audit", shortUrl.ID), nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", otherToken))
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Forwarded client addresses are only taken from trusted proxies", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8")
+		proxied := api.NewApiHandler(testQueries, testRedis, test.Mailer(), test.Resolver())
+
+		update := func(handler http.Handler, remoteAddr string) {
+			payload, err := json.Marshal(models.UpdateShortUrlInput{OriginalUrl: ptr("https://example.com/" + remoteAddr)})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/short_url/%s", shortUrl.ID), bytes.NewBuffer(payload))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+			req.Header.Set("X-Forwarded-For", "198.51.100.9, 203.0.113.7, 10.0.0.3")
+			req.Header.Set("X-Real-IP", "198.51.100.9")
+			req.RemoteAddr = remoteAddr + ":4242"
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			require.Equal(t, http.StatusOK, recorder.Code)
+		}
+
+		update(test.Handler(), "10.0.0.2")
+		update(proxied, "192.0.2.1")
+		update(proxied, "10.0.0.2")
+
+		page := getAuditPage(t, token, fmt.Sprintf("/api/short_url/%s/audit?limit=3", shortUrl.ID))
+		require.Len(t, page.Entries, 3)
+		assert.Equal(t, "203.0.113.7", page.Entries[0].IPAddress, "The first hop not behind a trusted proxy is the client")
+		assert.Equal(t, "192.0.2.1", page.Entries[1].IPAddress, "Headers from untrusted addresses are ignored")
+		assert.Equal(t, "10.0.0.2", page.Entries[2].IPAddress, "No proxy is trusted by default")
+	})
+
+	t.Run("User audit log lists changes made by the user", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		page := getAuditPage(t, token, "/api/users/audit")
+		require.NotEmpty(t, page.Entries)
+		assert.Equal(t, models.AuditEntityShortUrl, page.Entries[0].EntityType)
+		assert.Equal(t, shortUrl.ID, page.Entries[0].EntityID)
+		assert.Equal(t, models.AuditActionCreate, page.Entries[0].Action)
+	})
+}
+
+func getAuditPage(t *testing.T, token string, path string) *models.AuditLogPage {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Success bool                `json:"success"`
+		Data    models.AuditLogPage `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.True(t, response.Success)
+
+	return &response.Data
+}
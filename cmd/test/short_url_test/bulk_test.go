@@ -0,0 +1,174 @@
+package shorturltest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/worker"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/services"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationBulkCreateShortUrls(t *testing.T) {
+	token, _ := setupTestShortUrl(t)
+	tag := createTestTag(t, token, "bulk")
+
+	t.Run("Creates the valid rows of a JSON array and reports the others", func(t *testing.T) {
+		payload, _ := json.Marshal([]models.CreateShortUrlInput{
+			{OriginalUrl: "https://example.com/bulk/1", TagIDs: []string{tag.ID}},
+			{OriginalUrl: "not a url"},
+			{OriginalUrl: "https://example.com/bulk/3", FolderID: ptr("00000000-0000-0000-0000-000000000000")},
+			{OriginalUrl: "https://evil.blocked.test/"},
+		})
+		result := bulkTestRequest(t, token, "/api/short_url/bulk", "application/json", payload)
+
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 3, result.Failed)
+		require.Len(t, result.Rows, 4)
+
+		created := result.Rows[0]
+		assert.Equal(t, 1, created.Row)
+		assert.Empty(t, created.Errors)
+		require.NotEmpty(t, created.ID)
+		shortUrl := getTestShortUrl(t, token, created.ID)
+		assert.Equal(t, created.Slug, shortUrl.Slug)
+		require.Len(t, shortUrl.Tags, 1)
+		assert.Equal(t, tag.ID, shortUrl.Tags[0].ID)
+
+		assert.Equal(t, []models.ValidationError{{Field: "original_url", Tag: "url", Value: "not a url"}}, result.Rows[1].Errors)
+		require.Len(t, result.Rows[2].Errors, 1)
+		assert.Equal(t, "folder_id", result.Rows[2].Errors[0].Field)
+		assert.Equal(t, "not_found", result.Rows[2].Errors[0].Tag)
+		require.Len(t, result.Rows[3].Errors, 1)
+		assert.Equal(t, "original_url", result.Rows[3].Errors[0].Field)
+		assert.Empty(t, result.Rows[3].ID)
+	})
+
+	t.Run("Reads a CSV body", func(t *testing.T) {
+		body := "original_url,max_clicks,tag_ids,utm_source\n" +
+			"https://example.com/csv/1,10," + tag.ID + ",newsletter\n" +
+			"https://example.com/csv/2,many,,\n"
+		result := bulkTestRequest(t, token, "/api/short_url/bulk", "text/csv", []byte(body))
+
+		assert.Equal(t, 1, result.Created)
+		require.Len(t, result.Rows, 2)
+		assert.Equal(t, "https://example.com/csv/1?utm_source=newsletter", result.Rows[0].OriginalUrl)
+		shortUrl := getTestShortUrl(t, token, result.Rows[0].ID)
+		require.NotNil(t, shortUrl.MaxClicks)
+		assert.Equal(t, 10, *shortUrl.MaxClicks)
+		assert.Equal(t, []models.ValidationError{{Field: "max_clicks", Tag: "type", Value: "many"}}, result.Rows[1].Errors)
+	})
+
+	t.Run("Runs an uploaded CSV file as a job", func(t *testing.T) {
+		var file strings.Builder
+		file.WriteString("original_url\n")
+		for i := range 5 {
+			fmt.Fprintf(&file, "https://example.com/job/%d\n", i)
+		}
+		file.WriteString("ftp://example.com/job\n")
+
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "links.csv")
+		require.NoError(t, err)
+		part.Write([]byte(file.String()))
+		require.NoError(t, form.Close())
+
+		req := httptest.NewRequest(http.MethodPost, "/api/short_url/bulk?async=true", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusAccepted, recorder.Code, recorder.Body.String())
+
+		var queued struct {
+			Data models.BulkJob `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&queued))
+		assert.Equal(t, models.BulkJobStatusPending, queued.Data.Status)
+		assert.Equal(t, 6, queued.Data.TotalRows)
+		location := recorder.Header().Get("Location")
+		assert.Equal(t, "/api/short_url/bulk/"+queued.Data.ID, location)
+
+		bulkWorker := worker.NewBulkJobWorker(services.NewBulkService(testQueries, infra.NewDestinationPolicyFromEnv()))
+		assert.Positive(t, bulkWorker.ProcessPending(context.Background()))
+
+		recorder = sendAuthorizedRequest(token, http.MethodGet, location, nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var job struct {
+			Data models.BulkJob `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&job))
+		assert.Equal(t, models.BulkJobStatusCompleted, job.Data.Status)
+		assert.Equal(t, 6, job.Data.ProcessedRows)
+		assert.Equal(t, 5, job.Data.CreatedRows)
+		assert.Equal(t, 1, job.Data.FailedRows)
+		assert.NotNil(t, job.Data.FinishedAt)
+
+		recorder = sendAuthorizedRequest(token, http.MethodGet, location+"/results", nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("Content-Type"), "text/csv")
+		records, err := csv.NewReader(recorder.Body).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 7)
+		assert.Equal(t, models.BulkResultsCsvHeader, records[0])
+		assert.Equal(t, []string{"1", "https://example.com/job/0"}, records[1][:2])
+		assert.NotEmpty(t, records[1][2])
+		assert.NotEmpty(t, records[1][3])
+		assert.Equal(t, "original_url:url", records[6][4])
+		assert.Empty(t, records[6][2])
+
+		otherToken, _ := setupTestShortUrl(t)
+		recorder = sendAuthorizedRequest(otherToken, http.MethodGet, location, nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		recorder = sendAuthorizedRequest(otherToken, http.MethodGet, location+"/results", nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Refuses malformed uploads", func(t *testing.T) {
+		for _, upload := range []struct {
+			contentType string
+			body        string
+		}{
+			{"application/json", `{"original_url": "https://example.com"}`},
+			{"application/json", `[]`},
+			{"text/csv", "url\nhttps://example.com\n"},
+			{"text/csv", "original_url\n\"https://example.com\n"},
+		} {
+			req := httptest.NewRequest(http.MethodPost, "/api/short_url/bulk", strings.NewReader(upload.body))
+			req.Header.Set("Content-Type", upload.contentType)
+			req.Header.Set("Authorization", "Bearer "+token)
+			recorder := httptest.NewRecorder()
+			test.Handler().ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, upload.body)
+		}
+	})
+}
+
+func bulkTestRequest(t *testing.T, token, path, contentType string, body []byte) *models.BulkResult {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var response struct {
+		Data models.BulkResult `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return &response.Data
+}
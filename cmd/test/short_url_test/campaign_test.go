@@ -0,0 +1,130 @@
+package shorturltest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationCampaigns(t *testing.T) {
+	t.Run("UTM fields are merged into the original url", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/landing?ref=home&utm_source=old#pricing",
+			UtmParams: models.UtmParams{
+				UtmSource:   "newsletter",
+				UtmCampaign: "black friday",
+			},
+		})
+
+		assert.Equal(t, "https://example.com/landing?ref=home&utm_source=newsletter&utm_campaign=black+friday#pricing", shortUrl.OriginalUrl)
+	})
+
+	t.Run("Campaign fills the UTM fields left empty", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		campaign := createTestCampaign(t, token, models.CreateCampaignInput{
+			Name: "Launch",
+			UtmParams: models.UtmParams{
+				UtmSource:   "newsletter",
+				UtmMedium:   "email",
+				UtmCampaign: "launch",
+			},
+		})
+
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/landing",
+			CampaignID:  &campaign.ID,
+			UtmParams:   models.UtmParams{UtmMedium: "social"},
+		})
+		require.NotNil(t, shortUrl.CampaignID)
+		assert.Equal(t, campaign.ID, *shortUrl.CampaignID)
+
+		parsed, err := url.Parse(shortUrl.OriginalUrl)
+		require.NoError(t, err)
+		assert.Equal(t, url.Values{
+			"utm_source":   {"newsletter"},
+			"utm_medium":   {"social"},
+			"utm_campaign": {"launch"},
+		}, parsed.Query())
+	})
+
+	t.Run("List filters by campaign and campaigns report their links", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		campaign := createTestCampaign(t, token, models.CreateCampaignInput{Name: "Spring"})
+		tagged := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/spring",
+			CampaignID:  &campaign.ID,
+		})
+
+		recorder := sendAuthorizedRequest(token, http.MethodGet, "/api/short_url?campaign_id="+campaign.ID, nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var list struct {
+			Data models.ShortUrlList `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&list))
+		require.Len(t, list.Data.ShortUrls, 1)
+		assert.Equal(t, tagged.ID, list.Data.ShortUrls[0].ID)
+
+		recorder = sendAuthorizedRequest(token, http.MethodGet, "/api/campaigns/"+campaign.ID, nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Data models.Campaign `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		require.NotNil(t, response.Data.Stats)
+		assert.Equal(t, int64(1), response.Data.Stats.LinkCount)
+	})
+
+	t.Run("Link can be moved out of its campaign", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		campaign := createTestCampaign(t, token, models.CreateCampaignInput{Name: "Summer"})
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/summer",
+			CampaignID:  &campaign.ID,
+		})
+
+		payload, _ := json.Marshal(models.UpdateShortUrlInput{CampaignID: ptr("")})
+		updated := updateTestShortUrl(t, token, shortUrl.ID, payload)
+		assert.Nil(t, updated.CampaignID)
+	})
+
+	t.Run("Campaign names are unique per user", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		createTestCampaign(t, token, models.CreateCampaignInput{Name: "Duplicated"})
+
+		payload, _ := json.Marshal(models.CreateCampaignInput{Name: "Duplicated"})
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/campaigns", payload)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("Unknown campaign is rejected", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		payload, _ := json.Marshal(models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com",
+			CampaignID:  ptr("00000000-0000-0000-0000-000000000000"),
+		})
+
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/short_url", payload)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func createTestCampaign(t *testing.T, token string, input models.CreateCampaignInput) *models.Campaign {
+	payload, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/campaigns", payload)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	var response struct {
+		Data models.Campaign `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return &response.Data
+}
@@ -0,0 +1,125 @@
+package shorturltest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationClickLimitedShortUrl(t *testing.T) {
+	t.Run("One-time link is gone after the first click", func(t *testing.T) {
+		token, shortUrl := setupClickLimitedShortUrl(t, 1)
+		require.NotNil(t, shortUrl.RemainingClicks)
+		assert.Equal(t, 1, *shortUrl.RemainingClicks)
+
+		assert.Equal(t, http.StatusFound, redirectTestSlug(shortUrl.Slug))
+		assert.Equal(t, http.StatusGone, redirectTestSlug(shortUrl.Slug))
+		// The second redirect is served by the cache path, it must be evicted
+		assert.Equal(t, http.StatusGone, redirectTestSlug(shortUrl.Slug))
+
+		current := getTestShortUrl(t, token, shortUrl.ID)
+		require.NotNil(t, current.RemainingClicks)
+		assert.Equal(t, 0, *current.RemainingClicks)
+	})
+
+	t.Run("Owner sees the remaining clicks", func(t *testing.T) {
+		token, shortUrl := setupClickLimitedShortUrl(t, 3)
+
+		assert.Equal(t, http.StatusFound, redirectTestSlug(shortUrl.Slug))
+
+		current := getTestShortUrl(t, token, shortUrl.ID)
+		require.NotNil(t, current.MaxClicks)
+		require.NotNil(t, current.RemainingClicks)
+		assert.Equal(t, 3, *current.MaxClicks)
+		assert.Equal(t, 2, *current.RemainingClicks)
+	})
+
+	t.Run("Concurrent clicks don't overshoot the limit", func(t *testing.T) {
+		_, shortUrl := setupClickLimitedShortUrl(t, 5)
+
+		// Seed the limit so every goroutine goes through the same counter
+		require.Equal(t, http.StatusFound, redirectTestSlug(shortUrl.Slug))
+
+		var found, gone atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				switch redirectTestSlug(shortUrl.Slug) {
+				case http.StatusFound:
+					found.Add(1)
+				case http.StatusGone:
+					gone.Add(1)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(4), found.Load())
+		assert.Equal(t, int32(16), gone.Load())
+	})
+}
+
+func setupClickLimitedShortUrl(t *testing.T, maxClicks int) (string, *models.ShortUrl) {
+	token, _ := setupTestShortUrl(t)
+
+	shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+		OriginalUrl: "https://example.com/download",
+		MaxClicks:   &maxClicks,
+	})
+
+	return token, shortUrl
+}
+
+func createTestShortUrl(t *testing.T, token string, input models.CreateShortUrlInput) *models.ShortUrl {
+	payload, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/short_url", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	var response struct {
+		Data models.ShortUrl `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return &response.Data
+}
+
+func getTestShortUrl(t *testing.T, token, id string) *models.ShortUrl {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short_url/%s", id), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data models.ShortUrl `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return &response.Data
+}
+
+func redirectTestSlug(slug string) int {
+	req := httptest.NewRequest(http.MethodGet, "/"+slug, nil)
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+
+	return recorder.Code
+}
@@ -0,0 +1,120 @@
+package shorturltest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationReuseExistingShortUrl(t *testing.T) {
+	token, _ := setupTestShortUrl(t)
+	existing := createTestShortUrl(t, token, models.CreateShortUrlInput{
+		OriginalUrl: "https://Example.com:443/pricing?plan=pro&period=year#faq",
+	})
+
+	reuse := func(t *testing.T, input models.CreateShortUrlInput) (int, *models.ShortUrl) {
+		input.ReuseExisting = true
+		payload, err := json.Marshal(input)
+		require.NoError(t, err)
+
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/short_url", payload)
+		var response struct {
+			Data models.ShortUrl `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+		return recorder.Code, &response.Data
+	}
+
+	t.Run("The same URL spelled differently returns the existing link", func(t *testing.T) {
+		status, shortUrl := reuse(t, models.CreateShortUrlInput{OriginalUrl: "https://example.com/pricing?period=year&plan=pro"})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, existing.ID, shortUrl.ID)
+		assert.Equal(t, existing.Slug, shortUrl.Slug)
+	})
+
+	t.Run("Tracking parameters only match when ignored", func(t *testing.T) {
+		tracked := "https://example.com/pricing?plan=pro&period=year&utm_source=newsletter&gclid=abc"
+
+		status, shortUrl := reuse(t, models.CreateShortUrlInput{OriginalUrl: tracked, IgnoreTrackingParams: true})
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, existing.ID, shortUrl.ID)
+
+		status, shortUrl = reuse(t, models.CreateShortUrlInput{OriginalUrl: tracked})
+		assert.Equal(t, http.StatusCreated, status)
+		assert.NotEqual(t, existing.ID, shortUrl.ID)
+	})
+
+	t.Run("Without reuse_existing a new link is created", func(t *testing.T) {
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: existing.OriginalUrl})
+		assert.NotEqual(t, existing.ID, shortUrl.ID)
+	})
+
+	t.Run("Password protected links are not reused", func(t *testing.T) {
+		protected := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/private", Password: "secret"})
+
+		status, shortUrl := reuse(t, models.CreateShortUrlInput{OriginalUrl: "https://example.com/private"})
+		assert.Equal(t, http.StatusCreated, status)
+		assert.NotEqual(t, protected.ID, shortUrl.ID, "a public request must not get the protected link")
+
+		status, other := reuse(t, models.CreateShortUrlInput{OriginalUrl: "https://example.com/private", Password: "another"})
+		assert.Equal(t, http.StatusCreated, status)
+		assert.NotEqual(t, protected.ID, other.ID)
+		assert.NotEqual(t, shortUrl.ID, other.ID, "a protected request must not get the public link")
+	})
+
+	t.Run("Scheduled links, links with rules or redirect options are not reused", func(t *testing.T) {
+		startsAt := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		scheduled := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/scheduled", StartsAt: &startsAt})
+		permanent := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/permanent", RedirectStatus: http.StatusMovedPermanently})
+		forwarding := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/forwarding", ForwardQuery: true})
+		ruled := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/ruled"})
+		createTestRedirectRule(t, token, ruled.ID, models.CreateRedirectRuleInput{
+			Conditions: models.RuleConditions{OS: []string{"ios"}},
+			TargetUrl:  "https://apps.apple.com/app",
+		})
+
+		for _, link := range []*models.ShortUrl{scheduled, permanent, forwarding, ruled} {
+			status, shortUrl := reuse(t, models.CreateShortUrlInput{OriginalUrl: link.OriginalUrl})
+			assert.Equal(t, http.StatusCreated, status, link.OriginalUrl)
+			assert.NotEqual(t, link.ID, shortUrl.ID, link.OriginalUrl)
+		}
+	})
+
+	t.Run("Inputs with their own settings always create a link", func(t *testing.T) {
+		tag := createTestTag(t, token, "dedup")
+		startsAt := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+		inputs := []models.CreateShortUrlInput{
+			{OriginalUrl: existing.OriginalUrl, StartsAt: &startsAt},
+			{OriginalUrl: existing.OriginalUrl, RedirectStatus: http.StatusMovedPermanently},
+			{OriginalUrl: existing.OriginalUrl, ForwardQuery: true},
+			{OriginalUrl: existing.OriginalUrl, TagIDs: []string{tag.ID}},
+		}
+		for _, input := range inputs {
+			status, shortUrl := reuse(t, input)
+			assert.Equal(t, http.StatusCreated, status)
+			assert.NotEqual(t, existing.ID, shortUrl.ID)
+		}
+	})
+
+	t.Run("Inactive links and links of other users are not reused", func(t *testing.T) {
+		startsAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		scheduled := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/launch", StartsAt: &startsAt})
+
+		status, shortUrl := reuse(t, models.CreateShortUrlInput{OriginalUrl: "https://example.com/launch"})
+		assert.Equal(t, http.StatusCreated, status)
+		assert.NotEqual(t, scheduled.ID, shortUrl.ID)
+
+		otherToken, _ := setupTestShortUrl(t)
+		other := createTestShortUrl(t, otherToken, models.CreateShortUrlInput{OriginalUrl: "https://example.com/theirs"})
+		status, shortUrl = reuse(t, models.CreateShortUrlInput{OriginalUrl: "https://example.com/theirs"})
+		assert.Equal(t, http.StatusCreated, status)
+		assert.NotEqual(t, other.ID, shortUrl.ID)
+	})
+}
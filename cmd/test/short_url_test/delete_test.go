@@ -29,4 +29,65 @@ func TestIntegrationDeleteShortUrl(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, recorder.Code)
 		assert.Equal(t, true, response.Success)
 	})
+	t.Run("Links of a deleted user are gone even when cached", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+		require.Equal(t, http.StatusFound, redirectTestSlug(shortUrl.Slug))
+
+		recorder := sendAuthorizedRequest(token, http.MethodDelete, "/api/users", nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		assert.Equal(t, http.StatusGone, redirectTestSlug(shortUrl.Slug))
+	})
+	t.Run("Deleted short url is gone and can be restored", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/short_url/%s", shortUrl.ID), nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%s", shortUrl.Slug), nil)
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusGone, recorder.Code)
+
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/short_url/%s", shortUrl.ID), nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/short_url/%s/restore", shortUrl.ID), nil)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/%s", shortUrl.Slug), nil)
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+	})
+	t.Run("Restored short url comes back with its tags", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		tag := createTestTag(t, token, "restored")
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/restored",
+			TagIDs:      []string{tag.ID},
+		})
+
+		recorder := sendAuthorizedRequest(token, http.MethodDelete, "/api/short_url/"+shortUrl.ID, nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		recorder = sendAuthorizedRequest(token, http.MethodPost, "/api/short_url/"+shortUrl.ID+"/restore", nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var response struct {
+			Data models.ShortUrl `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		require.Len(t, response.Data.Tags, 1)
+		assert.Equal(t, tag.ID, response.Data.Tags[0].ID)
+	})
 }
@@ -0,0 +1,180 @@
+package shorturltest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationCustomDomains(t *testing.T) {
+	token, _ := setupTestShortUrl(t)
+	otherToken, _ := setupTestShortUrl(t)
+	suffix := time.Now().UnixNano()
+	slug := fmt.Sprintf("promo-%d", suffix)
+
+	notFoundUrl := "https://example.com/missing"
+	domain := createTestDomain(t, token, models.CreateDomainInput{
+		Host:        fmt.Sprintf("Go-%d.Example.org.", suffix),
+		NotFoundUrl: &notFoundUrl,
+	})
+	otherDomain := createTestDomain(t, otherToken, models.CreateDomainInput{Host: fmt.Sprintf("links-%d.example.org", suffix)})
+
+	t.Run("Hosts are normalized and come with their TXT record", func(t *testing.T) {
+		assert.Equal(t, fmt.Sprintf("go-%d.example.org", suffix), domain.Host)
+		assert.False(t, domain.Verified)
+		assert.Equal(t, models.DomainVerificationPrefix+domain.Host, domain.VerificationRecord)
+		assert.Contains(t, domain.VerificationValue, models.DomainVerificationValue)
+
+		payload, _ := json.Marshal(models.CreateDomainInput{Host: "127.0.0.1"})
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/domains", payload)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("Links can't be put on a domain before it is verified", func(t *testing.T) {
+		payload, _ := json.Marshal(models.CreateShortUrlInput{OriginalUrl: "https://example.com/mine", DomainID: &domain.ID})
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/short_url", payload)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		recorder = sendAuthorizedRequest(token, http.MethodPost, "/api/domains/"+domain.ID+"/verify", nil)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	verifyTestDomain(t, token, domain)
+	verifyTestDomain(t, otherToken, otherDomain)
+
+	mine := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/mine", DomainID: &domain.ID, Slug: slug})
+	theirs := createTestShortUrl(t, otherToken, models.CreateShortUrlInput{OriginalUrl: "https://example.com/theirs", DomainID: &otherDomain.ID, Slug: slug})
+
+	t.Run("Slugs are unique per domain", func(t *testing.T) {
+		assert.Equal(t, slug, mine.Slug)
+		assert.Equal(t, domain.Host, mine.Domain)
+		assert.Equal(t, slug, theirs.Slug)
+
+		payload, _ := json.Marshal(models.CreateShortUrlInput{OriginalUrl: "https://example.com/again", DomainID: &domain.ID, Slug: slug})
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/short_url", payload)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+
+		payload, _ = json.Marshal(models.CreateShortUrlInput{OriginalUrl: "https://example.com/again", Slug: slug})
+		recorder = sendAuthorizedRequest(token, http.MethodPost, "/api/short_url", payload)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		payload, _ = json.Marshal(models.CreateShortUrlInput{OriginalUrl: "https://example.com/again", DomainID: &otherDomain.ID})
+		recorder = sendAuthorizedRequest(token, http.MethodPost, "/api/short_url", payload)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("The Host header picks the domain the slug is looked up in", func(t *testing.T) {
+		recorder := domainRedirectRequest(domain.Host+":443", slug)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "https://example.com/mine", recorder.Header().Get("Location"))
+
+		recorder = domainRedirectRequest(otherDomain.Host, slug)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "https://example.com/theirs", recorder.Header().Get("Location"))
+
+		assert.Equal(t, http.StatusNotFound, redirectTestSlug(slug))
+	})
+
+	t.Run("Unknown slugs go to the fallback of the domain", func(t *testing.T) {
+		recorder := domainRedirectRequest(domain.Host, "missing-"+slug)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, notFoundUrl, recorder.Header().Get("Location"))
+
+		recorder = domainRedirectRequest(otherDomain.Host, "missing-"+slug)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		empty := ""
+		payload, _ := json.Marshal(models.UpdateDomainInput{NotFoundUrl: &empty})
+		recorder = sendAuthorizedRequest(token, http.MethodPatch, "/api/domains/"+domain.ID, payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder = domainRedirectRequest(domain.Host, "missing-"+slug)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Verified domains are not allowed as destinations", func(t *testing.T) {
+		payload, _ := json.Marshal(models.CreateShortUrlInput{OriginalUrl: "https://" + domain.Host + "/" + slug})
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/short_url", payload)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var body models.Response
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+		require.NotNil(t, body.Error)
+		require.Len(t, body.Error.Errors, 1)
+		assert.Equal(t, models.DestinationSelfReference, body.Error.Errors[0].Tag)
+	})
+
+	t.Run("A host is verified by one account only", func(t *testing.T) {
+		claim := createTestDomain(t, otherToken, models.CreateDomainInput{Host: domain.Host})
+		test.Resolver().Set(claim.VerificationRecord, domain.VerificationValue, claim.VerificationValue)
+
+		recorder := sendAuthorizedRequest(otherToken, http.MethodPost, "/api/domains/"+claim.ID+"/verify", nil)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("Links on a domain stay with the domain owner", func(t *testing.T) {
+		workspaceId := "00000000-0000-0000-0000-000000000000"
+		payload, _ := json.Marshal(models.TransferShortUrlInput{WorkspaceID: &workspaceId})
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/short_url/"+mine.ID+"/transfer", payload)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("Domains with links can't be deleted", func(t *testing.T) {
+		recorder := sendAuthorizedRequest(token, http.MethodDelete, "/api/domains/"+domain.ID, nil)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		recorder = sendAuthorizedRequest(token, http.MethodDelete, "/api/short_url/"+mine.ID, nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		recorder = sendAuthorizedRequest(token, http.MethodDelete, "/api/domains/"+domain.ID, nil)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+
+		recorder = domainRedirectRequest(domain.Host, slug)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func createTestDomain(t *testing.T, token string, input models.CreateDomainInput) *models.Domain {
+	payload, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/domains", payload)
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	var response struct {
+		Data models.Domain `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return &response.Data
+}
+
+func verifyTestDomain(t *testing.T, token string, domain *models.Domain) {
+	test.Resolver().Set(domain.VerificationRecord, "v=spf1 -all", domain.VerificationValue)
+
+	recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/domains/"+domain.ID+"/verify", nil)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var response struct {
+		Data models.Domain `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.True(t, response.Data.Verified)
+}
+
+func domainRedirectRequest(host, slug string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/"+slug, nil)
+	req.Host = host
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+
+	return recorder
+}
@@ -0,0 +1,83 @@
+package shorturltest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	api "github.com/jhonVitor-rs/url-shortener/internal/api/server"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const browserAccept = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+func TestIntegrationFallbackPages(t *testing.T) {
+	token, shortUrl := setupTestShortUrl(t)
+	missing := fmt.Sprintf("missing-%d", time.Now().UnixNano())
+
+	t.Run("Browsers get an HTML 404 page", func(t *testing.T) {
+		for _, path := range []string{"/", "/" + missing, "/" + missing + "+", "/not/a/route"} {
+			recorder := fallbackRequest(test.Handler(), path, browserAccept)
+			assert.Equal(t, http.StatusNotFound, recorder.Code, path)
+			assert.Contains(t, recorder.Header().Get("Content-Type"), "text/html", path)
+			assert.Contains(t, recorder.Body.String(), "Not Found", path)
+		}
+	})
+
+	t.Run("API clients keep the JSON envelope", func(t *testing.T) {
+		for _, accept := range []string{"", "application/json", "text/html;q=0.5, application/json", "*/*"} {
+			recorder := fallbackRequest(test.Handler(), "/"+missing, accept)
+			assert.Equal(t, http.StatusNotFound, recorder.Code, accept)
+			assert.Contains(t, recorder.Header().Get("Content-Type"), "application/json", accept)
+
+			var body models.Response
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&body), accept)
+			assert.NotNil(t, body.Error, accept)
+		}
+	})
+
+	t.Run("Deleted links show the 410 page", func(t *testing.T) {
+		recorder := sendAuthorizedRequest(token, http.MethodDelete, "/api/short_url/"+shortUrl.ID, nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		recorder = fallbackRequest(test.Handler(), "/"+shortUrl.Slug, browserAccept)
+		assert.Equal(t, http.StatusGone, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+		assert.Contains(t, recorder.Body.String(), "Gone")
+	})
+
+	t.Run("The root and unknown slugs can redirect", func(t *testing.T) {
+		t.Setenv("FALLBACK_ROOT_URL", "https://example.com/home")
+		t.Setenv("FALLBACK_NOT_FOUND_URL", "https://example.com/missing")
+		handler := api.NewApiHandler(testQueries, testRedis, test.Mailer(), test.Resolver())
+
+		recorder := fallbackRequest(handler, "/", "")
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "https://example.com/home", recorder.Header().Get("Location"))
+
+		recorder = fallbackRequest(handler, "/"+missing, "")
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "https://example.com/missing", recorder.Header().Get("Location"))
+
+		// deleted links are not unknown
+		recorder = fallbackRequest(handler, "/"+shortUrl.Slug, "")
+		assert.Equal(t, http.StatusGone, recorder.Code)
+	})
+}
+
+func fallbackRequest(handler http.Handler, path, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
@@ -30,8 +30,10 @@ func TestIntegrationGetShorUrl(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, true, response.Success)
 
-		shortUrls, ok := response.Data.([]interface{})
-		assert.True(t, ok, "Response data should be a slice")
+		list, ok := response.Data.(map[string]interface{})
+		require.True(t, ok, "Response data should be a map")
+		shortUrls, ok := list["short_urls"].([]interface{})
+		assert.True(t, ok, "Short URLs should be a slice")
 		assert.NotEmpty(t, shortUrls, "Short URLs list should not be empty")
 	})
 
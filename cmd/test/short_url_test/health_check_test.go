@@ -0,0 +1,198 @@
+package shorturltest_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/api/worker"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver records the events delivered to it and checks their signature
+type webhookReceiver struct {
+	mu     sync.Mutex
+	secret string
+	events []models.LinkHealthEvent
+	bad    int
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	var event models.LinkHealthEvent
+	if r.Header.Get(models.WebhookSignatureHeader) != models.SignWebhookPayload(rcv.secret, body) ||
+		json.Unmarshal(body, &event) != nil ||
+		r.Header.Get(models.WebhookEventHeader) != event.Event {
+		rcv.bad++
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rcv.events = append(rcv.events, event)
+}
+
+// received filters the events of one link, the other links of the user may
+// be broken too when the tests run offline
+func (rcv *webhookReceiver) received(shortUrlId string) []models.LinkHealthEvent {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	var events []models.LinkHealthEvent
+	for _, event := range rcv.events {
+		if event.ShortUrlID == shortUrlId {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestIntegrationDestinationHealth(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	var heads atomic.Int32
+
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/page", http.StatusMovedPermanently)
+		case "/head-not-allowed":
+			if r.Method == http.MethodHead {
+				heads.Add(1)
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(int(status.Load()))
+		}
+	}))
+	defer destination.Close()
+
+	receiver := &webhookReceiver{}
+	webhookServer := httptest.NewServer(receiver)
+	defer webhookServer.Close()
+
+	// failing destinations are due again right away, healthy ones in an hour
+	local := infra.SafeHTTPOptions{AllowPrivateNetworks: true, Timeout: 2 * time.Second}
+	healthWorker := worker.NewHealthCheckWorker(testQueries, infra.NewHealthChecker(local), infra.NewWebhookSender(local), worker.HealthCheckOptions{
+		Interval:         time.Hour,
+		FailureThreshold: 2,
+	})
+
+	token, _ := setupTestShortUrl(t)
+
+	payload, _ := json.Marshal(models.CreateWebhookInput{Url: webhookServer.URL + "/hooks"})
+	recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/webhooks", payload)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	var created struct {
+		Data models.Webhook `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&created))
+	require.NotEmpty(t, created.Data.Secret)
+	receiver.secret = created.Data.Secret
+
+	// checkUntil runs the worker until the link's health matches, other links
+	// in the test database may be due before it
+	checkUntil := func(t *testing.T, id string, done func(*models.DestinationHealth) bool) *models.DestinationHealth {
+		t.Helper()
+		for range 10 {
+			healthWorker.CheckDue(context.Background())
+			if health := getTestShortUrl(t, token, id).DestinationHealth; health != nil && done(health) {
+				return health
+			}
+		}
+		t.Fatalf("destination health of %s never matched", id)
+		return nil
+	}
+
+	t.Run("Records status, final URL and latency", func(t *testing.T) {
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: destination.URL + "/old"})
+		assert.Nil(t, shortUrl.DestinationHealth)
+
+		health := checkUntil(t, shortUrl.ID, func(h *models.DestinationHealth) bool { return true })
+		assert.Equal(t, models.DestinationStatusHealthy, health.Status)
+		require.NotNil(t, health.StatusCode)
+		assert.Equal(t, http.StatusOK, *health.StatusCode)
+		assert.Equal(t, destination.URL+"/page", health.FinalUrl)
+		assert.NotNil(t, health.LatencyMs)
+		assert.NotNil(t, health.LastSuccessAt)
+		assert.Zero(t, health.ConsecutiveFailures)
+	})
+
+	t.Run("Falls back to GET when HEAD is refused", func(t *testing.T) {
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: destination.URL + "/head-not-allowed"})
+
+		health := checkUntil(t, shortUrl.ID, func(h *models.DestinationHealth) bool { return true })
+		assert.Equal(t, models.DestinationStatusHealthy, health.Status)
+		assert.Positive(t, heads.Load())
+	})
+
+	t.Run("Owners are notified once when a link breaks and when it recovers", func(t *testing.T) {
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: destination.URL + "/flaky"})
+		status.Store(http.StatusServiceUnavailable)
+		defer status.Store(http.StatusOK)
+
+		health := checkUntil(t, shortUrl.ID, func(h *models.DestinationHealth) bool { return true })
+		assert.Equal(t, models.DestinationStatusFailing, health.Status)
+		assert.Equal(t, 1, health.ConsecutiveFailures)
+		assert.Empty(t, receiver.received(shortUrl.ID))
+
+		health = checkUntil(t, shortUrl.ID, func(h *models.DestinationHealth) bool { return h.ConsecutiveFailures >= 3 })
+		assert.Equal(t, http.StatusServiceUnavailable, *health.StatusCode)
+		assert.NotEmpty(t, health.Error)
+
+		events := receiver.received(shortUrl.ID)
+		require.Len(t, events, 1, "link.broken is sent only once per outage")
+		assert.Equal(t, models.WebhookEventLinkBroken, events[0].Event)
+		assert.Equal(t, shortUrl.ID, events[0].ShortUrlID)
+		assert.Equal(t, shortUrl.Slug, events[0].Slug)
+		assert.Equal(t, models.DestinationStatusFailing, events[0].DestinationHealth.Status)
+
+		status.Store(http.StatusOK)
+		health = checkUntil(t, shortUrl.ID, func(h *models.DestinationHealth) bool { return h.Status == models.DestinationStatusHealthy })
+		assert.Zero(t, health.ConsecutiveFailures)
+
+		events = receiver.received(shortUrl.ID)
+		require.Len(t, events, 2)
+		assert.Equal(t, models.WebhookEventLinkRecovered, events[1].Event)
+		assert.Zero(t, receiver.bad)
+	})
+
+	t.Run("Changing the destination resets its health", func(t *testing.T) {
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: destination.URL + "/page"})
+		checkUntil(t, shortUrl.ID, func(h *models.DestinationHealth) bool { return true })
+
+		payload, _ := json.Marshal(models.UpdateShortUrlInput{OriginalUrl: ptr(destination.URL + "/other")})
+		updated := updateTestShortUrl(t, token, shortUrl.ID, payload)
+		assert.Nil(t, updated.DestinationHealth)
+	})
+
+	t.Run("Webhooks can be listed and deleted", func(t *testing.T) {
+		recorder := sendAuthorizedRequest(token, http.MethodGet, "/api/webhooks", nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var listed struct {
+			Data []models.Webhook `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&listed))
+		require.Len(t, listed.Data, 1)
+		assert.Empty(t, listed.Data[0].Secret, "the secret is only shown on creation")
+
+		recorder = sendAuthorizedRequest(token, http.MethodDelete, "/api/webhooks/"+created.Data.ID, nil)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+
+		recorder = sendAuthorizedRequest(token, http.MethodDelete, "/api/webhooks/"+created.Data.ID, nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
@@ -0,0 +1,202 @@
+package shorturltest_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationImportShortUrls(t *testing.T) {
+	token, existing := setupTestShortUrl(t)
+	suffix := time.Now().UnixNano()
+	kept := fmt.Sprintf("kept-%d", suffix)
+
+	t.Run("Keeps the slugs, dates, clicks and tags of a CSV export", func(t *testing.T) {
+		body := "Title,Long URL,Bitlink,Created At,Total Clicks,Tags\n" +
+			fmt.Sprintf("Kept,https://example.com/kept,https://bit.ly/%s,2023-01-02 10:00:00,\"1,234\",imported;legacy\n", kept) +
+			fmt.Sprintf("Taken,https://example.com/taken,https://bit.ly/%s,,5,\n", existing.Slug) +
+			"No slug,https://example.com/no-slug,,,,\n" +
+			"Reserved,https://example.com/api,https://bit.ly/api,,,\n" +
+			"Broken,not a url,,,,\n"
+		result := importTestRequest(t, token, "/api/short_url/import", "text/csv", []byte(body))
+
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 0, result.Renamed)
+		assert.Equal(t, 3, result.Failed)
+		require.Len(t, result.Rows, 4)
+
+		assert.Equal(t, 2, result.Rows[0].Row)
+		assert.True(t, result.Rows[0].Conflict)
+		assert.Equal(t, []models.ValidationError{{Field: "slug", Tag: "conflict", Value: existing.Slug}}, result.Rows[0].Errors)
+		assert.Equal(t, 3, result.Rows[1].Row)
+		assert.NotEmpty(t, result.Rows[1].Slug)
+		assert.Empty(t, result.Rows[1].Errors)
+		assert.Equal(t, []models.ValidationError{{Field: "slug", Tag: "reserved", Value: "api"}}, result.Rows[2].Errors)
+		assert.Equal(t, []models.ValidationError{{Field: "original_url", Tag: "url", Value: "not a url"}}, result.Rows[3].Errors)
+
+		recorder := sendAuthorizedRequest(token, http.MethodGet, "/api/short_url?q="+kept, nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var list struct {
+			Data []*models.ShortUrl `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&list))
+		require.Len(t, list.Data, 1)
+		imported := list.Data[0]
+		assert.Equal(t, kept, imported.Slug)
+		assert.Equal(t, 1234, imported.AccessCount)
+		assert.Equal(t, time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC), imported.CreatedAt.UTC())
+		require.Len(t, imported.Tags, 2)
+		assert.Equal(t, "imported", imported.Tags[0].Name)
+		assert.Equal(t, "legacy", imported.Tags[1].Name)
+
+		req := httptest.NewRequest(http.MethodGet, "/"+kept, nil)
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "https://example.com/kept", recorder.Header().Get("Location"))
+	})
+
+	t.Run("Renames conflicting slugs of a JSON export when asked", func(t *testing.T) {
+		payload := fmt.Sprintf(`{"links": [
+			{"keyword": %q, "url": "https://example.com/renamed", "clicks": 3, "tags": [{"name": "imported"}]},
+			{"keyword": "dup-%d", "url": "https://example.com/first"},
+			{"keyword": "dup-%d", "url": "https://example.com/second"}
+		]}`, kept, suffix, suffix)
+		result := importTestRequest(t, token, "/api/short_url/import?on_conflict=rename", "application/json", []byte(payload))
+
+		assert.Equal(t, 3, result.Imported)
+		assert.Equal(t, 2, result.Renamed)
+		assert.Equal(t, 0, result.Failed)
+		require.Len(t, result.Rows, 2)
+		assert.Equal(t, 1, result.Rows[0].Row)
+		assert.True(t, result.Rows[0].Conflict)
+		assert.Equal(t, kept, result.Rows[0].OriginalSlug)
+		assert.NotEqual(t, kept, result.Rows[0].Slug)
+		assert.Equal(t, 3, result.Rows[1].Row)
+
+		shortUrl := getTestShortUrl(t, token, result.Rows[0].ID)
+		assert.Equal(t, 3, shortUrl.AccessCount)
+		require.Len(t, shortUrl.Tags, 1)
+		assert.Equal(t, "imported", shortUrl.Tags[0].Name)
+	})
+
+	t.Run("Refuses an unknown conflict mode and a CSV without destinations", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/short_url/import?on_conflict=overwrite", bytes.NewReader([]byte(`[{"url": "https://example.com"}]`)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		req = httptest.NewRequest(http.MethodPost, "/api/short_url/import", bytes.NewReader([]byte("title\nfoo\n")))
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set("Authorization", "Bearer "+token)
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestIntegrationExportAccount(t *testing.T) {
+	token, shortUrl := setupTestShortUrl(t)
+	tag := createTestTag(t, token, "exported")
+	recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/tags/"+tag.ID+"/links", []byte(fmt.Sprintf(`{"short_url_ids": [%q]}`, shortUrl.ID)))
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	// the access sync adds the clicks to the day they were synced on
+	err := testQueries.IncrementAccessCount(context.Background(), pgstore.IncrementAccessCountParams{
+		Slug:        shortUrl.Slug,
+		AccessCount: pgtype.Int4{Int32: 4, Valid: true},
+	})
+	require.NoError(t, err)
+	err = testQueries.IncrementScanCount(context.Background(), pgstore.IncrementScanCountParams{Slug: shortUrl.Slug, ScanCount: 1})
+	require.NoError(t, err)
+
+	recorder = sendAuthorizedRequest(token, http.MethodGet, "/api/users/export", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/zip", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), "attachment")
+
+	archive, err := zip.NewReader(bytes.NewReader(recorder.Body.Bytes()), int64(recorder.Body.Len()))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+		files[file.Name], err = io.ReadAll(reader)
+		require.NoError(t, err)
+		reader.Close()
+	}
+	for _, name := range []string{"profile.json", "links.json", "links.csv", "tags.json", "tags.csv", "daily_stats.json", "daily_stats.csv"} {
+		assert.Contains(t, files, name)
+	}
+
+	var profile models.User
+	require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.True(t, profile.EmailVerified)
+
+	var links []*models.ShortUrl
+	require.NoError(t, json.Unmarshal(files["links.json"], &links))
+	require.Len(t, links, 1)
+	assert.Equal(t, shortUrl.Slug, links[0].Slug)
+	assert.Equal(t, 4, links[0].AccessCount)
+	require.Len(t, links[0].Tags, 1)
+	assert.Equal(t, "exported", links[0].Tags[0].Name)
+
+	records, err := csv.NewReader(bytes.NewReader(files["links.csv"])).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, models.ExportLinksCsvHeader, records[0])
+	assert.Equal(t, shortUrl.Slug, records[1][1])
+	assert.Equal(t, "exported", records[1][len(records[1])-1])
+
+	var tags []*models.Tag
+	require.NoError(t, json.Unmarshal(files["tags.json"], &tags))
+	require.Len(t, tags, 1)
+	assert.Equal(t, int64(1), tags[0].LinkCount)
+
+	var stats []*models.DailyStat
+	require.NoError(t, json.Unmarshal(files["daily_stats.json"], &stats))
+	require.Len(t, stats, 1)
+	assert.Equal(t, shortUrl.ID, stats[0].ShortUrlID)
+	assert.Equal(t, time.Now().UTC().Format(time.DateOnly), stats[0].Day)
+	assert.Equal(t, 4, stats[0].Clicks)
+	assert.Equal(t, 1, stats[0].Scans)
+
+	// the links file of the export is read back by the importer
+	otherToken, _ := setupTestShortUrl(t)
+	result := importTestRequest(t, otherToken, "/api/short_url/import?on_conflict=rename", "text/csv", files["links.csv"])
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 1, result.Renamed)
+}
+
+func importTestRequest(t *testing.T, token, path, contentType string, body []byte) *models.ImportResult {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var response struct {
+		Data models.ImportResult `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return &response.Data
+}
@@ -0,0 +1,170 @@
+package shorturltest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listPage is one page of GET /api/short_url with its pagination envelope
+type listPage struct {
+	Data       models.ShortUrlList `json:"data"`
+	Pagination *models.Pagination  `json:"pagination"`
+	link       string
+}
+
+func TestIntegrationListShortUrls(t *testing.T) {
+	token, first := setupTestShortUrl(t)
+
+	soon := time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339)
+	later := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	expiresLater := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/reports/q1", ExpiresAt: &later})
+	expiresSoon := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/reports/q2", ExpiresAt: &soon})
+	scheduled := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/launch_100%25", StartsAt: &future})
+	last := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/about"})
+
+	t.Run("Pages through all links newest first", func(t *testing.T) {
+		var ids []string
+		query := "?limit=2"
+		for pages := 0; ; pages++ {
+			require.Less(t, pages, 5, "pagination never ended")
+
+			page := listTestShortUrlPage(t, token, query)
+			require.NotNil(t, page.Pagination)
+			assert.Equal(t, 2, page.Pagination.Limit)
+			for _, shortUrl := range page.Data.ShortUrls {
+				ids = append(ids, shortUrl.ID)
+			}
+			if !page.Pagination.HasMore {
+				assert.Empty(t, page.Pagination.NextCursor)
+				assert.Empty(t, page.link)
+				break
+			}
+
+			require.NotEmpty(t, page.Pagination.NextCursor)
+			assert.Contains(t, page.link, `rel="next"`)
+			assert.Contains(t, page.link, "cursor="+url.QueryEscape(page.Pagination.NextCursor))
+			query = "?limit=2&cursor=" + url.QueryEscape(page.Pagination.NextCursor)
+		}
+
+		assert.Equal(t, []string{last.ID, scheduled.ID, expiresSoon.ID, expiresLater.ID, first.ID}, ids)
+	})
+
+	t.Run("Sorts by expiry with the links that never expire last", func(t *testing.T) {
+		page := listTestShortUrlPage(t, token, "?sort=expires_at&order=asc&limit=2")
+		require.Len(t, page.Data.ShortUrls, 2)
+		assert.Equal(t, expiresSoon.ID, page.Data.ShortUrls[0].ID)
+		assert.Equal(t, expiresLater.ID, page.Data.ShortUrls[1].ID)
+		require.True(t, page.Pagination.HasMore)
+
+		next := listTestShortUrlPage(t, token, "?sort=expires_at&order=asc&limit=2&cursor="+url.QueryEscape(page.Pagination.NextCursor))
+		require.Len(t, next.Data.ShortUrls, 2)
+		assert.Nil(t, next.Data.ShortUrls[0].ExpiresAt)
+	})
+
+	t.Run("Filters by status", func(t *testing.T) {
+		page := listTestShortUrlPage(t, token, "?status=scheduled")
+		require.Len(t, page.Data.ShortUrls, 1)
+		assert.Equal(t, scheduled.ID, page.Data.ShortUrls[0].ID)
+
+		page = listTestShortUrlPage(t, token, "?status=active")
+		assert.Len(t, page.Data.ShortUrls, 4)
+
+		page = listTestShortUrlPage(t, token, "?status=expired")
+		assert.Empty(t, page.Data.ShortUrls)
+	})
+
+	t.Run("Filters by creation date", func(t *testing.T) {
+		after := url.QueryEscape(time.Now().Add(time.Minute).UTC().Format(time.RFC3339))
+		page := listTestShortUrlPage(t, token, "?created_after="+after)
+		assert.Empty(t, page.Data.ShortUrls)
+
+		today := time.Now().UTC().Format(time.DateOnly)
+		page = listTestShortUrlPage(t, token, "?created_after="+today+"&created_before="+today)
+		assert.Len(t, page.Data.ShortUrls, 5)
+	})
+
+	t.Run("Searches the destination with wildcards taken literally", func(t *testing.T) {
+		page := listTestShortUrlPage(t, token, "?q=REPORTS")
+		assert.Len(t, page.Data.ShortUrls, 2)
+
+		page = listTestShortUrlPage(t, token, "?q="+url.QueryEscape("100%"))
+		require.Len(t, page.Data.ShortUrls, 1)
+		assert.Equal(t, scheduled.ID, page.Data.ShortUrls[0].ID)
+
+		page = listTestShortUrlPage(t, token, "?q="+url.QueryEscape("%"))
+		assert.Len(t, page.Data.ShortUrls, 1)
+
+		page = listTestShortUrlPage(t, token, "?q="+first.Slug)
+		require.NotEmpty(t, page.Data.ShortUrls)
+		assert.Equal(t, first.ID, page.Data.ShortUrls[0].ID)
+	})
+
+	t.Run("Fails with invalid parameters", func(t *testing.T) {
+		page := listTestShortUrlPage(t, token, "?limit=1")
+		require.True(t, page.Pagination.HasMore)
+		cursor := url.QueryEscape(page.Pagination.NextCursor)
+
+		for _, query := range []string{
+			"?sort=slug",
+			"?order=up",
+			"?status=deleted",
+			"?limit=many",
+			"?created_after=yesterday",
+			"?cursor=not-a-cursor",
+			"?sort=clicks&cursor=" + cursor,
+			"?order=asc&cursor=" + cursor,
+		} {
+			recorder := sendAuthorizedRequest(token, http.MethodGet, "/api/short_url"+query, nil)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+		}
+	})
+}
+
+func TestIntegrationListUsersPagination(t *testing.T) {
+	setupTestShortUrl(t)
+	setupTestShortUrl(t)
+
+	recorder := sendAuthorizedRequest("", http.MethodGet, "/api/users/all?limit=1", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data       []models.User      `json:"data"`
+		Pagination *models.Pagination `json:"pagination"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+	require.Len(t, response.Data, 1)
+	require.True(t, response.Pagination.HasMore)
+	assert.Contains(t, recorder.Header().Get("Link"), `rel="next"`)
+
+	recorder = sendAuthorizedRequest("", http.MethodGet, fmt.Sprintf("/api/users/all?limit=1&cursor=%s", url.QueryEscape(response.Pagination.NextCursor)), nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var next struct {
+		Data []models.User `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&next))
+	require.Len(t, next.Data, 1)
+	assert.NotEqual(t, response.Data[0].ID, next.Data[0].ID)
+	assert.False(t, next.Data[0].CreatedAt.After(response.Data[0].CreatedAt))
+}
+
+func listTestShortUrlPage(t *testing.T, token, query string) *listPage {
+	recorder := sendAuthorizedRequest(token, http.MethodGet, "/api/short_url"+query, nil)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var page listPage
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&page))
+	page.link = recorder.Header().Get("Link")
+
+	return &page
+}
@@ -15,9 +15,16 @@ import (
 	api "github.com/jhonVitor-rs/url-shortener/internal/api/server"
 	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
 	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 )
 
+// testQueries lets tests drive the background workers against the test database
+var testQueries *pgstore.Queries
+
+// testRedis lets tests build handlers with their own configuration
+var testRedis *redis.Client
+
 func TestMain(m *testing.M) {
 	ctx := context.Background()
 
@@ -36,7 +43,14 @@ func TestMain(m *testing.M) {
 		}
 	}()
 
-	test.SetHandler(api.NewApiHandler(pgstore.New(pool), rdb))
+	// the metadata tests link to httptest servers on the loopback address,
+	// every other destination goes through the regular policy
+	os.Setenv("DESTINATION_ALLOW_DOMAINS", "127.0.0.1")
+	os.Setenv("DESTINATION_DENY_DOMAINS", "*.blocked.test")
+
+	testQueries = pgstore.New(pool)
+	testRedis = rdb
+	test.SetHandler(api.NewApiHandler(testQueries, rdb, test.Mailer(), test.Resolver()))
 
 	exitCode := m.Run()
 
@@ -56,7 +70,7 @@ func TestMain(m *testing.M) {
 func setupTestShortUrl(t *testing.T) (string, *models.ShortUrl) {
 	email := fmt.Sprintf("jhon.doe+%d@email.com", time.Now().UnixNano())
 
-	input := models.CreateUserInput{Name: "Jhon", Email: email}
+	input := models.CreateUserInput{Name: "Jhon", Email: email, Password: test.UserPassword}
 	payload, err := json.Marshal(input)
 	require.NoError(t, err)
 
@@ -69,7 +83,16 @@ func setupTestShortUrl(t *testing.T) (string, *models.ShortUrl) {
 		t.Fatalf("unexpected status code when creating user: %d", recorder.Code)
 	}
 
-	loginInput := models.GetUserByEmailInput{Email: input.Email}
+	verificationToken, ok := test.LastEmailToken(email)
+	require.True(t, ok, "Verification email should have been sent")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/users/verify_email?token="+verificationToken, nil)
+	recorder = httptest.NewRecorder()
+
+	test.Handler().ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code, "Failed to verify email")
+
+	loginInput := models.GetUserByEmailInput{Email: input.Email, Password: test.UserPassword}
 	loginPayload, err := json.Marshal(loginInput)
 	require.NoError(t, err)
 
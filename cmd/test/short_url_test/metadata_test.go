@@ -0,0 +1,113 @@
+package shorturltest_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/api/worker"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const metadataTestPage = `<!DOCTYPE html>
+<html>
+<head>
+<title>
+  Spring   Sale
+</title>
+<meta name="description" content="Everything 50% off">
+<meta property="og:image" content="/images/cover.png">
+<link rel="icon" href="https://cdn.example.com/favicon.png">
+</head>
+<body><title>Not this one</title></body>
+</html>`
+
+func TestIntegrationShortUrlMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/landing":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, metadataTestPage)
+		case "/report.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			fmt.Fprint(w, "%PDF-1.7")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	metadataWorker := worker.NewMetadataWorker(testQueries, infra.NewMetadataFetcher(infra.SafeHTTPOptions{AllowPrivateNetworks: true}))
+
+	t.Run("Metadata is fetched in the background", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: server.URL + "/landing"})
+		require.NotNil(t, shortUrl.Metadata)
+		assert.Equal(t, models.MetadataStatusPending, shortUrl.Metadata.Status)
+
+		metadataWorker.ProcessPending(context.Background())
+
+		fetched := getTestShortUrl(t, token, shortUrl.ID)
+		require.NotNil(t, fetched.Metadata)
+		assert.Equal(t, models.MetadataStatusFetched, fetched.Metadata.Status)
+		assert.Equal(t, "Spring Sale", fetched.Metadata.Title)
+		assert.Equal(t, "Everything 50% off", fetched.Metadata.Description)
+		assert.Equal(t, server.URL+"/images/cover.png", fetched.Metadata.ImageUrl)
+		assert.Equal(t, "https://cdn.example.com/favicon.png", fetched.Metadata.FaviconUrl)
+		assert.NotNil(t, fetched.Metadata.FetchedAt)
+
+		// The preview page shows what was fetched
+		recorder := redirectTestRequest(shortUrl.Slug+"+", "")
+		assert.Contains(t, recorder.Body.String(), "Spring Sale")
+	})
+
+	t.Run("Non HTML destinations only get the default favicon", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: server.URL + "/report.pdf"})
+
+		metadataWorker.ProcessPending(context.Background())
+
+		fetched := getTestShortUrl(t, token, shortUrl.ID)
+		require.NotNil(t, fetched.Metadata)
+		assert.Equal(t, models.MetadataStatusFetched, fetched.Metadata.Status)
+		assert.Empty(t, fetched.Metadata.Title)
+		assert.Equal(t, server.URL+"/favicon.ico", fetched.Metadata.FaviconUrl)
+	})
+
+	t.Run("Failed fetches stay pending for a retry", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: server.URL + "/missing"})
+
+		metadataWorker.ProcessPending(context.Background())
+
+		fetched := getTestShortUrl(t, token, shortUrl.ID)
+		require.NotNil(t, fetched.Metadata)
+		assert.Equal(t, models.MetadataStatusPending, fetched.Metadata.Status)
+	})
+
+	t.Run("Changing the destination fetches it again", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: server.URL + "/landing"})
+		metadataWorker.ProcessPending(context.Background())
+
+		payload, _ := json.Marshal(models.UpdateShortUrlInput{OriginalUrl: ptr(server.URL + "/report.pdf")})
+		updated := updateTestShortUrl(t, token, shortUrl.ID, payload)
+		require.NotNil(t, updated.Metadata)
+		assert.Equal(t, models.MetadataStatusPending, updated.Metadata.Status)
+		assert.Empty(t, updated.Metadata.Title)
+	})
+
+	t.Run("Private addresses are refused", func(t *testing.T) {
+		fetcher := infra.NewMetadataFetcher(infra.SafeHTTPOptions{})
+
+		_, err := fetcher.Fetch(context.Background(), server.URL+"/landing")
+		assert.True(t, errors.Is(err, infra.ErrPrivateAddress), err)
+	})
+}
@@ -0,0 +1,205 @@
+package shorturltest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationPasswordProtectedShortUrl(t *testing.T) {
+	t.Run("Protected link shows a form and redirects after the password", func(t *testing.T) {
+		slug := setupProtectedShortUrl(t, "s3cret-pass")
+
+		req := httptest.NewRequest(http.MethodGet, "/"+slug, nil)
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+		assert.Contains(t, recorder.Body.String(), `name="password"`)
+
+		recorder = submitLinkPassword(t, slug, "s3cret-pass", "198.51.100.1:1234")
+		require.Equal(t, http.StatusSeeOther, recorder.Code)
+		assert.Equal(t, "https://example.com/internal-docs", recorder.Header().Get("Location"))
+
+		cookies := recorder.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.True(t, cookies[0].HttpOnly)
+
+		req = httptest.NewRequest(http.MethodGet, "/"+slug, nil)
+		req.AddCookie(cookies[0])
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "https://example.com/internal-docs", recorder.Header().Get("Location"))
+
+		// A second visit without the cookie must not be served from the cache
+		req = httptest.NewRequest(http.MethodGet, "/"+slug, nil)
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Tampered access cookie is rejected", func(t *testing.T) {
+		slug := setupProtectedShortUrl(t, "s3cret-pass")
+
+		req := httptest.NewRequest(http.MethodGet, "/"+slug, nil)
+		req.AddCookie(&http.Cookie{Name: "link_access_" + slug, Value: "9999999999.forged"})
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `name="password"`)
+	})
+
+	t.Run("Wrong passwords are throttled per IP", func(t *testing.T) {
+		slug := setupProtectedShortUrl(t, "s3cret-pass")
+
+		for i := 0; i < 5; i++ {
+			recorder := submitLinkPassword(t, slug, "wrong", "198.51.100.2:1234")
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		}
+
+		recorder := submitLinkPassword(t, slug, "s3cret-pass", "198.51.100.2:1234")
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.NotEmpty(t, recorder.Header().Get("Retry-After"))
+
+		recorder = submitLinkPassword(t, slug, "s3cret-pass", "198.51.100.3:1234")
+		assert.Equal(t, http.StatusSeeOther, recorder.Code)
+	})
+
+	t.Run("Wrong passwords are throttled per slug across IPs", func(t *testing.T) {
+		slug := setupProtectedShortUrl(t, "s3cret-pass")
+
+		for i := 0; i < 20; i++ {
+			recorder := submitLinkPassword(t, slug, "wrong", fmt.Sprintf("198.51.100.%d:1234", 10+i/4))
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		}
+
+		recorder := submitLinkPassword(t, slug, "s3cret-pass", "198.51.100.99:1234")
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	})
+
+	t.Run("A locked IP does not count towards the slug limit", func(t *testing.T) {
+		slug := setupProtectedShortUrl(t, "s3cret-pass")
+
+		for i := 0; i < 25; i++ {
+			submitLinkPassword(t, slug, "wrong", "198.51.100.50:1234")
+		}
+
+		recorder := submitLinkPassword(t, slug, "s3cret-pass", "198.51.100.51:1234")
+		assert.Equal(t, http.StatusSeeOther, recorder.Code)
+	})
+
+	t.Run("Parallel guesses can't get past the limit", func(t *testing.T) {
+		slug := setupProtectedShortUrl(t, "s3cret-pass")
+
+		var wg sync.WaitGroup
+		codes := make(chan int, 10)
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				codes <- submitLinkPassword(t, slug, "wrong", "198.51.100.4:1234").Code
+			}()
+		}
+		wg.Wait()
+		close(codes)
+
+		throttled := 0
+		for code := range codes {
+			if code == http.StatusTooManyRequests {
+				throttled++
+			}
+		}
+		assert.Equal(t, 5, throttled)
+	})
+
+	t.Run("Short passwords are refused on update like on creation", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		payload, err := json.Marshal(models.UpdateShortUrlInput{Password: ptr("abc")})
+		require.NoError(t, err)
+		recorder := sendAuthorizedRequest(token, http.MethodPatch, "/api/short_url/"+shortUrl.ID, payload)
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		var body models.Response
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&body))
+		require.NotNil(t, body.Error)
+		require.Len(t, body.Error.Errors, 1)
+		assert.Equal(t, "Password", body.Error.Errors[0].Field)
+	})
+
+	t.Run("Removing the password restores the redirect", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		payload, err := json.Marshal(models.UpdateShortUrlInput{Password: ptr("s3cret-pass")})
+		require.NoError(t, err)
+		shortUrl = updateTestShortUrl(t, token, shortUrl.ID, payload)
+		assert.True(t, shortUrl.HasPassword)
+		assert.NotEmpty(t, shortUrl.OriginalUrl)
+
+		req := httptest.NewRequest(http.MethodGet, "/"+shortUrl.Slug, nil)
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		payload, err = json.Marshal(models.UpdateShortUrlInput{Password: ptr("")})
+		require.NoError(t, err)
+		shortUrl = updateTestShortUrl(t, token, shortUrl.ID, payload)
+		assert.False(t, shortUrl.HasPassword)
+
+		req = httptest.NewRequest(http.MethodGet, "/"+shortUrl.Slug, nil)
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+	})
+}
+
+func setupProtectedShortUrl(t *testing.T, password string) string {
+	token, _ := setupTestShortUrl(t)
+
+	shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+		OriginalUrl: "https://example.com/internal-docs",
+		Password:    password,
+	})
+	require.True(t, shortUrl.HasPassword)
+
+	return shortUrl.Slug
+}
+
+func submitLinkPassword(t *testing.T, slug, password, remoteAddr string) *httptest.ResponseRecorder {
+	form := url.Values{"password": {password}}
+	req := httptest.NewRequest(http.MethodPost, "/"+slug, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.RemoteAddr = remoteAddr
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func updateTestShortUrl(t *testing.T, token, id string, payload []byte) *models.ShortUrl {
+	req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/short_url/%s", id), bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data models.ShortUrl `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return &response.Data
+}
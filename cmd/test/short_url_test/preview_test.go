@@ -0,0 +1,64 @@
+package shorturltest_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationShortUrlPreview(t *testing.T) {
+	t.Run("Plus suffix and preview param show the destination", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://docs.example.com/guide"})
+
+		requests := map[string]string{shortUrl.Slug + "+": "", shortUrl.Slug: "?preview=1"}
+		for path, query := range requests {
+			recorder := redirectTestRequest(path, query)
+			require.Equal(t, http.StatusOK, recorder.Code, path+query)
+			assert.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+
+			body := recorder.Body.String()
+			assert.Contains(t, body, "docs.example.com")
+			assert.Contains(t, body, "/"+shortUrl.Slug+"?continue=1")
+			assert.NotContains(t, body, `role="alert"`)
+		}
+
+		// Previews are not clicks
+		assert.Equal(t, 0, getTestShortUrl(t, token, shortUrl.ID).AccessCount)
+	})
+
+	t.Run("Forced interstitial warns until the visitor continues", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl:  "https://example.com/download",
+			Interstitial: true,
+		})
+		assert.True(t, shortUrl.Interstitial)
+
+		recorder := redirectTestRequest(shortUrl.Slug, "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `role="alert"`)
+
+		recorder = redirectTestRequest(shortUrl.Slug, "?continue=1")
+		require.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "https://example.com/download", recorder.Header().Get("Location"))
+
+		// The confirmed visit cached the link, the cache must keep the interstitial
+		time.Sleep(100 * time.Millisecond)
+		recorder = redirectTestRequest(shortUrl.Slug, "")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Protected links do not reveal their destination", func(t *testing.T) {
+		slug := setupProtectedShortUrl(t, "s3cret-pass")
+
+		recorder := redirectTestRequest(slug+"+", "")
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), `name="password"`)
+		assert.NotContains(t, recorder.Body.String(), "internal-docs")
+	})
+}
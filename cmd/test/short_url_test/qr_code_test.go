@@ -0,0 +1,71 @@
+package shorturltest_test
+
+import (
+	"bytes"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationShortUrlQrCode(t *testing.T) {
+	t.Run("Renders a PNG with the requested size", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		recorder := sendAuthorizedRequest(token, http.MethodGet, "/api/short_url/"+shortUrl.ID+"/qr?size=300&level=H&fg=%23112233", nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "image/png", recorder.Header().Get("Content-Type"))
+
+		img, err := png.Decode(bytes.NewReader(recorder.Body.Bytes()))
+		require.NoError(t, err)
+		assert.Equal(t, 300, img.Bounds().Dx())
+		assert.Equal(t, 300, img.Bounds().Dy())
+	})
+
+	t.Run("Renders an SVG on the public slug route", func(t *testing.T) {
+		_, shortUrl := setupTestShortUrl(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/"+shortUrl.Slug+".qr?format=svg&margin=2&bg=fff", nil)
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "image/svg+xml", recorder.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(recorder.Body.String(), "<svg"))
+	})
+
+	t.Run("Invalid options are rejected", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+
+		for _, query := range []string{"format=gif", "size=10", "level=X", "fg=blue", "fg=000&bg=000", "margin=-1"} {
+			recorder := sendAuthorizedRequest(token, http.MethodGet, "/api/short_url/"+shortUrl.ID+"/qr?"+query, nil)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, query)
+		}
+	})
+
+	t.Run("Unknown slug has no QR code", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/missing-slug.qr", nil)
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Scan marker is not forwarded to the destination", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl:  "https://example.com/flyer",
+			ForwardQuery: true,
+		})
+
+		recorder := redirectTestRequest(shortUrl.Slug, "?qr=1&ref=print")
+		require.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, "https://example.com/flyer?ref=print", recorder.Header().Get("Location"))
+	})
+}
@@ -0,0 +1,99 @@
+package shorturltest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationRedirectOptions(t *testing.T) {
+	t.Run("Link uses its configured redirect status", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl:    "https://example.com/moved",
+			RedirectStatus: http.StatusTemporaryRedirect,
+		})
+		assert.Equal(t, http.StatusTemporaryRedirect, shortUrl.RedirectStatus)
+
+		// Both the database and the cache path
+		for range 2 {
+			recorder := redirectTestRequest(shortUrl.Slug, "")
+			assert.Equal(t, http.StatusTemporaryRedirect, recorder.Code)
+			assert.Empty(t, recorder.Header().Get("Cache-Control"))
+		}
+	})
+
+	t.Run("Permanent redirects are cacheable only when stable", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		stable := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl:    "https://example.com/moved",
+			RedirectStatus: http.StatusMovedPermanently,
+		})
+		recorder := redirectTestRequest(stable.Slug, "")
+		assert.Equal(t, http.StatusMovedPermanently, recorder.Code)
+		assert.Equal(t, "public, max-age=86400", recorder.Header().Get("Cache-Control"))
+
+		limited := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl:    "https://example.com/moved",
+			RedirectStatus: http.StatusPermanentRedirect,
+			MaxClicks:      intPtr(5),
+		})
+		recorder = redirectTestRequest(limited.Slug, "")
+		assert.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+		assert.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+	})
+
+	t.Run("Query string is forwarded with the merge policy", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+
+		cases := map[string]url.Values{
+			models.QueryMergeKeepDestination: {"utm_source": {"site"}, "gclid": {"abc"}},
+			models.QueryMergeOverride:        {"utm_source": {"ads"}, "gclid": {"abc"}},
+			models.QueryMergeAppend:          {"utm_source": {"site", "ads"}, "gclid": {"abc"}},
+		}
+		for policy, expected := range cases {
+			shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+				OriginalUrl:  "https://example.com/landing?utm_source=site",
+				ForwardQuery: true,
+				QueryMerge:   policy,
+			})
+
+			recorder := redirectTestRequest(shortUrl.Slug, "?utm_source=ads&gclid=abc")
+			require.Equal(t, http.StatusFound, recorder.Code)
+			location, err := url.Parse(recorder.Header().Get("Location"))
+			require.NoError(t, err)
+			assert.Equal(t, expected, location.Query(), policy)
+		}
+	})
+
+	t.Run("Query string is dropped unless forwarding is enabled", func(t *testing.T) {
+		_, shortUrl := setupTestShortUrl(t)
+
+		recorder := redirectTestRequest(shortUrl.Slug, "?gclid=abc")
+		assert.Equal(t, shortUrl.OriginalUrl, recorder.Header().Get("Location"))
+	})
+
+	t.Run("Failed to use an unsupported redirect status", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+
+		payload, err := json.Marshal(models.CreateShortUrlInput{OriginalUrl: "https://example.com", RedirectStatus: http.StatusSeeOther})
+		require.NoError(t, err)
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/short_url", payload)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func redirectTestRequest(slug, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/"+slug+query, nil)
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+
+	return recorder
+}
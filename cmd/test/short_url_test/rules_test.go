@@ -0,0 +1,153 @@
+package shorturltest_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	iphoneUserAgent  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+	desktopUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+)
+
+func TestIntegrationRedirectRules(t *testing.T) {
+	t.Run("Rule matching the device OS wins over the original URL", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+		createTestRedirectRule(t, token, shortUrl.ID, models.CreateRedirectRuleInput{
+			Conditions: models.RuleConditions{OS: []string{"ios"}},
+			TargetUrl:  "https://apps.apple.com/app",
+		})
+
+		// The second round is served from the cache with the stored rules
+		for range 2 {
+			assert.Equal(t, "https://apps.apple.com/app", redirectWithHeaders(shortUrl.Slug, "", map[string]string{"User-Agent": iphoneUserAgent}))
+			assert.Equal(t, shortUrl.OriginalUrl, redirectWithHeaders(shortUrl.Slug, "", map[string]string{"User-Agent": desktopUserAgent}))
+		}
+	})
+
+	t.Run("Rules are evaluated by position", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+		createTestRedirectRule(t, token, shortUrl.ID, models.CreateRedirectRuleInput{
+			Position:   intPtr(10),
+			Conditions: models.RuleConditions{Languages: []string{"pt"}},
+			TargetUrl:  "https://example.com/pt",
+		})
+		first := createTestRedirectRule(t, token, shortUrl.ID, models.CreateRedirectRuleInput{
+			Position:   intPtr(1),
+			Conditions: models.RuleConditions{Query: map[string]string{"ref": "newsletter"}},
+			TargetUrl:  "https://example.com/newsletter",
+		})
+		assert.Equal(t, 1, first.Position)
+
+		headers := map[string]string{"Accept-Language": "pt-BR,pt;q=0.9,en;q=0.8"}
+		assert.Equal(t, "https://example.com/pt", redirectWithHeaders(shortUrl.Slug, "", headers))
+		assert.Equal(t, "https://example.com/newsletter", redirectWithHeaders(shortUrl.Slug, "?ref=newsletter", headers))
+		assert.Equal(t, shortUrl.OriginalUrl, redirectWithHeaders(shortUrl.Slug, "", map[string]string{"Accept-Language": "en-US"}))
+	})
+
+	t.Run("Updating and deleting rules invalidates the cached redirect", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+		rule := createTestRedirectRule(t, token, shortUrl.ID, models.CreateRedirectRuleInput{
+			Conditions: models.RuleConditions{Device: []string{"desktop"}},
+			TargetUrl:  "https://example.com/desktop",
+		})
+		headers := map[string]string{"User-Agent": desktopUserAgent}
+		assert.Equal(t, "https://example.com/desktop", redirectWithHeaders(shortUrl.Slug, "", headers))
+
+		payload, err := json.Marshal(models.UpdateRedirectRuleInput{TargetUrl: ptr("https://example.com/desktop-v2")})
+		require.NoError(t, err)
+		recorder := sendAuthorizedRequest(token, http.MethodPatch, fmt.Sprintf("/api/short_url/%s/rules/%s", shortUrl.ID, rule.ID), payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "https://example.com/desktop-v2", redirectWithHeaders(shortUrl.Slug, "", headers))
+
+		recorder = sendAuthorizedRequest(token, http.MethodDelete, fmt.Sprintf("/api/short_url/%s/rules/%s", shortUrl.ID, rule.ID), nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, shortUrl.OriginalUrl, redirectWithHeaders(shortUrl.Slug, "", headers))
+
+		recorder = sendAuthorizedRequest(token, http.MethodGet, fmt.Sprintf("/api/short_url/%s/rules", shortUrl.ID), nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var response struct {
+			Data []models.RedirectRule `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		assert.Empty(t, response.Data)
+	})
+
+	t.Run("Failed to create invalid rules", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+		path := fmt.Sprintf("/api/short_url/%s/rules", shortUrl.ID)
+
+		invalid := []models.CreateRedirectRuleInput{
+			{Conditions: models.RuleConditions{}, TargetUrl: "https://example.com"},
+			{Conditions: models.RuleConditions{OS: []string{"beos"}}, TargetUrl: "https://example.com"},
+			{Conditions: models.RuleConditions{TimeFrom: "09:00"}, TargetUrl: "https://example.com"},
+			{Conditions: models.RuleConditions{Weekdays: []int{7}}, TargetUrl: "https://example.com"},
+			{Conditions: models.RuleConditions{Timezone: "Mars/Base", Weekdays: []int{1}}, TargetUrl: "https://example.com"},
+			{Conditions: models.RuleConditions{Countries: []string{"BR"}}, TargetUrl: "ftp://example.com"},
+		}
+		for _, input := range invalid {
+			payload, err := json.Marshal(input)
+			require.NoError(t, err)
+			recorder := sendAuthorizedRequest(token, http.MethodPost, path, payload)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, "%+v", input.Conditions)
+		}
+	})
+
+	t.Run("Failed to manage rules of another user's link", func(t *testing.T) {
+		_, shortUrl := setupTestShortUrl(t)
+		otherToken, _ := setupTestShortUrl(t)
+
+		recorder := sendAuthorizedRequest(otherToken, http.MethodGet, fmt.Sprintf("/api/short_url/%s/rules", shortUrl.ID), nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func createTestRedirectRule(t *testing.T, token, shortUrlId string, input models.CreateRedirectRuleInput) *models.RedirectRule {
+	payload, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	recorder := sendAuthorizedRequest(token, http.MethodPost, fmt.Sprintf("/api/short_url/%s/rules", shortUrlId), payload)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	var response struct {
+		Data models.RedirectRule `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return &response.Data
+}
+
+func sendAuthorizedRequest(token, method, path string, payload []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+// redirectWithHeaders follows no redirects and returns the Location header
+func redirectWithHeaders(slug, query string, headers map[string]string) string {
+	req := httptest.NewRequest(http.MethodGet, "/"+slug+query, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+
+	return recorder.Header().Get("Location")
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,248 @@
+package shorturltest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationTagsAndFolders(t *testing.T) {
+	t.Run("Links are tagged on creation and filtered by tag with counts", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		news := createTestTag(t, token, "news")
+		promo := createTestTag(t, token, "promo")
+
+		tagged := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/tagged",
+			TagIDs:      []string{news.ID, promo.ID, news.ID},
+		})
+		require.Len(t, tagged.Tags, 2)
+		assert.Equal(t, "news", tagged.Tags[0].Name)
+
+		list := listTestShortUrls(t, token, "?tag_id="+news.ID)
+		require.Len(t, list.ShortUrls, 1)
+		assert.Equal(t, tagged.ID, list.ShortUrls[0].ID)
+		assert.Equal(t, int64(1), countOf(list.Tags, news.ID))
+		assert.Equal(t, int64(1), countOf(list.Tags, promo.ID))
+
+		payload, _ := json.Marshal(models.UpdateShortUrlInput{TagIDs: &[]string{promo.ID}})
+		updated := updateTestShortUrl(t, token, tagged.ID, payload)
+		require.Len(t, updated.Tags, 1)
+		assert.Equal(t, promo.ID, updated.Tags[0].ID)
+
+		list = listTestShortUrls(t, token, "?tag_id="+news.ID)
+		assert.Empty(t, list.ShortUrls)
+		assert.Equal(t, int64(0), countOf(list.Tags, news.ID))
+	})
+
+	t.Run("Tags are renamed, merged and applied in bulk", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+		other := createTestShortUrl(t, token, models.CreateShortUrlInput{OriginalUrl: "https://example.com/other"})
+		js := createTestTag(t, token, "js")
+		javascript := createTestTag(t, token, "javascript")
+
+		payload, _ := json.Marshal(models.AssignLinksInput{ShortUrlIDs: []string{shortUrl.ID, other.ID}})
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/tags/"+js.ID+"/links", payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, int64(2), decodeTag(t, recorder.Body.Bytes()).LinkCount)
+
+		payload, _ = json.Marshal(models.UpdateTagInput{Name: "javascript"})
+		recorder = sendAuthorizedRequest(token, http.MethodPatch, "/api/tags/"+js.ID, payload)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+
+		payload, _ = json.Marshal(models.UpdateTagInput{Name: "ecmascript"})
+		recorder = sendAuthorizedRequest(token, http.MethodPatch, "/api/tags/"+js.ID, payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "ecmascript", decodeTag(t, recorder.Body.Bytes()).Name)
+
+		payload, _ = json.Marshal(models.MergeTagInput{TargetID: javascript.ID})
+		recorder = sendAuthorizedRequest(token, http.MethodPost, "/api/tags/"+js.ID+"/merge", payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		merged := decodeTag(t, recorder.Body.Bytes())
+		assert.Equal(t, javascript.ID, merged.ID)
+		assert.Equal(t, int64(2), merged.LinkCount)
+
+		recorder = sendAuthorizedRequest(token, http.MethodDelete, "/api/tags/"+js.ID, nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code, "the merged tag is gone")
+
+		payload, _ = json.Marshal(models.AssignLinksInput{ShortUrlIDs: []string{other.ID}})
+		recorder = sendAuthorizedRequest(token, http.MethodDelete, "/api/tags/"+javascript.ID+"/links", payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, int64(1), decodeTag(t, recorder.Body.Bytes()).LinkCount)
+	})
+
+	t.Run("Tags of another user cannot be used", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+		otherToken, _ := setupTestShortUrl(t)
+		foreign := createTestTag(t, otherToken, "foreign")
+
+		payload, _ := json.Marshal(models.UpdateShortUrlInput{TagIDs: &[]string{foreign.ID}})
+		recorder := sendAuthorizedRequest(token, http.MethodPatch, "/api/short_url/"+shortUrl.ID, payload)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		payload, _ = json.Marshal(models.AssignLinksInput{ShortUrlIDs: []string{shortUrl.ID}})
+		recorder = sendAuthorizedRequest(otherToken, http.MethodPost, "/api/tags/"+foreign.ID+"/links", payload)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+
+	t.Run("Folders nest, filter the listing and count their links", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		work := createTestFolder(t, token, models.CreateFolderInput{Name: "work"})
+		reports := createTestFolder(t, token, models.CreateFolderInput{Name: "reports", ParentID: &work.ID})
+		require.NotNil(t, reports.ParentID)
+		assert.Equal(t, work.ID, *reports.ParentID)
+
+		payload, _ := json.Marshal(models.CreateFolderInput{Name: "reports", ParentID: &work.ID})
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/folders", payload)
+		assert.Equal(t, http.StatusConflict, recorder.Code, "sibling names are unique")
+		createTestFolder(t, token, models.CreateFolderInput{Name: "reports"})
+
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/report",
+			FolderID:    &reports.ID,
+		})
+		require.NotNil(t, shortUrl.FolderID)
+		assert.Equal(t, reports.ID, *shortUrl.FolderID)
+
+		list := listTestShortUrls(t, token, "?folder_id="+reports.ID)
+		require.Len(t, list.ShortUrls, 1)
+		assert.Equal(t, shortUrl.ID, list.ShortUrls[0].ID)
+		assert.Equal(t, int64(1), folderCountOf(list.Folders, reports.ID))
+		assert.Equal(t, int64(0), folderCountOf(list.Folders, work.ID))
+
+		payload, _ = json.Marshal(models.UpdateShortUrlInput{FolderID: ptr("")})
+		updated := updateTestShortUrl(t, token, shortUrl.ID, payload)
+		assert.Nil(t, updated.FolderID)
+	})
+
+	t.Run("Folders cannot be moved into their own subfolders", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		parent := createTestFolder(t, token, models.CreateFolderInput{Name: "parent"})
+		child := createTestFolder(t, token, models.CreateFolderInput{Name: "child", ParentID: &parent.ID})
+
+		payload, _ := json.Marshal(models.UpdateFolderInput{ParentID: &child.ID})
+		recorder := sendAuthorizedRequest(token, http.MethodPatch, "/api/folders/"+parent.ID, payload)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		payload, _ = json.Marshal(models.MergeFolderInput{TargetID: child.ID})
+		recorder = sendAuthorizedRequest(token, http.MethodPost, "/api/folders/"+parent.ID+"/merge", payload)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		payload, _ = json.Marshal(models.UpdateFolderInput{Name: ptr("renamed"), ParentID: ptr("")})
+		recorder = sendAuthorizedRequest(token, http.MethodPatch, "/api/folders/"+child.ID, payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		moved := decodeFolder(t, recorder.Body.Bytes())
+		assert.Equal(t, "renamed", moved.Name)
+		assert.Nil(t, moved.ParentID)
+	})
+
+	t.Run("Deleting or merging a folder keeps its content", func(t *testing.T) {
+		token, shortUrl := setupTestShortUrl(t)
+		archive := createTestFolder(t, token, models.CreateFolderInput{Name: "archive"})
+		old := createTestFolder(t, token, models.CreateFolderInput{Name: "old", ParentID: &archive.ID})
+		nested := createTestFolder(t, token, models.CreateFolderInput{Name: "nested", ParentID: &old.ID})
+		target := createTestFolder(t, token, models.CreateFolderInput{Name: "target"})
+
+		payload, _ := json.Marshal(models.AssignLinksInput{ShortUrlIDs: []string{shortUrl.ID}})
+		recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/folders/"+old.ID+"/links", payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, int64(1), decodeFolder(t, recorder.Body.Bytes()).LinkCount)
+
+		recorder = sendAuthorizedRequest(token, http.MethodDelete, "/api/folders/"+old.ID, nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+		moved := getTestShortUrl(t, token, shortUrl.ID)
+		require.NotNil(t, moved.FolderID)
+		assert.Equal(t, archive.ID, *moved.FolderID, "links move up to the parent")
+
+		payload, _ = json.Marshal(models.MergeFolderInput{TargetID: target.ID})
+		recorder = sendAuthorizedRequest(token, http.MethodPost, "/api/folders/"+archive.ID+"/merge", payload)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, int64(1), decodeFolder(t, recorder.Body.Bytes()).LinkCount)
+
+		recorder = sendAuthorizedRequest(token, http.MethodGet, "/api/folders", nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var folders struct {
+			Data []models.Folder `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&folders))
+		require.Len(t, folders.Data, 2)
+		for _, folder := range folders.Data {
+			if folder.ID == nested.ID {
+				require.NotNil(t, folder.ParentID)
+				assert.Equal(t, target.ID, *folder.ParentID, "subfolders move into the target")
+			}
+		}
+	})
+}
+
+func createTestTag(t *testing.T, token, name string) *models.Tag {
+	payload, err := json.Marshal(models.CreateTagInput{Name: name})
+	require.NoError(t, err)
+
+	recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/tags", payload)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	return decodeTag(t, recorder.Body.Bytes())
+}
+
+func createTestFolder(t *testing.T, token string, input models.CreateFolderInput) *models.Folder {
+	payload, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/folders", payload)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	return decodeFolder(t, recorder.Body.Bytes())
+}
+
+func listTestShortUrls(t *testing.T, token, query string) *models.ShortUrlList {
+	recorder := sendAuthorizedRequest(token, http.MethodGet, "/api/short_url"+query, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Data models.ShortUrlList `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return &response.Data
+}
+
+func decodeTag(t *testing.T, body []byte) *models.Tag {
+	var response struct {
+		Data models.Tag `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &response))
+
+	return &response.Data
+}
+
+func decodeFolder(t *testing.T, body []byte) *models.Folder {
+	var response struct {
+		Data models.Folder `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &response))
+
+	return &response.Data
+}
+
+func countOf(tags []*models.Tag, id string) int64 {
+	for _, tag := range tags {
+		if tag.ID == id {
+			return tag.LinkCount
+		}
+	}
+	return -1
+}
+
+func folderCountOf(folders []*models.Folder, id string) int64 {
+	for _, folder := range folders {
+		if folder.ID == id {
+			return folder.LinkCount
+		}
+	}
+	return -1
+}
@@ -0,0 +1,108 @@
+package shorturltest_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationShortUrlVariants(t *testing.T) {
+	variants := []*models.ShortUrlVariantInput{
+		{Url: "https://example.com/landing-a", Weight: 70},
+		{Url: "https://example.com/landing-b", Weight: 30},
+	}
+
+	t.Run("Traffic is split between the variants", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/landing",
+			Variants:    variants,
+		})
+		require.Len(t, shortUrl.Variants, 2)
+		assert.Equal(t, 70, shortUrl.Variants[0].Weight)
+
+		seen := map[string]int{}
+		for range 50 {
+			seen[redirectWithHeaders(shortUrl.Slug, "", nil)]++
+		}
+		assert.Len(t, seen, 2)
+		assert.Contains(t, seen, "https://example.com/landing-a")
+		assert.Contains(t, seen, "https://example.com/landing-b")
+	})
+
+	t.Run("Sticky links keep the visitor on their variant", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl:    "https://example.com/landing",
+			Variants:       variants,
+			StickyVariants: true,
+		})
+		assert.True(t, shortUrl.StickyVariants)
+
+		req := httptest.NewRequest(http.MethodGet, "/"+shortUrl.Slug, nil)
+		recorder := httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		require.Equal(t, http.StatusFound, recorder.Code)
+		first := recorder.Header().Get("Location")
+
+		cookies := recorder.Result().Cookies()
+		require.Len(t, cookies, 1)
+
+		for range 10 {
+			req := httptest.NewRequest(http.MethodGet, "/"+shortUrl.Slug, nil)
+			req.AddCookie(cookies[0])
+			recorder := httptest.NewRecorder()
+			test.Handler().ServeHTTP(recorder, req)
+			assert.Equal(t, first, recorder.Header().Get("Location"))
+		}
+	})
+
+	t.Run("Replacing variants keeps the ones whose URL is kept", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+		shortUrl := createTestShortUrl(t, token, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/landing",
+			Variants:    variants,
+		})
+
+		replaced := []*models.ShortUrlVariantInput{
+			{Url: "https://example.com/landing-a", Weight: 50},
+			{Url: "https://example.com/landing-c", Weight: 50},
+		}
+		payload, err := json.Marshal(models.UpdateShortUrlInput{Variants: &replaced})
+		require.NoError(t, err)
+		updated := updateTestShortUrl(t, token, shortUrl.ID, payload)
+		require.Len(t, updated.Variants, 2)
+		assert.Equal(t, shortUrl.Variants[0].ID, updated.Variants[0].ID)
+		assert.Equal(t, 50, updated.Variants[0].Weight)
+		assert.Equal(t, "https://example.com/landing-c", updated.Variants[1].Url)
+
+		payload, err = json.Marshal(models.UpdateShortUrlInput{Variants: &[]*models.ShortUrlVariantInput{}})
+		require.NoError(t, err)
+		updated = updateTestShortUrl(t, token, updated.ID, payload)
+		assert.Empty(t, updated.Variants)
+		assert.Equal(t, updated.OriginalUrl, redirectWithHeaders(updated.Slug, "", nil))
+	})
+
+	t.Run("Failed to create invalid variants", func(t *testing.T) {
+		token, _ := setupTestShortUrl(t)
+
+		invalid := [][]*models.ShortUrlVariantInput{
+			{{Url: "https://example.com/a", Weight: 100}},
+			{{Url: "https://example.com/a", Weight: 0}, {Url: "https://example.com/b", Weight: 10}},
+			{{Url: "https://example.com/a", Weight: 10}, {Url: "https://example.com/a", Weight: 10}},
+			{{Url: "https://example.com/a", Weight: 10}, {Url: "javascript:alert(1)", Weight: 10}},
+		}
+		for _, variants := range invalid {
+			payload, err := json.Marshal(models.CreateShortUrlInput{OriginalUrl: "https://example.com", Variants: variants})
+			require.NoError(t, err)
+			recorder := sendAuthorizedRequest(token, http.MethodPost, "/api/short_url", payload)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		}
+	})
+}
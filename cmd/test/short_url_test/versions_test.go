@@ -0,0 +1,103 @@
+package shorturltest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationShortUrlVersions(t *testing.T) {
+	token, shortUrl := setupTestShortUrl(t)
+
+	t.Run("A link never edited has no versions", func(t *testing.T) {
+		assert.Empty(t, listTestShortUrlVersions(t, token, shortUrl.ID))
+	})
+
+	first := updateTestShortUrl(t, token, shortUrl.ID, []byte(`{"original_url": "https://example.com/first"}`))
+	second := updateTestShortUrl(t, token, shortUrl.ID, []byte(`{"original_url": "https://example.com/second", "password": "secret123"}`))
+
+	t.Run("Every update is recorded with its author", func(t *testing.T) {
+		versions := listTestShortUrlVersions(t, token, shortUrl.ID)
+		require.Len(t, versions, 3)
+
+		assert.Equal(t, 3, versions[0].Version)
+		assert.Equal(t, second.Slug, versions[0].Slug)
+		assert.Equal(t, "https://example.com/second", versions[0].OriginalUrl)
+		assert.True(t, versions[0].HasPassword)
+		require.NotNil(t, versions[0].Author)
+		assert.Equal(t, shortUrl.UserID, versions[0].Author.ID)
+		assert.Equal(t, "Jhon", versions[0].Author.Name)
+
+		assert.Equal(t, 2, versions[1].Version)
+		assert.Equal(t, first.Slug, versions[1].Slug)
+		assert.False(t, versions[1].HasPassword)
+
+		assert.Equal(t, 1, versions[2].Version)
+		assert.Equal(t, shortUrl.Slug, versions[2].Slug)
+		assert.Equal(t, shortUrl.OriginalUrl, versions[2].OriginalUrl)
+		assert.Nil(t, versions[2].Author)
+		assert.Nil(t, versions[2].RestoredFrom)
+	})
+
+	t.Run("Restoring a version brings back its destination and slug", func(t *testing.T) {
+		// the protected slug gets cached before the restore
+		assert.Equal(t, http.StatusOK, redirectTestSlug(second.Slug))
+
+		recorder := sendAuthorizedRequest(token, http.MethodPost, fmt.Sprintf("/api/short_url/%s/versions/1/restore", shortUrl.ID), nil)
+		require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+		var response struct {
+			Data models.ShortUrl `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		assert.Equal(t, shortUrl.Slug, response.Data.Slug)
+		assert.Equal(t, shortUrl.OriginalUrl, response.Data.OriginalUrl)
+		assert.False(t, response.Data.HasPassword)
+
+		req := httptest.NewRequest(http.MethodGet, "/"+shortUrl.Slug, nil)
+		recorder = httptest.NewRecorder()
+		test.Handler().ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusFound, recorder.Code)
+		assert.Equal(t, shortUrl.OriginalUrl, recorder.Header().Get("Location"))
+		assert.Equal(t, http.StatusNotFound, redirectTestSlug(second.Slug))
+
+		versions := listTestShortUrlVersions(t, token, shortUrl.ID)
+		require.Len(t, versions, 4)
+		assert.Equal(t, 4, versions[0].Version)
+		require.NotNil(t, versions[0].RestoredFrom)
+		assert.Equal(t, 1, *versions[0].RestoredFrom)
+		require.NotNil(t, versions[0].Author)
+	})
+
+	t.Run("Restoring a missing or malformed version fails", func(t *testing.T) {
+		recorder := sendAuthorizedRequest(token, http.MethodPost, fmt.Sprintf("/api/short_url/%s/versions/99/restore", shortUrl.ID), nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+		recorder = sendAuthorizedRequest(token, http.MethodPost, fmt.Sprintf("/api/short_url/%s/versions/latest/restore", shortUrl.ID), nil)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("Other users cannot read the versions", func(t *testing.T) {
+		otherToken, _ := setupTestShortUrl(t)
+		recorder := sendAuthorizedRequest(otherToken, http.MethodGet, fmt.Sprintf("/api/short_url/%s/versions", shortUrl.ID), nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+func listTestShortUrlVersions(t *testing.T, token, id string) []*models.ShortUrlVersion {
+	recorder := sendAuthorizedRequest(token, http.MethodGet, fmt.Sprintf("/api/short_url/%s/versions", id), nil)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+
+	var response struct {
+		Data []*models.ShortUrlVersion `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+
+	return response.Data
+}
@@ -0,0 +1,96 @@
+package user_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationAccount(t *testing.T) {
+	t.Run("Verify email with token sent by email", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+
+		verificationToken, ok := test.LastEmailToken(email)
+		require.True(t, ok, "Verification email should have been sent")
+
+		recorder, response := sendRequest(t, http.MethodGet, "/api/users/verify_email?token="+verificationToken, "", nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, true, response.Data.(map[string]interface{})["email_verified"])
+
+		recorder, response = sendRequest(t, http.MethodGet, "/api/users/verify_email?token="+verificationToken, "", nil)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, "Token must be single use")
+		assert.Equal(t, "Invalid or expired token", response.Error.Message)
+	})
+
+	t.Run("Unverified user cannot create short urls", func(t *testing.T) {
+		token := setupTestUser(t)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/short_url", token, models.CreateShortUrlInput{
+			OriginalUrl: "https://www.youtube.com/watch?v=-Ka4YKW7RwM&t=537s",
+		})
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.Equal(t, "Email address must be verified before creating short URLs", response.Error.Message)
+	})
+
+	t.Run("Changing email requires verification again", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+
+		verificationToken, _ := test.LastEmailToken(email)
+		sendRequest(t, http.MethodGet, "/api/users/verify_email?token="+verificationToken, "", nil)
+
+		newEmail := fmt.Sprintf("jhon.new+%d@email.com", time.Now().UnixNano())
+		recorder, response := sendRequest(t, http.MethodPatch, "/api/users", token, models.UpdateUserInput{Email: &newEmail})
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, false, response.Data.(map[string]interface{})["email_verified"])
+
+		_, ok := test.LastEmailToken(newEmail)
+		assert.True(t, ok, "Verification email should be sent to the new address")
+
+		recorder, response = sendRequest(t, http.MethodGet, "/api/users/verify_email?token="+verificationToken, "", nil)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, false, response.Success)
+	})
+
+	t.Run("Reset password and login with it", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+
+		recorder, _ := sendRequest(t, http.MethodPost, "/api/users/password/forgot", "", models.ForgotPasswordInput{Email: email})
+		require.Equal(t, http.StatusAccepted, recorder.Code)
+
+		resetToken, ok := test.LastEmailToken(email)
+		require.True(t, ok, "Reset email should have been sent")
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/users/password/reset", "", models.ResetPasswordInput{
+			Token:    resetToken,
+			Password: "a-new-password",
+		})
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/login", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, "Invalid email or password", response.Error.Message)
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/users/login", "", models.GetUserByEmailInput{
+			Email:    email,
+			Password: "a-new-password",
+		})
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+	})
+
+	t.Run("Forgot password does not reveal unknown emails", func(t *testing.T) {
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/password/forgot", "", models.ForgotPasswordInput{
+			Email: "nobody.here@email.com",
+		})
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+		assert.Equal(t, true, response.Success)
+	})
+}
@@ -16,8 +16,9 @@ import (
 func TestIntegrationCreateUser(t *testing.T) {
 	t.Run("Create user with success", func(t *testing.T) {
 		input := models.CreateUserInput{
-			Name:  "Jhon Doe",
-			Email: "jhon.doe@email.com",
+			Name:     "Jhon Doe",
+			Email:    "jhon.doe@email.com",
+			Password: test.UserPassword,
 		}
 		payload, err := json.Marshal(input)
 		require.NoError(t, err)
@@ -43,8 +44,9 @@ func TestIntegrationCreateUser(t *testing.T) {
 
 	t.Run("Error to create with the same email", func(t *testing.T) {
 		input := models.CreateUserInput{
-			Name:  "Jhon Doe",
-			Email: "jhon.doe@email.com",
+			Name:     "Jhon Doe",
+			Email:    "jhon.doe@email.com",
+			Password: test.UserPassword,
 		}
 		payload, err := json.Marshal(input)
 		require.NoError(t, err)
@@ -81,6 +83,16 @@ func TestIntegrationCreateUser(t *testing.T) {
 
 		assert.Equal(t, false, resp.Success)
 		assert.Equal(t, resp.Error.Message, "Invalid input")
-		assert.Len(t, resp.Error.Errors, 1)
+		assert.Len(t, resp.Error.Errors, 2)
+	})
+
+	t.Run("Error to create without password", func(t *testing.T) {
+		recorder, resp := sendRequest(t, http.MethodPost, "/api/users", "", models.CreateUserInput{
+			Name:  "Jhon Doe",
+			Email: "jhon.nopassword@email.com",
+		})
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		require.Len(t, resp.Error.Errors, 1)
+		assert.Equal(t, "Password", resp.Error.Errors[0].Field)
 	})
 }
@@ -30,4 +30,45 @@ func TestIntegrationDeleteUser(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, recorder.Code)
 		assert.Equal(t, true, response.Success)
 	})
+	t.Run("Restore deleted user", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+
+		recorder, _ := sendRequest(t, http.MethodDelete, "/api/users", token, nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/users/login", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+		assert.Equal(t, http.StatusNotFound, recorder.Code, "Deleted user should not log in")
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/restore", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, email, response.Data.(map[string]interface{})["email"])
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/users/login", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+	})
+
+	t.Run("Email of a deleted user can sign up again", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+
+		recorder, _ := sendRequest(t, http.MethodDelete, "/api/users", token, nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/users", "", models.CreateUserInput{Name: "Jhon", Email: email, Password: test.UserPassword})
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/restore", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+		assert.Equal(t, "Email now belongs to another account", response.Error.Message)
+	})
+
+	t.Run("Failed to restore an active user", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/restore", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, "Deleted user not found", response.Error.Message)
+	})
 }
@@ -53,7 +53,8 @@ func TestIntegrationGetUser(t *testing.T) {
 		setupTestUser(t)
 
 		input := models.GetUserByEmailInput{
-			Email: "jhon.doe@email.com",
+			Email:    "jhon.doe@email.com",
+			Password: test.UserPassword,
 		}
 		payload, err := json.Marshal(input)
 		require.NoError(t, err)
@@ -79,7 +80,8 @@ func TestIntegrationGetUser(t *testing.T) {
 
 	t.Run("Failed to login with invalid email", func(t *testing.T) {
 		input := models.GetUserByEmailInput{
-			Email: "jhon.due@email.com",
+			Email:    "jhon.due@email.com",
+			Password: test.UserPassword,
 		}
 		payload, err := json.Marshal(input)
 		require.NoError(t, err)
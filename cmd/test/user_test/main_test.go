@@ -36,7 +36,7 @@ func TestMain(m *testing.M) {
 		}
 	}()
 
-	test.SetHandler(api.NewApiHandler(pgstore.New(pool), rdb))
+	test.SetHandler(api.NewApiHandler(pgstore.New(pool), rdb, test.Mailer(), test.Resolver()))
 
 	exitCode := m.Run()
 
@@ -56,7 +56,7 @@ func TestMain(m *testing.M) {
 func setupTestUser(t *testing.T) string {
 	email := fmt.Sprintf("jhon.doe+%d@email.com", time.Now().UnixNano())
 
-	input := models.CreateUserInput{Name: "Jhon", Email: email}
+	input := models.CreateUserInput{Name: "Jhon", Email: email, Password: test.UserPassword}
 	payload, err := json.Marshal(input)
 	require.NoError(t, err)
 
@@ -69,7 +69,7 @@ func setupTestUser(t *testing.T) string {
 		t.Fatalf("unexpected status code when creating user: %d", recorder.Code)
 	}
 
-	loginInput := models.GetUserByEmailInput{Email: input.Email}
+	loginInput := models.GetUserByEmailInput{Email: input.Email, Password: test.UserPassword}
 	loginPayload, err := json.Marshal(loginInput)
 	require.NoError(t, err)
 
@@ -94,3 +94,33 @@ func setupTestUser(t *testing.T) string {
 
 	return token
 }
+
+func getTestUserEmail(t *testing.T, token string) string {
+	recorder, response := sendRequest(t, http.MethodGet, "/api/users", token, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	return response.Data.(map[string]interface{})["email"].(string)
+}
+
+func sendRequest(t *testing.T, method, path, token string, body interface{}) (*httptest.ResponseRecorder, models.Response) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+
+	var response models.Response
+	err := json.NewDecoder(recorder.Body).Decode(&response)
+	require.NoError(t, err)
+
+	return recorder, response
+}
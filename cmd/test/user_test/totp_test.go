@@ -0,0 +1,216 @@
+package user_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationTotp(t *testing.T) {
+	t.Run("Enroll, activate and login with TOTP", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+		secret := enrollTestTotp(t, token)
+
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/totp/verify", token, models.TotpCodeInput{Code: code})
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		codesData, ok := response.Data.(map[string]interface{})
+		require.True(t, ok, "Response data should be a map")
+		recoveryCodes, ok := codesData["recovery_codes"].([]interface{})
+		require.True(t, ok, "Recovery codes should be a list")
+		assert.Len(t, recoveryCodes, 10)
+
+		recorder, response = sendRequest(t, http.MethodPost, "/api/users/login", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+		require.Equal(t, http.StatusAccepted, recorder.Code)
+
+		challengeData, ok := response.Data.(map[string]interface{})
+		require.True(t, ok, "Response data should be a map")
+		_, exists := challengeData["jwt"]
+		assert.False(t, exists, "JWT should not be issued before the TOTP step")
+		challenge := challengeData["challenge_token"].(string)
+		require.NotEmpty(t, challenge)
+
+		recorder, _ = sendRequest(t, http.MethodGet, "/api/users", challenge, nil)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, "Challenge token must not authenticate requests")
+
+		recorder, response = sendRequest(t, http.MethodPost, "/api/users/login/totp", "", models.TotpLoginInput{
+			ChallengeToken: challenge,
+			Code:           code,
+		})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, "The activation code can't be used again")
+
+		recorder, response = sendRequest(t, http.MethodPost, "/api/users/login/totp", "", models.TotpLoginInput{
+			ChallengeToken: challenge,
+			Code:           nextTestTotpCode(t, secret),
+		})
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		tokenData, ok := response.Data.(map[string]interface{})
+		require.True(t, ok, "Response data should be a map")
+		assert.NotEmpty(t, tokenData["jwt"])
+	})
+
+	t.Run("Recovery code can be used only once", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+		secret := enrollTestTotp(t, token)
+
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+
+		_, response := sendRequest(t, http.MethodPost, "/api/users/totp/verify", token, models.TotpCodeInput{Code: code})
+		recoveryCode := response.Data.(map[string]interface{})["recovery_codes"].([]interface{})[0].(string)
+
+		_, response = sendRequest(t, http.MethodPost, "/api/users/login", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+		challenge := response.Data.(map[string]interface{})["challenge_token"].(string)
+
+		input := models.TotpLoginInput{ChallengeToken: challenge, Code: recoveryCode}
+		recorder, _ := sendRequest(t, http.MethodPost, "/api/users/login/totp", "", input)
+		assert.Equal(t, http.StatusCreated, recorder.Code)
+
+		input.ChallengeToken = loginTestTotpChallenge(t, email)
+		recorder, response = sendRequest(t, http.MethodPost, "/api/users/login/totp", "", input)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, "Invalid TOTP or recovery code", response.Error.Message)
+	})
+
+	t.Run("Challenge tokens and TOTP codes are accepted once", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+		secret := activateTestTotp(t, token)
+
+		input := models.TotpLoginInput{ChallengeToken: loginTestTotpChallenge(t, email), Code: nextTestTotpCode(t, secret)}
+		recorder, _ := sendRequest(t, http.MethodPost, "/api/users/login/totp", "", input)
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/login/totp", "", input)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, "Challenge token already used or expired", response.Error.Message)
+
+		input.ChallengeToken = loginTestTotpChallenge(t, email)
+		recorder, response = sendRequest(t, http.MethodPost, "/api/users/login/totp", "", input)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, "TOTP code already used", response.Error.Message)
+	})
+
+	t.Run("TOTP login is locked after too many attempts", func(t *testing.T) {
+		token := setupTestUser(t)
+		email := getTestUserEmail(t, token)
+		secret := activateTestTotp(t, token)
+		challenge := loginTestTotpChallenge(t, email)
+
+		for range 5 {
+			recorder, _ := sendRequest(t, http.MethodPost, "/api/users/login/totp", "", models.TotpLoginInput{
+				ChallengeToken: challenge,
+				Code:           "000000",
+			})
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		}
+
+		code, err := totp.GenerateCode(secret, time.Now())
+		require.NoError(t, err)
+
+		recorder, _ := sendRequest(t, http.MethodPost, "/api/users/login/totp", "", models.TotpLoginInput{
+			ChallengeToken: challenge,
+			Code:           code,
+		})
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	})
+
+	t.Run("Codes used to manage TOTP are checked like login codes", func(t *testing.T) {
+		token := setupTestUser(t)
+		secret := activateTestTotp(t, token)
+
+		code := nextTestTotpCode(t, secret)
+		recorder, _ := sendRequest(t, http.MethodPost, "/api/users/totp/recovery_codes", token, models.TotpCodeInput{Code: code})
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/totp/recovery_codes", token, models.TotpCodeInput{Code: code})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, "TOTP code already used", response.Error.Message)
+
+		for range 4 {
+			recorder, _ := sendRequest(t, http.MethodDelete, "/api/users/totp", token, models.TotpCodeInput{Code: "000000"})
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		}
+
+		recorder, _ = sendRequest(t, http.MethodDelete, "/api/users/totp", token, models.TotpCodeInput{Code: "000000"})
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code, "Disabling TOTP must not allow guessing codes")
+	})
+
+	t.Run("Failed to activate with invalid code", func(t *testing.T) {
+		token := setupTestUser(t)
+		enrollTestTotp(t, token)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/totp/verify", token, models.TotpCodeInput{Code: "000000"})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, false, response.Success)
+	})
+
+	t.Run("Failed to login with invalid challenge token", func(t *testing.T) {
+		token := setupTestUser(t)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/users/login/totp", "", models.TotpLoginInput{
+			ChallengeToken: token,
+			Code:           "123456",
+		})
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		assert.Equal(t, "Invalid challenge token", response.Error.Message)
+	})
+}
+
+func enrollTestTotp(t *testing.T, token string) string {
+	recorder, response := sendRequest(t, http.MethodPost, "/api/users/totp/enroll", token, nil)
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	enrollment, ok := response.Data.(map[string]interface{})
+	require.True(t, ok, "Response data should be a map")
+	assert.Contains(t, enrollment["otpauth_uri"], "otpauth://totp/")
+
+	secret, ok := enrollment["secret"].(string)
+	require.True(t, ok, "Secret should be a string")
+	require.NotEmpty(t, secret)
+
+	return secret
+}
+
+func activateTestTotp(t *testing.T, token string) string {
+	secret := enrollTestTotp(t, token)
+
+	code, err := totp.GenerateCode(secret, time.Now())
+	require.NoError(t, err)
+
+	recorder, _ := sendRequest(t, http.MethodPost, "/api/users/totp/verify", token, models.TotpCodeInput{Code: code})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	return secret
+}
+
+// nextTestTotpCode returns the code of the next time step, the current one may
+// already have been used by the activation
+func nextTestTotpCode(t *testing.T, secret string) string {
+	code, err := totp.GenerateCode(secret, time.Now().Add(30*time.Second))
+	require.NoError(t, err)
+
+	return code
+}
+
+func loginTestTotpChallenge(t *testing.T, email string) string {
+	recorder, response := sendRequest(t, http.MethodPost, "/api/users/login", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+
+	challenge, ok := response.Data.(map[string]interface{})["challenge_token"].(string)
+	require.True(t, ok, "Challenge token should be a string")
+
+	return challenge
+}
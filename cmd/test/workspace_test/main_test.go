@@ -0,0 +1,163 @@
+package workspacetest_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	api "github.com/jhonVitor-rs/url-shortener/internal/api/server"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	"github.com/stretchr/testify/require"
+)
+
+// testPool lets tests reach the database the way the background workers do
+var testPool *pgxpool.Pool
+
+func TestMain(m *testing.M) {
+	ctx := context.Background()
+
+	pool, rdb := test.Config(ctx)
+
+	if pool == nil || rdb == nil {
+		os.Exit(1)
+	}
+
+	defer func() {
+		if pool != nil {
+			pool.Close()
+		}
+		if rdb != nil {
+			rdb.Close()
+		}
+	}()
+
+	testPool = pool
+	test.SetHandler(api.NewApiHandler(pgstore.New(pool), rdb, test.Mailer(), test.Resolver()))
+
+	exitCode := m.Run()
+
+	if pool != nil {
+		_, err := pool.Exec(ctx, `
+			DELETE FROM short_urls;
+			DELETE FROM workspaces;
+			DELETE FROM users;
+		`)
+		if err != nil {
+			os.Exit(exitCode)
+		}
+	}
+
+	os.Exit(exitCode)
+}
+
+// setupVerifiedUser creates a user with a verified email and returns its JWT and email
+func setupVerifiedUser(t *testing.T) (string, string) {
+	email := fmt.Sprintf("jhon.doe+%d@email.com", time.Now().UnixNano())
+
+	recorder, _ := sendRequest(t, http.MethodPost, "/api/users", "", models.CreateUserInput{Name: "Jhon", Email: email, Password: test.UserPassword})
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	verificationToken, ok := test.LastEmailToken(email)
+	require.True(t, ok, "Verification email should have been sent")
+
+	recorder, _ = sendRequest(t, http.MethodGet, "/api/users/verify_email?token="+verificationToken, "", nil)
+	require.Equal(t, http.StatusOK, recorder.Code, "Failed to verify email")
+
+	recorder, response := sendRequest(t, http.MethodPost, "/api/users/login", "", models.GetUserByEmailInput{Email: email, Password: test.UserPassword})
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	token, ok := response.Data.(map[string]interface{})["jwt"].(string)
+	require.True(t, ok, "JWT field should exist in response")
+
+	return token, email
+}
+
+func setupTestWorkspace(t *testing.T, token string) string {
+	recorder, response := sendRequest(t, http.MethodPost, "/api/workspaces", token, models.CreateWorkspaceInput{Name: "Marketing"})
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	workspace, ok := response.Data.(map[string]interface{})
+	require.True(t, ok, "Response data should be a map")
+	require.Equal(t, models.WorkspaceRoleOwner, workspace["role"])
+
+	return workspace["id"].(string)
+}
+
+// addTestMember invites a new verified user with the given role and accepts the invitation
+func addTestMember(t *testing.T, ownerToken, workspaceId, role string) (string, string) {
+	token, email := setupVerifiedUser(t)
+
+	recorder, _ := sendRequest(t, http.MethodPost, "/api/workspaces/"+workspaceId+"/invitations", ownerToken, models.InviteMemberInput{
+		Email: email,
+		Role:  role,
+	})
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	invitationToken, ok := test.LastEmailToken(email)
+	require.True(t, ok, "Invitation email should have been sent")
+
+	recorder, _ = sendRequest(t, http.MethodPost, "/api/workspaces/invitations/accept", token, models.AcceptInvitationInput{Token: invitationToken})
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder, response := sendRequest(t, http.MethodGet, "/api/users", token, nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	return token, response.Data.(map[string]interface{})["id"].(string)
+}
+
+func createTestShortUrl(t *testing.T, token string, workspaceId *string) string {
+	recorder, response := sendRequest(t, http.MethodPost, "/api/short_url", token, models.CreateShortUrlInput{
+		OriginalUrl: "https://www.youtube.com/watch?v=-Ka4YKW7RwM&t=537s",
+		WorkspaceID: workspaceId,
+	})
+	require.Equal(t, http.StatusCreated, recorder.Code, "Failed to create short URL")
+
+	return response.Data.(map[string]interface{})["id"].(string)
+}
+
+// setupVerifiedDomain adds a verified custom domain to the user and returns its ID
+func setupVerifiedDomain(t *testing.T, token string) string {
+	host := fmt.Sprintf("links-%d.example.org", time.Now().UnixNano())
+	recorder, response := sendRequest(t, http.MethodPost, "/api/domains", token, models.CreateDomainInput{Host: host})
+	require.Equal(t, http.StatusCreated, recorder.Code, "Failed to create domain")
+	domain := response.Data.(map[string]interface{})
+	domainId := domain["id"].(string)
+
+	test.Resolver().Set(domain["verification_record"].(string), domain["verification_value"].(string))
+	recorder, _ = sendRequest(t, http.MethodPost, "/api/domains/"+domainId+"/verify", token, nil)
+	require.Equal(t, http.StatusOK, recorder.Code, "Failed to verify domain")
+
+	return domainId
+}
+
+func sendRequest(t *testing.T, method, path, token string, body interface{}) (*httptest.ResponseRecorder, models.Response) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+	recorder := httptest.NewRecorder()
+	test.Handler().ServeHTTP(recorder, req)
+
+	var response models.Response
+	err := json.NewDecoder(recorder.Body).Decode(&response)
+	require.NoError(t, err)
+
+	return recorder, response
+}
@@ -0,0 +1,271 @@
+package workspacetest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/jhonVitor-rs/url-shortener/cmd/test"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationWorkspace(t *testing.T) {
+	t.Run("Create and list workspace short urls", func(t *testing.T) {
+		token, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, token)
+		createTestShortUrl(t, token, &workspaceId)
+
+		recorder, response := sendRequest(t, http.MethodGet, "/api/short_url?workspace_id="+workspaceId, token, nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		list, ok := response.Data.(map[string]interface{})
+		require.True(t, ok, "Response data should be a map")
+		shortUrls, ok := list["short_urls"].([]interface{})
+		require.True(t, ok, "Short URLs should be a slice")
+		require.Len(t, shortUrls, 1)
+		assert.Equal(t, workspaceId, shortUrls[0].(map[string]interface{})["workspace_id"])
+
+		recorder, response = sendRequest(t, http.MethodGet, "/api/short_url", token, nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		list, ok = response.Data.(map[string]interface{})
+		require.True(t, ok, "Response data should be a map")
+		assert.Empty(t, list["short_urls"], "Workspace links should not be listed as personal links")
+	})
+
+	t.Run("Roles control what members can do", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+		shortUrlId := createTestShortUrl(t, ownerToken, &workspaceId)
+
+		editorToken, _ := addTestMember(t, ownerToken, workspaceId, models.WorkspaceRoleEditor)
+		viewerToken, _ := addTestMember(t, ownerToken, workspaceId, models.WorkspaceRoleViewer)
+		outsiderToken, _ := setupVerifiedUser(t)
+
+		originalUrl := "https://example.com/updated"
+		recorder, _ := sendRequest(t, http.MethodPatch, "/api/short_url/"+shortUrlId, editorToken, models.UpdateShortUrlInput{OriginalUrl: &originalUrl})
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, _ = sendRequest(t, http.MethodGet, "/api/short_url/"+shortUrlId, viewerToken, nil)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, response := sendRequest(t, http.MethodDelete, "/api/short_url/"+shortUrlId, viewerToken, nil)
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.Equal(t, "Insufficient workspace role", response.Error.Message)
+
+		recorder, response = sendRequest(t, http.MethodGet, "/api/short_url/"+shortUrlId, outsiderToken, nil)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+		assert.Equal(t, "Short URL not found", response.Error.Message)
+	})
+
+	t.Run("Workspace links survive their creator", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+		editorToken, _ := addTestMember(t, ownerToken, workspaceId, models.WorkspaceRoleEditor)
+		shortUrlId := createTestShortUrl(t, editorToken, &workspaceId)
+
+		recorder, _ := sendRequest(t, http.MethodDelete, "/api/users", editorToken, nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		recorder, _ = sendRequest(t, http.MethodGet, "/api/short_url/"+shortUrlId, ownerToken, nil)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Workspace links survive a purged member owning a domain", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+		memberToken, memberId := addTestMember(t, ownerToken, workspaceId, models.WorkspaceRoleEditor)
+		domainId := setupVerifiedDomain(t, memberToken)
+
+		recorder, _ := sendRequest(t, http.MethodPost, "/api/short_url", memberToken, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/branded",
+			WorkspaceID: &workspaceId,
+			DomainID:    &domainId,
+		})
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, "Workspace links can't be put on a personal domain")
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/short_url", memberToken, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/personal",
+			DomainID:    &domainId,
+		})
+		require.Equal(t, http.StatusCreated, recorder.Code)
+		shortUrlId := createTestShortUrl(t, memberToken, &workspaceId)
+
+		recorder, _ = sendRequest(t, http.MethodDelete, "/api/users", memberToken, nil)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+
+		// the purge worker hard-deletes the user, their domain goes with them
+		_, err := testPool.Exec(context.Background(), "DELETE FROM users WHERE id = $1", memberId)
+		require.NoError(t, err)
+
+		recorder, _ = sendRequest(t, http.MethodGet, "/api/short_url/"+shortUrlId, ownerToken, nil)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Members serving workspace links on a domain can't delete the account", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+		memberToken, memberId := addTestMember(t, ownerToken, workspaceId, models.WorkspaceRoleEditor)
+		domainId := setupVerifiedDomain(t, memberToken)
+		shortUrlId := createTestShortUrl(t, memberToken, &workspaceId)
+
+		// links put on a domain before workspace links were kept off them
+		_, err := testPool.Exec(context.Background(), "UPDATE short_urls SET domain_id = $1 WHERE id = $2", domainId, shortUrlId)
+		require.NoError(t, err)
+
+		recorder, _ := sendRequest(t, http.MethodDelete, "/api/users", memberToken, nil)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+
+		_, err = testPool.Exec(context.Background(), "DELETE FROM users WHERE id = $1", memberId)
+		assert.Error(t, err, "The domain of a workspace link must not be deleted")
+
+		recorder, _ = sendRequest(t, http.MethodGet, "/api/short_url/"+shortUrlId, ownerToken, nil)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Sole owner can't delete the account", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		setupTestWorkspace(t, ownerToken)
+
+		recorder, _ := sendRequest(t, http.MethodDelete, "/api/users", ownerToken, nil)
+		assert.Equal(t, http.StatusConflict, recorder.Code)
+	})
+
+	t.Run("Last owner can't leave the workspace", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+
+		_, response := sendRequest(t, http.MethodGet, "/api/users", ownerToken, nil)
+		ownerId := response.Data.(map[string]interface{})["id"].(string)
+
+		recorder, response := sendRequest(t, http.MethodDelete, "/api/workspaces/"+workspaceId+"/members/"+ownerId, ownerToken, nil)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, "Workspace must keep at least one owner", response.Error.Message)
+	})
+
+	t.Run("Transfer a personal link to a workspace and back", func(t *testing.T) {
+		ownerToken, ownerEmail := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+		shortUrlId := createTestShortUrl(t, ownerToken, nil)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/short_url/"+shortUrlId+"/transfer", ownerToken, models.TransferShortUrlInput{WorkspaceID: &workspaceId})
+		require.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, workspaceId, response.Data.(map[string]interface{})["workspace_id"])
+
+		recorder, _ = sendRequest(t, http.MethodDelete, "/api/workspaces/"+workspaceId, ownerToken, nil)
+		assert.Equal(t, http.StatusConflict, recorder.Code, "Workspace with links should not be deleted")
+
+		recorder, response = sendRequest(t, http.MethodPost, "/api/short_url/"+shortUrlId+"/transfer", ownerToken, models.TransferShortUrlInput{UserEmail: &ownerEmail})
+		require.Equal(t, http.StatusOK, recorder.Code)
+		_, exists := response.Data.(map[string]interface{})["workspace_id"]
+		assert.False(t, exists, "Link should be personal again")
+
+		recorder, _ = sendRequest(t, http.MethodDelete, "/api/workspaces/"+workspaceId, ownerToken, nil)
+		assert.Equal(t, http.StatusNoContent, recorder.Code)
+	})
+
+	t.Run("Links go to other users only from a shared workspace", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+		_, memberEmail := addTestMember(t, ownerToken, workspaceId, models.WorkspaceRoleViewer)
+		_, outsiderEmail := setupVerifiedUser(t)
+
+		personalId := createTestShortUrl(t, ownerToken, nil)
+		recorder, _ := sendRequest(t, http.MethodPost, "/api/short_url/"+personalId+"/transfer", ownerToken, models.TransferShortUrlInput{UserEmail: &memberEmail})
+		assert.Equal(t, http.StatusForbidden, recorder.Code, "Personal links can't be pushed to another account")
+
+		shortUrlId := createTestShortUrl(t, ownerToken, &workspaceId)
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/short_url/"+shortUrlId+"/transfer", ownerToken, models.TransferShortUrlInput{UserEmail: &outsiderEmail})
+		assert.Equal(t, http.StatusForbidden, recorder.Code, "Links can't leave for someone outside the workspace")
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/short_url/"+shortUrlId+"/transfer", ownerToken, models.TransferShortUrlInput{UserEmail: &memberEmail})
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("Folder, campaign and tags of the previous owner are dropped", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+		memberToken, memberEmail := addTestMember(t, ownerToken, workspaceId, models.WorkspaceRoleViewer)
+
+		_, response := sendRequest(t, http.MethodPost, "/api/folders", ownerToken, models.CreateFolderInput{Name: "Owner folder"})
+		folderId := response.Data.(map[string]interface{})["id"].(string)
+		_, response = sendRequest(t, http.MethodPost, "/api/campaigns", ownerToken, models.CreateCampaignInput{Name: "Owner campaign"})
+		campaignId := response.Data.(map[string]interface{})["id"].(string)
+		_, response = sendRequest(t, http.MethodPost, "/api/tags", ownerToken, models.CreateTagInput{Name: "owner"})
+		tagId := response.Data.(map[string]interface{})["id"].(string)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/short_url", ownerToken, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/organized",
+			WorkspaceID: &workspaceId,
+			FolderID:    &folderId,
+			CampaignID:  &campaignId,
+			TagIDs:      []string{tagId},
+		})
+		require.Equal(t, http.StatusCreated, recorder.Code)
+		shortUrlId := response.Data.(map[string]interface{})["id"].(string)
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/short_url/"+shortUrlId+"/transfer", ownerToken, models.TransferShortUrlInput{UserEmail: &memberEmail})
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, response = sendRequest(t, http.MethodGet, "/api/short_url/"+shortUrlId, memberToken, nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		shortUrl := response.Data.(map[string]interface{})
+		assert.NotContains(t, shortUrl, "folder_id")
+		assert.NotContains(t, shortUrl, "campaign_id")
+		assert.NotContains(t, shortUrl, "tags")
+	})
+
+	t.Run("Restoring a version keeps the previous owner's folder and campaign out", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+		memberToken, memberEmail := addTestMember(t, ownerToken, workspaceId, models.WorkspaceRoleViewer)
+
+		_, response := sendRequest(t, http.MethodPost, "/api/folders", ownerToken, models.CreateFolderInput{Name: "Owner folder"})
+		folderId := response.Data.(map[string]interface{})["id"].(string)
+		_, response = sendRequest(t, http.MethodPost, "/api/campaigns", ownerToken, models.CreateCampaignInput{Name: "Owner campaign"})
+		campaignId := response.Data.(map[string]interface{})["id"].(string)
+
+		recorder, response := sendRequest(t, http.MethodPost, "/api/short_url", ownerToken, models.CreateShortUrlInput{
+			OriginalUrl: "https://example.com/versioned",
+			WorkspaceID: &workspaceId,
+			FolderID:    &folderId,
+			CampaignID:  &campaignId,
+		})
+		require.Equal(t, http.StatusCreated, recorder.Code)
+		shortUrlId := response.Data.(map[string]interface{})["id"].(string)
+
+		originalUrl := "https://example.com/versioned/v2"
+		recorder, _ = sendRequest(t, http.MethodPatch, "/api/short_url/"+shortUrlId, ownerToken, models.UpdateShortUrlInput{OriginalUrl: &originalUrl})
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/short_url/"+shortUrlId+"/transfer", ownerToken, models.TransferShortUrlInput{UserEmail: &memberEmail})
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		recorder, response = sendRequest(t, http.MethodPost, "/api/short_url/"+shortUrlId+"/versions/1/restore", memberToken, nil)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		shortUrl := response.Data.(map[string]interface{})
+		assert.Equal(t, "https://example.com/versioned", shortUrl["original_url"])
+		assert.NotContains(t, shortUrl, "folder_id")
+		assert.NotContains(t, shortUrl, "campaign_id")
+	})
+
+	t.Run("Invitation only works for the invited email", func(t *testing.T) {
+		ownerToken, _ := setupVerifiedUser(t)
+		workspaceId := setupTestWorkspace(t, ownerToken)
+		_, inviteeEmail := setupVerifiedUser(t)
+		otherToken, _ := setupVerifiedUser(t)
+
+		recorder, _ := sendRequest(t, http.MethodPost, "/api/workspaces/"+workspaceId+"/invitations", ownerToken, models.InviteMemberInput{
+			Email: inviteeEmail,
+			Role:  models.WorkspaceRoleViewer,
+		})
+		require.Equal(t, http.StatusCreated, recorder.Code)
+
+		invitationToken, ok := test.LastEmailToken(inviteeEmail)
+		require.True(t, ok, "Invitation email should have been sent")
+
+		recorder, _ = sendRequest(t, http.MethodPost, "/api/workspaces/invitations/accept", otherToken, models.AcceptInvitationInput{Token: invitationToken})
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+	})
+}
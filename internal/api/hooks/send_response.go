@@ -3,6 +3,7 @@ package hooks
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 
@@ -31,6 +32,29 @@ func SendResponse(w http.ResponseWriter, status int, data interface{}, err error
 	WriteJSON(w, errorStatus, response)
 }
 
+// SendPageResponse sends a page of a cursor paginated list, the pagination
+// goes in the envelope and the next page is also linked in the Link header
+func SendPageResponse(w http.ResponseWriter, r *http.Request, status int, data interface{}, pagination *models.Pagination, err error) {
+	if err != nil {
+		SendResponse(w, status, nil, err)
+		return
+	}
+
+	if pagination != nil && pagination.HasMore {
+		next := *r.URL
+		query := next.Query()
+		query.Set("cursor", pagination.NextCursor)
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	}
+
+	WriteJSON(w, status, models.Response{
+		Success:    true,
+		Data:       data,
+		Pagination: pagination,
+	})
+}
+
 func processError(err error) *models.ErrorData {
 	errorData := &models.ErrorData{
 		Message: "Unexpected error ocurred",
@@ -42,6 +66,13 @@ func processError(err error) *models.ErrorData {
 		if appErr.Err != nil {
 			errorData.Details = appErr.Err.Error()
 		}
+		for _, field := range appErr.Fields {
+			errorData.Errors = append(errorData.Errors, models.ValidationError{
+				Field: field.Field,
+				Tag:   field.Tag,
+				Value: field.Value,
+			})
+		}
 	} else if err != nil {
 		errorData.Details = err.Error()
 	}
@@ -62,6 +93,12 @@ func determineErrorStatus(err error) int {
 		return http.StatusBadRequest
 	case wraperrors.IsUnauthorizedError(err):
 		return http.StatusUnauthorized
+	case wraperrors.IsForbiddenError(err):
+		return http.StatusForbidden
+	case wraperrors.IsGoneError(err):
+		return http.StatusGone
+	case wraperrors.IsTooManyRequestsError(err):
+		return http.StatusTooManyRequests
 	case wraperrors.IsAlreadyExistsError(err) || wraperrors.IsUniqueViolation(err):
 		return http.StatusConflict
 	default:
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
 	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
 )
@@ -18,6 +19,11 @@ type contextKey string
 
 const userIDKey contextKey = "user_id"
 
+const (
+	challengePurpose = "totp_challenge"
+	challengeTTL     = 5 * time.Minute
+)
+
 func GenerateJWT(userId string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userId,
@@ -27,6 +33,65 @@ func GenerateJWT(userId string) (string, error) {
 	return token.SignedString(jwtSecret)
 }
 
+// ChallengeToken is a signed challenge with the ID (jti) the caller stores so
+// the challenge can be used only once
+type ChallengeToken struct {
+	Token     string
+	ID        string
+	ExpiresAt time.Time
+}
+
+// GenerateChallengeJWT issues a short-lived token that only proves the first login
+// step succeeded; it is rejected by JWTAuth and must be exchanged with a TOTP code
+func GenerateChallengeJWT(userId string) (*ChallengeToken, error) {
+	challenge := &ChallengeToken{
+		ID:        uuid.NewString(),
+		ExpiresAt: time.Now().Add(challengeTTL),
+	}
+	claims := jwt.MapClaims{
+		"user_id": userId,
+		"purpose": challengePurpose,
+		"jti":     challenge.ID,
+		"exp":     challenge.ExpiresAt.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	signed, err := token.SignedString(jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+	challenge.Token = signed
+
+	return challenge, nil
+}
+
+// ParseChallengeJWT returns the user and the ID of a valid challenge token
+func ParseChallengeJWT(tokenStr string) (string, string, error) {
+	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return "", "", wraperrors.UnauthorizedErr("Invalid or expired challenge token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != challengePurpose {
+		return "", "", wraperrors.UnauthorizedErr("Invalid challenge token")
+	}
+
+	userId, ok := claims["user_id"].(string)
+	if !ok {
+		return "", "", wraperrors.UnauthorizedErr("Invalid user ID in token")
+	}
+
+	jti, ok := claims["jti"].(string)
+	if !ok || jti == "" {
+		return "", "", wraperrors.UnauthorizedErr("Invalid challenge token")
+	}
+
+	return userId, jti, nil
+}
+
 func JWTAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
@@ -52,6 +117,11 @@ func JWTAuth(next http.Handler) http.Handler {
 			return
 		}
 
+		if _, isChallenge := claims["purpose"]; isChallenge {
+			hooks.SendResponse(w, http.StatusUnauthorized, nil, wraperrors.UnauthorizedErr("Invalid token"))
+			return
+		}
+
 		userId, ok := claims["user_id"].(string)
 		if !ok {
 			hooks.SendResponse(w, http.StatusUnauthorized, nil, wraperrors.UnauthorizedErr("Invalid user ID in tokne"))
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// TrustedProxiesFromEnv reads TRUSTED_PROXIES, a comma separated list of
+// CIDRs or addresses of the reverse proxies in front of the API. Invalid
+// entries are logged and left out
+func TrustedProxiesFromEnv() []netip.Prefix {
+	var proxies []netip.Prefix
+	for _, raw := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(raw)
+			if addrErr != nil {
+				slog.Error("invalid trusted proxy, it is ignored", "proxy", raw)
+				continue
+			}
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+
+	return proxies
+}
+
+// TrustedRealIP sets RemoteAddr to the client address given by X-Forwarded-For
+// or X-Real-IP, only for requests sent by one of the trusted proxies. Anyone
+// else could put any address in those headers, so their own address is kept
+func TrustedRealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if remote, ok := remoteAddr(r); ok && isTrustedProxy(trusted, remote) {
+				if client, ok := forwardedClient(trusted, r); ok {
+					r.RemoteAddr = client.String()
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedClient walks X-Forwarded-For from the closest hop and stops at the
+// first address that is not a trusted proxy, the hops before it may be forged
+func forwardedClient(trusted []netip.Prefix, r *http.Request) (netip.Addr, bool) {
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+
+		var client netip.Addr
+		for i := len(hops) - 1; i >= 0; i-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				break
+			}
+			client = addr.Unmap()
+			if !isTrustedProxy(trusted, client) {
+				break
+			}
+		}
+		return client, client.IsValid()
+	}
+
+	addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP")))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func remoteAddr(r *http.Request) (netip.Addr, bool) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+func isTrustedProxy(trusted []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
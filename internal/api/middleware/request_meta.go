@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+// RequestMeta stores the request ID and client IP in the context so services
+// can attach them to audit entries. It must run after chi's RequestID and TrustedRealIP
+func RequestMeta(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := models.WithRequestMeta(r.Context(), models.RequestMeta{
+			RequestID: chimiddleware.GetReqID(r.Context()),
+			ClientIP:  clientIP(r),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// =============================================================================
+// Account Handlers
+// =============================================================================
+
+// handleVerifyEmail confirms the email address using the token sent by email
+//
+//		@Summary		Verify email
+//		@Description	Confirms the user's email address with the single-use token sent by email
+//	 @Tags 			users
+//		@Produce		json
+//		@Param			token	query		string			true	"Verification token"
+//		@Success		200		{object}	models.Response	"Verified user"
+//		@Failure		400		{object}	models.Response	"Token does not match the current email"
+//		@Failure		401		{object}	models.Response	"Invalid or expired token"
+//		@Failure		500		{object}	models.Response	"Internal server error"
+//		@Router			/api/users/verify_email [get]
+func (h apiHandler) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		hooks.SendResponse(w, http.StatusBadRequest, nil, wraperrors.ValidationErr("Missing token"))
+		return
+	}
+
+	user, err := h.user.VerifyEmail(r.Context(), token)
+	hooks.SendResponse(w, http.StatusOK, user, err)
+}
+
+// handleResendVerification sends a new verification email to the authenticated user
+//
+//		@Summary		Resend verification email
+//		@Description	Invalidates pending verification tokens and sends a new one
+//	 @Tags 			users
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Success		202	{object}	models.Response	"Verification email sent"
+//		@Failure		400	{object}	models.Response	"Email already verified"
+//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/api/users/verify_email/resend [post]
+func (h apiHandler) handleResendVerification(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	err = h.user.ResendVerification(r.Context(), userId)
+	hooks.SendResponse(w, http.StatusAccepted, nil, err)
+}
+
+// handleForgotPassword sends a password reset email
+//
+//		@Summary		Request password reset
+//		@Description	Sends a password reset token by email. The response is the same whether the email exists or not
+//	 @Tags 			users
+//		@Accept			json
+//		@Produce		json
+//		@Param			email	body		models.ForgotPasswordInput	true	"User email"
+//		@Success		202		{object}	models.Response				"Reset email sent if the account exists"
+//		@Failure		400		{object}	models.Response				"Invalid input data"
+//		@Router			/api/users/password/forgot [post]
+func (h apiHandler) handleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	body, ok := utils.ParseAndValidate[models.ForgotPasswordInput](w, r)
+	if !ok {
+		return
+	}
+
+	err := h.user.RequestPasswordReset(r.Context(), body.Email)
+	hooks.SendResponse(w, http.StatusAccepted, nil, err)
+}
+
+// handleResetPassword sets a new password using a reset token
+//
+//		@Summary		Reset password
+//		@Description	Sets a new password using the single-use token sent by email
+//	 @Tags 			users
+//		@Accept			json
+//		@Produce		json
+//		@Param			reset	body		models.ResetPasswordInput	true	"Reset token and new password"
+//		@Success		204		{object}	models.Response				"Password updated"
+//		@Failure		400		{object}	models.Response				"Invalid input data"
+//		@Failure		401		{object}	models.Response				"Invalid or expired token"
+//		@Failure		500		{object}	models.Response				"Internal server error"
+//		@Router			/api/users/password/reset [post]
+func (h apiHandler) handleResetPassword(w http.ResponseWriter, r *http.Request) {
+	body, ok := utils.ParseAndValidate[models.ResetPasswordInput](w, r)
+	if !ok {
+		return
+	}
+
+	err := h.user.ResetPassword(r.Context(), body)
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// =============================================================================
+// Audit Log Handlers
+// =============================================================================
+
+// handleListUserAuditLogs returns the audit trail of the authenticated user
+//
+//		@Summary		List user audit logs
+//		@Description	Returns the changes made by the authenticated user and to their account, newest first
+//	 @Tags 			audit
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			limit	query		int				false	"Page size (default 20, max 100)"
+//		@Param			offset	query		int				false	"Number of entries to skip"
+//		@Success		200		{object}	models.Response	"Page of audit log entries"
+//		@Failure		400		{object}	models.Response	"Invalid pagination parameters"
+//		@Failure		401		{object}	models.Response	"Invalid user ID in token"
+//		@Failure		500		{object}	models.Response	"Internal server error"
+//		@Router			/api/users/audit [get]
+func (h apiHandler) handleListUserAuditLogs(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	page, err := parsePageInput(r)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusBadRequest, nil, err)
+		return
+	}
+
+	logs, err := h.audit.ListUserAuditLogs(r.Context(), userId, page)
+	hooks.SendResponse(w, http.StatusOK, logs, err)
+}
+
+// handleListShortUrlAuditLogs returns the audit trail of a short URL
+//
+//		@Summary		List short URL audit logs
+//		@Description	Returns the changes made to a short URL, newest first. Available to anyone who can read the link, also after it was deleted
+//	 @Tags 			audit
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string			true	"Short URL ID"
+//		@Param			limit			query		int				false	"Page size (default 20, max 100)"
+//		@Param			offset			query		int				false	"Number of entries to skip"
+//		@Success		200				{object}	models.Response	"Page of audit log entries"
+//		@Failure		400				{object}	models.Response	"Invalid short URL ID or pagination parameters"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404				{object}	models.Response	"Short URL not found"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/audit [get]
+func (h apiHandler) handleListShortUrlAuditLogs(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	page, err := parsePageInput(r)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusBadRequest, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+
+	logs, err := h.audit.ListShortUrlAuditLogs(r.Context(), userId, shortUrlId, page)
+	hooks.SendResponse(w, http.StatusOK, logs, err)
+}
+
+// parsePageInput reads the optional limit and offset query parameters
+func parsePageInput(r *http.Request) (models.PageInput, error) {
+	var page models.PageInput
+	query := r.URL.Query()
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return page, wraperrors.ValidationErr("Invalid limit parameter")
+		}
+		page.Limit = limit
+	}
+
+	if raw := query.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil {
+			return page, wraperrors.ValidationErr("Invalid offset parameter")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const (
+	// maxBulkUploadSize bounds the body of a bulk upload, well above what
+	// MaxBulkRows ordinary rows take
+	maxBulkUploadSize = 64 << 20
+	// bulkUploadField is the multipart form field carrying the CSV file
+	bulkUploadField = "file"
+)
+
+// =============================================================================
+// Bulk Handlers
+// =============================================================================
+
+// handleBulkCreateShortUrls creates many short URLs from one upload
+//
+//		@Summary		Create short URLs in bulk
+//		@Description	Creates up to 100000 short URLs from a JSON array of links, a text/csv body or a CSV file sent as the file field of a multipart form. CSV columns are named like the JSON fields and original_url is required, tag_ids are separated by semicolons and variants can't be given. Rows are numbered from 1 without the CSV header and each one is checked like a single creation. Up to 1000 rows are created right away and every row is answered with its link or its errors. Larger uploads, or any upload with async=true, run as a job answered with 202, poll it at the Location header and download its results as CSV
+//	 @Tags 			short urls
+//		@Accept			json
+//		@Accept			text/csv
+//		@Accept			mpfd
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			async	query		bool						false	"Run the upload as a job whatever its size"
+//		@Param			rows	body		[]models.CreateShortUrlInput	true	"Links to create"
+//		@Success		200		{object}	models.Response				"Created links and refused rows"
+//		@Success		202		{object}	models.Response				"Bulk job queued"
+//		@Failure		400		{object}	models.Response				"Malformed upload, empty or over 100000 rows"
+//		@Failure		401		{object}	models.Response				"Invalid user ID in token"
+//		@Failure		403		{object}	models.Response				"Email address not verified"
+//		@Failure		500		{object}	models.Response				"Internal server error"
+//		@Router			/api/short_url/bulk [post]
+func (h apiHandler) handleBulkCreateShortUrls(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkUploadSize)
+	rows, err := decodeBulkUpload(r)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusBadRequest, nil, err)
+		return
+	}
+
+	if r.URL.Query().Get("async") != "true" && len(rows) <= models.BulkSyncLimit {
+		result, err := h.bulk.CreateShortUrls(r.Context(), userId, rows)
+		hooks.SendResponse(w, http.StatusOK, result, err)
+		return
+	}
+
+	job, err := h.bulk.CreateBulkJob(r.Context(), userId, rows)
+	if err == nil {
+		w.Header().Set("Location", "/api/short_url/bulk/"+job.ID)
+	}
+	hooks.SendResponse(w, http.StatusAccepted, job, err)
+}
+
+// handleGetBulkJob returns the progress of a bulk job
+//
+//		@Summary		Get bulk job
+//		@Description	Returns the status and row counts of a bulk job of the authenticated user
+//	 @Tags 			short urls
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			job_id	path		string			true	"Bulk job ID"
+//		@Success		200		{object}	models.Response	"Bulk job details"
+//		@Failure		401		{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404		{object}	models.Response	"Bulk job not found"
+//		@Failure		500		{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/bulk/{job_id} [get]
+func (h apiHandler) handleGetBulkJob(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	job, err := h.bulk.GetBulkJob(r.Context(), userId, chi.URLParam(r, "job_id"))
+	hooks.SendResponse(w, http.StatusOK, job, err)
+}
+
+// handleGetBulkJobResults downloads the results of a bulk job
+//
+//		@Summary		Download bulk job results
+//		@Description	Streams a CSV with the row, original_url, short_url_id, slug and errors of every row of the job, errors are written as field:tag separated by semicolons. Rows not processed yet have neither a link nor errors
+//	 @Tags 			short urls
+//		@Produce		text/csv
+//		@Security		BearerAuth
+//		@Param			job_id	path		string			true	"Bulk job ID"
+//		@Success		200		{file}		file			"Results CSV"
+//		@Failure		401		{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404		{object}	models.Response	"Bulk job not found"
+//		@Failure		500		{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/bulk/{job_id}/results [get]
+func (h apiHandler) handleGetBulkJobResults(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	job, err := h.bulk.GetBulkJob(r.Context(), userId, chi.URLParam(r, "job_id"))
+	if err != nil {
+		hooks.SendResponse(w, http.StatusOK, nil, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="bulk-%s-results.csv"`, job.ID))
+	w.WriteHeader(http.StatusOK)
+
+	// the status is sent, a failure past this point can only cut the file short
+	writer := csv.NewWriter(w)
+	writer.Write(models.BulkResultsCsvHeader)
+	err = h.bulk.ExportBulkJobResults(r.Context(), userId, job.ID, func(result *models.BulkRowResult) error {
+		return writer.Write(result.CsvRecord())
+	})
+	writer.Flush()
+	if err == nil {
+		err = writer.Error()
+	}
+	if err != nil {
+		slog.Error("failed to write bulk job results", "job_id", job.ID, "error", err)
+	}
+}
+
+// decodeBulkUpload reads the rows of a JSON array, a CSV body or a CSV file
+// in the file field of a multipart form, streaming the body
+func decodeBulkUpload(r *http.Request) ([]*models.BulkRowInput, error) {
+	return decodeUpload(r, models.DecodeBulkJson, models.DecodeBulkCsv)
+}
+
+// decodeUpload reads an upload with decodeCsv when it is a CSV body or a CSV
+// file in the file field of a multipart form, and with decodeJson otherwise
+func decodeUpload[T any](r *http.Request, decodeJson, decodeCsv func(io.Reader) (T, error)) (T, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var rows T
+	var err error
+	switch mediaType {
+	case "text/csv":
+		rows, err = decodeCsv(r.Body)
+	case "multipart/form-data":
+		rows, err = decodeMultipart(r, decodeCsv)
+	default:
+		rows, err = decodeJson(r.Body)
+	}
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return rows, wraperrors.ValidationErr(fmt.Sprintf("The upload is larger than %d bytes", maxBytesErr.Limit))
+		}
+		var appErr *wraperrors.AppError
+		if errors.As(err, &appErr) {
+			return rows, err
+		}
+		return rows, wraperrors.ValidationErr("Invalid upload: " + err.Error())
+	}
+
+	return rows, nil
+}
+
+func decodeMultipart[T any](r *http.Request, decodeCsv func(io.Reader) (T, error)) (T, error) {
+	var rows T
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return rows, err
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return rows, wraperrors.ValidationErr(fmt.Sprintf("The form has no %s field", bulkUploadField))
+		}
+		if err != nil {
+			return rows, err
+		}
+		if part.FormName() == bulkUploadField {
+			return decodeCsv(part)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+)
+
+// =============================================================================
+// Campaign Handlers
+// =============================================================================
+
+// handleListCampaigns returns the authenticated user's campaigns
+//
+//		@Summary		List campaigns
+//		@Description	Returns the campaigns of the authenticated user with the link count and clicks of each one
+//	 @Tags 			campaigns
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Success		200	{object}	models.Response	"List of campaigns"
+//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/api/campaigns [get]
+func (h apiHandler) handleListCampaigns(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	campaigns, err := h.campaign.ListCampaigns(r.Context(), userId)
+	hooks.SendResponse(w, http.StatusOK, campaigns, err)
+}
+
+// handleGetCampaign returns a campaign with its stats
+//
+//		@Summary		Get campaign details
+//		@Description	Returns a campaign, its UTM defaults and the link count and clicks of its links
+//	 @Tags 			campaigns
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			campaign_id	path		string			true	"Campaign ID"
+//		@Success		200			{object}	models.Response	"Campaign details"
+//		@Failure		400			{object}	models.Response	"Invalid campaign ID format"
+//		@Failure		401			{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response	"Campaign not found"
+//		@Failure		500			{object}	models.Response	"Internal server error"
+//		@Router			/api/campaigns/{campaign_id} [get]
+func (h apiHandler) handleGetCampaign(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	campaignId := chi.URLParam(r, "campaign_id")
+
+	campaign, err := h.campaign.GetCampaign(r.Context(), userId, campaignId)
+	hooks.SendResponse(w, http.StatusOK, campaign, err)
+}
+
+// handleCreateCampaign creates a campaign for the authenticated user
+//
+//		@Summary		Create campaign
+//		@Description	Creates a campaign whose UTM values are the defaults of the links tagged with it
+//	 @Tags 			campaigns
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			campaign	body		models.CreateCampaignInput	true	"Campaign information"
+//		@Success		201			{object}	models.Response				"Campaign created successfully"
+//		@Failure		400			{object}	models.Response				"Invalid input data"
+//		@Failure		401			{object}	models.Response				"Invalid user ID in token"
+//		@Failure		409			{object}	models.Response				"Campaign name already in use"
+//		@Failure		500			{object}	models.Response				"Internal server error"
+//		@Router			/api/campaigns [post]
+func (h apiHandler) handleCreateCampaign(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.CreateCampaignInput](w, r)
+	if !ok {
+		return
+	}
+
+	campaign, err := h.campaign.CreateCampaign(r.Context(), userId, body)
+	hooks.SendResponse(w, http.StatusCreated, campaign, err)
+}
+
+// handleUpdateCampaign replaces the name and UTM defaults of a campaign
+//
+//		@Summary		Update campaign
+//		@Description	Replaces the name and UTM defaults of a campaign. Links already tagged keep their destination
+//	 @Tags 			campaigns
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			campaign_id	path		string						true	"Campaign ID"
+//		@Param			campaign	body		models.UpdateCampaignInput	true	"Campaign information"
+//		@Success		200			{object}	models.Response				"Campaign updated successfully"
+//		@Failure		400			{object}	models.Response				"Invalid input data"
+//		@Failure		401			{object}	models.Response				"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response				"Campaign not found"
+//		@Failure		409			{object}	models.Response				"Campaign name already in use"
+//		@Failure		500			{object}	models.Response				"Internal server error"
+//		@Router			/api/campaigns/{campaign_id} [patch]
+func (h apiHandler) handleUpdateCampaign(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	campaignId := chi.URLParam(r, "campaign_id")
+	body, ok := utils.ParseAndValidate[models.UpdateCampaignInput](w, r)
+	if !ok {
+		return
+	}
+
+	campaign, err := h.campaign.UpdateCampaign(r.Context(), userId, campaignId, body)
+	hooks.SendResponse(w, http.StatusOK, campaign, err)
+}
+
+// handleDeleteCampaign deletes a campaign, its links are kept untagged
+//
+//		@Summary		Delete campaign
+//		@Description	Deletes a campaign. Its links keep working and are no longer tagged with it
+//	 @Tags 			campaigns
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			campaign_id	path		string			true	"Campaign ID"
+//		@Success		204			{object}	models.Response	"Campaign deleted with success"
+//		@Failure		400			{object}	models.Response	"Invalid campaign ID format"
+//		@Failure		401			{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response	"Campaign not found"
+//		@Failure		500			{object}	models.Response	"Internal server error"
+//		@Router			/api/campaigns/{campaign_id} [delete]
+func (h apiHandler) handleDeleteCampaign(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	campaignId := chi.URLParam(r, "campaign_id")
+
+	err = h.campaign.DeleteCampaign(r.Context(), userId, campaignId)
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+)
+
+// =============================================================================
+// Domain Handlers
+// =============================================================================
+
+// handleListDomains returns the authenticated user's custom domains
+//
+//		@Summary		List custom domains
+//		@Description	Returns the custom domains of the authenticated user, verified or not, with the TXT record that verifies each one
+//	 @Tags 			domains
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Success		200	{object}	models.Response	"List of domains"
+//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/api/domains [get]
+func (h apiHandler) handleListDomains(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	domains, err := h.domain.ListDomains(r.Context(), userId)
+	hooks.SendResponse(w, http.StatusOK, domains, err)
+}
+
+// handleCreateDomain adds a custom domain for the authenticated user
+//
+//		@Summary		Add custom domain
+//		@Description	Adds a host to serve links from, up to 10 per user. The domain serves links once verified: create the TXT record verification_record with the value verification_value, point the host at this server and call the verify endpoint. not_found_url receives the visitors of a slug the domain does not have
+//	 @Tags 			domains
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			domain	body		models.CreateDomainInput	true	"Domain information"
+//		@Success		201		{object}	models.Response				"Domain added, pending verification"
+//		@Failure		400		{object}	models.Response				"Invalid host, not_found_url not allowed or domain limit reached"
+//		@Failure		401		{object}	models.Response				"Invalid user ID in token"
+//		@Failure		409		{object}	models.Response				"Domain already added"
+//		@Failure		500		{object}	models.Response				"Internal server error"
+//		@Router			/api/domains [post]
+func (h apiHandler) handleCreateDomain(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.CreateDomainInput](w, r)
+	if !ok {
+		return
+	}
+
+	domain, err := h.domain.CreateDomain(r.Context(), userId, body)
+	hooks.SendResponse(w, http.StatusCreated, domain, err)
+}
+
+// handleGetDomain returns one of the authenticated user's custom domains
+//
+//		@Summary		Get custom domain
+//		@Description	Returns a custom domain of the authenticated user
+//	 @Tags 			domains
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			domain_id	path		string			true	"Domain ID"
+//		@Success		200			{object}	models.Response	"Domain"
+//		@Failure		400			{object}	models.Response	"Invalid domain ID format"
+//		@Failure		401			{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response	"Domain not found"
+//		@Failure		500			{object}	models.Response	"Internal server error"
+//		@Router			/api/domains/{domain_id} [get]
+func (h apiHandler) handleGetDomain(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	domain, err := h.domain.GetDomain(r.Context(), userId, chi.URLParam(r, "domain_id"))
+	hooks.SendResponse(w, http.StatusOK, domain, err)
+}
+
+// handleUpdateDomain changes the 404 fallback of a custom domain
+//
+//		@Summary		Update custom domain
+//		@Description	Sets where the visitors of a slug the domain does not have are sent, an empty not_found_url removes the fallback and they get a 404
+//	 @Tags 			domains
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			domain_id	path		string						true	"Domain ID"
+//		@Param			domain		body		models.UpdateDomainInput	true	"Domain information"
+//		@Success		200			{object}	models.Response				"Domain updated successfully"
+//		@Failure		400			{object}	models.Response				"Invalid input data or not_found_url not allowed"
+//		@Failure		401			{object}	models.Response				"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response				"Domain not found"
+//		@Failure		500			{object}	models.Response				"Internal server error"
+//		@Router			/api/domains/{domain_id} [patch]
+func (h apiHandler) handleUpdateDomain(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.UpdateDomainInput](w, r)
+	if !ok {
+		return
+	}
+
+	domain, err := h.domain.UpdateDomain(r.Context(), userId, chi.URLParam(r, "domain_id"), body)
+	if err == nil {
+		h.cache.InvalidateDomain(r.Context(), domain.Host)
+	}
+	hooks.SendResponse(w, http.StatusOK, domain, err)
+}
+
+// handleVerifyDomain checks the verification TXT record of a custom domain
+//
+//		@Summary		Verify custom domain
+//		@Description	Looks up the TXT record verification_record and verifies the domain when it holds verification_value. A verified domain stays verified and a host can only be verified by one account
+//	 @Tags 			domains
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			domain_id	path		string			true	"Domain ID"
+//		@Success		200			{object}	models.Response	"Domain verified"
+//		@Failure		400			{object}	models.Response	"Verification TXT record not found"
+//		@Failure		401			{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response	"Domain not found"
+//		@Failure		409			{object}	models.Response	"Host verified by another account"
+//		@Failure		500			{object}	models.Response	"Internal server error"
+//		@Router			/api/domains/{domain_id}/verify [post]
+func (h apiHandler) handleVerifyDomain(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	domain, err := h.domain.VerifyDomain(r.Context(), userId, chi.URLParam(r, "domain_id"))
+	if err == nil {
+		// The host may be cached as not being a custom domain
+		h.cache.InvalidateDomain(r.Context(), domain.Host)
+	}
+	hooks.SendResponse(w, http.StatusOK, domain, err)
+}
+
+// handleDeleteDomain removes a custom domain without links
+//
+//		@Summary		Delete custom domain
+//		@Description	Removes a custom domain, refused while it still has live links. The deleted links of the domain are purged with it
+//	 @Tags 			domains
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			domain_id	path		string			true	"Domain ID"
+//		@Success		204			{object}	models.Response	"Domain deleted with success"
+//		@Failure		400			{object}	models.Response	"Invalid domain ID format or domain still has links"
+//		@Failure		401			{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response	"Domain not found"
+//		@Failure		500			{object}	models.Response	"Internal server error"
+//		@Router			/api/domains/{domain_id} [delete]
+func (h apiHandler) handleDeleteDomain(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	domainId := chi.URLParam(r, "domain_id")
+	domain, err := h.domain.GetDomain(r.Context(), userId, domainId)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusNoContent, nil, err)
+		return
+	}
+
+	err = h.domain.DeleteDomain(r.Context(), userId, domainId)
+	if err == nil {
+		h.cache.InvalidateDomain(r.Context(), domain.Host)
+	}
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
+
+// resolveLink returns the key of the requested slug among the links of the
+// host the request was sent to, with the custom domain of that host or nil
+// for the shortener host
+func (h apiHandler) resolveLink(r *http.Request, slug string) (string, *models.Domain, error) {
+	domain, err := h.resolveDomain(r)
+	if err != nil {
+		return "", nil, err
+	}
+	if domain == nil {
+		return slug, nil, nil
+	}
+
+	return models.LinkKey(&domain.ID, slug), domain, nil
+}
+
+// resolveDomain looks the request host up among the verified custom domains,
+// the answer is cached either way so most redirects don't query the database
+func (h apiHandler) resolveDomain(r *http.Request) (*models.Domain, error) {
+	host := models.RequestHost(r.Host)
+	if domain, found := h.cache.GetDomain(r.Context(), host); found {
+		return domain, nil
+	}
+
+	domain, err := h.domain.ResolveHost(r.Context(), host)
+	if err != nil {
+		return nil, err
+	}
+	h.cache.SetDomain(r.Context(), host, domain)
+
+	return domain, nil
+}
@@ -0,0 +1,242 @@
+package server
+
+import (
+	"errors"
+	"html/template"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+var fallbackPageTemplate = template.Must(template.New("fallback").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Status}} {{.Title}}</title>
+</head>
+<body>
+<main>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+</main>
+</body>
+</html>
+`))
+
+// fallbackPageData is what the 404 and 410 templates are rendered with
+type fallbackPageData struct {
+	Status  int
+	Title   string
+	Message string
+	Path    string
+}
+
+// fallbackPages answers the visits that don't lead to a link: the root and the
+// slugs that don't redirect. Browsers get an HTML page, API clients the JSON
+// envelope. rootUrl and notFoundUrl, when set, redirect the root and unknown
+// slugs instead
+type fallbackPages struct {
+	rootUrl     string
+	notFoundUrl string
+	notFound    *template.Template
+	gone        *template.Template
+}
+
+// loadFallbackPagesFromEnv reads FALLBACK_ROOT_URL, FALLBACK_NOT_FOUND_URL and
+// the html/template files FALLBACK_NOT_FOUND_TEMPLATE and
+// FALLBACK_GONE_TEMPLATE. Invalid values are logged and left out
+func loadFallbackPagesFromEnv() *fallbackPages {
+	return &fallbackPages{
+		rootUrl:     fallbackUrlFromEnv("FALLBACK_ROOT_URL"),
+		notFoundUrl: fallbackUrlFromEnv("FALLBACK_NOT_FOUND_URL"),
+		notFound:    fallbackTemplateFromEnv("FALLBACK_NOT_FOUND_TEMPLATE"),
+		gone:        fallbackTemplateFromEnv("FALLBACK_GONE_TEMPLATE"),
+	}
+}
+
+func fallbackUrlFromEnv(name string) string {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.ParseRequestURI(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		slog.Error("invalid fallback url, it is ignored", "variable", name, "url", raw)
+		return ""
+	}
+	return raw
+}
+
+func fallbackTemplateFromEnv(name string) *template.Template {
+	path := os.Getenv(name)
+	if path == "" {
+		return fallbackPageTemplate
+	}
+
+	page, err := template.ParseFiles(path)
+	if err != nil {
+		slog.Error("failed to load fallback page template, the default page is used", "variable", name, "path", path, "error", err)
+		return fallbackPageTemplate
+	}
+	return page
+}
+
+// handleRoot answers the root of the shortener and of the custom domains
+//
+//		@Summary		Root page
+//		@Description	Redirects to the not_found_url of the custom domain the request was sent to, or to FALLBACK_ROOT_URL on the shortener host, when set. Otherwise answers 404, with an HTML page when the Accept header prefers text/html over application/json
+//	 @Tags 			redirect
+//		@Produce		html
+//		@Produce		json
+//		@Success		302	{string}	string			"Redirect to the configured root URL"
+//		@Failure		404	{object}	models.Response	"No root page"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/ [get]
+func (h apiHandler) handleRoot(w http.ResponseWriter, r *http.Request) {
+	domain, err := h.resolveDomain(r)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	target := h.fallback.rootUrl
+	if domain != nil {
+		target = ""
+		if domain.NotFoundUrl != nil {
+			target = *domain.NotFoundUrl
+		}
+	}
+	if target != "" {
+		http.Redirect(w, r, target, http.StatusFound)
+		return
+	}
+
+	h.fallback.sendError(w, r, wraperrors.NotFoundErr("Nothing here"))
+}
+
+// handleNotFound answers the paths no route matches, without redirecting as
+// they can't be slugs
+func (h apiHandler) handleNotFound(w http.ResponseWriter, r *http.Request) {
+	h.fallback.sendError(w, r, wraperrors.NotFoundErr("Page not found"))
+}
+
+// sendLinkError answers a visit to a link that can't be followed. An unknown
+// slug goes to the not_found_url of the custom domain, or on the shortener
+// host to FALLBACK_NOT_FOUND_URL, when there is one
+func (h apiHandler) sendLinkError(w http.ResponseWriter, r *http.Request, domain *models.Domain, err error) {
+	if wraperrors.IsNotFoundError(err) {
+		target := h.fallback.notFoundUrl
+		if domain != nil {
+			target = ""
+			if domain.NotFoundUrl != nil {
+				target = *domain.NotFoundUrl
+			}
+		}
+		if target != "" {
+			http.Redirect(w, r, target, http.StatusFound)
+			return
+		}
+	}
+
+	h.fallback.sendError(w, r, err)
+}
+
+// sendError renders the 404 or 410 page for browsers, every other error and
+// every other client get the JSON envelope
+func (p *fallbackPages) sendError(w http.ResponseWriter, r *http.Request, err error) {
+	var status int
+	var page *template.Template
+	switch {
+	case wraperrors.IsNotFoundError(err):
+		status, page = http.StatusNotFound, p.notFound
+	case wraperrors.IsGoneError(err):
+		status, page = http.StatusGone, p.gone
+	}
+	if page == nil || !prefersHtml(r) {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	data := fallbackPageData{
+		Status:  status,
+		Title:   http.StatusText(status),
+		Message: fallbackMessage(err, status),
+		Path:    r.URL.Path,
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Vary", "Accept")
+	w.WriteHeader(status)
+
+	if err := page.Execute(w, data); err != nil {
+		slog.Error("failed to render fallback page", "status", status, "error", err)
+	}
+}
+
+// fallbackMessage is the message of the error, which tells a deleted link
+// from one that reached its click limit
+func fallbackMessage(err error, status int) string {
+	var appErr *wraperrors.AppError
+	if errors.As(err, &appErr) && appErr.Message != "" {
+		return appErr.Message
+	}
+	return http.StatusText(status)
+}
+
+// prefersHtml tells whether the Accept header ranks text/html above
+// application/json, clients that accept both equally get JSON
+func prefersHtml(r *http.Request) bool {
+	htmlQuality, jsonQuality := 0.0, 0.0
+	htmlSpecificity, jsonSpecificity := -1, -1
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if raw, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+
+		// the most specific range matching a type gives its quality
+		if specificity := mediaRangeSpecificity(mediaType, "text/html"); specificity > htmlSpecificity {
+			htmlSpecificity, htmlQuality = specificity, quality
+		}
+		if specificity := mediaRangeSpecificity(mediaType, "application/json"); specificity > jsonSpecificity {
+			jsonSpecificity, jsonQuality = specificity, quality
+		}
+	}
+
+	return htmlQuality > jsonQuality
+}
+
+// mediaRangeSpecificity is 2 for an exact match, 1 for type/* and 0 for */*,
+// -1 when the range does not match
+func mediaRangeSpecificity(mediaRange string, mediaType string) int {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	switch {
+	case mediaRange == mediaType:
+		return 2
+	case mediaRange == typ+"/*":
+		return 1
+	case mediaRange == "*/*":
+		return 0
+	default:
+		return -1
+	}
+}
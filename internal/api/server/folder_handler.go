@@ -0,0 +1,199 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+)
+
+// =============================================================================
+// Folder Handlers
+// =============================================================================
+
+// handleListFolders returns the authenticated user's folders
+//
+//		@Summary		List folders
+//		@Description	Returns all the folders of the authenticated user with how many live links each one holds directly. parent_id gives the hierarchy
+//	 @Tags 			folders
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Success		200	{object}	models.Response	"List of folders"
+//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/api/folders [get]
+func (h apiHandler) handleListFolders(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	folders, err := h.folder.ListFolders(r.Context(), userId)
+	hooks.SendResponse(w, http.StatusOK, folders, err)
+}
+
+// handleCreateFolder creates a folder for the authenticated user
+//
+//		@Summary		Create folder
+//		@Description	Creates a folder at the top level or inside parent_id, folders nest up to 8 levels and names are unique among siblings
+//	 @Tags 			folders
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			folder	body		models.CreateFolderInput	true	"Folder information"
+//		@Success		201		{object}	models.Response				"Folder created successfully"
+//		@Failure		400		{object}	models.Response				"Invalid input data or folders nested too deep"
+//		@Failure		401		{object}	models.Response				"Invalid user ID in token"
+//		@Failure		404		{object}	models.Response				"Parent folder not found"
+//		@Failure		409		{object}	models.Response				"Folder name already in use"
+//		@Failure		500		{object}	models.Response				"Internal server error"
+//		@Router			/api/folders [post]
+func (h apiHandler) handleCreateFolder(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.CreateFolderInput](w, r)
+	if !ok {
+		return
+	}
+
+	folder, err := h.folder.CreateFolder(r.Context(), userId, body)
+	hooks.SendResponse(w, http.StatusCreated, folder, err)
+}
+
+// handleUpdateFolder renames or moves a folder
+//
+//		@Summary		Rename or move folder
+//		@Description	Renames a folder or moves it with its content under parent_id, an empty parent_id moves it to the top level
+//	 @Tags 			folders
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			folder_id	path		string						true	"Folder ID"
+//		@Param			folder		body		models.UpdateFolderInput	true	"Folder information"
+//		@Success		200			{object}	models.Response				"Folder updated successfully"
+//		@Failure		400			{object}	models.Response				"Invalid input data, move into a subfolder or folders nested too deep"
+//		@Failure		401			{object}	models.Response				"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response				"Folder not found"
+//		@Failure		409			{object}	models.Response				"Folder name already in use"
+//		@Failure		500			{object}	models.Response				"Internal server error"
+//		@Router			/api/folders/{folder_id} [patch]
+func (h apiHandler) handleUpdateFolder(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	folderId := chi.URLParam(r, "folder_id")
+	body, ok := utils.ParseAndValidate[models.UpdateFolderInput](w, r)
+	if !ok {
+		return
+	}
+
+	folder, err := h.folder.UpdateFolder(r.Context(), userId, folderId, body)
+	hooks.SendResponse(w, http.StatusOK, folder, err)
+}
+
+// handleDeleteFolder deletes a folder, its content moves up to its parent
+//
+//		@Summary		Delete folder
+//		@Description	Deletes a folder. Its links and subfolders move up to its parent folder, no link is deleted
+//	 @Tags 			folders
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			folder_id	path		string			true	"Folder ID"
+//		@Success		204			{object}	models.Response	"Folder deleted with success"
+//		@Failure		400			{object}	models.Response	"Invalid folder ID format"
+//		@Failure		401			{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response	"Folder not found"
+//		@Failure		409			{object}	models.Response	"A subfolder name is already in use in the parent"
+//		@Failure		500			{object}	models.Response	"Internal server error"
+//		@Router			/api/folders/{folder_id} [delete]
+func (h apiHandler) handleDeleteFolder(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	folderId := chi.URLParam(r, "folder_id")
+
+	err = h.folder.DeleteFolder(r.Context(), userId, folderId)
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
+
+// handleMergeFolder merges a folder into another one
+//
+//		@Summary		Merge folder
+//		@Description	Moves the links and subfolders of a folder into the target folder and deletes the folder. Returns the target folder
+//	 @Tags 			folders
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			folder_id	path		string					true	"Folder ID"
+//		@Param			merge		body		models.MergeFolderInput	true	"Folder to merge into"
+//		@Success		200			{object}	models.Response			"Folders merged successfully"
+//		@Failure		400			{object}	models.Response			"Invalid input data or merge into a subfolder"
+//		@Failure		401			{object}	models.Response			"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response			"Folder not found"
+//		@Failure		409			{object}	models.Response			"A subfolder name is already in use in the target"
+//		@Failure		500			{object}	models.Response			"Internal server error"
+//		@Router			/api/folders/{folder_id}/merge [post]
+func (h apiHandler) handleMergeFolder(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	folderId := chi.URLParam(r, "folder_id")
+	body, ok := utils.ParseAndValidate[models.MergeFolderInput](w, r)
+	if !ok {
+		return
+	}
+
+	folder, err := h.folder.MergeFolder(r.Context(), userId, folderId, body)
+	hooks.SendResponse(w, http.StatusOK, folder, err)
+}
+
+// handleAddFolderLinks moves several links into a folder at once
+//
+//		@Summary		Move links into folder
+//		@Description	Moves up to 500 links the user can edit into the folder, out of the folder they were in
+//	 @Tags 			folders
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			folder_id	path		string					true	"Folder ID"
+//		@Param			links		body		models.AssignLinksInput	true	"Links to move"
+//		@Success		200			{object}	models.Response			"Links moved successfully"
+//		@Failure		400			{object}	models.Response			"Invalid input data"
+//		@Failure		401			{object}	models.Response			"Invalid user ID in token"
+//		@Failure		403			{object}	models.Response			"Insufficient workspace role on one of the links"
+//		@Failure		404			{object}	models.Response			"Folder or short URL not found"
+//		@Failure		500			{object}	models.Response			"Internal server error"
+//		@Router			/api/folders/{folder_id}/links [post]
+func (h apiHandler) handleAddFolderLinks(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	folderId := chi.URLParam(r, "folder_id")
+	body, ok := utils.ParseAndValidate[models.AssignLinksInput](w, r)
+	if !ok {
+		return
+	}
+
+	folder, err := h.folder.AddFolderLinks(r.Context(), userId, folderId, body)
+	hooks.SendResponse(w, http.StatusOK, folder, err)
+}
@@ -1,31 +1,56 @@
 package server
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
 	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
 	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
 	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const (
+	// Sticky links remember the variant a visitor got in this cookie
+	linkVariantCookiePrefix = "link_variant_"
+	linkVariantCookieTTL    = 30 * 24 * time.Hour
+	// How long browsers may keep a permanent redirect, short enough for edits
+	// to the link to reach returning visitors
+	permanentRedirectMaxAge = 24 * time.Hour
 )
 
 // =============================================================================
 // User Handlers
 // =============================================================================
 
-// handleListUsers returns a list of all users
+// handleListUsers returns a page of users
 //
 //		@Summary		List all users
-//		@Description	Returns a list of all users in the system
+//		@Description	Returns a page of the users in the system ordered by creation date, newest first. Pass pagination.next_cursor, also linked in the Link header, as cursor to get the next page
 //	 @Tags 			users
 //		@Produce		json
-//		@Success		200	{object}	models.Response	"List of users"
-//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Param			limit	query		int				false	"Page size (default 50, max 200)"
+//		@Param			order	query		string			false	"Creation date order"	Enums(desc, asc)
+//		@Param			cursor	query		string			false	"Cursor of the next page"
+//		@Success		200		{object}	models.Response	"Page of users"
+//		@Failure		400		{object}	models.Response	"Invalid pagination parameters"
+//		@Failure		500		{object}	models.Response	"Internal server error"
 //		@Router			/api/users/all [get]
 func (h apiHandler) handleListUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.user.ListUsers(r.Context())
-	hooks.SendResponse(w, http.StatusOK, users, err)
+	page, err := parseCursorPageInput(r)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusBadRequest, nil, err)
+		return
+	}
+
+	users, pagination, err := h.user.ListUsers(r.Context(), page)
+	hooks.SendPageResponse(w, r, http.StatusOK, users, pagination, err)
 }
 
 // handleGetUser returns details of the authenticated user
@@ -51,16 +76,19 @@ func (h apiHandler) handleGetUser(w http.ResponseWriter, r *http.Request) {
 	hooks.SendResponse(w, http.StatusOK, user, err)
 }
 
-// handleGetUserByEmail authenticates a user by email and returns a JWT token
+// handleGetUserByEmail authenticates a user by email and password and returns a JWT token,
+// or a TOTP challenge token when the user has two-factor authentication enabled
 //
 //		@Summary		Login user
-//		@Description	Authenticates a user by email and returns a JWT token
+//		@Description	Authenticates a user by email and password and returns a JWT token, or a challenge token when TOTP is enabled. Accounts created without a password can't log in until they set one through the password reset email
 //	 @Tags 			users
 //		@Accept			json
 //		@Produce		json
-//		@Param			credentials	body		models.GetUserByEmailInput	true	"User email"
+//		@Param			credentials	body		models.GetUserByEmailInput	true	"User email and password"
 //		@Success		201			{object}	models.Response				"JWT token"
+//		@Success		202			{object}	models.Response				"TOTP challenge token"
 //		@Failure		400			{object}	models.Response				"Invalid input data"
+//		@Failure		401			{object}	models.Response				"Invalid email or password"
 //		@Failure		404			{object}	models.Response				"User not found"
 //		@Failure		500			{object}	models.Response				"Internal server error"
 //		@Router			/api/users/login [post]
@@ -70,12 +98,30 @@ func (h apiHandler) handleGetUserByEmail(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	user, err := h.user.GetUserByEmail(r.Context(), body.Email)
+	user, err := h.user.Login(r.Context(), body)
 	if err != nil {
 		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
 		return
 	}
 
+	if user.TotpEnabled {
+		challenge, err := middleware.GenerateChallengeJWT(user.ID)
+		if err == nil {
+			err = h.totpGuard.IssueChallenge(r.Context(), challenge.ID, user.ID, time.Until(challenge.ExpiresAt))
+		}
+		if err != nil {
+			hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+			return
+		}
+
+		hooks.SendResponse(w, http.StatusAccepted, models.LoginChallenge{
+			ChallengeToken: challenge.Token,
+			TotpRequired:   true,
+			ExpiresAt:      challenge.ExpiresAt,
+		}, nil)
+		return
+	}
+
 	token, err := middleware.GenerateJWT(user.ID)
 	hooks.SendResponse(w, http.StatusCreated, models.Token{JWT: token}, err)
 }
@@ -83,7 +129,7 @@ func (h apiHandler) handleGetUserByEmail(w http.ResponseWriter, r *http.Request)
 // handleCreateUser creates a new user
 //
 //		@Summary		Create a new user
-//		@Description	Creates a new user with the provided information
+//		@Description	Creates a new user with the provided information, the password is required
 //	 @Tags 			users
 //		@Accept			json
 //		@Produce		json
@@ -122,6 +168,7 @@ func (h apiHandler) handleCreateUser(w http.ResponseWriter, r *http.Request) {
 //		@Failure		400		{object}	models.Response			"Invalid input data"
 //		@Failure		401		{object}	models.Response			"Invalid user ID in token"
 //		@Failure		404		{object}	models.Response			"User not found"
+//		@Failure		409		{object}	models.Response			"Email already in use by another user"
 //		@Failure		500		{object}	models.Response			"Internal server error"
 //		@Router			/api/users [patch]
 func (h apiHandler) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
@@ -143,13 +190,14 @@ func (h apiHandler) handleUpdateUser(w http.ResponseWriter, r *http.Request) {
 // handleDeleteUser deletes the authenticated user
 //
 //		@Summary		Delete user
-//		@Description	Deletes the authenticated user's account
+//		@Description	Deletes the authenticated user's account and personal short URLs. The account can be restored within the restore window
 //	 @Tags 			users
 //		@Produce		json
 //		@Security		BearerAuth
 //		@Success		204	{object}	models.Response	"User deleted with success"
 //		@Failure		401	{object}	models.Response	"Invalid user ID in token"
 //		@Failure		404	{object}	models.Response	"User not found"
+//		@Failure		409	{object}	models.Response	"User is the only owner of a workspace or serves workspace links on a domain"
 //		@Failure		500	{object}	models.Response	"Internal server error"
 //		@Router			/api/users [delete]
 func (h apiHandler) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
@@ -159,24 +207,71 @@ func (h apiHandler) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.user.DeleteUser(r.Context(), userId)
+	keys, err := h.user.DeleteUser(r.Context(), userId)
+	// The deleted links would keep redirecting from the cache until its TTL
+	for _, key := range keys {
+		h.cache.Invalidate(r.Context(), key)
+		h.accessCount.ResetClickLimit(r.Context(), key)
+	}
 	hooks.SendResponse(w, http.StatusNoContent, nil, err)
 }
 
+// handleRestoreUser restores a deleted user account
+//
+//		@Summary		Restore user
+//		@Description	Restores a deleted account and the personal short URLs deleted with it, while still within the restore window
+//	 @Tags 			users
+//		@Accept			json
+//		@Produce		json
+//		@Param			credentials	body		models.GetUserByEmailInput	true	"User email and password"
+//		@Success		200			{object}	models.Response				"Restored user"
+//		@Failure		400			{object}	models.Response				"Invalid input data"
+//		@Failure		401			{object}	models.Response				"Invalid email or password"
+//		@Failure		404			{object}	models.Response				"Deleted user not found"
+//		@Failure		409			{object}	models.Response				"Email now belongs to another account"
+//		@Failure		410			{object}	models.Response				"Restore window has expired"
+//		@Failure		500			{object}	models.Response				"Internal server error"
+//		@Router			/api/users/restore [post]
+func (h apiHandler) handleRestoreUser(w http.ResponseWriter, r *http.Request) {
+	body, ok := utils.ParseAndValidate[models.GetUserByEmailInput](w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.user.RestoreUser(r.Context(), body)
+	hooks.SendResponse(w, http.StatusOK, user, err)
+}
+
 // =============================================================================
 // Short URL Handlers
 // =============================================================================
 
-// handleListShortUrlsByUser returns all short URLs created by the authenticated user
+// handleListShortUrlsByUser returns a page of the authenticated user's personal
+// short URLs, or of the short URLs of a workspace they belong to, filtered,
+// searched and sorted
 //
 //		@Summary		List user's short URLs
-//		@Description	Returns all short URLs created by the authenticated user
+//		@Description	Returns a page of the personal short URLs of the authenticated user, or of the short URLs of the given workspace. campaign_id, tag_id and folder_id keep only the links of that campaign, tag or folder, status keeps the active, expired (past expires_at or out of clicks) or scheduled links and created_after and created_before bound the creation date. q searches the slug, the destination and the page title. Pass pagination.next_cursor, also linked in the Link header, as cursor to get the next page with the same sort and order. The response also lists the user's tags and folders with how many of the personal or workspace links each one has
 //	 @Tags 			short urls
 //		@Produce		json
 //		@Security		BearerAuth
-//		@Success		200	{object}	models.Response	"List of short URLs"
-//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
-//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Param			workspace_id	query		string			false	"Workspace ID"
+//		@Param			campaign_id		query		string			false	"Campaign ID"
+//		@Param			tag_id			query		string			false	"Tag ID"
+//		@Param			folder_id		query		string			false	"Folder ID, only the links directly in it"
+//		@Param			status			query		string			false	"Link status"	Enums(active, expired, scheduled)
+//		@Param			created_after	query		string			false	"Created at or after, RFC3339 or YYYY-MM-DD"
+//		@Param			created_before	query		string			false	"Created before, RFC3339 or YYYY-MM-DD (the whole day included)"
+//		@Param			q				query		string			false	"Search over slug, destination and title"
+//		@Param			sort			query		string			false	"Sort key, links without expiry sort as expiring last"	Enums(created_at, clicks, expires_at)
+//		@Param			order			query		string			false	"Sort order"	Enums(desc, asc)
+//		@Param			limit			query		int				false	"Page size (default 50, max 200)"
+//		@Param			cursor			query		string			false	"Cursor of the next page"
+//		@Success		200				{object}	models.Response	"Page of short URLs with tag and folder counts"
+//		@Failure		400				{object}	models.Response	"Invalid filter, sort or pagination parameters"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404				{object}	models.Response	"Workspace, campaign, tag or folder not found"
+//		@Failure		500				{object}	models.Response	"Internal server error"
 //		@Router			/api/short_url [get]
 func (h apiHandler) handleListShortUrlsByUser(w http.ResponseWriter, r *http.Request) {
 	userId, err := middleware.GetUserIdFromContext(r.Context())
@@ -185,40 +280,80 @@ func (h apiHandler) handleListShortUrlsByUser(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	shortUrls, err := h.shortUrl.ListShortUrl(r.Context(), userId)
-	hooks.SendResponse(w, http.StatusOK, shortUrls, err)
+	page, err := parseCursorPageInput(r)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusBadRequest, nil, err)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := models.ShortUrlFilter{
+		WorkspaceID:   query.Get("workspace_id"),
+		CampaignID:    query.Get("campaign_id"),
+		TagID:         query.Get("tag_id"),
+		FolderID:      query.Get("folder_id"),
+		Status:        query.Get("status"),
+		CreatedAfter:  query.Get("created_after"),
+		CreatedBefore: query.Get("created_before"),
+		Search:        query.Get("q"),
+		Sort:          query.Get("sort"),
+		Page:          page,
+	}
+
+	list, pagination, err := h.shortUrl.ListShortUrl(r.Context(), userId, filter)
+	if list != nil {
+		for _, shortUrl := range list.ShortUrls {
+			h.fillRemainingClicks(r.Context(), shortUrl)
+		}
+	}
+	hooks.SendPageResponse(w, r, http.StatusOK, list, pagination, err)
 }
 
 // handleGetShortUrl returns details of a specific short URL
 //
 //		@Summary		Get short URL details
-//		@Description	Returns details of a specific short URL by ID
+//		@Description	Returns details of a specific short URL by ID, including the remaining clicks of click-limited links
 //	 @Tags 			short urls
 //		@Produce		json
+//		@Security		BearerAuth
 //		@Param			short_url_id	path		string			true	"Short URL ID"
 //		@Success		200				{object}	models.Response	"Short URL details"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
 //		@Failure		404				{object}	models.Response	"Short URL not found"
 //		@Failure		500				{object}	models.Response	"Internal server error"
 //		@Router			/api/short_url/{short_url_id} [get]
 func (h apiHandler) handleGetShortUrl(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
 	shortUrlId := chi.URLParam(r, "short_url_id")
 
-	shortUrl, err := h.shortUrl.GetShortUrl(r.Context(), shortUrlId)
+	shortUrl, err := h.shortUrl.GetShortUrl(r.Context(), userId, shortUrlId)
+	if err == nil {
+		h.fillRemainingClicks(r.Context(), shortUrl)
+	}
 	hooks.SendResponse(w, http.StatusOK, shortUrl, err)
 }
 
 // handleCreateShortUrl creates a new short URL
 //
 //		@Summary		Create short URL
-//		@Description	Creates a new short URL for the authenticated user
+//		@Description	Creates a new short URL for the authenticated user, or for a workspace where the user is at least an editor. Links with a password show a password form instead of redirecting. Destinations are screened against the domain allow and deny lists, the malicious URL blocklist, other shorteners and private addresses, refused ones are listed in error.errors with the reason as tag. folder_id and tag_ids organize the link with the user's folders and tags. With reuse_existing the oldest active link of the user, or of the workspace, leading to the same URL is returned with a 200 instead. URLs are compared with a lowercase scheme and host, without default port or fragment and with sorted query parameters, ignore_tracking_params also leaves out utm_* and click identifiers such as gclid or fbclid. domain_id puts the link on one of the user's verified custom domains, where slug may choose its slug among the slugs of that domain
 //	 @Tags 			short urls
 //		@Accept			json
 //		@Produce		json
 //		@Security		BearerAuth
 //		@Param			url	body		models.CreateShortUrlInput	true	"Original URL information"
+//		@Success		200	{object}	models.Response				"Existing short URL reused"
 //		@Success		201	{object}	models.Response				"Short URL created successfully"
-//		@Failure		400	{object}	models.Response				"Invalid input data or URL format"
+//		@Failure		400	{object}	models.Response				"Invalid input data, URL format or refused destination"
 //		@Failure		401	{object}	models.Response				"Invalid user ID in token"
+//		@Failure		403	{object}	models.Response				"Email address not verified or insufficient workspace role"
+//		@Failure		404	{object}	models.Response				"Workspace or domain not found"
+//		@Failure		409	{object}	models.Response				"Slug already taken on the domain"
 //		@Failure		500	{object}	models.Response				"Internal server error"
 //		@Router			/api/short_url [post]
 func (h apiHandler) handleCreateShortUrl(w http.ResponseWriter, r *http.Request) {
@@ -237,53 +372,160 @@ func (h apiHandler) handleCreateShortUrl(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	shortUrl, err := h.shortUrl.CreateShortUrl(r.Context(), userId, body)
-	hooks.SendResponse(w, http.StatusCreated, shortUrl, err)
+	shortUrl, reused, err := h.shortUrl.CreateShortUrl(r.Context(), userId, body)
+	status := http.StatusCreated
+	if reused {
+		status = http.StatusOK
+	}
+	hooks.SendResponse(w, status, shortUrl, err)
 }
 
 // handleUpdateShortUrl updates a specific short URL
 //
 //		@Summary		Update short URL
-//		@Description	Updates a specific short URL by ID
+//		@Description	Updates a specific short URL by ID. An empty password removes the link password, an empty folder_id takes the link out of its folder and tag_ids replaces the tags the user put on it
 //	 @Tags 			short urls
 //		@Accept			json
 //		@Produce		json
+//		@Security		BearerAuth
 //		@Param			short_url_id	path		string						true	"Short URL ID"
 //		@Param			url				body		models.UpdateShortUrlInput	true	"Updated URL information"
 //		@Success		200				{object}	models.Response				"Short URL updated successfully"
-//		@Failure		400				{object}	models.Response				"Invalid input data"
+//		@Failure		400				{object}	models.Response				"Invalid input data or refused destination"
+//		@Failure		401				{object}	models.Response				"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response				"Insufficient workspace role"
 //		@Failure		404				{object}	models.Response				"Short URL not found"
 //		@Failure		500				{object}	models.Response				"Internal server error"
 //		@Router			/api/short_url/{short_url_id} [patch]
 func (h apiHandler) handleUpdateShortUrl(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
 	shortUrlId := chi.URLParam(r, "short_url_id")
 	body, ok := utils.ParseAndValidate[models.UpdateShortUrlInput](w, r)
 	if !ok {
 		return
 	}
 
-	shortUrl, err := h.shortUrl.UpdateShortUrl(r.Context(), shortUrlId, body)
+	current, err := h.shortUrl.GetShortUrl(r.Context(), userId, shortUrlId)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	shortUrl, err := h.shortUrl.UpdateShortUrl(r.Context(), userId, shortUrlId, body)
+	if err == nil {
+		// The old slug may be cached with the previous destination, or
+		// without the password that was just set
+		h.cache.Invalidate(r.Context(), current.Key())
+		h.accessCount.ResetClickLimit(r.Context(), current.Key())
+	}
 	hooks.SendResponse(w, http.StatusOK, shortUrl, err)
 }
 
 // handleDeleteShortUrl deletes a specific short URL
 //
 //		@Summary		Delete short URL
-//		@Description	Deletes a specific short URL by ID
+//		@Description	Deletes a specific short URL by ID. The link can be restored within the restore window
 //	 @Tags 			short urls
 //		@Produce		json
+//		@Security		BearerAuth
 //		@Param			short_url_id	path		string			true	"Short URL ID"
 //		@Success		204				{object}	models.Response	"Short URL has deleted with success"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response	"Insufficient workspace role"
 //		@Failure		404				{object}	models.Response	"Short URL not found"
 //		@Failure		500				{object}	models.Response	"Internal server error"
 //		@Router			/api/short_url/{short_url_id} [delete]
 func (h apiHandler) handleDeleteShortUrl(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
 	shortUrlId := chi.URLParam(r, "short_url_id")
 
-	err := h.shortUrl.DeleteShortUrl(r.Context(), shortUrlId)
+	shortUrl, err := h.shortUrl.GetShortUrl(r.Context(), userId, shortUrlId)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	err = h.shortUrl.DeleteShortUrl(r.Context(), userId, shortUrlId)
+	if err == nil {
+		// A cached destination would keep redirecting until its TTL
+		h.cache.Invalidate(r.Context(), shortUrl.Key())
+	}
 	hooks.SendResponse(w, http.StatusNoContent, nil, err)
 }
 
+// handleRestoreShortUrl restores a deleted short URL
+//
+//		@Summary		Restore short URL
+//		@Description	Restores a deleted short URL while it is still within the restore window
+//	 @Tags 			short urls
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string			true	"Short URL ID"
+//		@Success		200				{object}	models.Response	"Short URL restored"
+//		@Failure		400				{object}	models.Response	"Invalid short URL ID format"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response	"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response	"Deleted short URL not found"
+//		@Failure		410				{object}	models.Response	"Restore window has expired"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/restore [post]
+func (h apiHandler) handleRestoreShortUrl(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+
+	shortUrl, err := h.shortUrl.RestoreShortUrl(r.Context(), userId, shortUrlId)
+	hooks.SendResponse(w, http.StatusOK, shortUrl, err)
+}
+
+// handleTransferShortUrl moves a short URL to a workspace or to another user
+//
+//		@Summary		Transfer short URL
+//		@Description	Moves a short URL to a workspace (editor role required there) or to personal links. Moving a link out of a workspace requires the owner role there, and a link goes to the personal links of another user only from a workspace that user is a member of
+//	 @Tags 			short urls
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string							true	"Short URL ID"
+//		@Param			target			body		models.TransferShortUrlInput	true	"Target workspace or user email"
+//		@Success		200				{object}	models.Response					"Short URL transferred"
+//		@Failure		400				{object}	models.Response					"Invalid input data or link on a custom domain"
+//		@Failure		401				{object}	models.Response					"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response					"Insufficient workspace role or target user outside the link's workspace"
+//		@Failure		404				{object}	models.Response					"Short URL, workspace or user not found"
+//		@Failure		500				{object}	models.Response					"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/transfer [post]
+func (h apiHandler) handleTransferShortUrl(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+	body, ok := utils.ParseAndValidate[models.TransferShortUrlInput](w, r)
+	if !ok {
+		return
+	}
+
+	shortUrl, err := h.shortUrl.TransferShortUrl(r.Context(), userId, shortUrlId, body)
+	hooks.SendResponse(w, http.StatusOK, shortUrl, err)
+}
+
 // =============================================================================
 // Redirect URL Handler
 // =============================================================================
@@ -291,32 +533,203 @@ func (h apiHandler) handleDeleteShortUrl(w http.ResponseWriter, r *http.Request)
 // handleRedirect redirects to the original URL
 //
 //		@Summary		Redirect to original URL
-//		@Description	Redirects to the original URL associated with the provided slug
+//		@Description	Redirects to the target of the first redirect rule matching the visitor, to a variant drawn by weight when the link splits its traffic or to the original URL associated with the provided slug, using the link's redirect status and forwarding the query string when enabled. Scheduled links show their inactive fallback (404, holding page redirect or countdown page) until starts_at. preview=1 shows the preview page instead and links with a forced interstitial show it until the visitor continues. On a verified custom domain, recognized by the Host header, the slug is looked up among the links of that domain and a slug it does not have redirects to its not_found_url when set. On the shortener host an unknown slug redirects to FALLBACK_NOT_FOUND_URL when set. Browsers, whose Accept header prefers text/html over application/json, get the 404 and 410 errors as an HTML page
 //	 @Tags 			redirect
-//		@Param			slug	path		string				true	"Short URL slug"
-//		@Success		302		{string}	string				"Redirect to original URL"
-//		@Failure		404		{object}	models.Response	"Short URL not found"
-//		@Failure		500		{object}	models.Response	"Internal server error"
+//		@Param			slug		path		string				true	"Short URL slug"
+//		@Param			preview		query		string				false	"1 to show the preview page"
+//		@Param			continue	query		string				false	"Set by the preview page to go past a forced interstitial"
+//		@Success		200			{string}	string				"Password form for protected links, countdown page for scheduled links or preview page"
+//		@Success		302			{string}	string				"Redirect to the matching rule target, a variant or the original URL, with the link's status (301, 302, 307 or 308), or to the not_found_url of the custom domain or FALLBACK_NOT_FOUND_URL"
+//		@Failure		404			{object}	models.Response	"Short URL not found or not active yet"
+//		@Failure		410			{object}	models.Response	"Short URL has been deleted or reached its click limit"
+//		@Failure		500			{object}	models.Response	"Internal server error"
 //		@Router			/{slug} [get]
 func (h apiHandler) handleRedirect(w http.ResponseWriter, r *http.Request) {
 	slug := chi.URLParam(r, "slug")
 
-	url, err := h.cache.GetURL(r.Context(), slug)
-	if err == nil {
-		h.accessCount.IncrementAccess(r.Context(), slug)
-		http.Redirect(w, r, url, http.StatusFound)
+	query := r.URL.Query()
+	if models.IsPreviewRequest(query) {
+		h.handlePreview(w, r)
+		return
+	}
+	confirmed := models.IsConfirmedVisit(query)
+
+	key, domain, err := h.resolveLink(r, slug)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	cached, err := h.cache.GetRedirect(r.Context(), key)
+	if err == nil && (!cached.Interstitial || confirmed) {
+		if err := h.registerClick(r.Context(), key, nil); err != nil {
+			h.sendLinkError(w, r, domain, err)
+			return
+		}
+		h.registerScan(r, key)
+		h.followRedirect(w, r, slug, cached, cached.Status())
 		return
 	}
 
 	// Not found in cache, fetch from database
-	shortUrl, err := h.shortUrl.GetShortUrlBySlug(r.Context(), slug)
+	shortUrl, err := h.shortUrl.GetShortUrlBySlug(r.Context(), key)
+	if err != nil {
+		h.sendLinkError(w, r, domain, err)
+		return
+	}
+
+	if shortUrl.NotYetActive(time.Now()) {
+		h.serveInactiveShortUrl(w, r, shortUrl)
+		return
+	}
+
+	if shortUrl.HasPassword {
+		h.serveProtectedRedirect(w, r, shortUrl)
+		return
+	}
+
+	if shortUrl.Interstitial && !confirmed {
+		h.servePreview(w, r, shortUrl)
+		return
+	}
+
+	redirect, err := h.loadRedirect(r.Context(), shortUrl)
 	if err != nil {
 		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
 		return
 	}
 
-	// Cache the result and update access statistics
-	h.cache.LogRecentAccess(r.Context(), shortUrl)
-	h.accessCount.IncrementAccess(r.Context(), slug)
-	http.Redirect(w, r, shortUrl.OriginalUrl, http.StatusFound)
+	// Update access statistics and cache the result
+	if err := h.registerClick(r.Context(), key, shortUrl); err != nil {
+		h.sendLinkError(w, r, domain, err)
+		return
+	}
+	h.registerScan(r, key)
+	h.cache.LogRecentAccess(r.Context(), shortUrl, redirect)
+	h.followRedirect(w, r, slug, redirect, redirect.Status())
+}
+
+// loadRedirect pairs a link with its redirect rules and variants
+func (h apiHandler) loadRedirect(ctx context.Context, shortUrl *models.ShortUrl) (*models.CachedRedirect, error) {
+	rules, err := h.redirectRule.GetRedirectRules(ctx, shortUrl.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CachedRedirect{
+		OriginalUrl:    shortUrl.OriginalUrl,
+		Rules:          rules,
+		Variants:       shortUrl.Variants,
+		StickyVariants: shortUrl.StickyVariants,
+		RedirectStatus: shortUrl.RedirectStatus,
+		ForwardQuery:   shortUrl.ForwardQuery,
+		QueryMerge:     shortUrl.QueryMerge,
+		Interstitial:   shortUrl.Interstitial,
+		Stable: len(rules) == 0 && len(shortUrl.Variants) == 0 && !shortUrl.HasPassword &&
+			shortUrl.MaxClicks == nil && shortUrl.ExpiresAt == nil,
+	}, nil
+}
+
+// followRedirect sends the visitor to the resolved destination once the
+// click was registered. A variant gets its own click and, on sticky links,
+// is remembered in a cookie scoped to the slug. Permanent redirects are only
+// cacheable by browsers when every visit must end up in the same place
+func (h apiHandler) followRedirect(w http.ResponseWriter, r *http.Request, slug string, redirect *models.CachedRedirect, status int) {
+	target, variant := redirect.Resolve(h.newRedirectVisitor(r, slug))
+	// Markers set by this service never reach the destination
+	query := r.URL.Query()
+	query.Del(models.QrScanParam)
+	query.Del(models.ContinueParam)
+	target = redirect.Destination(target, query)
+
+	if status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect {
+		if redirect.Stable {
+			w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(permanentRedirectMaxAge.Seconds())))
+		} else {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+	}
+
+	if variant != nil {
+		h.accessCount.IncrementVariantAccess(r.Context(), variant.ID)
+
+		if redirect.StickyVariants {
+			http.SetCookie(w, &http.Cookie{
+				Name:     linkVariantCookiePrefix + slug,
+				Value:    variant.ID,
+				Path:     "/" + slug,
+				MaxAge:   int(linkVariantCookieTTL.Seconds()),
+				HttpOnly: true,
+				Secure:   r.TLS != nil,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+	}
+
+	http.Redirect(w, r, target, status)
+}
+
+// registerClick counts a redirect of the link with the given key, shortUrl is
+// nil on cache hits. A used up click-limited link is evicted from the cache
+// and reported as gone
+func (h apiHandler) registerClick(ctx context.Context, key string, shortUrl *models.ShortUrl) error {
+	var limit *infra.ClickLimit
+	if shortUrl != nil && shortUrl.MaxClicks != nil {
+		limit = &infra.ClickLimit{MaxClicks: *shortUrl.MaxClicks, Used: shortUrl.AccessCount}
+	}
+
+	err := h.accessCount.RegisterClick(ctx, key, limit)
+	if err == nil {
+		return nil
+	}
+
+	if wraperrors.IsGoneError(err) {
+		h.cache.Invalidate(ctx, key)
+		return err
+	}
+
+	// Access statistics are best effort, a click limit is not
+	if limit != nil {
+		return err
+	}
+	return nil
+}
+
+// registerScan counts a click that came from a QR code, like access
+// statistics it is best effort
+func (h apiHandler) registerScan(r *http.Request, key string) {
+	if models.IsQrScan(r.URL.Query()) {
+		h.accessCount.IncrementScanAccess(r.Context(), key)
+	}
+}
+
+// fillRemainingClicks replaces the remaining clicks computed from the database,
+// which lags behind until access counts are synced, with the live count
+func (h apiHandler) fillRemainingClicks(ctx context.Context, shortUrl *models.ShortUrl) {
+	if shortUrl.MaxClicks == nil {
+		return
+	}
+	if remaining, ok := h.accessCount.RemainingClicks(ctx, shortUrl.Key()); ok {
+		shortUrl.RemainingClicks = &remaining
+	}
+}
+
+// parseCursorPageInput reads the optional limit, order and cursor query
+// parameters of a cursor paginated list
+func parseCursorPageInput(r *http.Request) (models.CursorPageInput, error) {
+	query := r.URL.Query()
+	page := models.CursorPageInput{
+		Order:  query.Get("order"),
+		Cursor: query.Get("cursor"),
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return page, wraperrors.ValidationErr("Invalid limit parameter")
+		}
+		page.Limit = limit
+	}
+
+	return page, nil
 }
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+var countdownTemplate = template.Must(template.New("countdown").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Coming soon</title>
+</head>
+<body>
+<main>
+<h1>This link opens soon</h1>
+<p>Available from <time id="starts-at" datetime="{{.StartsAt}}">{{.StartsAt}}</time></p>
+<p id="countdown"></p>
+</main>
+<script>
+(function () {
+  var startsAt = new Date(document.getElementById("starts-at").getAttribute("datetime")).getTime();
+  var output = document.getElementById("countdown");
+  function tick() {
+    var left = Math.max(0, Math.floor((startsAt - Date.now()) / 1000));
+    if (left === 0) {
+      window.location.reload();
+      return;
+    }
+    var days = Math.floor(left / 86400), hours = Math.floor(left % 86400 / 3600);
+    var minutes = Math.floor(left % 3600 / 60), seconds = left % 60;
+    output.textContent = days + "d " + hours + "h " + minutes + "m " + seconds + "s";
+    setTimeout(tick, 1000);
+  }
+  tick();
+})();
+</script>
+</body>
+</html>
+`))
+
+type countdownData struct {
+	StartsAt string
+}
+
+// serveInactiveShortUrl answers for a link whose starts_at is still ahead with
+// the fallback chosen by its owner
+func (h apiHandler) serveInactiveShortUrl(w http.ResponseWriter, r *http.Request, shortUrl *models.ShortUrl) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	switch shortUrl.InactiveFallback {
+	case models.InactiveFallbackHoldingPage:
+		if shortUrl.InactiveUrl != nil {
+			http.Redirect(w, r, *shortUrl.InactiveUrl, http.StatusFound)
+			return
+		}
+	case models.InactiveFallbackCountdown:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		data := countdownData{StartsAt: shortUrl.StartsAt.UTC().Format(time.RFC3339)}
+		if err := countdownTemplate.Execute(w, data); err != nil {
+			slog.Error("failed to render countdown page", "slug", shortUrl.Slug, "error", err)
+		}
+		return
+	}
+
+	h.fallback.sendError(w, r, wraperrors.NotFoundErr("Short URL is not active yet"))
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+// =============================================================================
+// Portability Handlers
+// =============================================================================
+
+// handleImportShortUrls imports the links exported from another shortener
+//
+//		@Summary		Import links from another shortener
+//		@Description	Creates up to 100000 links from the CSV or JSON export of another shortener, sent as a text/csv body, a CSV file in the file field of a multipart form or a JSON body. JSON may be an array of links or an object keeping them under links, data, items or results. Columns and keys are recognized by name: the slug (slug, keyword, alias, slashtag, back_half) or a short link whose last path segment is the slug (short_url, short_link, bitlink, link), the destination (original_url, long_url, destination, url, target), the click total (clicks, total_clicks, engagements, hits, access_count), the creation date (created_at, created, date_created, timestamp, RFC3339, date or unix seconds) and tags (tags, labels, separated by commas, semicolons or bars). Links keep their slug, creation date and click total. A slug already taken is a conflict, the link is refused with on_conflict=skip and gets a generated slug with on_conflict=rename. Only the links refused or imported under another slug are listed
+//	 @Tags 			short urls
+//		@Accept			json
+//		@Accept			text/csv
+//		@Accept			mpfd
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			on_conflict	query		string			false	"What to do with a slug already taken (default skip)"	Enums(skip, rename)
+//		@Success		200			{object}	models.Response	"Import counts and the links not imported as they were"
+//		@Failure		400			{object}	models.Response	"Malformed upload, empty or over 100000 links"
+//		@Failure		401			{object}	models.Response	"Invalid user ID in token"
+//		@Failure		403			{object}	models.Response	"Email address not verified"
+//		@Failure		500			{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/import [post]
+func (h apiHandler) handleImportShortUrls(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkUploadSize)
+	links, err := decodeUpload(r, models.DecodeImportJson, models.DecodeImportCsv)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusBadRequest, nil, err)
+		return
+	}
+
+	result, err := h.portability.ImportShortUrls(r.Context(), userId, links, r.URL.Query().Get("on_conflict"))
+	hooks.SendResponse(w, http.StatusOK, result, err)
+}
+
+// handleExportAccount downloads all the data of the authenticated user
+//
+//		@Summary		Export account data
+//		@Description	Downloads a zip archive with profile.json, the live links with their tags as links.json and links.csv, the tags as tags.json and tags.csv and the clicks and QR scans of each link per UTC day as daily_stats.json and daily_stats.csv. links.csv can be imported back. Days are counted from when this server started keeping them, the click totals of imported links have no daily breakdown
+//	 @Tags 			users
+//		@Produce		application/zip
+//		@Security		BearerAuth
+//		@Success		200	{file}		file			"Account archive"
+//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404	{object}	models.Response	"User not found"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/api/users/export [get]
+func (h apiHandler) handleExportAccount(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	if _, err := h.user.GetUser(r.Context(), userId); err != nil {
+		hooks.SendResponse(w, http.StatusOK, nil, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%s.zip"`, time.Now().UTC().Format("20060102")))
+	w.WriteHeader(http.StatusOK)
+
+	// the status is sent, a failure past this point can only cut the archive short
+	if err := h.portability.ExportAccount(r.Context(), userId, w); err != nil {
+		slog.Error("failed to write account export", "user_id", userId, "error", err)
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+var previewTemplate = template.Must(template.New("preview").Funcs(template.FuncMap{
+	"date": func(t time.Time) string { return t.UTC().Format("January 2, 2006") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Link preview</title>
+</head>
+<body>
+<main>
+{{if .Warning}}<p role="alert"><strong>Check this link before continuing.</strong> The owner of this link asked us to show where it goes. Only continue if you trust {{.Domain}}.</p>{{end}}
+<h1>This link goes to {{.Domain}}</h1>
+<p><code>{{.Destination}}</code></p>
+{{if .Title}}<h2>{{.Title}}</h2>{{end}}
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<dl>
+<dt>Created</dt>
+<dd><time datetime="{{.CreatedAt.UTC.Format "2006-01-02T15:04:05Z07:00"}}">{{date .CreatedAt}}</time></dd>
+<dt>Clicks</dt>
+<dd>{{.Clicks}}</dd>
+</dl>
+<p><a href="{{.ContinueUrl}}" rel="noreferrer">Continue to {{.Domain}}</a></p>
+</main>
+</body>
+</html>
+`))
+
+// =============================================================================
+// Preview Handlers
+// =============================================================================
+
+// handlePreview shows where a short URL goes without following it
+//
+//		@Summary		Preview short URL
+//		@Description	Renders a page with the destination domain, its title and description when known, the creation date and the total clicks, with a continue button. Same as GET /{slug}?preview=1. Protected and not yet active links answer as they would on redirect
+//	 @Tags 			redirect
+//		@Produce		html
+//		@Param			slug	path		string			true	"Short URL slug"
+//		@Success		200		{string}	string			"Preview page"
+//		@Failure		404		{object}	models.Response	"Short URL not found"
+//		@Failure		410		{object}	models.Response	"Short URL has expired"
+//		@Router			/{slug}+ [get]
+func (h apiHandler) handlePreview(w http.ResponseWriter, r *http.Request) {
+	key, domain, err := h.resolveLink(r, chi.URLParam(r, "slug"))
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	shortUrl, err := h.shortUrl.GetShortUrlBySlug(r.Context(), key)
+	if err != nil {
+		h.sendLinkError(w, r, domain, err)
+		return
+	}
+
+	if shortUrl.NotYetActive(time.Now()) {
+		h.serveInactiveShortUrl(w, r, shortUrl)
+		return
+	}
+
+	// The destination of a protected link is only revealed after the password
+	if shortUrl.HasPassword {
+		h.serveProtectedRedirect(w, r, shortUrl)
+		return
+	}
+
+	h.servePreview(w, r, shortUrl)
+}
+
+// servePreview renders the preview page, also used as the interstitial of
+// links that force it. Showing it is not a click
+func (h apiHandler) servePreview(w http.ResponseWriter, r *http.Request, shortUrl *models.ShortUrl) {
+	clicks := shortUrl.AccessCount + h.accessCount.PendingAccess(r.Context(), shortUrl.Key())
+	preview := models.NewLinkPreview(shortUrl, clicks, continueURL(r, shortUrl.Slug))
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	if err := previewTemplate.Execute(w, preview); err != nil {
+		slog.Error("failed to render preview page", "slug", shortUrl.Slug, "error", err)
+	}
+}
+
+// continueURL points the continue button at the redirect, keeping the
+// visitor's query string for links that forward it
+func continueURL(r *http.Request, slug string) string {
+	query := r.URL.Query()
+	query.Del(models.PreviewParam)
+	query.Set(models.ContinueParam, "1")
+
+	return (&url.URL{Path: "/" + slug, RawQuery: query.Encode()}).String()
+}
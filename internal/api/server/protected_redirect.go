@@ -0,0 +1,163 @@
+package server
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const linkAccessCookiePrefix = "link_access_"
+
+var passwordFormTemplate = template.Must(template.New("password_form").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>Protected link</title>
+</head>
+<body>
+<main>
+<h1>This link is password protected</h1>
+{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+<form method="post" action="/{{.Slug}}">
+<label for="password">Password</label>
+<input id="password" name="password" type="password" autocomplete="current-password" required autofocus>
+<button type="submit">Continue</button>
+</form>
+</main>
+</body>
+</html>
+`))
+
+type passwordFormData struct {
+	Slug  string
+	Error string
+}
+
+// =============================================================================
+// Protected Redirect Handlers
+// =============================================================================
+
+// handleUnlockRedirect checks the password of a protected short URL
+//
+//		@Summary		Unlock password-protected short URL
+//		@Description	Checks the password submitted by the form served for protected links. On success sets a short-lived access cookie and redirects to the original URL. Attempts are throttled per slug and IP, 5 in 15 minutes, and per slug across all IPs, 20 in 15 minutes counting only the IPs not locked yet. Past the slug limit the form is locked for every visitor until the window ends
+//	 @Tags 			redirect
+//		@Accept			x-www-form-urlencoded
+//		@Produce		html
+//		@Param			slug		path		string	true	"Short URL slug"
+//		@Param			password	formData	string	true	"Link password"
+//		@Success		303			{string}	string	"Redirect to original URL"
+//		@Failure		400			{string}	string	"Short URL is not password protected"
+//		@Failure		401			{string}	string	"Invalid password"
+//		@Failure		404			{object}	models.Response	"Short URL not found"
+//		@Failure		410			{object}	models.Response	"Short URL has been deleted or reached its click limit"
+//		@Failure		429			{string}	string	"Too many password attempts"
+//		@Router			/{slug} [post]
+func (h apiHandler) handleUnlockRedirect(w http.ResponseWriter, r *http.Request) {
+	slug := chi.URLParam(r, "slug")
+	ip := models.RequestMetaFromContext(r.Context()).ClientIP
+
+	key, domain, err := h.resolveLink(r, slug)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	// The attempt is counted before the password is compared so parallel
+	// guesses can't get past the limit
+	retryAfter, err := h.passwordAttempts.Register(r.Context(), key, ip)
+	if err != nil {
+		if wraperrors.IsTooManyRequestsError(err) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+			renderPasswordForm(w, http.StatusTooManyRequests, slug, "Too many attempts, try again later.")
+			return
+		}
+		renderPasswordForm(w, http.StatusInternalServerError, slug, "Something went wrong, try again.")
+		return
+	}
+
+	password := r.PostFormValue("password")
+	access, err := h.shortUrl.UnlockShortUrl(r.Context(), key, password)
+	if err != nil {
+		switch {
+		case wraperrors.IsUnauthorizedError(err):
+			renderPasswordForm(w, http.StatusUnauthorized, slug, "Wrong password.")
+		case wraperrors.IsValidationError(err):
+			http.Redirect(w, r, "/"+slug, http.StatusSeeOther)
+		default:
+			h.sendLinkError(w, r, domain, err)
+		}
+		return
+	}
+	h.passwordAttempts.Reset(r.Context(), key, ip)
+
+	shortUrl, err := h.shortUrl.GetShortUrlBySlug(r.Context(), key)
+	if err != nil {
+		h.sendLinkError(w, r, domain, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     linkAccessCookiePrefix + slug,
+		Value:    access.Token,
+		Path:     "/" + slug,
+		Expires:  access.ExpiresAt,
+		MaxAge:   int(time.Until(access.ExpiresAt).Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	redirect, err := h.loadRedirect(r.Context(), shortUrl)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	if err := h.registerClick(r.Context(), key, shortUrl); err != nil {
+		h.fallback.sendError(w, r, err)
+		return
+	}
+	h.followRedirect(w, r, slug, redirect, http.StatusSeeOther)
+}
+
+// serveProtectedRedirect lets visitors holding a valid access cookie through
+// and shows the password form to everyone else
+func (h apiHandler) serveProtectedRedirect(w http.ResponseWriter, r *http.Request, shortUrl *models.ShortUrl) {
+	if cookie, err := r.Cookie(linkAccessCookiePrefix + shortUrl.Slug); err == nil {
+		if h.shortUrl.VerifyShortUrlAccess(r.Context(), shortUrl.Key(), cookie.Value) == nil {
+			redirect, err := h.loadRedirect(r.Context(), shortUrl)
+			if err != nil {
+				hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+				return
+			}
+			if err := h.registerClick(r.Context(), shortUrl.Key(), shortUrl); err != nil {
+				h.fallback.sendError(w, r, err)
+				return
+			}
+			h.followRedirect(w, r, shortUrl.Slug, redirect, redirect.Status())
+			return
+		}
+	}
+
+	renderPasswordForm(w, http.StatusOK, shortUrl.Slug, "")
+}
+
+func renderPasswordForm(w http.ResponseWriter, status int, slug string, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	if err := passwordFormTemplate.Execute(w, passwordFormData{Slug: slug, Error: message}); err != nil {
+		slog.Error("failed to render password form", "slug", slug, "error", err)
+	}
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/qrcode"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// =============================================================================
+// QR Code Handlers
+// =============================================================================
+
+// handleGetShortUrlQrCode renders the QR code of a short URL
+//
+//		@Summary		Get short URL QR code
+//		@Description	Renders a QR code for the short URL as PNG or SVG. The encoded URL carries the qr=1 marker so scans are counted apart from clicks
+//	 @Tags 			short urls
+//		@Produce		png
+//		@Produce		image/svg+xml
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string			true	"Short URL ID"
+//		@Param			format			query		string			false	"png (default) or svg"
+//		@Param			size			query		int				false	"Width and height in pixels, 64 to 2048 (default 256)"
+//		@Param			level			query		string			false	"Error correction level L, M (default), Q or H"
+//		@Param			margin			query		int				false	"Quiet zone in modules, 0 to 16 (default 4)"
+//		@Param			fg				query		string			false	"Foreground color as hex (default 000000)"
+//		@Param			bg				query		string			false	"Background color as hex (default ffffff)"
+//		@Param			logo			query		bool			false	"Draw the configured logo in the center, forces level H"
+//		@Success		200				{file}		binary			"QR code image"
+//		@Failure		400				{object}	models.Response	"Invalid QR code options"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404				{object}	models.Response	"Short URL not found"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/qr [get]
+func (h apiHandler) handleGetShortUrlQrCode(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+
+	shortUrl, err := h.shortUrl.GetShortUrl(r.Context(), userId, shortUrlId)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	h.writeQrCode(w, r, shortUrl)
+}
+
+// handlePublicQrCode renders the QR code of a live short URL by its slug
+//
+//		@Summary		Get public QR code
+//		@Description	Renders the QR code of a short URL that still redirects, with the same options as the authenticated endpoint
+//	 @Tags 			redirect
+//		@Produce		png
+//		@Produce		image/svg+xml
+//		@Param			slug	path		string			true	"Short URL slug"
+//		@Param			format	query		string			false	"png (default) or svg"
+//		@Param			size	query		int				false	"Width and height in pixels, 64 to 2048 (default 256)"
+//		@Param			level	query		string			false	"Error correction level L, M (default), Q or H"
+//		@Param			margin	query		int				false	"Quiet zone in modules, 0 to 16 (default 4)"
+//		@Param			fg		query		string			false	"Foreground color as hex (default 000000)"
+//		@Param			bg		query		string			false	"Background color as hex (default ffffff)"
+//		@Param			logo	query		bool			false	"Draw the configured logo in the center, forces level H"
+//		@Success		200		{file}		binary			"QR code image"
+//		@Failure		400		{object}	models.Response	"Invalid QR code options"
+//		@Failure		404		{object}	models.Response	"Short URL not found"
+//		@Failure		410		{object}	models.Response	"Short URL has expired"
+//		@Failure		500		{object}	models.Response	"Internal server error"
+//		@Router			/{slug}.qr [get]
+func (h apiHandler) handlePublicQrCode(w http.ResponseWriter, r *http.Request) {
+	key, _, err := h.resolveLink(r, chi.URLParam(r, "slug"))
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	shortUrl, err := h.shortUrl.GetShortUrlBySlug(r.Context(), key)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	h.writeQrCode(w, r, shortUrl)
+}
+
+// writeQrCode renders the scan-marked short URL with the options in the query
+func (h apiHandler) writeQrCode(w http.ResponseWriter, r *http.Request, shortUrl *models.ShortUrl) {
+	format, options, err := h.parseQrCodeOptions(r)
+	if err != nil {
+		w.Header().Del("Cache-Control")
+		hooks.SendResponse(w, http.StatusBadRequest, nil, err)
+		return
+	}
+
+	content := models.QrScanUrl(shortLinkURL(r, shortUrl))
+
+	var image []byte
+	contentType := "image/png"
+	if format == models.QrFormatSVG {
+		image, err = qrcode.SVG(content, options)
+		contentType = "image/svg+xml"
+	} else {
+		image, err = qrcode.PNG(content, options)
+	}
+	if err != nil {
+		w.Header().Del("Cache-Control")
+		hooks.SendResponse(w, http.StatusBadRequest, nil, wraperrors.ValidationErr(err.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(image)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(image)
+}
+
+func (h apiHandler) parseQrCodeOptions(r *http.Request) (string, qrcode.Options, error) {
+	query := r.URL.Query()
+	options := qrcode.DefaultOptions()
+
+	format := strings.ToLower(query.Get("format"))
+	switch format {
+	case "":
+		format = models.QrFormatPNG
+	case models.QrFormatPNG, models.QrFormatSVG:
+	default:
+		return "", options, wraperrors.ValidationErr("Invalid format, expected png or svg")
+	}
+
+	if raw := query.Get("size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil {
+			return "", options, wraperrors.ValidationErr("Invalid size")
+		}
+		options.Size = size
+	}
+	if raw := query.Get("margin"); raw != "" {
+		margin, err := strconv.Atoi(raw)
+		if err != nil {
+			return "", options, wraperrors.ValidationErr("Invalid margin")
+		}
+		options.Margin = margin
+	}
+	if raw := query.Get("level"); raw != "" {
+		level, err := qrcode.ParseLevel(raw)
+		if err != nil {
+			return "", options, wraperrors.ValidationErr("Invalid level, expected L, M, Q or H")
+		}
+		options.Level = level
+	}
+	if raw := query.Get("fg"); raw != "" {
+		fg, err := qrcode.ParseColor(raw)
+		if err != nil {
+			return "", options, wraperrors.ValidationErr("Invalid fg color")
+		}
+		options.Foreground = fg
+	}
+	if raw := query.Get("bg"); raw != "" {
+		bg, err := qrcode.ParseColor(raw)
+		if err != nil {
+			return "", options, wraperrors.ValidationErr("Invalid bg color")
+		}
+		options.Background = bg
+	}
+	if raw := query.Get("logo"); raw != "" {
+		logo, err := strconv.ParseBool(raw)
+		if err != nil {
+			return "", options, wraperrors.ValidationErr("Invalid logo")
+		}
+		if logo {
+			if h.qrLogo == nil {
+				return "", options, wraperrors.ValidationErr("QR code logo is not configured")
+			}
+			options.Logo = h.qrLogo
+		}
+	}
+
+	return format, options, nil
+}
+
+// shortLinkURL is the public URL of a link, on its custom domain when it has
+// one, APP_BASE_URL when configured and the requested host otherwise
+func shortLinkURL(r *http.Request, shortUrl *models.ShortUrl) string {
+	slug := shortUrl.Slug
+	if shortUrl.Domain != "" {
+		return "https://" + shortUrl.Domain + "/" + slug
+	}
+	if baseURL := os.Getenv("APP_BASE_URL"); baseURL != "" {
+		return strings.TrimRight(baseURL, "/") + "/" + slug
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/" + slug
+}
@@ -0,0 +1,231 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+	"github.com/mssola/useragent"
+)
+
+// =============================================================================
+// Redirect Rule Handlers
+// =============================================================================
+
+// handleListRedirectRules lists the conditional redirect rules of a short URL
+//
+//		@Summary		List redirect rules
+//		@Description	Returns the redirect rules of a short URL in evaluation order
+//	 @Tags 			redirect rules
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string			true	"Short URL ID"
+//		@Success		200				{object}	models.Response	"List of redirect rules"
+//		@Failure		400				{object}	models.Response	"Invalid short URL ID format"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404				{object}	models.Response	"Short URL not found"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/rules [get]
+func (h apiHandler) handleListRedirectRules(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+
+	rules, err := h.redirectRule.ListRedirectRules(r.Context(), userId, shortUrlId)
+	hooks.SendResponse(w, http.StatusOK, rules, err)
+}
+
+// handleCreateRedirectRule adds a conditional redirect rule to a short URL
+//
+//		@Summary		Create redirect rule
+//		@Description	Adds a rule sending visitors that match all of its conditions to target_url. Without a position the rule is evaluated last
+//	 @Tags 			redirect rules
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string							true	"Short URL ID"
+//		@Param			rule			body		models.CreateRedirectRuleInput	true	"Rule conditions and target"
+//		@Success		201				{object}	models.Response					"Redirect rule created"
+//		@Failure		400				{object}	models.Response					"Invalid conditions, target URL or too many rules"
+//		@Failure		401				{object}	models.Response					"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response					"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response					"Short URL not found"
+//		@Failure		500				{object}	models.Response					"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/rules [post]
+func (h apiHandler) handleCreateRedirectRule(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+	body, ok := utils.ParseAndValidate[models.CreateRedirectRuleInput](w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := h.redirectRule.CreateRedirectRule(r.Context(), userId, shortUrlId, body)
+	if err == nil {
+		h.invalidateShortUrl(r.Context(), userId, shortUrlId)
+	}
+	hooks.SendResponse(w, http.StatusCreated, rule, err)
+}
+
+// handleUpdateRedirectRule changes a redirect rule
+//
+//		@Summary		Update redirect rule
+//		@Description	Updates the position, conditions or target of a redirect rule, conditions are replaced as a whole
+//	 @Tags 			redirect rules
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string							true	"Short URL ID"
+//		@Param			rule_id			path		string							true	"Redirect rule ID"
+//		@Param			rule			body		models.UpdateRedirectRuleInput	true	"Fields to update"
+//		@Success		200				{object}	models.Response					"Redirect rule updated"
+//		@Failure		400				{object}	models.Response					"Invalid conditions or target URL"
+//		@Failure		401				{object}	models.Response					"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response					"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response					"Short URL or redirect rule not found"
+//		@Failure		500				{object}	models.Response					"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/rules/{rule_id} [patch]
+func (h apiHandler) handleUpdateRedirectRule(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+	ruleId := chi.URLParam(r, "rule_id")
+	body, ok := utils.ParseAndValidate[models.UpdateRedirectRuleInput](w, r)
+	if !ok {
+		return
+	}
+
+	rule, err := h.redirectRule.UpdateRedirectRule(r.Context(), userId, shortUrlId, ruleId, body)
+	if err == nil {
+		h.invalidateShortUrl(r.Context(), userId, shortUrlId)
+	}
+	hooks.SendResponse(w, http.StatusOK, rule, err)
+}
+
+// handleDeleteRedirectRule removes a redirect rule
+//
+//		@Summary		Delete redirect rule
+//		@Description	Removes a redirect rule from a short URL
+//	 @Tags 			redirect rules
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string			true	"Short URL ID"
+//		@Param			rule_id			path		string			true	"Redirect rule ID"
+//		@Success		204				{object}	models.Response	"Redirect rule deleted"
+//		@Failure		400				{object}	models.Response	"Invalid ID format"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response	"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response	"Short URL or redirect rule not found"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/rules/{rule_id} [delete]
+func (h apiHandler) handleDeleteRedirectRule(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+	ruleId := chi.URLParam(r, "rule_id")
+
+	err = h.redirectRule.DeleteRedirectRule(r.Context(), userId, shortUrlId, ruleId)
+	if err == nil {
+		h.invalidateShortUrl(r.Context(), userId, shortUrlId)
+	}
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
+
+// invalidateShortUrl drops the cached redirect of a link whose rules changed
+func (h apiHandler) invalidateShortUrl(ctx context.Context, userId string, shortUrlId string) {
+	if shortUrl, err := h.shortUrl.GetShortUrl(ctx, userId, shortUrlId); err == nil {
+		h.cache.Invalidate(ctx, shortUrl.Key())
+	}
+}
+
+// newRedirectVisitor collects what redirect rules and variants are
+// evaluated against
+func (h apiHandler) newRedirectVisitor(r *http.Request, slug string) *models.RedirectVisitor {
+	ua := useragent.New(r.UserAgent())
+
+	visitor := &models.RedirectVisitor{
+		OS:        visitorOS(ua),
+		Device:    visitorDevice(ua),
+		Languages: parseAcceptLanguage(r.Header.Get("Accept-Language")),
+		Country:   h.geoip.Country(models.RequestMetaFromContext(r.Context()).ClientIP),
+		Query:     r.URL.Query(),
+		Time:      time.Now(),
+	}
+	if cookie, err := r.Cookie(linkVariantCookiePrefix + slug); err == nil {
+		visitor.VariantID = cookie.Value
+	}
+
+	return visitor
+}
+
+func visitorOS(ua *useragent.UserAgent) string {
+	platform := strings.ToLower(ua.Platform())
+	osName := strings.ToLower(ua.OS())
+
+	switch {
+	case strings.Contains(osName, "android"):
+		return "android"
+	case platform == "iphone" || platform == "ipad" || platform == "ipod" || strings.Contains(osName, "iphone os") || strings.Contains(osName, "cpu os"):
+		return "ios"
+	case strings.Contains(osName, "windows"):
+		return "windows"
+	case strings.Contains(osName, "cros"):
+		return "chromeos"
+	case strings.Contains(osName, "mac os"):
+		return "macos"
+	case strings.Contains(osName, "linux"):
+		return "linux"
+	}
+	return ""
+}
+
+func visitorDevice(ua *useragent.UserAgent) string {
+	platform := strings.ToLower(ua.Platform())
+	raw := strings.ToLower(ua.UA())
+
+	switch {
+	case platform == "ipad" || strings.Contains(raw, "tablet") || (strings.Contains(raw, "android") && !strings.Contains(raw, "mobile")):
+		return models.RuleDeviceTablet
+	case ua.Mobile():
+		return models.RuleDeviceMobile
+	}
+	return models.RuleDeviceDesktop
+}
+
+// parseAcceptLanguage returns the accepted language tags in lower case,
+// ignoring quality values and the "*" wildcard
+func parseAcceptLanguage(header string) []string {
+	var languages []string
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" || strings.ReplaceAll(strings.TrimSpace(params), " ", "") == "q=0" {
+			continue
+		}
+		languages = append(languages, tag)
+	}
+	return languages
+}
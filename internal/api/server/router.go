@@ -9,7 +9,7 @@ import (
 )
 
 func (h *apiHandler) registerRoutes() {
-	h.r.Use(middleware.RequestID, middleware.Recoverer, middleware.Logger)
+	h.r.Use(middleware.RequestID, my_middleware.TrustedRealIP(my_middleware.TrustedProxiesFromEnv()), middleware.Recoverer, middleware.Logger, my_middleware.RequestMeta)
 
 	h.r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
@@ -26,7 +26,12 @@ func (h *apiHandler) registerRoutes() {
 		r.Route("/users", func(r chi.Router) {
 			r.Get("/all", h.handleListUsers)
 			r.Post("/login", h.handleGetUserByEmail)
+			r.Post("/login/totp", h.handleLoginTotp)
 			r.Post("/", h.handleCreateUser)
+			r.Post("/restore", h.handleRestoreUser)
+			r.Get("/verify_email", h.handleVerifyEmail)
+			r.Post("/password/forgot", h.handleForgotPassword)
+			r.Post("/password/reset", h.handleResetPassword)
 
 			r.Group(func(r chi.Router) {
 				r.Use(my_middleware.JWTAuth)
@@ -34,6 +39,16 @@ func (h *apiHandler) registerRoutes() {
 				r.Get("/", h.handleGetUser)
 				r.Patch("/", h.handleUpdateUser)
 				r.Delete("/", h.handleDeleteUser)
+				r.Get("/audit", h.handleListUserAuditLogs)
+				r.Get("/export", h.handleExportAccount)
+				r.Post("/verify_email/resend", h.handleResendVerification)
+
+				r.Route("/totp", func(r chi.Router) {
+					r.Post("/enroll", h.handleEnrollTotp)
+					r.Post("/verify", h.handleActivateTotp)
+					r.Post("/recovery_codes", h.handleRegenerateRecoveryCodes)
+					r.Delete("/", h.handleDisableTotp)
+				})
 			})
 		})
 
@@ -43,10 +58,95 @@ func (h *apiHandler) registerRoutes() {
 			r.Get("/", h.handleListShortUrlsByUser)
 			r.Get("/{short_url_id}", h.handleGetShortUrl)
 			r.Post("/", h.handleCreateShortUrl)
+			r.Post("/bulk", h.handleBulkCreateShortUrls)
+			r.Get("/bulk/{job_id}", h.handleGetBulkJob)
+			r.Get("/bulk/{job_id}/results", h.handleGetBulkJobResults)
+			r.Post("/import", h.handleImportShortUrls)
 			r.Patch("/{short_url_id}", h.handleUpdateShortUrl)
 			r.Delete("/{short_url_id}", h.handleDeleteShortUrl)
+			r.Post("/{short_url_id}/transfer", h.handleTransferShortUrl)
+			r.Post("/{short_url_id}/restore", h.handleRestoreShortUrl)
+			r.Get("/{short_url_id}/versions", h.handleListShortUrlVersions)
+			r.Post("/{short_url_id}/versions/{version}/restore", h.handleRestoreShortUrlVersion)
+			r.Get("/{short_url_id}/audit", h.handleListShortUrlAuditLogs)
+			r.Get("/{short_url_id}/rules", h.handleListRedirectRules)
+			r.Post("/{short_url_id}/rules", h.handleCreateRedirectRule)
+			r.Patch("/{short_url_id}/rules/{rule_id}", h.handleUpdateRedirectRule)
+			r.Delete("/{short_url_id}/rules/{rule_id}", h.handleDeleteRedirectRule)
+			r.Get("/{short_url_id}/qr", h.handleGetShortUrlQrCode)
+		})
+
+		r.Route("/workspaces", func(r chi.Router) {
+			r.Use(my_middleware.JWTAuth)
+
+			r.Get("/", h.handleListWorkspaces)
+			r.Post("/", h.handleCreateWorkspace)
+			r.Post("/invitations/accept", h.handleAcceptInvitation)
+			r.Get("/{workspace_id}", h.handleGetWorkspace)
+			r.Patch("/{workspace_id}", h.handleUpdateWorkspace)
+			r.Delete("/{workspace_id}", h.handleDeleteWorkspace)
+			r.Post("/{workspace_id}/invitations", h.handleInviteMember)
+			r.Patch("/{workspace_id}/members/{user_id}", h.handleUpdateMemberRole)
+			r.Delete("/{workspace_id}/members/{user_id}", h.handleRemoveMember)
+		})
+
+		r.Route("/campaigns", func(r chi.Router) {
+			r.Use(my_middleware.JWTAuth)
+
+			r.Get("/", h.handleListCampaigns)
+			r.Post("/", h.handleCreateCampaign)
+			r.Get("/{campaign_id}", h.handleGetCampaign)
+			r.Patch("/{campaign_id}", h.handleUpdateCampaign)
+			r.Delete("/{campaign_id}", h.handleDeleteCampaign)
+		})
+
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(my_middleware.JWTAuth)
+
+			r.Get("/", h.handleListWebhooks)
+			r.Post("/", h.handleCreateWebhook)
+			r.Delete("/{webhook_id}", h.handleDeleteWebhook)
+		})
+
+		r.Route("/tags", func(r chi.Router) {
+			r.Use(my_middleware.JWTAuth)
+
+			r.Get("/", h.handleListTags)
+			r.Post("/", h.handleCreateTag)
+			r.Patch("/{tag_id}", h.handleUpdateTag)
+			r.Delete("/{tag_id}", h.handleDeleteTag)
+			r.Post("/{tag_id}/merge", h.handleMergeTag)
+			r.Post("/{tag_id}/links", h.handleAddTagLinks)
+			r.Delete("/{tag_id}/links", h.handleRemoveTagLinks)
+		})
+
+		r.Route("/folders", func(r chi.Router) {
+			r.Use(my_middleware.JWTAuth)
+
+			r.Get("/", h.handleListFolders)
+			r.Post("/", h.handleCreateFolder)
+			r.Patch("/{folder_id}", h.handleUpdateFolder)
+			r.Delete("/{folder_id}", h.handleDeleteFolder)
+			r.Post("/{folder_id}/merge", h.handleMergeFolder)
+			r.Post("/{folder_id}/links", h.handleAddFolderLinks)
+		})
+
+		r.Route("/domains", func(r chi.Router) {
+			r.Use(my_middleware.JWTAuth)
+
+			r.Get("/", h.handleListDomains)
+			r.Post("/", h.handleCreateDomain)
+			r.Get("/{domain_id}", h.handleGetDomain)
+			r.Patch("/{domain_id}", h.handleUpdateDomain)
+			r.Post("/{domain_id}/verify", h.handleVerifyDomain)
+			r.Delete("/{domain_id}", h.handleDeleteDomain)
 		})
 	})
 
+	h.r.NotFound(h.handleNotFound)
+	h.r.Get("/", h.handleRoot)
+	h.r.Get("/{slug}.qr", h.handlePublicQrCode)
+	h.r.Get("/{slug}+", h.handlePreview)
 	h.r.Get("/{slug}", h.handleRedirect)
+	h.r.Post("/{slug}", h.handleUnlockRedirect)
 }
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"image"
 	"net/http"
 	"sync"
 
@@ -13,26 +14,62 @@ import (
 )
 
 type apiHandler struct {
-	r           *chi.Mux
-	mu          *sync.Mutex
-	user        ports.UserUseCase
-	shortUrl    ports.ShortUrlUseCase
-	cache       *infra.URLCache
-	accessCount *infra.AccessCounter
+	r                *chi.Mux
+	mu               *sync.Mutex
+	user             ports.UserUseCase
+	shortUrl         ports.ShortUrlUseCase
+	bulk             ports.BulkUseCase
+	portability      ports.PortabilityUseCase
+	totp             ports.TotpUseCase
+	workspace        ports.WorkspaceUseCase
+	audit            ports.AuditUseCase
+	redirectRule     ports.RedirectRuleUseCase
+	campaign         ports.CampaignUseCase
+	webhook          ports.WebhookUseCase
+	tag              ports.TagUseCase
+	folder           ports.FolderUseCase
+	domain           ports.DomainUseCase
+	cache            *infra.URLCache
+	accessCount      *infra.AccessCounter
+	passwordAttempts *infra.PasswordAttempts
+	totpGuard        *infra.TotpGuard
+	geoip            *infra.GeoIP
+	qrLogo           image.Image
+	fallback         *fallbackPages
 }
 
 func (h apiHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.r.ServeHTTP(w, r)
 }
 
-func NewApiHandler(q *pgstore.Queries, rdb *redis.Client) http.Handler {
+// NewApiHandler wires the services, resolver answers the TXT lookups that
+// verify custom domains
+func NewApiHandler(q *pgstore.Queries, rdb *redis.Client, mailer ports.Mailer, resolver ports.TxtResolver) http.Handler {
+	policy := infra.NewDestinationPolicyFromEnv().WithCustomDomains(q.ListVerifiedDomainHosts)
+	totpGuard := infra.NewTotpGuard(rdb)
 	a := apiHandler{
-		r:           chi.NewRouter(),
-		mu:          &sync.Mutex{},
-		user:        services.NewUserService(q),
-		shortUrl:    services.NewShortUrlService(q),
-		cache:       infra.NewURLCache(rdb),
-		accessCount: infra.NewAccessCounter(rdb),
+		r:                chi.NewRouter(),
+		mu:               &sync.Mutex{},
+		user:             services.NewUserService(q, mailer),
+		shortUrl:         services.NewShortUrlService(q, policy),
+		bulk:             services.NewBulkService(q, policy),
+		portability:      services.NewPortabilityService(q, policy),
+		totp:             services.NewTotpService(q, totpGuard),
+		workspace:        services.NewWorkspaceService(q, mailer),
+		audit:            services.NewAuditService(q),
+		redirectRule:     services.NewRedirectRuleService(q, policy),
+		campaign:         services.NewCampaignService(q),
+		webhook:          services.NewWebhookService(q),
+		tag:              services.NewTagService(q),
+		folder:           services.NewFolderService(q),
+		domain:           services.NewDomainService(q, policy, resolver),
+		cache:            infra.NewURLCache(rdb),
+		accessCount:      infra.NewAccessCounter(rdb),
+		passwordAttempts: infra.NewPasswordAttempts(rdb),
+		totpGuard:        totpGuard,
+		geoip:            infra.NewGeoIPFromEnv(),
+		qrLogo:           infra.LoadQrLogoFromEnv(),
+		fallback:         loadFallbackPagesFromEnv(),
 	}
 	a.registerRoutes()
 
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// =============================================================================
+// Short URL Version Handlers
+// =============================================================================
+
+// handleListShortUrlVersions returns the version history of a short URL
+//
+//		@Summary		List short URL versions
+//		@Description	Returns the states a short URL was left in by each edit, newest first, with when and by whom. Version 1 is the link as it was before its first recorded edit and has no author. Versions written by a restore tell which version they came from. Links never edited have no versions
+//	 @Tags 			short urls
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string			true	"Short URL ID"
+//		@Success		200				{object}	models.Response	"Short URL versions"
+//		@Failure		400				{object}	models.Response	"Invalid short URL ID format"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response	"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response	"Short URL not found"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/versions [get]
+func (h apiHandler) handleListShortUrlVersions(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+
+	versions, err := h.shortUrl.ListShortUrlVersions(r.Context(), userId, shortUrlId)
+	hooks.SendResponse(w, http.StatusOK, versions, err)
+}
+
+// handleRestoreShortUrlVersion puts a short URL back in the state of one of
+// its versions
+//
+//		@Summary		Restore short URL version
+//		@Description	Puts the destination, expiry, password, schedule, inactive behaviour, redirect options, campaign and folder of a short URL back as they were in a version, recorded as a new version. The slug of the version is taken back unless another link uses it. Variants and tags are not versioned and are kept
+//	 @Tags 			short urls
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			short_url_id	path		string			true	"Short URL ID"
+//		@Param			version			path		int				true	"Version number"
+//		@Success		200				{object}	models.Response	"Short URL restored to the version"
+//		@Failure		400				{object}	models.Response	"Invalid version number or refused destination"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response	"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response	"Short URL or version not found"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/short_url/{short_url_id}/versions/{version}/restore [post]
+func (h apiHandler) handleRestoreShortUrlVersion(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	shortUrlId := chi.URLParam(r, "short_url_id")
+	version, err := strconv.Atoi(chi.URLParam(r, "version"))
+	if err != nil || version < 1 {
+		hooks.SendResponse(w, http.StatusBadRequest, nil, wraperrors.ValidationErr("Invalid version number"))
+		return
+	}
+
+	current, err := h.shortUrl.GetShortUrl(r.Context(), userId, shortUrlId)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusInternalServerError, nil, err)
+		return
+	}
+
+	shortUrl, err := h.shortUrl.RestoreShortUrlVersion(r.Context(), userId, shortUrlId, version)
+	if err == nil {
+		// Like an update, the slug left behind may be cached with the
+		// destination being replaced
+		h.cache.Invalidate(r.Context(), current.Key())
+		h.accessCount.ResetClickLimit(r.Context(), current.Key())
+		if shortUrl.Key() != current.Key() {
+			h.cache.Invalidate(r.Context(), shortUrl.Key())
+		}
+	}
+	hooks.SendResponse(w, http.StatusOK, shortUrl, err)
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+)
+
+// =============================================================================
+// Tag Handlers
+// =============================================================================
+
+// handleListTags returns the authenticated user's tags
+//
+//		@Summary		List tags
+//		@Description	Returns the tags of the authenticated user with how many live links carry each one
+//	 @Tags 			tags
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Success		200	{object}	models.Response	"List of tags"
+//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/api/tags [get]
+func (h apiHandler) handleListTags(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	tags, err := h.tag.ListTags(r.Context(), userId)
+	hooks.SendResponse(w, http.StatusOK, tags, err)
+}
+
+// handleCreateTag creates a tag for the authenticated user
+//
+//		@Summary		Create tag
+//		@Description	Creates a tag, names are unique per user
+//	 @Tags 			tags
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			tag	body		models.CreateTagInput	true	"Tag information"
+//		@Success		201	{object}	models.Response			"Tag created successfully"
+//		@Failure		400	{object}	models.Response			"Invalid input data"
+//		@Failure		401	{object}	models.Response			"Invalid user ID in token"
+//		@Failure		409	{object}	models.Response			"Tag name already in use"
+//		@Failure		500	{object}	models.Response			"Internal server error"
+//		@Router			/api/tags [post]
+func (h apiHandler) handleCreateTag(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.CreateTagInput](w, r)
+	if !ok {
+		return
+	}
+
+	tag, err := h.tag.CreateTag(r.Context(), userId, body)
+	hooks.SendResponse(w, http.StatusCreated, tag, err)
+}
+
+// handleUpdateTag renames a tag
+//
+//		@Summary		Rename tag
+//		@Description	Renames a tag, the links keep it
+//	 @Tags 			tags
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			tag_id	path		string					true	"Tag ID"
+//		@Param			tag		body		models.UpdateTagInput	true	"Tag information"
+//		@Success		200		{object}	models.Response			"Tag renamed successfully"
+//		@Failure		400		{object}	models.Response			"Invalid input data"
+//		@Failure		401		{object}	models.Response			"Invalid user ID in token"
+//		@Failure		404		{object}	models.Response			"Tag not found"
+//		@Failure		409		{object}	models.Response			"Tag name already in use"
+//		@Failure		500		{object}	models.Response			"Internal server error"
+//		@Router			/api/tags/{tag_id} [patch]
+func (h apiHandler) handleUpdateTag(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	tagId := chi.URLParam(r, "tag_id")
+	body, ok := utils.ParseAndValidate[models.UpdateTagInput](w, r)
+	if !ok {
+		return
+	}
+
+	tag, err := h.tag.UpdateTag(r.Context(), userId, tagId, body)
+	hooks.SendResponse(w, http.StatusOK, tag, err)
+}
+
+// handleDeleteTag deletes a tag, its links are kept
+//
+//		@Summary		Delete tag
+//		@Description	Deletes a tag and removes it from its links, the links themselves are kept
+//	 @Tags 			tags
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			tag_id	path		string			true	"Tag ID"
+//		@Success		204		{object}	models.Response	"Tag deleted with success"
+//		@Failure		400		{object}	models.Response	"Invalid tag ID format"
+//		@Failure		401		{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404		{object}	models.Response	"Tag not found"
+//		@Failure		500		{object}	models.Response	"Internal server error"
+//		@Router			/api/tags/{tag_id} [delete]
+func (h apiHandler) handleDeleteTag(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	tagId := chi.URLParam(r, "tag_id")
+
+	err = h.tag.DeleteTag(r.Context(), userId, tagId)
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
+
+// handleMergeTag merges a tag into another one
+//
+//		@Summary		Merge tag
+//		@Description	Puts the target tag on every link of the tag and deletes the tag. Returns the target tag
+//	 @Tags 			tags
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			tag_id	path		string					true	"Tag ID"
+//		@Param			merge	body		models.MergeTagInput	true	"Tag to merge into"
+//		@Success		200		{object}	models.Response			"Tags merged successfully"
+//		@Failure		400		{object}	models.Response			"Invalid input data"
+//		@Failure		401		{object}	models.Response			"Invalid user ID in token"
+//		@Failure		404		{object}	models.Response			"Tag not found"
+//		@Failure		500		{object}	models.Response			"Internal server error"
+//		@Router			/api/tags/{tag_id}/merge [post]
+func (h apiHandler) handleMergeTag(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	tagId := chi.URLParam(r, "tag_id")
+	body, ok := utils.ParseAndValidate[models.MergeTagInput](w, r)
+	if !ok {
+		return
+	}
+
+	tag, err := h.tag.MergeTag(r.Context(), userId, tagId, body)
+	hooks.SendResponse(w, http.StatusOK, tag, err)
+}
+
+// handleAddTagLinks tags several links at once
+//
+//		@Summary		Tag links
+//		@Description	Puts the tag on up to 500 links the user can edit, links already tagged are left as they are
+//	 @Tags 			tags
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			tag_id	path		string					true	"Tag ID"
+//		@Param			links	body		models.AssignLinksInput	true	"Links to tag"
+//		@Success		200		{object}	models.Response			"Links tagged successfully"
+//		@Failure		400		{object}	models.Response			"Invalid input data"
+//		@Failure		401		{object}	models.Response			"Invalid user ID in token"
+//		@Failure		403		{object}	models.Response			"Insufficient workspace role on one of the links"
+//		@Failure		404		{object}	models.Response			"Tag or short URL not found"
+//		@Failure		500		{object}	models.Response			"Internal server error"
+//		@Router			/api/tags/{tag_id}/links [post]
+func (h apiHandler) handleAddTagLinks(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	tagId := chi.URLParam(r, "tag_id")
+	body, ok := utils.ParseAndValidate[models.AssignLinksInput](w, r)
+	if !ok {
+		return
+	}
+
+	tag, err := h.tag.AddTagLinks(r.Context(), userId, tagId, body)
+	hooks.SendResponse(w, http.StatusOK, tag, err)
+}
+
+// handleRemoveTagLinks removes a tag from several links at once
+//
+//		@Summary		Untag links
+//		@Description	Removes the tag from up to 500 links the user can edit
+//	 @Tags 			tags
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			tag_id	path		string					true	"Tag ID"
+//		@Param			links	body		models.AssignLinksInput	true	"Links to untag"
+//		@Success		200		{object}	models.Response			"Links untagged successfully"
+//		@Failure		400		{object}	models.Response			"Invalid input data"
+//		@Failure		401		{object}	models.Response			"Invalid user ID in token"
+//		@Failure		403		{object}	models.Response			"Insufficient workspace role on one of the links"
+//		@Failure		404		{object}	models.Response			"Tag or short URL not found"
+//		@Failure		500		{object}	models.Response			"Internal server error"
+//		@Router			/api/tags/{tag_id}/links [delete]
+func (h apiHandler) handleRemoveTagLinks(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	tagId := chi.URLParam(r, "tag_id")
+	body, ok := utils.ParseAndValidate[models.AssignLinksInput](w, r)
+	if !ok {
+		return
+	}
+
+	tag, err := h.tag.RemoveTagLinks(r.Context(), userId, tagId, body)
+	hooks.SendResponse(w, http.StatusOK, tag, err)
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// =============================================================================
+// TOTP Handlers
+// =============================================================================
+
+// handleLoginTotp exchanges a challenge token and a TOTP code for a JWT token
+//
+//		@Summary		Complete TOTP login
+//		@Description	Exchanges the challenge token returned by the login and a TOTP or recovery code for a JWT token. A challenge token is accepted once and a TOTP code can't be reused. After 5 attempts in 15 minutes the user's TOTP login is locked until the window ends
+//	 @Tags 			totp
+//		@Accept			json
+//		@Produce		json
+//		@Param			credentials	body		models.TotpLoginInput	true	"Challenge token and TOTP code"
+//		@Success		201			{object}	models.Response			"JWT token"
+//		@Failure		400			{object}	models.Response			"Invalid input data"
+//		@Failure		401			{object}	models.Response			"Invalid or used challenge token or code"
+//		@Failure		429			{object}	models.Response			"Too many attempts"
+//		@Failure		500			{object}	models.Response			"Internal server error"
+//		@Router			/api/users/login/totp [post]
+func (h apiHandler) handleLoginTotp(w http.ResponseWriter, r *http.Request) {
+	body, ok := utils.ParseAndValidate[models.TotpLoginInput](w, r)
+	if !ok {
+		return
+	}
+
+	userId, challengeId, err := middleware.ParseChallengeJWT(body.ChallengeToken)
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	if !h.registerTotpAttempt(w, r, userId) {
+		return
+	}
+
+	if err := h.totpGuard.CheckChallenge(r.Context(), challengeId, userId); err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	if err := h.totp.VerifyLogin(r.Context(), userId, body.Code); err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	if err := h.totpGuard.ConsumeChallenge(r.Context(), challengeId); err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+	h.totpGuard.ResetAttempts(r.Context(), userId)
+
+	token, err := middleware.GenerateJWT(userId)
+	hooks.SendResponse(w, http.StatusCreated, models.Token{JWT: token}, err)
+}
+
+// registerTotpAttempt counts a TOTP code attempt of the user before the code
+// is checked, so parallel guesses can't get past the limit, and answers 429
+// when the user is locked
+func (h apiHandler) registerTotpAttempt(w http.ResponseWriter, r *http.Request, userId string) bool {
+	retryAfter, err := h.totpGuard.RegisterAttempt(r.Context(), userId)
+	if err != nil {
+		if wraperrors.IsTooManyRequestsError(err) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		}
+		hooks.SendResponse(w, http.StatusTooManyRequests, nil, err)
+		return false
+	}
+	return true
+}
+
+// handleEnrollTotp starts the TOTP enrollment of the authenticated user
+//
+//		@Summary		Enroll TOTP
+//		@Description	Generates a new TOTP secret and otpauth URI for the authenticated user
+//	 @Tags 			totp
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Success		201	{object}	models.Response	"TOTP secret and otpauth URI"
+//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
+//		@Failure		409	{object}	models.Response	"TOTP already enabled"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/api/users/totp/enroll [post]
+func (h apiHandler) handleEnrollTotp(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	enrollment, err := h.totp.Enroll(r.Context(), userId)
+	hooks.SendResponse(w, http.StatusCreated, enrollment, err)
+}
+
+// handleActivateTotp confirms the TOTP enrollment with a valid code
+//
+//		@Summary		Activate TOTP
+//		@Description	Verifies a TOTP code for the pending secret, enables two-factor login and returns recovery codes. The code attempts count towards the TOTP lock of the user
+//	 @Tags 			totp
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			code	body		models.TotpCodeInput	true	"TOTP code"
+//		@Success		200		{object}	models.Response			"Recovery codes"
+//		@Failure		400		{object}	models.Response			"Invalid input data"
+//		@Failure		401		{object}	models.Response			"Invalid TOTP code"
+//		@Failure		409		{object}	models.Response			"TOTP already enabled"
+//		@Failure		429		{object}	models.Response			"Too many attempts"
+//		@Failure		500		{object}	models.Response			"Internal server error"
+//		@Router			/api/users/totp/verify [post]
+func (h apiHandler) handleActivateTotp(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.TotpCodeInput](w, r)
+	if !ok {
+		return
+	}
+
+	if !h.registerTotpAttempt(w, r, userId) {
+		return
+	}
+
+	codes, err := h.totp.Activate(r.Context(), userId, body.Code)
+	if err == nil {
+		h.totpGuard.ResetAttempts(r.Context(), userId)
+	}
+	hooks.SendResponse(w, http.StatusOK, codes, err)
+}
+
+// handleRegenerateRecoveryCodes replaces the recovery codes of the authenticated user
+//
+//		@Summary		Regenerate recovery codes
+//		@Description	Invalidates the current recovery codes and returns a new set after checking a TOTP code. The code attempts count towards the TOTP lock of the user
+//	 @Tags 			totp
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			code	body		models.TotpCodeInput	true	"TOTP code"
+//		@Success		200		{object}	models.Response			"Recovery codes"
+//		@Failure		400		{object}	models.Response			"Invalid input data"
+//		@Failure		401		{object}	models.Response			"Invalid TOTP code"
+//		@Failure		429		{object}	models.Response			"Too many attempts"
+//		@Failure		500		{object}	models.Response			"Internal server error"
+//		@Router			/api/users/totp/recovery_codes [post]
+func (h apiHandler) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.TotpCodeInput](w, r)
+	if !ok {
+		return
+	}
+
+	if !h.registerTotpAttempt(w, r, userId) {
+		return
+	}
+
+	codes, err := h.totp.RegenerateRecoveryCodes(r.Context(), userId, body.Code)
+	if err == nil {
+		h.totpGuard.ResetAttempts(r.Context(), userId)
+	}
+	hooks.SendResponse(w, http.StatusOK, codes, err)
+}
+
+// handleDisableTotp disables two-factor authentication for the authenticated user
+//
+//		@Summary		Disable TOTP
+//		@Description	Disables TOTP after checking a TOTP or recovery code. The code attempts count towards the TOTP lock of the user
+//	 @Tags 			totp
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			code	body		models.TotpCodeInput	true	"TOTP or recovery code"
+//		@Success		204		{object}	models.Response			"TOTP disabled"
+//		@Failure		400		{object}	models.Response			"Invalid input data"
+//		@Failure		401		{object}	models.Response			"Invalid TOTP code"
+//		@Failure		429		{object}	models.Response			"Too many attempts"
+//		@Failure		500		{object}	models.Response			"Internal server error"
+//		@Router			/api/users/totp [delete]
+func (h apiHandler) handleDisableTotp(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.TotpCodeInput](w, r)
+	if !ok {
+		return
+	}
+
+	if !h.registerTotpAttempt(w, r, userId) {
+		return
+	}
+
+	err = h.totp.Disable(r.Context(), userId, body.Code)
+	if err == nil {
+		h.totpGuard.ResetAttempts(r.Context(), userId)
+	}
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
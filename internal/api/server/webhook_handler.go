@@ -0,0 +1,94 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+)
+
+// =============================================================================
+// Webhook Handlers
+// =============================================================================
+
+// handleListWebhooks returns the authenticated user's webhooks
+//
+//		@Summary		List webhooks
+//		@Description	Returns the webhooks of the authenticated user, secrets are not included
+//	 @Tags 			webhooks
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Success		200	{object}	models.Response	"List of webhooks"
+//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/api/webhooks [get]
+func (h apiHandler) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	webhooks, err := h.webhook.ListWebhooks(r.Context(), userId)
+	hooks.SendResponse(w, http.StatusOK, webhooks, err)
+}
+
+// handleCreateWebhook registers a webhook for the authenticated user
+//
+//		@Summary		Create webhook
+//		@Description	Registers an endpoint that receives link.broken and link.recovered events as JSON POSTs. Each request carries the event name in X-Webhook-Event and an HMAC-SHA256 of the body keyed with the secret in X-Webhook-Signature. The secret is only returned here
+//	 @Tags 			webhooks
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			webhook	body		models.CreateWebhookInput	true	"Webhook endpoint"
+//		@Success		201		{object}	models.Response				"Webhook created successfully"
+//		@Failure		400		{object}	models.Response				"Invalid URL or too many webhooks"
+//		@Failure		401		{object}	models.Response				"Invalid user ID in token"
+//		@Failure		500		{object}	models.Response				"Internal server error"
+//		@Router			/api/webhooks [post]
+func (h apiHandler) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.CreateWebhookInput](w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhook.CreateWebhook(r.Context(), userId, body)
+	hooks.SendResponse(w, http.StatusCreated, webhook, err)
+}
+
+// handleDeleteWebhook removes a webhook
+//
+//		@Summary		Delete webhook
+//		@Description	Deletes a webhook, it stops receiving events right away
+//	 @Tags 			webhooks
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			webhook_id	path		string			true	"Webhook ID"
+//		@Success		204			{object}	models.Response	"Webhook deleted with success"
+//		@Failure		400			{object}	models.Response	"Invalid webhook ID format"
+//		@Failure		401			{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404			{object}	models.Response	"Webhook not found"
+//		@Failure		500			{object}	models.Response	"Internal server error"
+//		@Router			/api/webhooks/{webhook_id} [delete]
+func (h apiHandler) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	webhookId := chi.URLParam(r, "webhook_id")
+
+	err = h.webhook.DeleteWebhook(r.Context(), userId, webhookId)
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
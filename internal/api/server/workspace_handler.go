@@ -0,0 +1,287 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/hooks"
+	"github.com/jhonVitor-rs/url-shortener/internal/api/middleware"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+)
+
+// =============================================================================
+// Workspace Handlers
+// =============================================================================
+
+// handleListWorkspaces returns the workspaces the authenticated user belongs to
+//
+//		@Summary		List workspaces
+//		@Description	Returns the workspaces the authenticated user is a member of, with the user's role in each
+//	 @Tags 			workspaces
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Success		200	{object}	models.Response	"List of workspaces"
+//		@Failure		401	{object}	models.Response	"Invalid user ID in token"
+//		@Failure		500	{object}	models.Response	"Internal server error"
+//		@Router			/api/workspaces [get]
+func (h apiHandler) handleListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	workspaces, err := h.workspace.ListWorkspaces(r.Context(), userId)
+	hooks.SendResponse(w, http.StatusOK, workspaces, err)
+}
+
+// handleGetWorkspace returns a workspace with its members
+//
+//		@Summary		Get workspace details
+//		@Description	Returns a workspace and its members. Only members can see it
+//	 @Tags 			workspaces
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			workspace_id	path		string			true	"Workspace ID"
+//		@Success		200				{object}	models.Response	"Workspace details"
+//		@Failure		400				{object}	models.Response	"Invalid workspace ID format"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		404				{object}	models.Response	"Workspace not found"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/workspaces/{workspace_id} [get]
+func (h apiHandler) handleGetWorkspace(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	workspaceId := chi.URLParam(r, "workspace_id")
+
+	workspace, err := h.workspace.GetWorkspace(r.Context(), userId, workspaceId)
+	hooks.SendResponse(w, http.StatusOK, workspace, err)
+}
+
+// handleCreateWorkspace creates a workspace owned by the authenticated user
+//
+//		@Summary		Create workspace
+//		@Description	Creates a workspace with the authenticated user as its owner
+//	 @Tags 			workspaces
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			workspace	body		models.CreateWorkspaceInput	true	"Workspace information"
+//		@Success		201			{object}	models.Response				"Workspace created successfully"
+//		@Failure		400			{object}	models.Response				"Invalid input data"
+//		@Failure		401			{object}	models.Response				"Invalid user ID in token"
+//		@Failure		500			{object}	models.Response				"Internal server error"
+//		@Router			/api/workspaces [post]
+func (h apiHandler) handleCreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.CreateWorkspaceInput](w, r)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.workspace.CreateWorkspace(r.Context(), userId, body)
+	hooks.SendResponse(w, http.StatusCreated, workspace, err)
+}
+
+// handleUpdateWorkspace renames a workspace
+//
+//		@Summary		Update workspace
+//		@Description	Renames a workspace. Requires the owner role
+//	 @Tags 			workspaces
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			workspace_id	path		string						true	"Workspace ID"
+//		@Param			workspace		body		models.UpdateWorkspaceInput	true	"Workspace information"
+//		@Success		200				{object}	models.Response				"Workspace updated successfully"
+//		@Failure		400				{object}	models.Response				"Invalid input data"
+//		@Failure		401				{object}	models.Response				"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response				"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response				"Workspace not found"
+//		@Failure		500				{object}	models.Response				"Internal server error"
+//		@Router			/api/workspaces/{workspace_id} [patch]
+func (h apiHandler) handleUpdateWorkspace(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	workspaceId := chi.URLParam(r, "workspace_id")
+	body, ok := utils.ParseAndValidate[models.UpdateWorkspaceInput](w, r)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.workspace.UpdateWorkspace(r.Context(), userId, workspaceId, body)
+	hooks.SendResponse(w, http.StatusOK, workspace, err)
+}
+
+// handleDeleteWorkspace deletes an empty workspace
+//
+//		@Summary		Delete workspace
+//		@Description	Deletes a workspace that no longer owns short URLs. Requires the owner role
+//	 @Tags 			workspaces
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			workspace_id	path		string			true	"Workspace ID"
+//		@Success		204				{object}	models.Response	"Workspace deleted with success"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response	"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response	"Workspace not found"
+//		@Failure		409				{object}	models.Response	"Workspace still owns short URLs"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/workspaces/{workspace_id} [delete]
+func (h apiHandler) handleDeleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	workspaceId := chi.URLParam(r, "workspace_id")
+
+	err = h.workspace.DeleteWorkspace(r.Context(), userId, workspaceId)
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
+
+// handleInviteMember sends a workspace invitation by email
+//
+//		@Summary		Invite member
+//		@Description	Sends an invitation token to the given email address. Requires the owner role
+//	 @Tags 			workspaces
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			workspace_id	path		string						true	"Workspace ID"
+//		@Param			invitation		body		models.InviteMemberInput	true	"Invitee email and role"
+//		@Success		201				{object}	models.Response				"Invitation sent"
+//		@Failure		400				{object}	models.Response				"Invalid input data"
+//		@Failure		401				{object}	models.Response				"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response				"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response				"Workspace not found"
+//		@Failure		500				{object}	models.Response				"Internal server error"
+//		@Router			/api/workspaces/{workspace_id}/invitations [post]
+func (h apiHandler) handleInviteMember(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	workspaceId := chi.URLParam(r, "workspace_id")
+	body, ok := utils.ParseAndValidate[models.InviteMemberInput](w, r)
+	if !ok {
+		return
+	}
+
+	invitation, err := h.workspace.InviteMember(r.Context(), userId, workspaceId, body)
+	hooks.SendResponse(w, http.StatusCreated, invitation, err)
+}
+
+// handleAcceptInvitation joins the workspace of an invitation
+//
+//		@Summary		Accept invitation
+//		@Description	Adds the authenticated user to the workspace. The user's verified email must match the invited address
+//	 @Tags 			workspaces
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			invitation	body		models.AcceptInvitationInput	true	"Invitation token"
+//		@Success		200			{object}	models.Response					"Joined workspace"
+//		@Failure		400			{object}	models.Response					"Invalid input data"
+//		@Failure		401			{object}	models.Response					"Invalid or expired token"
+//		@Failure		403			{object}	models.Response					"Invitation sent to another email or email not verified"
+//		@Failure		500			{object}	models.Response					"Internal server error"
+//		@Router			/api/workspaces/invitations/accept [post]
+func (h apiHandler) handleAcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	body, ok := utils.ParseAndValidate[models.AcceptInvitationInput](w, r)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.workspace.AcceptInvitation(r.Context(), userId, body.Token)
+	hooks.SendResponse(w, http.StatusOK, workspace, err)
+}
+
+// handleUpdateMemberRole changes the role of a workspace member
+//
+//		@Summary		Update member role
+//		@Description	Changes a member's role. Requires the owner role and the workspace must keep at least one owner
+//	 @Tags 			workspaces
+//		@Accept			json
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			workspace_id	path		string							true	"Workspace ID"
+//		@Param			user_id			path		string							true	"Member user ID"
+//		@Param			role			body		models.UpdateMemberRoleInput	true	"New role"
+//		@Success		204				{object}	models.Response					"Role updated"
+//		@Failure		400				{object}	models.Response					"Invalid input data or last owner"
+//		@Failure		401				{object}	models.Response					"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response					"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response					"Workspace or member not found"
+//		@Failure		500				{object}	models.Response					"Internal server error"
+//		@Router			/api/workspaces/{workspace_id}/members/{user_id} [patch]
+func (h apiHandler) handleUpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	workspaceId := chi.URLParam(r, "workspace_id")
+	memberId := chi.URLParam(r, "user_id")
+	body, ok := utils.ParseAndValidate[models.UpdateMemberRoleInput](w, r)
+	if !ok {
+		return
+	}
+
+	err = h.workspace.UpdateMemberRole(r.Context(), userId, workspaceId, memberId, body)
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
+
+// handleRemoveMember removes a member from a workspace
+//
+//		@Summary		Remove member
+//		@Description	Removes a member from the workspace. Owners can remove anyone, members can remove themselves
+//	 @Tags 			workspaces
+//		@Produce		json
+//		@Security		BearerAuth
+//		@Param			workspace_id	path		string			true	"Workspace ID"
+//		@Param			user_id			path		string			true	"Member user ID"
+//		@Success		204				{object}	models.Response	"Member removed"
+//		@Failure		400				{object}	models.Response	"Last owner can't be removed"
+//		@Failure		401				{object}	models.Response	"Invalid user ID in token"
+//		@Failure		403				{object}	models.Response	"Insufficient workspace role"
+//		@Failure		404				{object}	models.Response	"Workspace or member not found"
+//		@Failure		500				{object}	models.Response	"Internal server error"
+//		@Router			/api/workspaces/{workspace_id}/members/{user_id} [delete]
+func (h apiHandler) handleRemoveMember(w http.ResponseWriter, r *http.Request) {
+	userId, err := middleware.GetUserIdFromContext(r.Context())
+	if err != nil {
+		hooks.SendResponse(w, http.StatusUnauthorized, nil, err)
+		return
+	}
+
+	workspaceId := chi.URLParam(r, "workspace_id")
+	memberId := chi.URLParam(r, "user_id")
+
+	err = h.workspace.RemoveMember(r.Context(), userId, workspaceId, memberId)
+	hooks.SendResponse(w, http.StatusNoContent, nil, err)
+}
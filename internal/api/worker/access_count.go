@@ -6,7 +6,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
 	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
 	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
 	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
@@ -18,6 +20,8 @@ const (
 	workerInterval         = 1 * time.Hour
 	workerTimeout          = 5 * time.Minute
 	accessKeyPrefix        = "access:"
+	variantAccessKeyPrefix = "variant_access:"
+	scanAccessKeyPrefix    = "scan_access:"
 )
 
 type AccessSyncWorker struct {
@@ -86,21 +90,83 @@ func (w *AccessSyncWorker) processAccessCount(ctx context.Context) {
 	start := time.Now()
 	w.logger.Info("processing accss counts from Redis to database")
 
-	processed := 0
-	failed := 0
-	var mu sync.Mutex
-
 	keys, err := w.counter.GetAllAccessKeys(ctx)
 	if err != nil {
 		w.logger.Error("failed to get access keys", "error", err)
 		return
 	}
 
-	if len(keys) == 0 {
+	variantKeys, err := w.counter.GetAllVariantAccessKeys(ctx)
+	if err != nil {
+		w.logger.Error("failed to get variant access keys", "error", err)
+	}
+
+	scanKeys, err := w.counter.GetAllScanAccessKeys(ctx)
+	if err != nil {
+		w.logger.Error("failed to get scan access keys", "error", err)
+	}
+
+	if len(keys) == 0 && len(variantKeys) == 0 && len(scanKeys) == 0 {
 		w.logger.Info("no access counters to process")
 		return
 	}
 
+	processed, failed := w.syncCounters(ctx, keys, accessKeyPrefix, func(ctx context.Context, key string, count int) error {
+		domainId, slug, ok := w.parseLinkKey(key, count)
+		if !ok {
+			return nil
+		}
+		return w.db.IncrementAccessCount(ctx, pgstore.IncrementAccessCountParams{
+			Slug:     slug,
+			DomainID: domainId,
+			AccessCount: pgtype.Int4{
+				Int32: int32(count),
+				Valid: true,
+			},
+		})
+	})
+
+	variantsProcessed, variantsFailed := w.syncCounters(ctx, variantKeys, variantAccessKeyPrefix, func(ctx context.Context, rawId string, count int) error {
+		id, err := uuid.Parse(rawId)
+		if err != nil {
+			// Uma chave inválida nunca vai sincronizar, não vale restaurar
+			w.logger.Warn("dropping variant counter with invalid id", "variant_id", rawId, "count", count)
+			return nil
+		}
+		return w.db.IncrementVariantAccessCount(ctx, pgstore.IncrementVariantAccessCountParams{
+			ID:          id,
+			AccessCount: int32(count),
+		})
+	})
+
+	scansProcessed, scansFailed := w.syncCounters(ctx, scanKeys, scanAccessKeyPrefix, func(ctx context.Context, key string, count int) error {
+		domainId, slug, ok := w.parseLinkKey(key, count)
+		if !ok {
+			return nil
+		}
+		return w.db.IncrementScanCount(ctx, pgstore.IncrementScanCountParams{
+			Slug:      slug,
+			DomainID:  domainId,
+			ScanCount: int32(count),
+		})
+	})
+
+	duration := time.Since(start)
+	w.logger.Info("access count processing completed",
+		"processed", processed+variantsProcessed+scansProcessed,
+		"failed", failed+variantsFailed+scansFailed,
+		"duration", duration,
+		"keys_count", len(keys)+len(variantKeys)+len(scanKeys),
+	)
+}
+
+// syncCounters moves each Redis counter to the database with save, restoring
+// it when the database update fails so no click is lost
+func (w *AccessSyncWorker) syncCounters(ctx context.Context, keys []string, prefix string, save func(ctx context.Context, id string, count int) error) (int, int) {
+	processed := 0
+	failed := 0
+	var mu sync.Mutex
+
 	sem := make(chan struct{}, w.concurrency)
 	var wg sync.WaitGroup
 
@@ -110,12 +176,12 @@ func (w *AccessSyncWorker) processAccessCount(ctx context.Context) {
 			break
 		}
 
-		slug := key[len(accessKeyPrefix):]
+		id := key[len(prefix):]
 
 		wg.Add(1)
 		sem <- struct{}{}
 
-		go func(slug, key string) {
+		go func(id, key string) {
 			defer func() {
 				<-sem
 				wg.Done()
@@ -133,20 +199,14 @@ func (w *AccessSyncWorker) processAccessCount(ctx context.Context) {
 				return
 			}
 
-			err = w.db.IncrementAccessCount(ctx, pgstore.IncrementAccessCountParams{
-				Slug: slug,
-				AccessCount: pgtype.Int4{
-					Int32: int32(count),
-					Valid: true,
-				},
-			})
+			err = save(ctx, id, count)
 
 			if err != nil {
-				w.logger.Error("failed to update access count in database", "slug", slug, "count", count, "error", err)
+				w.logger.Error("failed to update access count in database", "key", key, "count", count, "error", err)
 
 				restoreErr := w.counter.RestoreCounter(ctx, key, int64(count))
 				if restoreErr != nil {
-					w.logger.Error("critical: failed to restore counter after DB error", "slug", slug, "count", count, "error", restoreErr)
+					w.logger.Error("critical: failed to restore counter after DB error", "key", key, "count", count, "error", restoreErr)
 				}
 
 				mu.Lock()
@@ -158,13 +218,28 @@ func (w *AccessSyncWorker) processAccessCount(ctx context.Context) {
 			mu.Lock()
 			processed++
 			mu.Unlock()
-		}(slug, key)
+		}(id, key)
 	}
 
 	wg.Wait()
 
-	duration := time.Since(start)
-	w.logger.Info("access count processing completed", "processed", processed, "failed", failed, "duration", duration, "keys_count", len(keys))
+	return processed, failed
+}
+
+// parseLinkKey splits the key of a link counter into its domain and slug, see
+// models.LinkKey. A key with an invalid domain id never syncs and is dropped
+func (w *AccessSyncWorker) parseLinkKey(key string, count int) (pgtype.UUID, string, bool) {
+	rawDomainId, slug := models.ParseLinkKey(key)
+	if rawDomainId == nil {
+		return pgtype.UUID{}, slug, true
+	}
+
+	domainId, err := uuid.Parse(*rawDomainId)
+	if err != nil {
+		w.logger.Warn("dropping access counter with invalid domain id", "key", key, "count", count)
+		return pgtype.UUID{}, "", false
+	}
+	return pgtype.UUID{Bytes: domainId, Valid: true}, slug, true
 }
 
 func StartHourlyAccessSyncWorker(db *pgstore.Queries, rdb *redis.Client) {
@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/services"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const (
+	bulkJobInterval = 10 * time.Second
+	// A job still running when the timeout hits is resumed by a later run
+	bulkJobRunTimeout = 30 * time.Minute
+)
+
+// BulkJobWorker creates the links of the uploads too large to be created
+// while the request waits, one job after the other
+type BulkJobWorker struct {
+	jobs         ports.BulkUseCase
+	logger       *slog.Logger
+	interval     time.Duration
+	shotdownChan chan struct{}
+	wg           sync.WaitGroup
+}
+
+func NewBulkJobWorker(jobs ports.BulkUseCase) *BulkJobWorker {
+	return &BulkJobWorker{
+		jobs:         jobs,
+		logger:       slog.Default().With("component", "bulk_job_worker"),
+		interval:     bulkJobInterval,
+		shotdownChan: make(chan struct{}),
+	}
+}
+
+func (w *BulkJobWorker) Start() error {
+	if w.jobs == nil {
+		return wraperrors.InternalErr("Cannot start bulk job worker without a bulk service", nil)
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		w.logger.Info("starting bulk job worker", "interval", w.interval)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), bulkJobRunTimeout)
+				w.ProcessPending(ctx)
+				cancel()
+
+			case <-w.shotdownChan:
+				w.logger.Info("bulk job worker shutting down")
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (w *BulkJobWorker) Stop() {
+	close(w.shotdownChan)
+	w.wg.Wait()
+	w.logger.Info("bulk job worker stopped")
+}
+
+// ProcessPending runs the unfinished jobs until there are none left and
+// returns how many were run
+func (w *BulkJobWorker) ProcessPending(ctx context.Context) int {
+	processed := 0
+	for ctx.Err() == nil {
+		found, err := w.jobs.ProcessNextBulkJob(ctx)
+		if err != nil {
+			w.logger.Error("bulk job failed", "error", err)
+		}
+		if !found {
+			break
+		}
+		processed++
+	}
+
+	if processed > 0 {
+		w.logger.Info("bulk jobs processed", "jobs", processed)
+	}
+	return processed
+}
+
+func StartBulkJobWorker(db *pgstore.Queries) {
+	if db == nil {
+		slog.Error("cannot start bulk job worker with nil database")
+		return
+	}
+
+	worker := NewBulkJobWorker(services.NewBulkService(db, infra.NewDestinationPolicyFromEnv()))
+	if err := worker.Start(); err != nil {
+		slog.Error("failed to start bulk job worker", "error", err)
+	}
+}
@@ -0,0 +1,344 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const (
+	healthCheckTick        = 1 * time.Minute
+	healthCheckRunTimeout  = 10 * time.Minute
+	healthCheckBatchSize   = 100
+	healthCheckConcurrency = 10
+	maxHealthErrorSize     = 500
+
+	defaultHealthCheckInterval    = 6 * time.Hour
+	defaultHealthRetryInterval    = 15 * time.Minute
+	defaultHealthFailureThreshold = 3
+	defaultHealthHostInterval     = 1 * time.Second
+)
+
+// HealthCheckOptions tunes how often destinations are checked and when their
+// owners are told they are broken
+type HealthCheckOptions struct {
+	// Interval between checks of a healthy destination
+	Interval time.Duration
+	// RetryInterval between checks of a failing destination
+	RetryInterval time.Duration
+	// FailureThreshold is how many consecutive failed checks make a link
+	// broken and fire the link.broken webhooks
+	FailureThreshold int
+	// HostInterval is the minimum time between two requests to the same host
+	HostInterval time.Duration
+}
+
+// HealthCheckOptionsFromEnv reads HEALTH_CHECK_INTERVAL,
+// HEALTH_CHECK_RETRY_INTERVAL, HEALTH_CHECK_FAILURE_THRESHOLD and
+// HEALTH_CHECK_HOST_INTERVAL, falling back to the defaults
+func HealthCheckOptionsFromEnv() HealthCheckOptions {
+	options := HealthCheckOptions{
+		Interval:         envDuration("HEALTH_CHECK_INTERVAL", defaultHealthCheckInterval),
+		RetryInterval:    envDuration("HEALTH_CHECK_RETRY_INTERVAL", defaultHealthRetryInterval),
+		FailureThreshold: defaultHealthFailureThreshold,
+		HostInterval:     envDuration("HEALTH_CHECK_HOST_INTERVAL", defaultHealthHostInterval),
+	}
+	if raw := os.Getenv("HEALTH_CHECK_FAILURE_THRESHOLD"); raw != "" {
+		if threshold, err := strconv.Atoi(raw); err == nil && threshold > 0 {
+			options.FailureThreshold = threshold
+		}
+	}
+
+	return options
+}
+
+// HealthCheckWorker periodically requests the destination of every active
+// link, records what it answered and notifies the owner's webhooks when a
+// destination breaks or recovers
+type HealthCheckWorker struct {
+	db           *pgstore.Queries
+	checker      *infra.HealthChecker
+	sender       *infra.WebhookSender
+	options      HealthCheckOptions
+	limiter      *hostLimiter
+	logger       *slog.Logger
+	interval     time.Duration
+	concurrency  int
+	shotdownChan chan struct{}
+	wg           sync.WaitGroup
+}
+
+func NewHealthCheckWorker(db *pgstore.Queries, checker *infra.HealthChecker, sender *infra.WebhookSender, options HealthCheckOptions) *HealthCheckWorker {
+	return &HealthCheckWorker{
+		db:           db,
+		checker:      checker,
+		sender:       sender,
+		options:      options,
+		limiter:      newHostLimiter(options.HostInterval),
+		logger:       slog.Default().With("component", "health_check_worker"),
+		interval:     healthCheckTick,
+		concurrency:  healthCheckConcurrency,
+		shotdownChan: make(chan struct{}),
+	}
+}
+
+func (w *HealthCheckWorker) Start() error {
+	if w.db == nil {
+		return wraperrors.InternalErr("Cannot start health check worker with nil database", nil)
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		w.logger.Info("starting health check worker", "interval", w.options.Interval, "failure_threshold", w.options.FailureThreshold)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), healthCheckRunTimeout)
+				w.CheckDue(ctx)
+				cancel()
+
+			case <-w.shotdownChan:
+				w.logger.Info("health check worker shutting down")
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (w *HealthCheckWorker) Stop() {
+	close(w.shotdownChan)
+	w.wg.Wait()
+	w.logger.Info("health check worker stopped")
+}
+
+// CheckDue checks one batch of links whose next check is due and returns how
+// many were checked
+func (w *HealthCheckWorker) CheckDue(ctx context.Context) int {
+	due, err := w.db.ListDueHealthChecks(ctx, healthCheckBatchSize)
+	if err != nil {
+		w.logger.Error("failed to list due health checks", "error", err)
+		return 0
+	}
+	if len(due) == 0 {
+		return 0
+	}
+
+	checked := 0
+	var mu sync.Mutex
+
+	sem := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+
+	for _, row := range due {
+		if ctx.Err() != nil {
+			w.logger.Warn("context canceled while checking destinations", "error", ctx.Err())
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(row pgstore.ListDueHealthChecksRow) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if w.check(ctx, row) {
+				mu.Lock()
+				checked++
+				mu.Unlock()
+			}
+		}(row)
+	}
+
+	wg.Wait()
+	w.limiter.prune()
+
+	w.logger.Info("health checks completed", "due", len(due), "checked", checked)
+	return checked
+}
+
+func (w *HealthCheckWorker) check(ctx context.Context, row pgstore.ListDueHealthChecksRow) bool {
+	host := row.OriginalUrl
+	if u, err := url.Parse(row.OriginalUrl); err == nil {
+		host = u.Host
+	}
+	if err := w.limiter.wait(ctx, host); err != nil {
+		return false
+	}
+
+	result := w.checker.Check(ctx, row.OriginalUrl)
+	healthy := result.Healthy()
+
+	params := pgstore.SaveShortUrlHealthParams{
+		ShortUrlID:  row.ID,
+		Url:         row.OriginalUrl,
+		Healthy:     healthy,
+		LatencyMs:   pgtype.Int4{Int32: int32(result.Latency.Milliseconds()), Valid: true},
+		NextCheckAt: pgtype.Timestamptz{Time: time.Now().Add(w.options.Interval), Valid: true},
+	}
+	if result.StatusCode > 0 {
+		params.StatusCode = pgtype.Int4{Int32: int32(result.StatusCode), Valid: true}
+		params.FinalUrl = pgtype.Text{String: result.FinalUrl, Valid: true}
+	}
+	if !healthy {
+		params.NextCheckAt.Time = time.Now().Add(w.options.RetryInterval)
+		message := "destination answered with an error status"
+		if result.Err != nil {
+			message = result.Err.Error()
+		}
+		if len(message) > maxHealthErrorSize {
+			message = message[:maxHealthErrorSize]
+		}
+		params.LastError = pgtype.Text{String: message, Valid: true}
+	}
+
+	saved, err := w.db.SaveShortUrlHealth(ctx, params)
+	if err != nil {
+		// the link was deleted or got another destination while it was checked
+		if !errors.Is(err, pgx.ErrNoRows) {
+			w.logger.Error("failed to save health check", "short_url_id", row.ID, "error", err)
+		}
+		return false
+	}
+
+	switch {
+	case !healthy && int(saved.ConsecutiveFailures) >= w.options.FailureThreshold && !saved.NotifiedAt.Valid:
+		w.logger.Warn("destination is broken", "short_url_id", row.ID, "failures", saved.ConsecutiveFailures)
+		w.notify(ctx, row, saved, models.WebhookEventLinkBroken)
+		if err := w.db.MarkShortUrlHealthNotified(ctx, row.ID); err != nil {
+			w.logger.Error("failed to mark broken link as notified", "short_url_id", row.ID, "error", err)
+		}
+	case healthy && row.NotifiedAt.Valid:
+		w.logger.Info("destination recovered", "short_url_id", row.ID)
+		w.notify(ctx, row, saved, models.WebhookEventLinkRecovered)
+	}
+
+	return true
+}
+
+// notify delivers the event to every webhook of the link owner, a failed
+// delivery is logged and not retried
+func (w *HealthCheckWorker) notify(ctx context.Context, row pgstore.ListDueHealthChecksRow, saved pgstore.ShortUrlHealth, event string) {
+	if w.sender == nil || !row.UserID.Valid {
+		return
+	}
+
+	webhooks, err := w.db.ListWebhooksByUserId(ctx, uuid.UUID(row.UserID.Bytes))
+	if err != nil {
+		w.logger.Error("failed to list webhooks", "user_id", row.UserID, "error", err)
+		return
+	}
+
+	payload := models.LinkHealthEvent{
+		Event:             event,
+		OccurredAt:        time.Now().UTC(),
+		ShortUrlID:        row.ID.String(),
+		Slug:              row.Slug,
+		OriginalUrl:       row.OriginalUrl,
+		DestinationHealth: models.NewDestinationHealth(saved),
+	}
+	for _, webhook := range webhooks {
+		if err := w.sender.Send(ctx, webhook.Url, webhook.Secret, event, payload); err != nil {
+			w.logger.Warn("failed to deliver webhook", "webhook_id", webhook.ID, "event", event, "error", err)
+		}
+	}
+}
+
+// hostLimiter spaces requests to the same host, each caller reserves the
+// next free slot of its host and sleeps until then
+type hostLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     map[string]time.Time
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{interval: interval, next: map[string]time.Time{}}
+}
+
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[host] = at.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// prune forgets the hosts whose slot is already free
+func (l *hostLimiter) prune() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for host, at := range l.next {
+		if at.Before(now) {
+			delete(l.next, host)
+		}
+	}
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	if raw := os.Getenv(key); raw != "" {
+		if value, err := time.ParseDuration(raw); err == nil && value > 0 {
+			return value
+		}
+	}
+	return fallback
+}
+
+func StartHealthCheckWorker(db *pgstore.Queries) {
+	if db == nil {
+		slog.Error("cannot start health check worker with nil database")
+		return
+	}
+
+	worker := NewHealthCheckWorker(
+		db,
+		infra.NewHealthChecker(infra.SafeHTTPOptions{Timeout: envDuration("HEALTH_CHECK_TIMEOUT", 0)}),
+		infra.NewWebhookSender(infra.SafeHTTPOptions{}),
+		HealthCheckOptionsFromEnv(),
+	)
+	if err := worker.Start(); err != nil {
+		slog.Error("failed to start health check worker", "error", err)
+	}
+}
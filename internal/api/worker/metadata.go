@@ -0,0 +1,194 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/infra"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const (
+	metadataInterval     = 30 * time.Second
+	metadataTimeout      = 2 * time.Minute
+	metadataBatchSize    = 50
+	metadataConcurrency  = 5
+	maxMetadataAttempts  = 5
+	metadataRetryBackoff = 1 * time.Minute
+	maxMetadataErrorSize = 500
+)
+
+// MetadataWorker fetches the destination metadata requested when links are
+// created or change their original URL. Failures are retried with an
+// exponential backoff until they run out of attempts
+type MetadataWorker struct {
+	db           *pgstore.Queries
+	fetcher      *infra.MetadataFetcher
+	logger       *slog.Logger
+	interval     time.Duration
+	concurrency  int
+	shotdownChan chan struct{}
+	wg           sync.WaitGroup
+}
+
+func NewMetadataWorker(db *pgstore.Queries, fetcher *infra.MetadataFetcher) *MetadataWorker {
+	return &MetadataWorker{
+		db:           db,
+		fetcher:      fetcher,
+		logger:       slog.Default().With("component", "metadata_worker"),
+		interval:     metadataInterval,
+		concurrency:  metadataConcurrency,
+		shotdownChan: make(chan struct{}),
+	}
+}
+
+func (w *MetadataWorker) Start() error {
+	if w.db == nil {
+		return wraperrors.InternalErr("Cannot start metadata worker with nil database", nil)
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		w.logger.Info("starting metadata worker", "interval", w.interval, "concurrency", w.concurrency)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+				w.ProcessPending(ctx)
+				cancel()
+
+			case <-w.shotdownChan:
+				w.logger.Info("metadata worker shutting down")
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (w *MetadataWorker) Stop() {
+	close(w.shotdownChan)
+	w.wg.Wait()
+	w.logger.Info("metadata worker stopped")
+}
+
+// ProcessPending fetches one batch of pending metadata and returns how many
+// were fetched
+func (w *MetadataWorker) ProcessPending(ctx context.Context) int {
+	pending, err := w.db.ListPendingShortUrlMetadata(ctx, metadataBatchSize)
+	if err != nil {
+		w.logger.Error("failed to list pending metadata", "error", err)
+		return 0
+	}
+	if len(pending) == 0 {
+		return 0
+	}
+
+	fetched := 0
+	var mu sync.Mutex
+
+	sem := make(chan struct{}, w.concurrency)
+	var wg sync.WaitGroup
+
+	for _, row := range pending {
+		if ctx.Err() != nil {
+			w.logger.Warn("context canceled while fetching metadata", "error", ctx.Err())
+			break
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(row pgstore.ShortUrlMetadatum) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if w.fetch(ctx, row) {
+				mu.Lock()
+				fetched++
+				mu.Unlock()
+			}
+		}(row)
+	}
+
+	wg.Wait()
+
+	w.logger.Info("metadata processing completed", "pending", len(pending), "fetched", fetched)
+	return fetched
+}
+
+func (w *MetadataWorker) fetch(ctx context.Context, row pgstore.ShortUrlMetadatum) bool {
+	metadata, fetchErr := w.fetcher.Fetch(ctx, row.Url)
+	if fetchErr == nil {
+		err := w.db.SaveShortUrlMetadata(ctx, pgstore.SaveShortUrlMetadataParams{
+			ShortUrlID:  row.ShortUrlID,
+			Url:         row.Url,
+			Title:       optionalText(metadata.Title),
+			Description: optionalText(metadata.Description),
+			ImageUrl:    optionalText(metadata.ImageUrl),
+			FaviconUrl:  optionalText(metadata.FaviconUrl),
+		})
+		if err != nil {
+			w.logger.Error("failed to save metadata", "short_url_id", row.ShortUrlID, "error", err)
+			return false
+		}
+		return true
+	}
+
+	attempts := int(row.Attempts) + 1
+	status := models.MetadataStatusPending
+	if attempts >= maxMetadataAttempts {
+		status = models.MetadataStatusFailed
+	}
+	// 1m, 4m, 16m, 64m...
+	nextAttempt := time.Now().Add(metadataRetryBackoff << (2 * (attempts - 1)))
+
+	message := fetchErr.Error()
+	if len(message) > maxMetadataErrorSize {
+		message = message[:maxMetadataErrorSize]
+	}
+
+	w.logger.Warn("failed to fetch metadata", "short_url_id", row.ShortUrlID, "attempts", attempts, "status", status, "error", fetchErr)
+	err := w.db.FailShortUrlMetadata(ctx, pgstore.FailShortUrlMetadataParams{
+		ShortUrlID:    row.ShortUrlID,
+		Url:           row.Url,
+		Status:        status,
+		LastError:     pgtype.Text{String: message, Valid: true},
+		NextAttemptAt: pgtype.Timestamptz{Time: nextAttempt, Valid: true},
+	})
+	if err != nil {
+		w.logger.Error("failed to record metadata failure", "short_url_id", row.ShortUrlID, "error", err)
+	}
+
+	return false
+}
+
+func optionalText(value string) pgtype.Text {
+	return pgtype.Text{String: value, Valid: value != ""}
+}
+
+func StartMetadataWorker(db *pgstore.Queries) {
+	if db == nil {
+		slog.Error("cannot start metadata worker with nil database")
+		return
+	}
+
+	worker := NewMetadataWorker(db, infra.NewMetadataFetcher(infra.SafeHTTPOptions{}))
+	if err := worker.Start(); err != nil {
+		slog.Error("failed to start metadata worker", "error", err)
+	}
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const (
+	purgeInterval    = 24 * time.Hour
+	purgeTimeout     = 5 * time.Minute
+	defaultRetention = 30 * 24 * time.Hour
+)
+
+// PurgeDeletedWorker hard-deletes users and short URLs that were soft deleted
+// longer than the retention period ago
+type PurgeDeletedWorker struct {
+	db           *pgstore.Queries
+	logger       *slog.Logger
+	interval     time.Duration
+	retention    time.Duration
+	shotdownChan chan struct{}
+	wg           sync.WaitGroup
+}
+
+func NewPurgeDeletedWorker(db *pgstore.Queries) *PurgeDeletedWorker {
+	return &PurgeDeletedWorker{
+		db:           db,
+		logger:       slog.Default().With("component", "purge_deleted_worker"),
+		interval:     purgeInterval,
+		retention:    retentionFromEnv(),
+		shotdownChan: make(chan struct{}),
+	}
+}
+
+func (w *PurgeDeletedWorker) Start() error {
+	if w.db == nil {
+		return wraperrors.InternalErr("Cannot start purge worker with nil database", nil)
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		w.logger.Info("starting purge deleted worker", "interval", w.interval, "retention", w.retention)
+
+		ctx, cancel := context.WithTimeout(context.Background(), purgeTimeout)
+		w.purge(ctx)
+		cancel()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), purgeTimeout)
+				w.purge(ctx)
+				cancel()
+
+			case <-w.shotdownChan:
+				w.logger.Info("purge deleted worker shutting down")
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (w *PurgeDeletedWorker) Stop() {
+	close(w.shotdownChan)
+	w.wg.Wait()
+	w.logger.Info("purge deleted worker stopped")
+}
+
+func (w *PurgeDeletedWorker) purge(ctx context.Context) {
+	cutoff := pgtype.Timestamptz{Time: time.Now().Add(-w.retention), Valid: true}
+
+	// Links first: purging a user removes its remaining personal links anyway
+	shortUrls, err := w.db.PurgeDeletedShortUrls(ctx, cutoff)
+	if err != nil {
+		w.logger.Error("failed to purge deleted short urls", "error", err)
+		return
+	}
+
+	users, err := w.db.PurgeDeletedUsers(ctx, cutoff)
+	if err != nil {
+		w.logger.Error("failed to purge deleted users", "error", err)
+		return
+	}
+
+	w.logger.Info("purge deleted completed", "short_urls", shortUrls, "users", users)
+}
+
+func retentionFromEnv() time.Duration {
+	if raw := os.Getenv("SOFT_DELETE_RETENTION"); raw != "" {
+		if retention, err := time.ParseDuration(raw); err == nil && retention > 0 {
+			return retention
+		}
+		slog.Warn("invalid SOFT_DELETE_RETENTION, using default", "value", raw, "default", defaultRetention)
+	}
+	return defaultRetention
+}
+
+func StartDailyPurgeDeletedWorker(db *pgstore.Queries) {
+	if db == nil {
+		slog.Error("cannot start purge worker with nil database")
+		return
+	}
+
+	worker := NewPurgeDeletedWorker(db)
+	if err := worker.Start(); err != nil {
+		slog.Error("failed to start purge deleted worker", "error", err)
+	}
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DailyStat counts the clicks and QR scans of a link on one UTC day, Day is
+// written as 2006-01-02
+type DailyStat struct {
+	ShortUrlID string `json:"short_url_id"`
+	Slug       string `json:"slug"`
+	Day        string `json:"day"`
+	Clicks     int    `json:"clicks"`
+	Scans      int    `json:"scans"`
+}
+
+// The columns of the CSV files of an account export. The links file can be
+// imported back, tags are separated by semicolons
+var (
+	ExportLinksCsvHeader      = []string{"id", "slug", "original_url", "created_at", "expires_at", "access_count", "scan_count", "workspace_id", "folder_id", "campaign_id", "tags"}
+	ExportTagsCsvHeader       = []string{"id", "name", "created_at", "link_count"}
+	ExportDailyStatsCsvHeader = []string{"short_url_id", "slug", "day", "clicks", "scans"}
+)
+
+// ExportCsvRecord lays out a link as a row of the links file of an account
+// export
+func (s *ShortUrl) ExportCsvRecord() []string {
+	tags := make([]string, 0, len(s.Tags))
+	for _, tag := range s.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	return []string{
+		s.ID,
+		s.Slug,
+		s.OriginalUrl,
+		s.CreatedAt.UTC().Format(time.RFC3339),
+		formatExportTime(s.ExpiresAt),
+		strconv.Itoa(s.AccessCount),
+		strconv.Itoa(s.ScanCount),
+		derefString(s.WorkspaceID),
+		derefString(s.FolderID),
+		derefString(s.CampaignID),
+		strings.Join(tags, ";"),
+	}
+}
+
+func (t *Tag) CsvRecord() []string {
+	return []string{t.ID, t.Name, t.CreatedAt.UTC().Format(time.RFC3339), strconv.FormatInt(t.LinkCount, 10)}
+}
+
+func (d *DailyStat) CsvRecord() []string {
+	return []string{d.ShortUrlID, d.Slug, d.Day, strconv.Itoa(d.Clicks), strconv.Itoa(d.Scans)}
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditEntityUser     = "user"
+	AuditEntityShortUrl = "short_url"
+
+	AuditActionCreate   = "create"
+	AuditActionUpdate   = "update"
+	AuditActionDelete   = "delete"
+	AuditActionRestore  = "restore"
+	AuditActionTransfer = "transfer"
+	AuditActionImport   = "import"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+type AuditLog struct {
+	ID         string          `json:"id"`
+	ActorID    string          `json:"actor_id,omitempty"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Action     string          `json:"action"`
+	Changes    json.RawMessage `json:"changes" swaggertype:"object"`
+	RequestID  string          `json:"request_id,omitempty"`
+	IPAddress  string          `json:"ip_address,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// AuditChange holds the value of one field before and after a change,
+// a nil side means the entity didn't exist yet or anymore
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditLogPage struct {
+	Entries []*AuditLog `json:"entries"`
+	Limit   int         `json:"limit"`
+	Offset  int         `json:"offset"`
+	HasMore bool        `json:"has_more"`
+}
+
+type PageInput struct {
+	Limit  int
+	Offset int
+}
+
+// Normalize applies the default limit and clamps out of range values
+func (p PageInput) Normalize() PageInput {
+	if p.Limit <= 0 {
+		p.Limit = DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		p.Limit = MaxPageLimit
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+	return p
+}
+
+// RequestMeta identifies the HTTP request behind a change for the audit log
+type RequestMeta struct {
+	RequestID string
+	ClientIP  string
+}
+
+type requestMetaKey struct{}
+
+func WithRequestMeta(ctx context.Context, meta RequestMeta) context.Context {
+	return context.WithValue(ctx, requestMetaKey{}, meta)
+}
+
+func RequestMetaFromContext(ctx context.Context) RequestMeta {
+	meta, _ := ctx.Value(requestMetaKey{}).(RequestMeta)
+	return meta
+}
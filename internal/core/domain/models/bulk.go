@@ -0,0 +1,227 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxBulkRows is the most links one upload may create
+	MaxBulkRows = 100000
+	// BulkSyncLimit is the most rows created while the request waits, larger
+	// uploads run as a job
+	BulkSyncLimit = 1000
+
+	BulkJobStatusPending   = "pending"
+	BulkJobStatusRunning   = "running"
+	BulkJobStatusCompleted = "completed"
+	BulkJobStatusFailed    = "failed"
+)
+
+// BulkRowInput is one row of an upload numbered from 1, not counting the CSV
+// header. Errors lists the cells that could not be read, such rows are
+// reported as failed without being validated
+type BulkRowInput struct {
+	Row    int
+	Input  *CreateShortUrlInput
+	Errors []ValidationError
+}
+
+// BulkRowResult is the outcome of one row: the link it created or why it
+// was refused, with the same errors a single creation reports
+type BulkRowResult struct {
+	Row         int               `json:"row"`
+	ID          string            `json:"id,omitempty"`
+	Slug        string            `json:"slug,omitempty"`
+	OriginalUrl string            `json:"original_url"`
+	Errors      []ValidationError `json:"errors,omitempty"`
+}
+
+// BulkResult answers an upload created while the request waited
+type BulkResult struct {
+	Created int              `json:"created"`
+	Failed  int              `json:"failed"`
+	Rows    []*BulkRowResult `json:"rows"`
+}
+
+// BulkJob is an upload being created in the background, its results can be
+// downloaded as CSV as the rows are processed
+type BulkJob struct {
+	ID            string     `json:"id"`
+	Status        string     `json:"status"`
+	TotalRows     int        `json:"total_rows"`
+	ProcessedRows int        `json:"processed_rows"`
+	CreatedRows   int        `json:"created_rows"`
+	FailedRows    int        `json:"failed_rows"`
+	Error         string     `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// BulkResultsCsvHeader names the columns of the results download
+var BulkResultsCsvHeader = []string{"row", "original_url", "short_url_id", "slug", "errors"}
+
+// CsvRecord lays out a result as a row of the results download, errors are
+// written as field:tag separated by semicolons
+func (r *BulkRowResult) CsvRecord() []string {
+	errs := make([]string, 0, len(r.Errors))
+	for _, verr := range r.Errors {
+		errs = append(errs, verr.Field+":"+verr.Tag)
+	}
+
+	return []string{strconv.Itoa(r.Row), r.OriginalUrl, r.ID, r.Slug, strings.Join(errs, ";")}
+}
+
+var errTooManyBulkRows = fmt.Errorf("an upload may have at most %d rows", MaxBulkRows)
+
+// DecodeBulkJson reads a JSON array of links one element at a time. An
+// element with a value of the wrong type becomes a row error, malformed JSON
+// fails the whole upload
+func DecodeBulkJson(r io.Reader) ([]*BulkRowInput, error) {
+	decoder := json.NewDecoder(r)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, errors.New("expected a JSON array of links")
+	}
+
+	var rows []*BulkRowInput
+	for decoder.More() {
+		if len(rows) == MaxBulkRows {
+			return nil, errTooManyBulkRows
+		}
+
+		row := &BulkRowInput{Row: len(rows) + 1, Input: &CreateShortUrlInput{}}
+		if err := decoder.Decode(row.Input); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return nil, err
+			}
+			row.Errors = []ValidationError{{Field: typeErr.Field, Tag: "type", Value: typeErr.Value}}
+		}
+		rows = append(rows, row)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// bulkCsvColumns sets the field of each CSV column from a non empty cell,
+// the columns are named like the JSON fields. Tag IDs are separated by
+// semicolons and variants can't be given in a CSV
+var bulkCsvColumns = map[string]func(input *CreateShortUrlInput, value string) error{
+	"original_url": func(i *CreateShortUrlInput, v string) error { i.OriginalUrl = v; return nil },
+	"expires_at":   func(i *CreateShortUrlInput, v string) error { i.ExpiresAt = &v; return nil },
+	"workspace_id": func(i *CreateShortUrlInput, v string) error { i.WorkspaceID = &v; return nil },
+	"password":     func(i *CreateShortUrlInput, v string) error { i.Password = v; return nil },
+	"max_clicks": func(i *CreateShortUrlInput, v string) error {
+		n, err := strconv.Atoi(v)
+		i.MaxClicks = &n
+		return err
+	},
+	"starts_at":         func(i *CreateShortUrlInput, v string) error { i.StartsAt = &v; return nil },
+	"inactive_fallback": func(i *CreateShortUrlInput, v string) error { i.InactiveFallback = v; return nil },
+	"inactive_url":      func(i *CreateShortUrlInput, v string) error { i.InactiveUrl = &v; return nil },
+	"redirect_status": func(i *CreateShortUrlInput, v string) error {
+		var err error
+		i.RedirectStatus, err = strconv.Atoi(v)
+		return err
+	},
+	"forward_query": func(i *CreateShortUrlInput, v string) error {
+		var err error
+		i.ForwardQuery, err = strconv.ParseBool(v)
+		return err
+	},
+	"query_merge":  func(i *CreateShortUrlInput, v string) error { i.QueryMerge = v; return nil },
+	"campaign_id":  func(i *CreateShortUrlInput, v string) error { i.CampaignID = &v; return nil },
+	"utm_source":   func(i *CreateShortUrlInput, v string) error { i.UtmSource = v; return nil },
+	"utm_medium":   func(i *CreateShortUrlInput, v string) error { i.UtmMedium = v; return nil },
+	"utm_campaign": func(i *CreateShortUrlInput, v string) error { i.UtmCampaign = v; return nil },
+	"utm_term":     func(i *CreateShortUrlInput, v string) error { i.UtmTerm = v; return nil },
+	"utm_content":  func(i *CreateShortUrlInput, v string) error { i.UtmContent = v; return nil },
+	"interstitial": func(i *CreateShortUrlInput, v string) error {
+		var err error
+		i.Interstitial, err = strconv.ParseBool(v)
+		return err
+	},
+	"folder_id": func(i *CreateShortUrlInput, v string) error { i.FolderID = &v; return nil },
+	"tag_ids": func(i *CreateShortUrlInput, v string) error {
+		for _, id := range strings.Split(v, ";") {
+			if id = strings.TrimSpace(id); id != "" {
+				i.TagIDs = append(i.TagIDs, id)
+			}
+		}
+		return nil
+	},
+}
+
+// DecodeBulkCsv reads a CSV upload one record at a time. The first record
+// names the columns, original_url is required. Cells that can't be read
+// become row errors, a malformed CSV fails the whole upload
+func DecodeBulkCsv(r io.Reader) ([]*BulkRowInput, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the CSV has no header")
+		}
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if _, ok := bulkCsvColumns[column]; !ok {
+			return nil, fmt.Errorf("unknown CSV column %q", column)
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("repeated CSV column %q", column)
+		}
+		seen[column] = true
+		header[i] = column
+	}
+	if !seen["original_url"] {
+		return nil, errors.New("the CSV must have an original_url column")
+	}
+
+	var rows []*BulkRowInput
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == MaxBulkRows {
+			return nil, errTooManyBulkRows
+		}
+
+		row := &BulkRowInput{Row: len(rows) + 1, Input: &CreateShortUrlInput{}}
+		if len(record) > len(header) {
+			row.Errors = append(row.Errors, ValidationError{Field: "row", Tag: "columns", Value: strconv.Itoa(len(record))})
+		}
+		for i, column := range header {
+			if i >= len(record) || strings.TrimSpace(record[i]) == "" {
+				continue
+			}
+			value := strings.TrimSpace(record[i])
+			if err := bulkCsvColumns[column](row.Input, value); err != nil {
+				row.Errors = append(row.Errors, ValidationError{Field: column, Tag: "type", Value: value})
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UtmParams are the UTM parameters tagged onto a destination URL, empty
+// values are left out
+type UtmParams struct {
+	UtmSource   string `json:"utm_source,omitempty" validate:"max=200"`
+	UtmMedium   string `json:"utm_medium,omitempty" validate:"max=200"`
+	UtmCampaign string `json:"utm_campaign,omitempty" validate:"max=200"`
+	UtmTerm     string `json:"utm_term,omitempty" validate:"max=200"`
+	UtmContent  string `json:"utm_content,omitempty" validate:"max=200"`
+}
+
+// Campaign groups links of a user and holds the UTM values they default to
+type Campaign struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	UtmParams
+	CreatedAt time.Time      `json:"created_at"`
+	Stats     *CampaignStats `json:"stats,omitempty"`
+}
+
+// CampaignStats aggregates the live links of a campaign
+type CampaignStats struct {
+	LinkCount int64 `json:"link_count"`
+	Clicks    int64 `json:"clicks"`
+}
+
+type CreateCampaignInput struct {
+	Name string `json:"name" validate:"required,max=100"`
+	UtmParams
+}
+
+// UpdateCampaignInput replaces the name and UTM defaults, links created
+// before keep the parameters they were tagged with
+type UpdateCampaignInput struct {
+	Name string `json:"name" validate:"required,max=100"`
+	UtmParams
+}
+
+// WithDefaults fills the empty values from defaults
+func (u UtmParams) WithDefaults(defaults UtmParams) UtmParams {
+	pick := func(value, fallback string) string {
+		if value != "" {
+			return value
+		}
+		return fallback
+	}
+
+	return UtmParams{
+		UtmSource:   pick(u.UtmSource, defaults.UtmSource),
+		UtmMedium:   pick(u.UtmMedium, defaults.UtmMedium),
+		UtmCampaign: pick(u.UtmCampaign, defaults.UtmCampaign),
+		UtmTerm:     pick(u.UtmTerm, defaults.UtmTerm),
+		UtmContent:  pick(u.UtmContent, defaults.UtmContent),
+	}
+}
+
+func (u UtmParams) IsEmpty() bool {
+	return u == UtmParams{}
+}
+
+// ApplyTo sets the UTM parameters on rawUrl. Parameters already in the URL
+// keep their position and encoding, a UTM key present in both takes the
+// given value once and the fragment is preserved
+func (u UtmParams) ApplyTo(rawUrl string) (string, error) {
+	if u.IsEmpty() {
+		return rawUrl, nil
+	}
+
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+
+	values := []struct{ key, value string }{
+		{"utm_source", u.UtmSource},
+		{"utm_medium", u.UtmMedium},
+		{"utm_campaign", u.UtmCampaign},
+		{"utm_term", u.UtmTerm},
+		{"utm_content", u.UtmContent},
+	}
+	pending := map[string]string{}
+	for _, v := range values {
+		if v.value != "" {
+			pending[v.key] = v.value
+		}
+	}
+
+	var parts []string
+	applied := map[string]bool{}
+	if parsed.RawQuery != "" {
+		for _, part := range strings.Split(parsed.RawQuery, "&") {
+			rawKey, _, _ := strings.Cut(part, "=")
+			key, err := url.QueryUnescape(rawKey)
+			if err != nil {
+				key = rawKey
+			}
+			if applied[key] {
+				// Repeated UTM keys would keep the old value around
+				continue
+			}
+			if value, ok := pending[key]; ok {
+				part = url.QueryEscape(key) + "=" + url.QueryEscape(value)
+				delete(pending, key)
+				applied[key] = true
+			}
+			parts = append(parts, part)
+		}
+	}
+	for _, v := range values {
+		if value, ok := pending[v.key]; ok {
+			parts = append(parts, v.key+"="+url.QueryEscape(value))
+		}
+	}
+
+	parsed.RawQuery = strings.Join(parts, "&")
+	parsed.ForceQuery = false
+
+	return parsed.String(), nil
+}
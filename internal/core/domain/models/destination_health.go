@@ -0,0 +1,67 @@
+package models
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+)
+
+const (
+	DestinationStatusHealthy = "healthy"
+	DestinationStatusFailing = "failing"
+)
+
+// HealthCheckResult is what one check of a destination found, Err is set when
+// no response came back at all
+type HealthCheckResult struct {
+	StatusCode int
+	FinalUrl   string
+	Latency    time.Duration
+	Err        error
+}
+
+// Healthy reports whether the destination answered without an error status
+func (r *HealthCheckResult) Healthy() bool {
+	return r.Err == nil && r.StatusCode > 0 && r.StatusCode < http.StatusBadRequest
+}
+
+// DestinationHealth is the latest check of the destination of a link.
+// ConsecutiveFailures counts the checks failed since the last success
+type DestinationHealth struct {
+	Status              string     `json:"status"`
+	StatusCode          *int       `json:"status_code,omitempty"`
+	FinalUrl            string     `json:"final_url,omitempty"`
+	LatencyMs           *int       `json:"latency_ms,omitempty"`
+	Error               string     `json:"error,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CheckedAt           time.Time  `json:"checked_at"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+}
+
+// NewDestinationHealth converts the stored check result
+func NewDestinationHealth(dbHealth pgstore.ShortUrlHealth) *DestinationHealth {
+	health := &DestinationHealth{
+		Status:              DestinationStatusFailing,
+		FinalUrl:            dbHealth.FinalUrl.String,
+		Error:               dbHealth.LastError.String,
+		ConsecutiveFailures: int(dbHealth.ConsecutiveFailures),
+		CheckedAt:           dbHealth.CheckedAt.Time,
+	}
+	if dbHealth.Healthy {
+		health.Status = DestinationStatusHealthy
+	}
+	if dbHealth.StatusCode.Valid {
+		statusCode := int(dbHealth.StatusCode.Int32)
+		health.StatusCode = &statusCode
+	}
+	if dbHealth.LatencyMs.Valid {
+		latency := int(dbHealth.LatencyMs.Int32)
+		health.LatencyMs = &latency
+	}
+	if dbHealth.LastSuccessAt.Valid {
+		health.LastSuccessAt = &dbHealth.LastSuccessAt.Time
+	}
+
+	return health
+}
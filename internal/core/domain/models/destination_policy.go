@@ -0,0 +1,42 @@
+package models
+
+import "fmt"
+
+// Reasons a destination URL is refused, they are reported as the tag of the
+// field that holds the URL
+const (
+	DestinationInvalidUrl       = "invalid_url"
+	DestinationCredentials      = "credentials"
+	DestinationSelfReference    = "self_reference"
+	DestinationDeniedDomain     = "denied_domain"
+	DestinationChainedShortener = "chained_shortener"
+	DestinationBlocklisted      = "blocklisted"
+	DestinationPrivateAddress   = "private_address"
+)
+
+// DestinationViolation tells why the policy refused a destination URL
+type DestinationViolation struct {
+	Reason string
+	Host   string
+}
+
+func (v *DestinationViolation) Error() string {
+	switch v.Reason {
+	case DestinationInvalidUrl:
+		return "destination must be an absolute http or https URL"
+	case DestinationCredentials:
+		return "destination URLs can't carry credentials"
+	case DestinationSelfReference:
+		return fmt.Sprintf("%s is this shortener, the link would redirect to itself", v.Host)
+	case DestinationDeniedDomain:
+		return fmt.Sprintf("%s is not an allowed destination", v.Host)
+	case DestinationChainedShortener:
+		return fmt.Sprintf("%s is a URL shortener, link to the final destination instead", v.Host)
+	case DestinationBlocklisted:
+		return fmt.Sprintf("%s is flagged as malicious", v.Host)
+	case DestinationPrivateAddress:
+		return fmt.Sprintf("%s points to a private or loopback address", v.Host)
+	default:
+		return fmt.Sprintf("%s is not an allowed destination", v.Host)
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const MaxDomainsPerUser = 10
+
+// DomainVerificationPrefix names the DNS TXT record that proves the ownership
+// of a domain, the record is _shortener-verification.<host> and holds
+// shortener-verification=<token>
+const (
+	DomainVerificationPrefix = "_shortener-verification."
+	DomainVerificationValue  = "shortener-verification="
+)
+
+// Domain is a branded host serving the links put on it, with slugs of its own.
+// Until it is verified its links don't resolve
+type Domain struct {
+	ID       string `json:"id"`
+	Host     string `json:"host"`
+	Verified bool   `json:"verified"`
+	// VerificationRecord and VerificationValue are the TXT record to create
+	VerificationRecord string     `json:"verification_record"`
+	VerificationValue  string     `json:"verification_value"`
+	VerifiedAt         *time.Time `json:"verified_at,omitempty"`
+	// NotFoundUrl receives the visitors of a slug the domain does not have,
+	// without it they get the usual 404
+	NotFoundUrl *string   `json:"not_found_url,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CreateDomainInput struct {
+	Host        string  `json:"host" validate:"required,max=253"`
+	NotFoundUrl *string `json:"not_found_url,omitempty" validate:"omitempty,url"`
+}
+
+// UpdateDomainInput sets the 404 fallback of a domain, an empty not_found_url
+// removes it
+type UpdateDomainInput struct {
+	NotFoundUrl *string `json:"not_found_url" validate:"required"`
+}
+
+var hostLabelRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// NormalizeHost lowercases a host name and checks it is a domain name with at
+// least two labels, IP addresses and ports are refused
+func NormalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if net.ParseIP(host) != nil {
+		return "", fmt.Errorf("%s is an IP address, a domain name is expected", host)
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 || len(host) > 253 {
+		return "", fmt.Errorf("%q is not a domain name", host)
+	}
+	for _, label := range labels {
+		if !hostLabelRegex.MatchString(label) {
+			return "", fmt.Errorf("%q is not a domain name", host)
+		}
+	}
+
+	return host, nil
+}
+
+// RequestHost is the host a request was sent to, without port, lowercase
+func RequestHost(hostHeader string) string {
+	host := hostHeader
+	if h, _, err := net.SplitHostPort(hostHeader); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// LinkKey identifies a link among all domains: its slug on the shortener
+// host, <domain id>/<slug> on a custom domain. The cache and the click
+// counters are keyed by it
+func LinkKey(domainId *string, slug string) string {
+	if domainId == nil {
+		return slug
+	}
+	return *domainId + "/" + slug
+}
+
+// ParseLinkKey splits a key made by LinkKey, domainId is nil for the
+// shortener host
+func ParseLinkKey(key string) (domainId *string, slug string) {
+	if id, slug, ok := strings.Cut(key, "/"); ok {
+		return &id, slug
+	}
+	return nil, key
+}
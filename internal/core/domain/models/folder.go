@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// MaxFolderDepth is how deep folders can be nested, top level folders are at
+// depth 1
+const MaxFolderDepth = 8
+
+// Folder holds links of a user and other folders, ParentID is nil for top
+// level folders. LinkCount counts the live links directly in the folder
+type Folder struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ParentID  *string   `json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+	LinkCount int64     `json:"link_count"`
+}
+
+type CreateFolderInput struct {
+	Name     string  `json:"name" validate:"required,max=100"`
+	ParentID *string `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// UpdateFolderInput renames or moves a folder, an empty parent_id moves it to
+// the top level
+type UpdateFolderInput struct {
+	Name     *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	ParentID *string `json:"parent_id,omitempty"`
+}
+
+// MergeFolderInput moves the links and subfolders of a folder to TargetID and
+// deletes the folder
+type MergeFolderInput struct {
+	TargetID string `json:"target_id" validate:"required,uuid"`
+}
@@ -0,0 +1,453 @@
+package models
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// ImportOnConflictSkip refuses the links whose slug is already taken
+	ImportOnConflictSkip = "skip"
+	// ImportOnConflictRename imports them under a generated slug
+	ImportOnConflictRename = "rename"
+)
+
+// ImportedLink is one link read from the export of another shortener,
+// numbered from 1 without the CSV header. Errors lists what could not be
+// read, such links are reported as failed without being imported
+type ImportedLink struct {
+	Row         int
+	Slug        string
+	OriginalUrl string
+	Clicks      int
+	CreatedAt   *time.Time
+	Tags        []string
+	Errors      []ValidationError
+}
+
+// ImportRowResult is the outcome of a link not imported as it was: refused
+// with its errors, or created under another slug than the one it had.
+// Conflict is set when OriginalSlug was already taken
+type ImportRowResult struct {
+	Row          int               `json:"row"`
+	ID           string            `json:"id,omitempty"`
+	Slug         string            `json:"slug,omitempty"`
+	OriginalSlug string            `json:"original_slug,omitempty"`
+	OriginalUrl  string            `json:"original_url"`
+	Conflict     bool              `json:"conflict,omitempty"`
+	Errors       []ValidationError `json:"errors,omitempty"`
+}
+
+// ImportResult answers an import. Rows lists only the links that failed or
+// got a new slug, the others kept their slug and click total
+type ImportResult struct {
+	Imported int                `json:"imported"`
+	Renamed  int                `json:"renamed"`
+	Failed   int                `json:"failed"`
+	Rows     []*ImportRowResult `json:"rows"`
+}
+
+// importSlugPattern is the shape of the slugs kept on import, anything else
+// could be mistaken for the QR code and preview routes
+var importSlugPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// reservedSlugs are the first path segments taken by the API itself
+var reservedSlugs = map[string]bool{"api": true, "swagger": true}
+
+// CheckImportSlug tells why a slug brought from another shortener can't be
+// kept, as a validation tag, or returns an empty string
+func CheckImportSlug(slug string) string {
+	switch {
+	case !importSlugPattern.MatchString(slug):
+		return "slug"
+	case reservedSlugs[strings.ToLower(slug)]:
+		return "reserved"
+	default:
+		return ""
+	}
+}
+
+// The fields an import reads, each column or JSON key is matched to one of
+// them by its name in lower case without separators
+const (
+	importFieldSlug      = "slug"
+	importFieldShortLink = "short_link"
+	importFieldUrl       = "original_url"
+	importFieldClicks    = "clicks"
+	importFieldCreatedAt = "created_at"
+	importFieldTags      = "tags"
+)
+
+// importFields is the order the fields of a link are read in, a slug wins
+// over a short link whatever their order in the export
+var importFields = []string{importFieldSlug, importFieldShortLink, importFieldUrl, importFieldClicks, importFieldCreatedAt, importFieldTags}
+
+// importAliases names the columns of the exports of common shorteners and of
+// our own account export. A short link only gives its slug, the last segment
+// of its path, and is used when there is no slug column
+var importAliases = map[string]string{
+	"slug":             importFieldSlug,
+	"keyword":          importFieldSlug,
+	"alias":            importFieldSlug,
+	"slashtag":         importFieldSlug,
+	"backhalf":         importFieldSlug,
+	"customslug":       importFieldSlug,
+	"path":             importFieldSlug,
+	"shorturl":         importFieldShortLink,
+	"shortlink":        importFieldShortLink,
+	"shortenedurl":     importFieldShortLink,
+	"bitlink":          importFieldShortLink,
+	"link":             importFieldShortLink,
+	"originalurl":      importFieldUrl,
+	"longurl":          importFieldUrl,
+	"destination":      importFieldUrl,
+	"destinationurl":   importFieldUrl,
+	"target":           importFieldUrl,
+	"targeturl":        importFieldUrl,
+	"url":              importFieldUrl,
+	"clicks":           importFieldClicks,
+	"totalclicks":      importFieldClicks,
+	"clickcount":       importFieldClicks,
+	"engagements":      importFieldClicks,
+	"totalengagements": importFieldClicks,
+	"hits":             importFieldClicks,
+	"visits":           importFieldClicks,
+	"accesscount":      importFieldClicks,
+	"createdat":        importFieldCreatedAt,
+	"created":          importFieldCreatedAt,
+	"datecreated":      importFieldCreatedAt,
+	"creationdate":     importFieldCreatedAt,
+	"timestamp":        importFieldCreatedAt,
+	"tags":             importFieldTags,
+	"labels":           importFieldTags,
+}
+
+// importField matches a column or key name to the field it holds, "Long URL",
+// "long_url" and "longUrl" are the same column
+func importField(name string) string {
+	var squashed strings.Builder
+	for _, r := range strings.TrimPrefix(name, "\ufeff") {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			squashed.WriteRune(unicode.ToLower(r))
+		}
+	}
+
+	return importAliases[squashed.String()]
+}
+
+// importTimeLayouts are the creation dates understood besides unix seconds
+var importTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02",
+}
+
+func parseImportTime(value string) (*time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds > 1e12 {
+			// milliseconds, as JavaScript based exports write them
+			seconds /= 1000
+		}
+		t := time.Unix(seconds, 0).UTC()
+		return &t, nil
+	}
+
+	for _, layout := range importTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown date format %q", value)
+}
+
+func parseImportClicks(value string) (int, error) {
+	clicks, err := strconv.Atoi(strings.ReplaceAll(value, ",", ""))
+	if err != nil {
+		return 0, err
+	}
+	if clicks < 0 || clicks > math.MaxInt32 {
+		return 0, errors.New("clicks out of range")
+	}
+	return clicks, nil
+}
+
+// shortLinkSlug is the slug of a short link like https://bit.ly/abc or
+// bit.ly/abc
+func shortLinkSlug(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	path := strings.Trim(u.Path, "/")
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func splitImportTags(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' || r == '|' })
+}
+
+// setText fills a text field of the link, the short link is only used when
+// there is no slug
+func (l *ImportedLink) setText(field string, value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	switch field {
+	case importFieldSlug:
+		l.Slug = value
+	case importFieldShortLink:
+		if l.Slug == "" {
+			l.Slug = shortLinkSlug(value)
+		}
+	case importFieldUrl:
+		l.OriginalUrl = value
+	case importFieldClicks:
+		clicks, err := parseImportClicks(value)
+		if err != nil {
+			return err
+		}
+		l.Clicks = clicks
+	case importFieldCreatedAt:
+		createdAt, err := parseImportTime(value)
+		if err != nil {
+			return err
+		}
+		l.CreatedAt = createdAt
+	case importFieldTags:
+		l.addTags(splitImportTags(value)...)
+	}
+	return nil
+}
+
+func (l *ImportedLink) addTags(names ...string) {
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			l.Tags = append(l.Tags, name)
+		}
+	}
+}
+
+// finish checks the link once all its fields were read
+func (l *ImportedLink) finish() {
+	if l.OriginalUrl == "" && !l.hasError(importFieldUrl) {
+		l.Errors = append(l.Errors, ValidationError{Field: importFieldUrl, Tag: "required"})
+	}
+	if l.Slug != "" {
+		if tag := CheckImportSlug(l.Slug); tag != "" {
+			l.Errors = append(l.Errors, ValidationError{Field: importFieldSlug, Tag: tag, Value: l.Slug})
+		}
+	}
+	for _, name := range l.Tags {
+		if len(name) > 50 {
+			l.Errors = append(l.Errors, ValidationError{Field: importFieldTags, Tag: "max", Value: name})
+		}
+	}
+}
+
+func (l *ImportedLink) hasError(field string) bool {
+	for _, verr := range l.Errors {
+		if verr.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// importJsonArrayKeys are the keys an export object keeps its links under
+var importJsonArrayKeys = map[string]bool{"links": true, "data": true, "items": true, "results": true}
+
+// DecodeImportJson reads the links of a JSON export, either an array of
+// links or an object keeping the array under links, data, items or results.
+// Each link is an object whose keys are matched like CSV columns, tags may
+// be a string, an array of names or an array of objects with a name
+func DecodeImportJson(r io.Reader) ([]*ImportedLink, error) {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if token == json.Delim('{') {
+		for {
+			if !decoder.More() {
+				return nil, errors.New("the export has no array of links")
+			}
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			if importJsonArrayKeys[fmt.Sprint(key)] {
+				if token, err = decoder.Token(); err != nil {
+					return nil, err
+				}
+				break
+			}
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if token != json.Delim('[') {
+		return nil, errors.New("expected a JSON array of links")
+	}
+
+	var links []*ImportedLink
+	for decoder.More() {
+		if len(links) == MaxBulkRows {
+			return nil, errTooManyBulkRows
+		}
+
+		var object map[string]json.RawMessage
+		if err := decoder.Decode(&object); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return nil, err
+			}
+			links = append(links, &ImportedLink{
+				Row:    len(links) + 1,
+				Errors: []ValidationError{{Field: "row", Tag: "type", Value: typeErr.Value}},
+			})
+			continue
+		}
+
+		link := &ImportedLink{Row: len(links) + 1}
+		fields := make(map[string]json.RawMessage, len(object))
+		for key, value := range object {
+			if field := importField(key); field != "" {
+				if _, ok := fields[field]; !ok {
+					fields[field] = value
+				}
+			}
+		}
+		for _, field := range importFields {
+			if value, ok := fields[field]; ok {
+				if err := link.setJson(field, value); err != nil {
+					link.Errors = append(link.Errors, ValidationError{Field: field, Tag: "type", Value: string(value)})
+				}
+			}
+		}
+		link.finish()
+		links = append(links, link)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+func (l *ImportedLink) setJson(field string, raw json.RawMessage) error {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return l.setText(field, v)
+	case float64:
+		if field != importFieldClicks && field != importFieldCreatedAt {
+			return errors.New("unexpected number")
+		}
+		return l.setText(field, strconv.FormatInt(int64(v), 10))
+	case []any:
+		if field != importFieldTags {
+			return errors.New("unexpected array")
+		}
+		for _, item := range v {
+			switch tag := item.(type) {
+			case string:
+				l.addTags(tag)
+			case map[string]any:
+				name, ok := tag["name"].(string)
+				if !ok {
+					return errors.New("tag without a name")
+				}
+				l.addTags(name)
+			default:
+				return errors.New("unexpected tag")
+			}
+		}
+		return nil
+	default:
+		return errors.New("unexpected value")
+	}
+}
+
+// DecodeImportCsv reads the links of a CSV export one record at a time. The
+// first record names the columns, unknown columns are ignored and a column
+// with the destination is required. Cells that can't be read become row
+// errors, a malformed CSV fails the whole import
+func DecodeImportCsv(r io.Reader) ([]*ImportedLink, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("the CSV has no header")
+		}
+		return nil, err
+	}
+
+	// the first column of each field is read, later ones are ignored
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if field := importField(name); field != "" {
+			if _, ok := columns[field]; !ok {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns[importFieldUrl]; !ok {
+		return nil, errors.New("the CSV has no column with the destination URL")
+	}
+
+	var links []*ImportedLink
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(links) == MaxBulkRows {
+			return nil, errTooManyBulkRows
+		}
+
+		link := &ImportedLink{Row: len(links) + 1}
+		for _, field := range importFields {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				continue
+			}
+			if err := link.setText(field, record[i]); err != nil {
+				link.Errors = append(link.Errors, ValidationError{Field: field, Tag: "type", Value: strings.TrimSpace(record[i])})
+			}
+		}
+		link.finish()
+		links = append(links, link)
+	}
+
+	return links, nil
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"net/url"
+	"time"
+)
+
+const (
+	// PreviewParam asks for the preview page instead of the redirect, same as
+	// the /{slug}+ suffix
+	PreviewParam = "preview"
+	// ContinueParam is set by the continue button of the preview page, it
+	// lets visitors through links that force the interstitial
+	ContinueParam = "continue"
+)
+
+// LinkPreview is what the preview page shows about a link before the visitor
+// decides to follow it. Title and Description are empty until the
+// destination's metadata was fetched
+type LinkPreview struct {
+	Slug        string
+	Destination string
+	Domain      string
+	Title       string
+	Description string
+	CreatedAt   time.Time
+	Clicks      int
+	// Warning is set on links whose owner forced the interstitial
+	Warning     bool
+	ContinueUrl string
+}
+
+func NewLinkPreview(shortUrl *ShortUrl, clicks int, continueUrl string) *LinkPreview {
+	preview := &LinkPreview{
+		Slug:        shortUrl.Slug,
+		Destination: shortUrl.OriginalUrl,
+		CreatedAt:   shortUrl.CreatedAt,
+		Clicks:      clicks,
+		Warning:     shortUrl.Interstitial,
+		ContinueUrl: continueUrl,
+	}
+	if u, err := url.Parse(shortUrl.OriginalUrl); err == nil {
+		preview.Domain = u.Hostname()
+	}
+	if shortUrl.Metadata != nil {
+		preview.Title = shortUrl.Metadata.Title
+		preview.Description = shortUrl.Metadata.Description
+	}
+
+	return preview
+}
+
+func IsPreviewRequest(query url.Values) bool {
+	return query.Get(PreviewParam) == "1"
+}
+
+func IsConfirmedVisit(query url.Values) bool {
+	return query.Get(ContinueParam) == "1"
+}
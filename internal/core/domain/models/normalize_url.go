@@ -0,0 +1,83 @@
+package models
+
+import (
+	"net/url"
+	"strings"
+)
+
+// trackingParams are the query parameters that only tell where a visit came
+// from, keys starting with utm_ are tracking parameters too
+var trackingParams = map[string]bool{
+	"fbclid":    true,
+	"gclid":     true,
+	"gbraid":    true,
+	"wbraid":    true,
+	"dclid":     true,
+	"msclkid":   true,
+	"yclid":     true,
+	"twclid":    true,
+	"ttclid":    true,
+	"li_fat_id": true,
+	"igshid":    true,
+	"mc_cid":    true,
+	"mc_eid":    true,
+	"_ga":       true,
+	"_gl":       true,
+	"_hsenc":    true,
+	"_hsmi":     true,
+}
+
+// IsTrackingParam reports whether a query parameter only tracks where a
+// visit came from
+func IsTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	return strings.HasPrefix(key, "utm_") || trackingParams[key]
+}
+
+// NormalizeUrl writes a URL the same way whatever the spelling it was given
+// in: lowercase scheme and host, no default port, a / path when it is empty,
+// query parameters sorted by key and no fragment. With dropTracking the
+// tracking parameters are removed as well
+func NormalizeUrl(rawUrl string, dropTracking bool) (string, error) {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	host := strings.ToLower(parsed.Hostname())
+	port := parsed.Port()
+	if (parsed.Scheme == "http" && port == "80") || (parsed.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	parsed.Host = host
+
+	if parsed.Path == "" && parsed.Opaque == "" {
+		parsed.Path = "/"
+	}
+
+	query, err := url.ParseQuery(parsed.RawQuery)
+	if err != nil {
+		return "", err
+	}
+	if dropTracking {
+		for key := range query {
+			if IsTrackingParam(key) {
+				delete(query, key)
+			}
+		}
+	}
+	// Encode sorts by key and keeps the order of repeated values
+	parsed.RawQuery = query.Encode()
+	parsed.ForceQuery = false
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+
+	return parsed.String(), nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// Pagination goes in the response envelope of cursor paginated lists, pass
+// NextCursor back as the cursor query parameter to get the next page
+type Pagination struct {
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// CursorPageInput reads a page of a list sorted by one key and the ID, newest
+// first unless Order is asc
+type CursorPageInput struct {
+	Limit  int
+	Order  string
+	Cursor string
+}
+
+// Normalize applies the defaults and clamps out of range values
+func (p CursorPageInput) Normalize() CursorPageInput {
+	if p.Limit <= 0 {
+		p.Limit = DefaultListLimit
+	}
+	if p.Limit > MaxListLimit {
+		p.Limit = MaxListLimit
+	}
+	if p.Order == "" {
+		p.Order = SortOrderDesc
+	}
+	return p
+}
+
+func (p CursorPageInput) Descending() bool {
+	return p.Order != SortOrderAsc
+}
+
+// Cursor is the position after the last item of a page: the sort key of that
+// item and its ID. It is handed out opaque, the sort and order it was made for
+// are kept so it is not reused with another sorting
+type Cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor reads a cursor made for the given sort and order
+func DecodeCursor(encoded string, sort string, order string) (*Cursor, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, false
+	}
+	if cursor.Sort != sort || cursor.Order != order || cursor.ID == "" {
+		return nil, false
+	}
+
+	return &cursor, true
+}
@@ -0,0 +1,31 @@
+package models
+
+import "net/url"
+
+const (
+	// QrScanParam marks the short URL encoded in QR codes, redirects carrying
+	// it also count as scans and the marker is never forwarded
+	QrScanParam = "qr"
+	QrScanValue = "1"
+
+	QrFormatPNG = "png"
+	QrFormatSVG = "svg"
+)
+
+// QrScanUrl appends the scan marker to a short URL
+func QrScanUrl(shortUrl string) string {
+	u, err := url.Parse(shortUrl)
+	if err != nil {
+		return shortUrl
+	}
+	query := u.Query()
+	query.Set(QrScanParam, QrScanValue)
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// IsQrScan reports whether the query came from a QR code
+func IsQrScan(query url.Values) bool {
+	return query.Get(QrScanParam) == QrScanValue
+}
@@ -0,0 +1,300 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const MaxRedirectRulesPerLink = 50
+
+const (
+	RuleDeviceMobile  = "mobile"
+	RuleDeviceTablet  = "tablet"
+	RuleDeviceDesktop = "desktop"
+)
+
+// RedirectRule sends visitors matching all of its conditions to TargetUrl.
+// Rules are evaluated by ascending position and the first match wins, the
+// link's original URL is the fallback when none matches
+type RedirectRule struct {
+	ID         string         `json:"id"`
+	ShortUrlID string         `json:"short_url_id"`
+	Position   int            `json:"position"`
+	Conditions RuleConditions `json:"conditions"`
+	TargetUrl  string         `json:"target_url"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
+
+// RuleConditions are combined with AND, the values inside a list with OR.
+// Empty conditions are ignored
+type RuleConditions struct {
+	// OS is one of ios, android, windows, macos, linux or chromeos
+	OS []string `json:"os,omitempty" validate:"omitempty,dive,oneof=ios android windows macos linux chromeos"`
+	// Device is one of mobile, tablet or desktop
+	Device []string `json:"device,omitempty" validate:"omitempty,dive,oneof=mobile tablet desktop"`
+	// Languages are matched against Accept-Language, "pt" also matches "pt-BR"
+	Languages []string `json:"languages,omitempty" validate:"omitempty,dive,min=2,max=35"`
+	// Countries are ISO 3166-1 alpha-2 codes resolved from the GeoIP database
+	Countries []string `json:"countries,omitempty" validate:"omitempty,dive,len=2"`
+	// Weekdays go from 0 (Sunday) to 6 (Saturday)
+	Weekdays []int `json:"weekdays,omitempty" validate:"omitempty,dive,min=0,max=6"`
+	// TimeFrom and TimeTo are "HH:MM" in Timezone (UTC by default), a range
+	// ending before it starts wraps past midnight
+	TimeFrom string `json:"time_from,omitempty"`
+	TimeTo   string `json:"time_to,omitempty"`
+	Timezone string `json:"timezone,omitempty"`
+	// Query requires each parameter to have the given value, an empty value
+	// only requires the parameter to be present
+	Query map[string]string `json:"query,omitempty"`
+}
+
+type CreateRedirectRuleInput struct {
+	Position   *int           `json:"position,omitempty" validate:"omitempty,min=0"`
+	Conditions RuleConditions `json:"conditions"`
+	TargetUrl  string         `json:"target_url" validate:"required,url"`
+}
+
+type UpdateRedirectRuleInput struct {
+	Position   *int            `json:"position,omitempty" validate:"omitempty,min=0"`
+	Conditions *RuleConditions `json:"conditions,omitempty"`
+	TargetUrl  *string         `json:"target_url,omitempty" validate:"omitempty,url"`
+}
+
+func (i *UpdateRedirectRuleInput) ApplyTo(rule *RedirectRule) {
+	if i.Position != nil {
+		rule.Position = *i.Position
+	}
+	if i.Conditions != nil {
+		rule.Conditions = *i.Conditions
+	}
+	if i.TargetUrl != nil {
+		rule.TargetUrl = *i.TargetUrl
+	}
+}
+
+// RedirectVisitor is what rules are evaluated against, VariantID is the
+// variant a sticky link assigned to the visitor before
+type RedirectVisitor struct {
+	OS        string
+	Device    string
+	Languages []string
+	Country   string
+	Query     url.Values
+	Time      time.Time
+	VariantID string
+}
+
+// CachedRedirect is what URLCache stores for a slug, the rules are kept
+// normalized so a cache hit can evaluate them without the database. Stable
+// tells that every visitor gets the same destination, only then may browsers
+// keep a permanent redirect. Interstitial links only redirect visitors who
+// confirmed on the preview page
+type CachedRedirect struct {
+	OriginalUrl    string             `json:"original_url"`
+	Rules          []*RedirectRule    `json:"rules,omitempty"`
+	Variants       []*ShortUrlVariant `json:"variants,omitempty"`
+	StickyVariants bool               `json:"sticky_variants,omitempty"`
+	RedirectStatus int                `json:"redirect_status,omitempty"`
+	ForwardQuery   bool               `json:"forward_query,omitempty"`
+	QueryMerge     string             `json:"query_merge,omitempty"`
+	Stable         bool               `json:"stable,omitempty"`
+	Interstitial   bool               `json:"interstitial,omitempty"`
+}
+
+// Status returns the link's redirect status, entries cached before it was
+// configurable use the default
+func (c *CachedRedirect) Status() int {
+	if c.RedirectStatus == 0 {
+		return DefaultRedirectStatus
+	}
+	return c.RedirectStatus
+}
+
+// IsPermanent tells whether browsers may cache the redirect
+func (c *CachedRedirect) IsPermanent() bool {
+	status := c.Status()
+	return status == http.StatusMovedPermanently || status == http.StatusPermanentRedirect
+}
+
+// Destination forwards the incoming query parameters to target when the link
+// asks for it, merging duplicated keys according to QueryMerge
+func (c *CachedRedirect) Destination(target string, incoming url.Values) string {
+	if !c.ForwardQuery || len(incoming) == 0 {
+		return target
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+
+	query := u.Query()
+	for key, values := range incoming {
+		switch c.QueryMerge {
+		case QueryMergeOverride:
+			query[key] = values
+		case QueryMergeAppend:
+			query[key] = append(query[key], values...)
+		default:
+			if !query.Has(key) {
+				query[key] = values
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// Resolve returns the URL of the first rule matching the visitor, otherwise
+// a variant drawn by weight or the original URL. The variant is nil unless
+// the URL came from one
+func (c *CachedRedirect) Resolve(visitor *RedirectVisitor) (string, *ShortUrlVariant) {
+	for _, rule := range c.Rules {
+		if rule.Conditions.Matches(visitor) {
+			return rule.TargetUrl, nil
+		}
+	}
+
+	assignedId := ""
+	if c.StickyVariants {
+		assignedId = visitor.VariantID
+	}
+	if variant := pickVariant(c.Variants, assignedId); variant != nil {
+		return variant.Url, variant
+	}
+
+	return c.OriginalUrl, nil
+}
+
+// Normalize checks what the validator tags can't and lowercases the values
+// matching is case-insensitive for, it must run before a rule is stored
+func (c *RuleConditions) Normalize() error {
+	if c.IsEmpty() {
+		return errors.New("rule needs at least one condition")
+	}
+
+	c.OS = lowerAll(c.OS)
+	c.Device = lowerAll(c.Device)
+	c.Languages = lowerAll(c.Languages)
+	for i, country := range c.Countries {
+		c.Countries[i] = strings.ToUpper(country)
+	}
+
+	if (c.TimeFrom == "") != (c.TimeTo == "") {
+		return errors.New("time_from and time_to must be set together")
+	}
+	if c.TimeFrom != "" {
+		if _, err := parseClock(c.TimeFrom); err != nil {
+			return fmt.Errorf("invalid time_from: %w", err)
+		}
+		if _, err := parseClock(c.TimeTo); err != nil {
+			return fmt.Errorf("invalid time_to: %w", err)
+		}
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q", c.Timezone)
+		}
+	}
+
+	for key := range c.Query {
+		if key == "" {
+			return errors.New("query parameter names can't be empty")
+		}
+	}
+
+	return nil
+}
+
+func (c *RuleConditions) IsEmpty() bool {
+	return len(c.OS) == 0 && len(c.Device) == 0 && len(c.Languages) == 0 &&
+		len(c.Countries) == 0 && len(c.Weekdays) == 0 && c.TimeFrom == "" && len(c.Query) == 0
+}
+
+// Matches evaluates normalized conditions
+func (c *RuleConditions) Matches(visitor *RedirectVisitor) bool {
+	if len(c.OS) > 0 && !slices.Contains(c.OS, visitor.OS) {
+		return false
+	}
+	if len(c.Device) > 0 && !slices.Contains(c.Device, visitor.Device) {
+		return false
+	}
+	if len(c.Countries) > 0 && !slices.Contains(c.Countries, visitor.Country) {
+		return false
+	}
+	if len(c.Languages) > 0 && !c.matchesLanguage(visitor.Languages) {
+		return false
+	}
+	if (len(c.Weekdays) > 0 || c.TimeFrom != "") && !c.matchesTime(visitor.Time) {
+		return false
+	}
+	for key, value := range c.Query {
+		if !visitor.Query.Has(key) || (value != "" && visitor.Query.Get(key) != value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *RuleConditions) matchesLanguage(languages []string) bool {
+	for _, language := range languages {
+		for _, wanted := range c.Languages {
+			if language == wanted || strings.HasPrefix(language, wanted+"-") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *RuleConditions) matchesTime(now time.Time) bool {
+	location := time.UTC
+	if c.Timezone != "" {
+		if loaded, err := time.LoadLocation(c.Timezone); err == nil {
+			location = loaded
+		}
+	}
+	now = now.In(location)
+
+	if len(c.Weekdays) > 0 && !slices.Contains(c.Weekdays, int(now.Weekday())) {
+		return false
+	}
+
+	if c.TimeFrom == "" {
+		return true
+	}
+	from, err1 := parseClock(c.TimeFrom)
+	to, err2 := parseClock(c.TimeTo)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	if from <= to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to
+}
+
+// parseClock converts "HH:MM" to minutes since midnight
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.New("expected HH:MM")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func lowerAll(values []string) []string {
+	for i, value := range values {
+		values[i] = strings.ToLower(value)
+	}
+	return values
+}
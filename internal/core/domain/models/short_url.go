@@ -1,6 +1,7 @@
 package models
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,27 +9,200 @@ import (
 	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
 )
 
+// What a link shows before its starts_at
+const (
+	InactiveFallbackNotFound    = "not_found"
+	InactiveFallbackHoldingPage = "holding_page"
+	InactiveFallbackCountdown   = "countdown"
+)
+
+// How forwarded query parameters are merged with the ones already in the
+// destination URL
+const (
+	QueryMergeKeepDestination = "keep_destination"
+	QueryMergeOverride        = "override"
+	QueryMergeAppend          = "append"
+)
+
+const DefaultRedirectStatus = http.StatusFound
+
 type ShortUrl struct {
 	ID          string     `json:"id"`
 	Slug        string     `json:"slug"`
 	OriginalUrl string     `json:"original_url"`
-	UserID      string     `json:"user_id"`
+	UserID      string     `json:"user_id,omitempty"`
+	WorkspaceID *string    `json:"workspace_id,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
 	AccessCount int        `json:"access_count"`
+	// ScanCount is the part of AccessCount that came from QR code scans
+	ScanCount        int        `json:"scan_count"`
+	HasPassword      bool       `json:"has_password"`
+	MaxClicks        *int       `json:"max_clicks,omitempty"`
+	RemainingClicks  *int       `json:"remaining_clicks,omitempty"`
+	StartsAt         *time.Time `json:"starts_at,omitempty"`
+	InactiveFallback string     `json:"inactive_fallback"`
+	InactiveUrl      *string    `json:"inactive_url,omitempty"`
+	// Variants split the traffic instead of OriginalUrl when set
+	Variants       []*ShortUrlVariant `json:"variants,omitempty"`
+	StickyVariants bool               `json:"sticky_variants"`
+	RedirectStatus int                `json:"redirect_status"`
+	ForwardQuery   bool               `json:"forward_query"`
+	QueryMerge     string             `json:"query_merge"`
+	CampaignID     *string            `json:"campaign_id,omitempty"`
+	// Interstitial shows the preview page with a safety warning on every visit
+	Interstitial bool `json:"interstitial"`
+	// Metadata describes the destination once it was fetched
+	Metadata *ShortUrlMetadata `json:"metadata,omitempty"`
+	// DestinationHealth is the result of the latest periodic check
+	DestinationHealth *DestinationHealth `json:"destination_health,omitempty"`
+	FolderID          *string            `json:"folder_id,omitempty"`
+	Tags              []*ShortUrlTag     `json:"tags,omitempty"`
+	// DomainID and Domain are the custom domain serving the link, links
+	// without one are served by the shortener host
+	DomainID *string `json:"domain_id,omitempty"`
+	Domain   string  `json:"domain,omitempty"`
 }
 
 type CreateShortUrlInput struct {
 	OriginalUrl string  `json:"original_url" validate:"required"`
 	ExpiresAt   *string `json:"expires_at,omitempty"`
+	WorkspaceID *string `json:"workspace_id,omitempty" validate:"omitempty,uuid"`
+	Password    string  `json:"password,omitempty" validate:"omitempty,min=4,max=72"`
+	MaxClicks   *int    `json:"max_clicks,omitempty" validate:"omitempty,min=1"`
+	StartsAt    *string `json:"starts_at,omitempty"`
+	// InactiveFallback is one of not_found (default), holding_page, which
+	// redirects to InactiveUrl, or countdown
+	InactiveFallback string  `json:"inactive_fallback,omitempty" validate:"omitempty,oneof=not_found holding_page countdown"`
+	InactiveUrl      *string `json:"inactive_url,omitempty" validate:"omitempty,url"`
+	// Variants split the traffic between 2 to 10 URLs by weight, with
+	// StickyVariants a returning visitor keeps the variant they got first
+	Variants       []*ShortUrlVariantInput `json:"variants,omitempty"`
+	StickyVariants bool                    `json:"sticky_variants,omitempty"`
+	// RedirectStatus is 301, 302 (default), 307 or 308
+	RedirectStatus int `json:"redirect_status,omitempty" validate:"omitempty,oneof=301 302 307 308"`
+	// ForwardQuery appends the visitor's query string to the destination,
+	// QueryMerge is keep_destination (default), override or append
+	ForwardQuery bool   `json:"forward_query,omitempty"`
+	QueryMerge   string `json:"query_merge,omitempty" validate:"omitempty,oneof=keep_destination override append"`
+	// CampaignID tags the link with one of the user's campaigns, whose UTM
+	// values fill the UTM fields left empty. The UTM values are merged into
+	// OriginalUrl, keeping the parameters it already has
+	CampaignID *string `json:"campaign_id,omitempty" validate:"omitempty,uuid"`
+	UtmParams
+	// Interstitial makes visitors confirm on the preview page before leaving
+	Interstitial bool `json:"interstitial,omitempty"`
+	// FolderID and TagIDs organize the link with the user's folders and tags
+	FolderID *string  `json:"folder_id,omitempty" validate:"omitempty,uuid"`
+	TagIDs   []string `json:"tag_ids,omitempty" validate:"omitempty,max=20,dive,uuid"`
+	// ReuseExisting returns the oldest active link of the user, or of the
+	// workspace, leading to the same URL once normalized instead of creating
+	// one, the other fields are then ignored. With IgnoreTrackingParams URLs
+	// differing only by tracking parameters (utm_*, gclid, fbclid...) are the
+	// same. Only plain links are reused: a link with a password, a schedule,
+	// a click limit, variants, rules or redirect options is never handed
+	// out, and an input setting any of those, a campaign, a folder or tags
+	// always creates a link. Bulk creation does not reuse links
+	ReuseExisting        bool `json:"reuse_existing,omitempty"`
+	IgnoreTrackingParams bool `json:"ignore_tracking_params,omitempty"`
+	// DomainID puts the link on one of the user's verified domains, where
+	// Slug may choose its slug. Slugs are unique per domain, bulk creation
+	// supports neither
+	DomainID *string `json:"domain_id,omitempty" validate:"omitempty,uuid"`
+	Slug     string  `json:"slug,omitempty" validate:"omitempty,max=64"`
 }
 
+// UpdateShortUrlInput changes the given fields. An empty password, starts_at
+// or inactive_url removes the value and max_clicks 0 removes the click limit.
+// Variants replace the whole list, an empty list removes the split. An empty
+// campaign_id removes the link from its campaign and an empty folder_id from
+// its folder. tag_ids replaces the tags the user put on the link
 type UpdateShortUrlInput struct {
-	OriginalUrl *string `json:"original_url,omitempty"`
-	ExpiresAt   *string `json:"expires_at,omitempty"`
+	OriginalUrl      *string                  `json:"original_url,omitempty"`
+	ExpiresAt        *string                  `json:"expires_at,omitempty"`
+	Password         *string                  `json:"password,omitempty" validate:"omitempty,eq=|min=4,max=72"`
+	MaxClicks        *int                     `json:"max_clicks,omitempty" validate:"omitempty,min=0"`
+	StartsAt         *string                  `json:"starts_at,omitempty"`
+	InactiveFallback *string                  `json:"inactive_fallback,omitempty" validate:"omitempty,oneof=not_found holding_page countdown"`
+	InactiveUrl      *string                  `json:"inactive_url,omitempty" validate:"omitempty,eq=|url"`
+	Variants         *[]*ShortUrlVariantInput `json:"variants,omitempty"`
+	StickyVariants   *bool                    `json:"sticky_variants,omitempty"`
+	RedirectStatus   *int                     `json:"redirect_status,omitempty" validate:"omitempty,oneof=301 302 307 308"`
+	ForwardQuery     *bool                    `json:"forward_query,omitempty"`
+	QueryMerge       *string                  `json:"query_merge,omitempty" validate:"omitempty,oneof=keep_destination override append"`
+	CampaignID       *string                  `json:"campaign_id,omitempty"`
+	Interstitial     *bool                    `json:"interstitial,omitempty"`
+	FolderID         *string                  `json:"folder_id,omitempty"`
+	TagIDs           *[]string                `json:"tag_ids,omitempty" validate:"omitempty,max=20,dive,uuid"`
+}
+
+// ShortUrlAccess grants access to a password-protected link until ExpiresAt
+type ShortUrlAccess struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TransferShortUrlInput moves a link to a workspace or to a user's personal
+// links, exactly one of the targets must be set
+type TransferShortUrlInput struct {
+	WorkspaceID *string `json:"workspace_id,omitempty" validate:"omitempty,uuid"`
+	UserEmail   *string `json:"user_email,omitempty" validate:"omitempty,email"`
+}
+
+const (
+	ShortUrlSortCreatedAt = "created_at"
+	ShortUrlSortClicks    = "clicks"
+	ShortUrlSortExpiresAt = "expires_at"
+
+	ShortUrlStatusActive    = "active"
+	ShortUrlStatusExpired   = "expired"
+	ShortUrlStatusScheduled = "scheduled"
+)
+
+// ShortUrlFilter narrows ListShortUrl, an empty WorkspaceID lists personal links
+// and CampaignID, TagID and FolderID keep only the links of that campaign, tag
+// or folder. Status is one of active, expired (past expires_at or out of
+// clicks) and scheduled, CreatedAfter and CreatedBefore bound the creation
+// date and Search matches the slug, the destination and the page title
+type ShortUrlFilter struct {
+	WorkspaceID   string
+	CampaignID    string
+	TagID         string
+	FolderID      string
+	Status        string
+	CreatedAfter  string
+	CreatedBefore string
+	Search        string
+	// Sort is created_at (default), clicks or expires_at, links without an
+	// expiry sort as expiring last
+	Sort string
+	Page CursorPageInput
+}
+
+// ShortUrlList is the answer of ListShortUrl. Tags and Folders are all the
+// user's tags and folders, their LinkCount counts the personal or workspace
+// links being listed regardless of the other filters
+type ShortUrlList struct {
+	ShortUrls []*ShortUrl `json:"short_urls"`
+	Tags      []*Tag      `json:"tags"`
+	Folders   []*Folder   `json:"folders"`
 }
 
-func (i *CreateShortUrlInput) ToPgCreateShortUrl(userId uuid.UUID, slug string) *pgstore.CreateShortUrlParams {
+func (i *CreateShortUrlInput) ToPgCreateShortUrl(userId uuid.UUID, workspaceId pgtype.UUID, slug string, passwordHash pgtype.Text) *pgstore.CreateShortUrlParams {
+	startsAt, _ := ParseTimestamp(i.StartsAt)
+	inactiveFallback := i.InactiveFallback
+	if inactiveFallback == "" {
+		inactiveFallback = InactiveFallbackNotFound
+	}
+	redirectStatus := i.RedirectStatus
+	if redirectStatus == 0 {
+		redirectStatus = DefaultRedirectStatus
+	}
+	queryMerge := i.QueryMerge
+	if queryMerge == "" {
+		queryMerge = QueryMergeKeepDestination
+	}
+
 	var expiresAt pgtype.Timestamptz
 	if i.ExpiresAt != nil {
 		t, err := time.Parse(time.RFC3339, *i.ExpiresAt)
@@ -41,10 +215,21 @@ func (i *CreateShortUrlInput) ToPgCreateShortUrl(userId uuid.UUID, slug string)
 	}
 
 	return &pgstore.CreateShortUrlParams{
-		Slug:        slug,
-		UserID:      userId,
-		OriginalUrl: i.OriginalUrl,
-		ExpiresAt:   expiresAt,
+		Slug:             slug,
+		UserID:           pgtype.UUID{Bytes: userId, Valid: true},
+		WorkspaceID:      workspaceId,
+		OriginalUrl:      i.OriginalUrl,
+		ExpiresAt:        expiresAt,
+		PasswordHash:     passwordHash,
+		MaxClicks:        toPgMaxClicks(i.MaxClicks),
+		StartsAt:         toPgTimestamp(startsAt),
+		InactiveFallback: inactiveFallback,
+		InactiveUrl:      toPgText(i.InactiveUrl),
+		StickyVariants:   i.StickyVariants,
+		RedirectStatus:   int32(redirectStatus),
+		ForwardQuery:     i.ForwardQuery,
+		QueryMerge:       queryMerge,
+		Interstitial:     i.Interstitial,
 	}
 }
 
@@ -56,24 +241,136 @@ func (i *UpdateShortUrlInput) ApplyTo(shortUrl *ShortUrl) {
 		t, _ := time.Parse(time.RFC3339, *i.ExpiresAt)
 		shortUrl.ExpiresAt = &t
 	}
+	if i.Password != nil {
+		shortUrl.HasPassword = *i.Password != ""
+	}
+	if i.MaxClicks != nil {
+		shortUrl.MaxClicks = i.MaxClicks
+		if *i.MaxClicks == 0 {
+			shortUrl.MaxClicks = nil
+		}
+	}
+	if i.StartsAt != nil {
+		shortUrl.StartsAt, _ = ParseTimestamp(i.StartsAt)
+	}
+	if i.InactiveFallback != nil {
+		shortUrl.InactiveFallback = *i.InactiveFallback
+	}
+	if i.InactiveUrl != nil {
+		shortUrl.InactiveUrl = i.InactiveUrl
+		if *i.InactiveUrl == "" {
+			shortUrl.InactiveUrl = nil
+		}
+	}
+	if i.StickyVariants != nil {
+		shortUrl.StickyVariants = *i.StickyVariants
+	}
+	if i.RedirectStatus != nil {
+		shortUrl.RedirectStatus = *i.RedirectStatus
+	}
+	if i.ForwardQuery != nil {
+		shortUrl.ForwardQuery = *i.ForwardQuery
+	}
+	if i.QueryMerge != nil {
+		shortUrl.QueryMerge = *i.QueryMerge
+	}
+	if i.CampaignID != nil {
+		shortUrl.CampaignID = i.CampaignID
+		if *i.CampaignID == "" {
+			shortUrl.CampaignID = nil
+		}
+	}
+	if i.Interstitial != nil {
+		shortUrl.Interstitial = *i.Interstitial
+	}
+	if i.FolderID != nil {
+		shortUrl.FolderID = i.FolderID
+		if *i.FolderID == "" {
+			shortUrl.FolderID = nil
+		}
+	}
 }
 
-func (i *UpdateShortUrlInput) ToPgUpdateShortUrl(id uuid.UUID, slug string) *pgstore.UpdateShortUrlParams {
+// ToPgUpdateShortUrl builds the update from the link after ApplyTo, so fields
+// missing from the input keep their current values
+func (i *UpdateShortUrlInput) ToPgUpdateShortUrl(id uuid.UUID, shortUrl *ShortUrl, slug string, passwordHash pgtype.Text) *pgstore.UpdateShortUrlParams {
 	var expiresAt pgtype.Timestamptz
-	if i.ExpiresAt != nil {
-		t, err := time.Parse(time.RFC3339, *i.ExpiresAt)
-		if err == nil {
-			expiresAt.Time = t
-			expiresAt.Valid = true
-		}
-	} else {
-		expiresAt.Valid = false
+	if shortUrl.ExpiresAt != nil && !shortUrl.ExpiresAt.IsZero() {
+		expiresAt.Time = *shortUrl.ExpiresAt
+		expiresAt.Valid = true
 	}
 
 	return &pgstore.UpdateShortUrlParams{
-		ID:          id,
-		Slug:        slug,
-		OriginalUrl: *i.OriginalUrl,
-		ExpiresAt:   expiresAt,
+		ID:               id,
+		Slug:             slug,
+		OriginalUrl:      shortUrl.OriginalUrl,
+		ExpiresAt:        expiresAt,
+		PasswordHash:     passwordHash,
+		MaxClicks:        toPgMaxClicks(shortUrl.MaxClicks),
+		StartsAt:         toPgTimestamp(shortUrl.StartsAt),
+		InactiveFallback: shortUrl.InactiveFallback,
+		InactiveUrl:      toPgText(shortUrl.InactiveUrl),
+		StickyVariants:   shortUrl.StickyVariants,
+		RedirectStatus:   int32(shortUrl.RedirectStatus),
+		ForwardQuery:     shortUrl.ForwardQuery,
+		QueryMerge:       shortUrl.QueryMerge,
+		CampaignID:       toPgUUID(shortUrl.CampaignID),
+		Interstitial:     shortUrl.Interstitial,
+		FolderID:         toPgUUID(shortUrl.FolderID),
+	}
+}
+
+// Key identifies the link among all domains, see LinkKey
+func (s *ShortUrl) Key() string {
+	return LinkKey(s.DomainID, s.Slug)
+}
+
+// NotYetActive tells whether the link must not redirect yet
+func (s *ShortUrl) NotYetActive(now time.Time) bool {
+	return s.StartsAt != nil && now.Before(*s.StartsAt)
+}
+
+// ParseTimestamp parses an optional RFC3339 value, nil and "" mean no value
+func ParseTimestamp(raw *string) (*time.Time, error) {
+	if raw == nil || *raw == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, *raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func toPgTimestamp(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func toPgUUID(value *string) pgtype.UUID {
+	if value == nil {
+		return pgtype.UUID{}
+	}
+	id, err := uuid.Parse(*value)
+	if err != nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
+
+func toPgText(value *string) pgtype.Text {
+	if value == nil || *value == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *value, Valid: true}
+}
+
+func toPgMaxClicks(maxClicks *int) pgtype.Int4 {
+	if maxClicks == nil || *maxClicks <= 0 {
+		return pgtype.Int4{}
 	}
+	return pgtype.Int4{Int32: int32(*maxClicks), Valid: true}
 }
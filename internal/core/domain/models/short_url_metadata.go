@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+const (
+	MetadataStatusPending = "pending"
+	MetadataStatusFetched = "fetched"
+	MetadataStatusFailed  = "failed"
+)
+
+// PageMetadata is what a destination page says about itself
+type PageMetadata struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageUrl    string `json:"image_url,omitempty"`
+	FaviconUrl  string `json:"favicon_url,omitempty"`
+}
+
+// ShortUrlMetadata is the metadata fetched in the background for the
+// destination of a link, empty while Status is pending
+type ShortUrlMetadata struct {
+	Status string `json:"status"`
+	PageMetadata
+	FetchedAt *time.Time `json:"fetched_at,omitempty"`
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+)
+
+const (
+	MaxShortUrlVariants = 10
+	MaxVariantWeight    = 1000
+)
+
+// ShortUrlVariant is one of the destinations a link splits its traffic
+// between, each one gets Weight out of the sum of all weights
+type ShortUrlVariant struct {
+	ID          string `json:"id"`
+	Url         string `json:"url"`
+	Weight      int    `json:"weight"`
+	AccessCount int    `json:"access_count"`
+}
+
+// ShortUrlVariantInput describes a variant, variants are identified by URL so
+// keeping a URL when replacing the list keeps its ID and click count
+type ShortUrlVariantInput struct {
+	Url    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// CheckVariants validates a variant list, an empty list means no split
+func CheckVariants(variants []*ShortUrlVariantInput) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	if len(variants) < 2 || len(variants) > MaxShortUrlVariants {
+		return fmt.Errorf("a split needs between 2 and %d variants", MaxShortUrlVariants)
+	}
+
+	seen := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		if variant == nil {
+			return errors.New("variants can't be null")
+		}
+		u, err := url.ParseRequestURI(variant.Url)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("invalid variant url %q", variant.Url)
+		}
+		if variant.Weight < 1 || variant.Weight > MaxVariantWeight {
+			return fmt.Errorf("variant weight must be between 1 and %d", MaxVariantWeight)
+		}
+		if seen[variant.Url] {
+			return fmt.Errorf("duplicated variant url %q", variant.Url)
+		}
+		seen[variant.Url] = true
+	}
+
+	return nil
+}
+
+// pickVariant keeps a visitor on the variant they were assigned before when
+// it still exists and otherwise draws one by weight
+func pickVariant(variants []*ShortUrlVariant, assignedId string) *ShortUrlVariant {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, variant := range variants {
+		if assignedId != "" && variant.ID == assignedId {
+			return variant
+		}
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return variants[0]
+	}
+
+	n := rand.IntN(total)
+	for _, variant := range variants {
+		if n < variant.Weight {
+			return variant
+		}
+		n -= variant.Weight
+	}
+	return variants[len(variants)-1]
+}
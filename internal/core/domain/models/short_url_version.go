@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// ShortUrlVersion is the state one edit left a link in. Version 1 is the
+// state before the first recorded edit, RestoredFrom is set on the versions
+// written by a restore
+type ShortUrlVersion struct {
+	Version          int                    `json:"version"`
+	Slug             string                 `json:"slug"`
+	OriginalUrl      string                 `json:"original_url"`
+	ExpiresAt        *time.Time             `json:"expires_at,omitempty"`
+	HasPassword      bool                   `json:"has_password"`
+	MaxClicks        *int                   `json:"max_clicks,omitempty"`
+	StartsAt         *time.Time             `json:"starts_at,omitempty"`
+	InactiveFallback string                 `json:"inactive_fallback"`
+	InactiveUrl      *string                `json:"inactive_url,omitempty"`
+	StickyVariants   bool                   `json:"sticky_variants"`
+	RedirectStatus   int                    `json:"redirect_status"`
+	ForwardQuery     bool                   `json:"forward_query"`
+	QueryMerge       string                 `json:"query_merge"`
+	CampaignID       *string                `json:"campaign_id,omitempty"`
+	Interstitial     bool                   `json:"interstitial"`
+	FolderID         *string                `json:"folder_id,omitempty"`
+	Author           *ShortUrlVersionAuthor `json:"author,omitempty"`
+	RestoredFrom     *int                   `json:"restored_from,omitempty"`
+	CreatedAt        time.Time              `json:"created_at"`
+}
+
+// ShortUrlVersionAuthor is the user who made an edit
+type ShortUrlVersionAuthor struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
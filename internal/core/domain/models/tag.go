@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Tag labels any number of links of a user, LinkCount counts the live links
+// carrying it
+type Tag struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	LinkCount int64     `json:"link_count"`
+}
+
+// ShortUrlTag is a tag as shown on a link
+type ShortUrlTag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type CreateTagInput struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+type UpdateTagInput struct {
+	Name string `json:"name" validate:"required,max=50"`
+}
+
+// MergeTagInput moves the links of a tag to TargetID and deletes the tag
+type MergeTagInput struct {
+	TargetID string `json:"target_id" validate:"required,uuid"`
+}
+
+// AssignLinksInput lists the links a tag or folder is applied to or removed
+// from, up to 500 per request
+type AssignLinksInput struct {
+	ShortUrlIDs []string `json:"short_url_ids" validate:"required,min=1,max=500,dive,uuid"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type LoginChallenge struct {
+	ChallengeToken string    `json:"challenge_token"`
+	TotpRequired   bool      `json:"totp_required"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type TotpEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpAuthURI string `json:"otpauth_uri"`
+}
+
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+type TotpCodeInput struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TotpLoginInput struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
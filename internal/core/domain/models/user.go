@@ -3,19 +3,23 @@ package models
 import (
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
 )
 
 type User struct {
-	ID        string    `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	TotpEnabled   bool      `json:"totp_enabled"`
+	CreatedAt     time.Time `json:"created_at"`
 }
 
 type CreateUserInput struct {
-	Name  string `json:"name" validate:"required"`
-	Email string `json:"email" validate:"required,email"`
+	Name     string `json:"name" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
 }
 
 type UpdateUserInput struct {
@@ -24,13 +28,28 @@ type UpdateUserInput struct {
 }
 
 type GetUserByEmailInput struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ForgotPasswordInput struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
-func (i *CreateUserInput) ToPgCreateUser() *pgstore.CreateUserParams {
+type ResetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+func (i *CreateUserInput) ToPgCreateUser(passwordHash pgtype.Text) *pgstore.CreateUserParams {
 	return &pgstore.CreateUserParams{
-		Name:  i.Name,
-		Email: i.Email,
+		Name:         i.Name,
+		Email:        i.Email,
+		PasswordHash: passwordHash,
 	}
 }
 
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const MaxWebhooksPerUser = 10
+
+const (
+	// WebhookEventLinkBroken is sent once a destination fails the configured
+	// number of consecutive checks
+	WebhookEventLinkBroken = "link.broken"
+	// WebhookEventLinkRecovered is sent when a destination reported as broken
+	// answers again
+	WebhookEventLinkRecovered = "link.recovered"
+
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// Webhook is an endpoint that receives the events of the user's links. The
+// secret is only returned when the webhook is created
+type Webhook struct {
+	ID        string    `json:"id"`
+	Url       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateWebhookInput struct {
+	Url string `json:"url" validate:"required,url"`
+}
+
+// LinkHealthEvent is the payload of the link.broken and link.recovered events
+type LinkHealthEvent struct {
+	Event             string             `json:"event"`
+	OccurredAt        time.Time          `json:"occurred_at"`
+	ShortUrlID        string             `json:"short_url_id"`
+	Slug              string             `json:"slug"`
+	OriginalUrl       string             `json:"original_url"`
+	DestinationHealth *DestinationHealth `json:"destination_health"`
+}
+
+// SignWebhookPayload is the value of the signature header, an HMAC-SHA256 of
+// the raw body keyed with the webhook secret
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
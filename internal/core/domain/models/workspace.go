@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleEditor = "editor"
+	WorkspaceRoleViewer = "viewer"
+)
+
+// WorkspaceRoleRank orders roles so permission checks can compare them,
+// unknown roles rank below viewer
+func WorkspaceRoleRank(role string) int {
+	switch role {
+	case WorkspaceRoleOwner:
+		return 3
+	case WorkspaceRoleEditor:
+		return 2
+	case WorkspaceRoleViewer:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type Workspace struct {
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Role      string             `json:"role,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	Members   []*WorkspaceMember `json:"members,omitempty"`
+}
+
+type WorkspaceMember struct {
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WorkspaceInvitation struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspace_id"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type CreateWorkspaceInput struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type UpdateWorkspaceInput struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+type InviteMemberInput struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+type AcceptInvitationInput struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type UpdateMemberRoleInput struct {
+	Role string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type AuditUseCase interface {
+	ListShortUrlAuditLogs(ctx context.Context, userId string, id string, page models.PageInput) (*models.AuditLogPage, error)
+	ListUserAuditLogs(ctx context.Context, userId string, page models.PageInput) (*models.AuditLogPage, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type BulkUseCase interface {
+	CreateShortUrls(ctx context.Context, userId string, rows []*models.BulkRowInput) (*models.BulkResult, error)
+	CreateBulkJob(ctx context.Context, userId string, rows []*models.BulkRowInput) (*models.BulkJob, error)
+	GetBulkJob(ctx context.Context, userId string, id string) (*models.BulkJob, error)
+	ExportBulkJobResults(ctx context.Context, userId string, id string, fn func(*models.BulkRowResult) error) error
+	ProcessNextBulkJob(ctx context.Context) (bool, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type CampaignUseCase interface {
+	ListCampaigns(ctx context.Context, userId string) ([]*models.Campaign, error)
+	GetCampaign(ctx context.Context, userId string, id string) (*models.Campaign, error)
+	CreateCampaign(ctx context.Context, userId string, input *models.CreateCampaignInput) (*models.Campaign, error)
+	UpdateCampaign(ctx context.Context, userId string, id string, input *models.UpdateCampaignInput) (*models.Campaign, error)
+	DeleteCampaign(ctx context.Context, userId string, id string) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+// DestinationPolicy screens the URLs a link may redirect to, nil means the
+// URL is allowed. The verified custom domains count as the shortener's own,
+// ReloadCustomDomains picks up a domain just verified or deleted
+type DestinationPolicy interface {
+	Check(ctx context.Context, rawUrl string) *models.DestinationViolation
+	ReloadCustomDomains(ctx context.Context) error
+}
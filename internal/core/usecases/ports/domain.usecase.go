@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type DomainUseCase interface {
+	ListDomains(ctx context.Context, userId string) ([]*models.Domain, error)
+	CreateDomain(ctx context.Context, userId string, input *models.CreateDomainInput) (*models.Domain, error)
+	GetDomain(ctx context.Context, userId string, id string) (*models.Domain, error)
+	UpdateDomain(ctx context.Context, userId string, id string, input *models.UpdateDomainInput) (*models.Domain, error)
+	VerifyDomain(ctx context.Context, userId string, id string) (*models.Domain, error)
+	DeleteDomain(ctx context.Context, userId string, id string) error
+	// ResolveHost returns the verified domain a request host belongs to, nil
+	// when the host is not a custom domain
+	ResolveHost(ctx context.Context, host string) (*models.Domain, error)
+}
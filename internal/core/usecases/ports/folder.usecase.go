@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type FolderUseCase interface {
+	ListFolders(ctx context.Context, userId string) ([]*models.Folder, error)
+	CreateFolder(ctx context.Context, userId string, input *models.CreateFolderInput) (*models.Folder, error)
+	UpdateFolder(ctx context.Context, userId string, id string, input *models.UpdateFolderInput) (*models.Folder, error)
+	DeleteFolder(ctx context.Context, userId string, id string) error
+	MergeFolder(ctx context.Context, userId string, id string, input *models.MergeFolderInput) (*models.Folder, error)
+	AddFolderLinks(ctx context.Context, userId string, id string, input *models.AssignLinksInput) (*models.Folder, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, email models.Email) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type PortabilityUseCase interface {
+	ImportShortUrls(ctx context.Context, userId string, links []*models.ImportedLink, onConflict string) (*models.ImportResult, error)
+	ExportAccount(ctx context.Context, userId string, w io.Writer) error
+}
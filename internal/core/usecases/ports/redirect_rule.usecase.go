@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type RedirectRuleUseCase interface {
+	ListRedirectRules(ctx context.Context, userId string, shortUrlId string) ([]*models.RedirectRule, error)
+	CreateRedirectRule(ctx context.Context, userId string, shortUrlId string, input *models.CreateRedirectRuleInput) (*models.RedirectRule, error)
+	UpdateRedirectRule(ctx context.Context, userId string, shortUrlId string, id string, input *models.UpdateRedirectRuleInput) (*models.RedirectRule, error)
+	DeleteRedirectRule(ctx context.Context, userId string, shortUrlId string, id string) error
+	GetRedirectRules(ctx context.Context, shortUrlId string) ([]*models.RedirectRule, error)
+}
@@ -7,10 +7,17 @@ import (
 )
 
 type ShortUrlUseCase interface {
-	ListShortUrl(ctx context.Context, userId string) ([]*models.ShortUrl, error)
-	GetShortUrl(ctx context.Context, id string) (*models.ShortUrl, error)
-	GetShortUrlBySlug(ctx context.Context, slug string) (*models.ShortUrl, error)
-	CreateShortUrl(ctx context.Context, userId string, input *models.CreateShortUrlInput) (*models.ShortUrl, error)
-	UpdateShortUrl(ctx context.Context, id string, input *models.UpdateShortUrlInput) (*models.ShortUrl, error)
-	DeleteShortUrl(ctx context.Context, id string) error
+	ListShortUrl(ctx context.Context, userId string, filter models.ShortUrlFilter) (*models.ShortUrlList, *models.Pagination, error)
+	GetShortUrl(ctx context.Context, userId string, id string) (*models.ShortUrl, error)
+	// GetShortUrlBySlug takes the key of the link, see models.LinkKey
+	GetShortUrlBySlug(ctx context.Context, key string) (*models.ShortUrl, error)
+	CreateShortUrl(ctx context.Context, userId string, input *models.CreateShortUrlInput) (*models.ShortUrl, bool, error)
+	UpdateShortUrl(ctx context.Context, userId string, id string, input *models.UpdateShortUrlInput) (*models.ShortUrl, error)
+	DeleteShortUrl(ctx context.Context, userId string, id string) error
+	RestoreShortUrl(ctx context.Context, userId string, id string) (*models.ShortUrl, error)
+	TransferShortUrl(ctx context.Context, userId string, id string, input *models.TransferShortUrlInput) (*models.ShortUrl, error)
+	ListShortUrlVersions(ctx context.Context, userId string, id string) ([]*models.ShortUrlVersion, error)
+	RestoreShortUrlVersion(ctx context.Context, userId string, id string, version int) (*models.ShortUrl, error)
+	UnlockShortUrl(ctx context.Context, key string, password string) (*models.ShortUrlAccess, error)
+	VerifyShortUrlAccess(ctx context.Context, key string, token string) error
 }
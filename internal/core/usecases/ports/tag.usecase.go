@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type TagUseCase interface {
+	ListTags(ctx context.Context, userId string) ([]*models.Tag, error)
+	CreateTag(ctx context.Context, userId string, input *models.CreateTagInput) (*models.Tag, error)
+	UpdateTag(ctx context.Context, userId string, id string, input *models.UpdateTagInput) (*models.Tag, error)
+	DeleteTag(ctx context.Context, userId string, id string) error
+	MergeTag(ctx context.Context, userId string, id string, input *models.MergeTagInput) (*models.Tag, error)
+	AddTagLinks(ctx context.Context, userId string, id string, input *models.AssignLinksInput) (*models.Tag, error)
+	RemoveTagLinks(ctx context.Context, userId string, id string, input *models.AssignLinksInput) (*models.Tag, error)
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type TotpUseCase interface {
+	Enroll(ctx context.Context, userId string) (*models.TotpEnrollment, error)
+	Activate(ctx context.Context, userId string, code string) (*models.RecoveryCodes, error)
+	Disable(ctx context.Context, userId string, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userId string, code string) (*models.RecoveryCodes, error)
+	VerifyLogin(ctx context.Context, userId string, code string) error
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// TotpStepStore remembers the TOTP time steps accepted for each user, UseStep
+// is false for a step already used or older than the last one used
+type TotpStepStore interface {
+	UseStep(ctx context.Context, userId string, step int64) (bool, error)
+}
@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// TxtResolver looks up the TXT records of a DNS name, *net.Resolver is one
+type TxtResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
@@ -7,10 +7,16 @@ import (
 )
 
 type UserUseCase interface {
-	ListUsers(ctx context.Context) ([]*models.User, error)
+	ListUsers(ctx context.Context, page models.CursorPageInput) ([]*models.User, *models.Pagination, error)
 	GetUser(ctx context.Context, id string) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	Login(ctx context.Context, input *models.GetUserByEmailInput) (*models.User, error)
 	CreateUser(ctx context.Context, input *models.CreateUserInput) (*models.User, error)
 	UpdateUser(ctx context.Context, id string, input *models.UpdateUserInput) (*models.User, error)
-	DeleteUser(ctx context.Context, id string) error
+	DeleteUser(ctx context.Context, id string) ([]string, error)
+	RestoreUser(ctx context.Context, input *models.GetUserByEmailInput) (*models.User, error)
+	VerifyEmail(ctx context.Context, token string) (*models.User, error)
+	ResendVerification(ctx context.Context, id string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, input *models.ResetPasswordInput) error
 }
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type WebhookUseCase interface {
+	ListWebhooks(ctx context.Context, userId string) ([]*models.Webhook, error)
+	CreateWebhook(ctx context.Context, userId string, input *models.CreateWebhookInput) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, userId string, id string) error
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+type WorkspaceUseCase interface {
+	ListWorkspaces(ctx context.Context, userId string) ([]*models.Workspace, error)
+	GetWorkspace(ctx context.Context, userId string, id string) (*models.Workspace, error)
+	CreateWorkspace(ctx context.Context, userId string, input *models.CreateWorkspaceInput) (*models.Workspace, error)
+	UpdateWorkspace(ctx context.Context, userId string, id string, input *models.UpdateWorkspaceInput) (*models.Workspace, error)
+	DeleteWorkspace(ctx context.Context, userId string, id string) error
+	InviteMember(ctx context.Context, userId string, id string, input *models.InviteMemberInput) (*models.WorkspaceInvitation, error)
+	AcceptInvitation(ctx context.Context, userId string, token string) (*models.Workspace, error)
+	UpdateMemberRole(ctx context.Context, userId string, id string, memberId string, input *models.UpdateMemberRoleInput) error
+	RemoveMember(ctx context.Context, userId string, id string, memberId string) error
+}
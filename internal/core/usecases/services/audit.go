@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+type auditService struct {
+	db *pgstore.Queries
+}
+
+func NewAuditService(queries *pgstore.Queries) ports.AuditUseCase {
+	return &auditService{
+		db: queries,
+	}
+}
+
+// ListShortUrlAuditLogs returns the history of a link to anyone who can read
+// it, deleted links included so their deletion can be inspected
+func (s *auditService) ListShortUrlAuditLogs(ctx context.Context, rawUserId string, id string, page models.PageInput) (*models.AuditLogPage, error) {
+	shortUrlId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid short URL ID format")
+	}
+
+	dbShortUrl, err := s.db.GetShortUrlById(ctx, shortUrlId)
+	if errors.Is(err, pgx.ErrNoRows) {
+		dbShortUrl, err = s.db.GetDeletedShortUrlById(ctx, shortUrlId)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("Short URL not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get short URL", err)
+	}
+
+	if err := authorizeShortUrl(ctx, s.db, rawUserId, dbShortUrl, models.WorkspaceRoleViewer); err != nil {
+		return nil, err
+	}
+
+	page = page.Normalize()
+	dbLogs, err := s.db.ListAuditLogsByEntity(ctx, pgstore.ListAuditLogsByEntityParams{
+		EntityType: models.AuditEntityShortUrl,
+		EntityID:   shortUrlId,
+		Limit:      int32(page.Limit + 1),
+		Offset:     int32(page.Offset),
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list audit logs", err)
+	}
+
+	return toAuditLogPage(dbLogs, page), nil
+}
+
+// ListUserAuditLogs returns the changes made by the user and the changes made
+// to their account
+func (s *auditService) ListUserAuditLogs(ctx context.Context, rawUserId string, page models.PageInput) (*models.AuditLogPage, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	page = page.Normalize()
+	dbLogs, err := s.db.ListAuditLogsByUser(ctx, pgstore.ListAuditLogsByUserParams{
+		ActorID: pgtype.UUID{Bytes: userId, Valid: true},
+		Limit:   int32(page.Limit + 1),
+		Offset:  int32(page.Offset),
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list audit logs", err)
+	}
+
+	return toAuditLogPage(dbLogs, page), nil
+}
+
+// toAuditLogPage expects one row more than the page limit to tell whether
+// there is a next page
+func toAuditLogPage(dbLogs []pgstore.AuditLog, page models.PageInput) *models.AuditLogPage {
+	hasMore := len(dbLogs) > page.Limit
+	if hasMore {
+		dbLogs = dbLogs[:page.Limit]
+	}
+
+	entries := make([]*models.AuditLog, 0, len(dbLogs))
+	for _, dbLog := range dbLogs {
+		entry := &models.AuditLog{
+			ID:         dbLog.ID.String(),
+			EntityType: dbLog.EntityType,
+			EntityID:   dbLog.EntityID.String(),
+			Action:     dbLog.Action,
+			Changes:    json.RawMessage(dbLog.Changes),
+			RequestID:  dbLog.RequestID.String,
+			IPAddress:  dbLog.IpAddress.String,
+			CreatedAt:  dbLog.CreatedAt.Time,
+		}
+		if dbLog.ActorID.Valid {
+			entry.ActorID = uuid.UUID(dbLog.ActorID.Bytes).String()
+		}
+		entries = append(entries, entry)
+	}
+
+	return &models.AuditLogPage{
+		Entries: entries,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+		HasMore: hasMore,
+	}
+}
+
+// recordAudit appends an entry with the fields that differ between the before
+// and after snapshots. It must be called with the Queries of the transaction
+// that applies the change so both are committed or rolled back together
+func recordAudit(ctx context.Context, db *pgstore.Queries, actorId uuid.UUID, entityType string, entityId uuid.UUID, action string, before, after any) error {
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		return wraperrors.InternalErr("Failed to record audit log", err)
+	}
+
+	meta := models.RequestMetaFromContext(ctx)
+	err = db.CreateAuditLog(ctx, pgstore.CreateAuditLogParams{
+		ActorID:    pgtype.UUID{Bytes: actorId, Valid: actorId != uuid.Nil},
+		EntityType: entityType,
+		EntityID:   entityId,
+		Action:     action,
+		Changes:    changes,
+		RequestID:  pgtype.Text{String: meta.RequestID, Valid: meta.RequestID != ""},
+		IpAddress:  pgtype.Text{String: meta.ClientIP, Valid: meta.ClientIP != ""},
+	})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to record audit log", err)
+	}
+
+	return nil
+}
+
+// auditActor parses the authenticated user ID, callers have already
+// validated it so a malformed value is recorded without an actor
+func auditActor(rawUserId string) uuid.UUID {
+	actorId, _ := uuid.Parse(rawUserId)
+	return actorId
+}
+
+// auditDiff compares the JSON form of both snapshots, a nil snapshot stands
+// for an entity that didn't exist before or after the change
+func auditDiff(before, after any) ([]byte, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]models.AuditChange{}
+	for key, value := range beforeFields {
+		if other, ok := afterFields[key]; !ok || !reflect.DeepEqual(value, other) {
+			changes[key] = models.AuditChange{Before: value, After: afterFields[key]}
+		}
+	}
+	for key, value := range afterFields {
+		if _, ok := beforeFields[key]; !ok {
+			changes[key] = models.AuditChange{After: value}
+		}
+	}
+
+	return json.Marshal(changes)
+}
+
+func auditFields(snapshot any) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if value := reflect.ValueOf(snapshot); !value.IsValid() || (value.Kind() == reflect.Pointer && value.IsNil()) {
+		return fields, nil
+	}
+
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// execTx runs fn in a transaction, errors that aren't already AppErrors come
+// from the transaction itself
+func execTx(ctx context.Context, db *pgstore.Queries, fn func(*pgstore.Queries) error) error {
+	err := db.ExecTx(ctx, fn)
+	if err == nil {
+		return nil
+	}
+
+	var appErr *wraperrors.AppError
+	if errors.As(err, &appErr) {
+		return err
+	}
+
+	return wraperrors.InternalErr("Failed to commit changes", err)
+}
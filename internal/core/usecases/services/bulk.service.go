@@ -0,0 +1,780 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const (
+	// bulkChunkSize is how many rows of a job are created per transaction
+	bulkChunkSize = 1000
+	// bulkJobLease is how long a claimed job stays locked, it is renewed after
+	// every chunk and the job is resumed by the next claim once it lapses
+	bulkJobLease        = 5 * time.Minute
+	maxBulkJobErrorSize = 500
+)
+
+type bulkService struct {
+	db     *pgstore.Queries
+	policy ports.DestinationPolicy
+}
+
+func NewBulkService(queries *pgstore.Queries, policy ports.DestinationPolicy) ports.BulkUseCase {
+	return &bulkService{
+		db:     queries,
+		policy: policy,
+	}
+}
+
+// CreateShortUrls creates the links of a small upload while the request
+// waits. Every row is checked like a single creation, the refused rows are
+// reported with their errors and the others are created together
+func (s *bulkService) CreateShortUrls(ctx context.Context, rawUserId string, rows []*models.BulkRowInput) (*models.BulkResult, error) {
+	userId, err := checkUpload(ctx, s.db, rawUserId, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) > models.BulkSyncLimit {
+		return nil, wraperrors.ValidationErr(fmt.Sprintf("Uploads of more than %d rows must run as a job", models.BulkSyncLimit))
+	}
+
+	var results []*models.BulkRowResult
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		results, err = s.createRows(ctx, q, userId, rows)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BulkResult{Rows: results}
+	for _, row := range results {
+		if len(row.Errors) > 0 {
+			result.Failed++
+		} else {
+			result.Created++
+		}
+	}
+
+	return result, nil
+}
+
+// CreateBulkJob stores the rows of an upload to be created in the background,
+// rows that could not be read are failed right away
+func (s *bulkService) CreateBulkJob(ctx context.Context, rawUserId string, rows []*models.BulkRowInput) (*models.BulkJob, error) {
+	userId, err := checkUpload(ctx, s.db, rawUserId, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := 0
+	jobRows := make([]pgstore.CreateBulkJobRowsParams, 0, len(rows))
+	for _, row := range rows {
+		jobRow := pgstore.CreateBulkJobRowsParams{RowNumber: int32(row.Row)}
+		input := *row.Input
+		if len(row.Errors) > 0 {
+			// failed rows are never processed, their password would be kept
+			input.Password = ""
+			failed++
+			if jobRow.Errors, err = json.Marshal(row.Errors); err != nil {
+				return nil, wraperrors.InternalErr("Failed to store bulk rows", err)
+			}
+		}
+		if jobRow.Input, err = json.Marshal(input); err != nil {
+			return nil, wraperrors.InternalErr("Failed to store bulk rows", err)
+		}
+		jobRows = append(jobRows, jobRow)
+	}
+
+	var dbJob pgstore.BulkJob
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		dbJob, err = q.CreateBulkJob(ctx, pgstore.CreateBulkJobParams{
+			UserID:        userId,
+			TotalRows:     int32(len(rows)),
+			ProcessedRows: int32(failed),
+			FailedRows:    int32(failed),
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to create bulk job", err)
+		}
+
+		for i := range jobRows {
+			jobRows[i].JobID = dbJob.ID
+		}
+		if _, err := q.CreateBulkJobRows(ctx, jobRows); err != nil {
+			return wraperrors.InternalErr("Failed to store bulk rows", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toBulkJobModel(dbJob), nil
+}
+
+func (s *bulkService) GetBulkJob(ctx context.Context, rawUserId string, id string) (*models.BulkJob, error) {
+	dbJob, err := getUserBulkJob(ctx, s.db, rawUserId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toBulkJobModel(dbJob), nil
+}
+
+// ExportBulkJobResults passes fn the result of every row in order, rows not
+// processed yet have neither a link nor errors
+func (s *bulkService) ExportBulkJobResults(ctx context.Context, rawUserId string, id string, fn func(*models.BulkRowResult) error) error {
+	dbJob, err := getUserBulkJob(ctx, s.db, rawUserId, id)
+	if err != nil {
+		return err
+	}
+
+	var after int32
+	for {
+		dbRows, err := s.db.ListBulkJobResults(ctx, pgstore.ListBulkJobResultsParams{
+			JobID:     dbJob.ID,
+			RowNumber: after,
+			Limit:     bulkChunkSize,
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to list bulk job results", err)
+		}
+
+		for _, dbRow := range dbRows {
+			result := &models.BulkRowResult{
+				Row:         int(dbRow.RowNumber),
+				Slug:        dbRow.Slug.String,
+				OriginalUrl: dbRow.OriginalUrl,
+			}
+			if dbRow.ShortUrlID.Valid {
+				result.ID = uuid.UUID(dbRow.ShortUrlID.Bytes).String()
+			}
+			if dbRow.Errors != nil {
+				if err := json.Unmarshal(dbRow.Errors, &result.Errors); err != nil {
+					return wraperrors.InternalErr("Failed to read bulk job results", err)
+				}
+			}
+
+			if err := fn(result); err != nil {
+				return err
+			}
+			after = dbRow.RowNumber
+		}
+
+		if len(dbRows) < bulkChunkSize {
+			return nil
+		}
+	}
+}
+
+// ProcessNextBulkJob claims the oldest unfinished job and creates its
+// remaining rows. Each chunk is committed with the job progress, so a job
+// interrupted midway is resumed after its last chunk. It reports whether
+// there was a job to process
+func (s *bulkService) ProcessNextBulkJob(ctx context.Context) (bool, error) {
+	dbJob, err := s.db.ClaimBulkJob(ctx, bulkJobLockedUntil())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, wraperrors.InternalErr("Failed to claim bulk job", err)
+	}
+
+	for {
+		processed, err := s.processBulkJobChunk(ctx, dbJob)
+		if err != nil {
+			if ctx.Err() != nil {
+				// the lock lapses and the job is resumed later
+				return true, err
+			}
+			return true, s.finishBulkJob(ctx, dbJob.ID, models.BulkJobStatusFailed, err)
+		}
+		if processed == 0 {
+			break
+		}
+	}
+
+	return true, s.finishBulkJob(ctx, dbJob.ID, models.BulkJobStatusCompleted, nil)
+}
+
+func (s *bulkService) processBulkJobChunk(ctx context.Context, dbJob pgstore.BulkJob) (int, error) {
+	pending, err := s.db.ListPendingBulkJobRows(ctx, pgstore.ListPendingBulkJobRowsParams{
+		JobID: dbJob.ID,
+		Limit: bulkChunkSize,
+	})
+	if err != nil {
+		return 0, wraperrors.InternalErr("Failed to list bulk job rows", err)
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	rows := make([]*models.BulkRowInput, 0, len(pending))
+	for _, dbRow := range pending {
+		row := &models.BulkRowInput{Row: int(dbRow.RowNumber), Input: &models.CreateShortUrlInput{}}
+		if err := json.Unmarshal(dbRow.Input, row.Input); err != nil {
+			return 0, wraperrors.InternalErr("Failed to read bulk job rows", err)
+		}
+		rows = append(rows, row)
+	}
+
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		results, err := s.createRows(ctx, q, dbJob.UserID, rows)
+		if err != nil {
+			return err
+		}
+
+		params := pgstore.SaveBulkJobRowsParams{JobID: dbJob.ID}
+		var created, failed int32
+		for _, result := range results {
+			var errs []byte
+			if len(result.Errors) > 0 {
+				if errs, err = json.Marshal(result.Errors); err != nil {
+					return wraperrors.InternalErr("Failed to save bulk job rows", err)
+				}
+				failed++
+			} else {
+				created++
+			}
+			shortUrlId, _ := uuid.Parse(result.ID)
+
+			params.RowNumbers = append(params.RowNumbers, int32(result.Row))
+			params.ShortUrlIds = append(params.ShortUrlIds, shortUrlId)
+			params.Slugs = append(params.Slugs, result.Slug)
+			params.Errors = append(params.Errors, string(errs))
+		}
+		if err := q.SaveBulkJobRows(ctx, params); err != nil {
+			return wraperrors.InternalErr("Failed to save bulk job rows", err)
+		}
+
+		err = q.UpdateBulkJobProgress(ctx, pgstore.UpdateBulkJobProgressParams{
+			ID:            dbJob.ID,
+			ProcessedRows: created + failed,
+			CreatedRows:   created,
+			FailedRows:    failed,
+			LockedUntil:   bulkJobLockedUntil(),
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to update bulk job progress", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rows), nil
+}
+
+// finishBulkJob records the final status, jobErr is the reason a job failed
+// and is returned so the caller can log it
+func (s *bulkService) finishBulkJob(ctx context.Context, id uuid.UUID, status string, jobErr error) error {
+	var message pgtype.Text
+	if jobErr != nil {
+		message = pgtype.Text{String: jobErr.Error(), Valid: true}
+		if len(message.String) > maxBulkJobErrorSize {
+			message.String = message.String[:maxBulkJobErrorSize]
+		}
+	}
+
+	err := s.db.FinishBulkJob(ctx, pgstore.FinishBulkJobParams{ID: id, Status: status, Error: message})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to finish bulk job", err)
+	}
+
+	return jobErr
+}
+
+// bulkLink is a checked row ready to be inserted
+type bulkLink struct {
+	params   pgstore.CreateShortUrlsParams
+	variants []*models.ShortUrlVariantInput
+	tags     []pgstore.Tag
+	result   *models.BulkRowResult
+}
+
+// createRows creates the links of the rows that pass the checks with a few
+// statements for all of them. It must be called with the Queries of a
+// transaction, the returned error is one that aborts the whole batch
+func (s *bulkService) createRows(ctx context.Context, q *pgstore.Queries, userId uuid.UUID, rows []*models.BulkRowInput) ([]*models.BulkRowResult, error) {
+	lookups := newBulkLookups(q, userId)
+	results := make([]*models.BulkRowResult, 0, len(rows))
+	var links []*bulkLink
+	for _, row := range rows {
+		result := &models.BulkRowResult{Row: row.Row, OriginalUrl: row.Input.OriginalUrl, Errors: row.Errors}
+		results = append(results, result)
+		if len(row.Errors) > 0 {
+			continue
+		}
+
+		link, err := s.prepareRow(ctx, lookups, row.Input)
+		if err != nil {
+			if result.Errors, err = rowErrors(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		link.result = result
+		result.OriginalUrl = link.params.OriginalUrl
+		links = append(links, link)
+	}
+	if len(links) == 0 {
+		return results, nil
+	}
+
+	slugs, err := genSlugs(ctx, q, len(links))
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]pgstore.CreateShortUrlsParams, 0, len(links))
+	ids := make([]uuid.UUID, 0, len(links))
+	metadata := make([]pgstore.RequestShortUrlsMetadataParams, 0, len(links))
+	for i, link := range links {
+		link.params.ID = uuid.New()
+		link.params.Slug = slugs[i]
+		params = append(params, link.params)
+		ids = append(ids, link.params.ID)
+		metadata = append(metadata, pgstore.RequestShortUrlsMetadataParams{ShortUrlID: link.params.ID, Url: link.params.OriginalUrl})
+	}
+	if _, err := q.CreateShortUrls(ctx, params); err != nil {
+		return nil, wraperrors.InternalErr("Failed to create short URLs", err)
+	}
+	if _, err := q.RequestShortUrlsMetadata(ctx, metadata); err != nil {
+		return nil, wraperrors.InternalErr("Failed to request short URL metadata", err)
+	}
+
+	dbShortUrls, err := q.ListShortUrlsByIds(ctx, ids)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to get short URLs", err)
+	}
+	byId := make(map[uuid.UUID]pgstore.ShortUrl, len(dbShortUrls))
+	for _, dbShortUrl := range dbShortUrls {
+		byId[dbShortUrl.ID] = dbShortUrl
+	}
+
+	shortUrls := make([]*models.ShortUrl, 0, len(links))
+	tagged := map[uuid.UUID][]uuid.UUID{}
+	for _, link := range links {
+		dbShortUrl, ok := byId[link.params.ID]
+		if !ok {
+			return nil, wraperrors.InternalErr("Created short URL not found", nil)
+		}
+		shortUrl := toShortUrlModel(dbShortUrl)
+		shortUrls = append(shortUrls, shortUrl)
+		link.result.ID = shortUrl.ID
+		link.result.Slug = shortUrl.Slug
+
+		if len(link.variants) > 0 {
+			if shortUrl.Variants, err = replaceVariants(ctx, q, dbShortUrl.ID, nil, link.variants); err != nil {
+				return nil, err
+			}
+		}
+		for _, dbTag := range link.tags {
+			tagged[dbTag.ID] = append(tagged[dbTag.ID], dbShortUrl.ID)
+		}
+	}
+
+	if len(tagged) > 0 {
+		for tagId, shortUrlIds := range tagged {
+			err := q.AddShortUrlTags(ctx, pgstore.AddShortUrlTagsParams{ShortUrlIds: shortUrlIds, TagID: tagId})
+			if err != nil {
+				return nil, wraperrors.InternalErr("Failed to tag short URLs", err)
+			}
+		}
+		if err := attachTags(ctx, q, shortUrls...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := recordBulkAudit(ctx, q, userId, models.AuditActionCreate, shortUrls); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// prepareRow makes the checks CreateShortUrl makes on one row
+func (s *bulkService) prepareRow(ctx context.Context, lookups *bulkLookups, input *models.CreateShortUrlInput) (*bulkLink, error) {
+	validationErrors, err := utils.ValidateStruct(input)
+	if err != nil {
+		return nil, wraperrors.ValidationErr(err.Error())
+	}
+	if len(validationErrors) > 0 {
+		fields := make([]wraperrors.FieldError, 0, len(validationErrors))
+		for _, verr := range validationErrors {
+			if verr.Field == "Password" {
+				verr.Value = ""
+			}
+			fields = append(fields, wraperrors.FieldError{Field: verr.Field, Tag: verr.Tag, Value: verr.Value})
+		}
+		return nil, wraperrors.FieldValidationErr("Invalid input", fields...)
+	}
+	if !utils.IsValidUrl(input.OriginalUrl) {
+		return nil, onField(wraperrors.ValidationErr("Invalid URL"), "original_url", input.OriginalUrl)
+	}
+	// bulk links are put on the shortener host, with generated slugs
+	if input.DomainID != nil {
+		return nil, onField(wraperrors.ValidationErr("Bulk links cannot be put on a custom domain"), "domain_id", *input.DomainID)
+	}
+	if input.Slug != "" {
+		return nil, onField(wraperrors.ValidationErr("Bulk links cannot choose their slug"), "slug", input.Slug)
+	}
+
+	var workspaceId pgtype.UUID
+	if input.WorkspaceID != nil {
+		id, err := lookups.workspace(ctx, *input.WorkspaceID)
+		if err != nil {
+			return nil, onField(err, "workspace_id", *input.WorkspaceID)
+		}
+		workspaceId = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	startsAt, err := models.ParseTimestamp(input.StartsAt)
+	if err != nil {
+		return nil, onField(wraperrors.ValidationErr("Invalid starts_at, expected an RFC3339 date"), "starts_at", *input.StartsAt)
+	}
+	expiresAt, err := models.ParseTimestamp(input.ExpiresAt)
+	if err != nil {
+		return nil, onField(wraperrors.ValidationErr("Invalid expires_at, expected an RFC3339 date"), "expires_at", *input.ExpiresAt)
+	}
+	if err := checkActivation(startsAt, expiresAt, input.InactiveFallback, input.InactiveUrl); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := hashLinkPassword(input.Password)
+	if err != nil {
+		return nil, onField(err, "password", "")
+	}
+
+	if err := models.CheckVariants(input.Variants); err != nil {
+		return nil, wraperrors.ValidationErr(err.Error())
+	}
+
+	var campaignId pgtype.UUID
+	utm := input.UtmParams
+	if input.CampaignID != nil {
+		dbCampaign, err := lookups.campaign(ctx, *input.CampaignID)
+		if err != nil {
+			return nil, onField(err, "campaign_id", *input.CampaignID)
+		}
+		campaignId = pgtype.UUID{Bytes: dbCampaign.ID, Valid: true}
+		utm = utm.WithDefaults(toCampaignModel(dbCampaign).UtmParams)
+	}
+	originalUrl, err := utm.ApplyTo(input.OriginalUrl)
+	if err != nil {
+		return nil, onField(wraperrors.ValidationErr("Invalid original_url"), "original_url", input.OriginalUrl)
+	}
+	if err := checkDestinations(ctx, s.policy, linkDestinations(originalUrl, input.InactiveUrl, input.Variants)...); err != nil {
+		return nil, err
+	}
+
+	var folderId pgtype.UUID
+	if input.FolderID != nil {
+		dbFolder, err := lookups.folder(ctx, *input.FolderID)
+		if err != nil {
+			return nil, onField(err, "folder_id", *input.FolderID)
+		}
+		folderId = pgtype.UUID{Bytes: dbFolder.ID, Valid: true}
+	}
+
+	link := &bulkLink{variants: input.Variants}
+	seen := make(map[uuid.UUID]bool, len(input.TagIDs))
+	for _, tagId := range input.TagIDs {
+		dbTag, err := lookups.tag(ctx, tagId)
+		if err != nil {
+			return nil, onField(err, "tag_ids", tagId)
+		}
+		if !seen[dbTag.ID] {
+			seen[dbTag.ID] = true
+			link.tags = append(link.tags, dbTag)
+		}
+	}
+
+	create := *input
+	create.OriginalUrl = originalUrl
+	pgShortUrl := create.ToPgCreateShortUrl(lookups.userId, workspaceId, "", passwordHash)
+	link.params = pgstore.CreateShortUrlsParams{
+		UserID:           pgShortUrl.UserID,
+		WorkspaceID:      pgShortUrl.WorkspaceID,
+		OriginalUrl:      pgShortUrl.OriginalUrl,
+		ExpiresAt:        pgShortUrl.ExpiresAt,
+		PasswordHash:     pgShortUrl.PasswordHash,
+		MaxClicks:        pgShortUrl.MaxClicks,
+		StartsAt:         pgShortUrl.StartsAt,
+		InactiveFallback: pgShortUrl.InactiveFallback,
+		InactiveUrl:      pgShortUrl.InactiveUrl,
+		StickyVariants:   pgShortUrl.StickyVariants,
+		RedirectStatus:   pgShortUrl.RedirectStatus,
+		ForwardQuery:     pgShortUrl.ForwardQuery,
+		QueryMerge:       pgShortUrl.QueryMerge,
+		CampaignID:       campaignId,
+		Interstitial:     pgShortUrl.Interstitial,
+		FolderID:         folderId,
+	}
+
+	return link, nil
+}
+
+// bulkLookups remembers the workspaces, campaigns, folders and tags the rows
+// of an upload refer to, most rows repeat the same few
+type bulkLookups struct {
+	db         *pgstore.Queries
+	userId     uuid.UUID
+	workspaces map[string]lookup[uuid.UUID]
+	campaigns  map[string]lookup[pgstore.Campaign]
+	folders    map[string]lookup[pgstore.Folder]
+	tags       map[string]lookup[pgstore.Tag]
+}
+
+type lookup[T any] struct {
+	value T
+	err   error
+}
+
+func newBulkLookups(db *pgstore.Queries, userId uuid.UUID) *bulkLookups {
+	return &bulkLookups{
+		db:         db,
+		userId:     userId,
+		workspaces: map[string]lookup[uuid.UUID]{},
+		campaigns:  map[string]lookup[pgstore.Campaign]{},
+		folders:    map[string]lookup[pgstore.Folder]{},
+		tags:       map[string]lookup[pgstore.Tag]{},
+	}
+}
+
+func cachedLookup[T any](cache map[string]lookup[T], key string, load func() (T, error)) (T, error) {
+	if found, ok := cache[key]; ok {
+		return found.value, found.err
+	}
+
+	value, err := load()
+	cache[key] = lookup[T]{value: value, err: err}
+	return value, err
+}
+
+// workspace checks the user may create links in the workspace
+func (l *bulkLookups) workspace(ctx context.Context, id string) (uuid.UUID, error) {
+	return cachedLookup(l.workspaces, id, func() (uuid.UUID, error) {
+		workspaceId, err := uuid.Parse(id)
+		if err != nil {
+			return uuid.Nil, wraperrors.ValidationErr("Invalid workspace ID format")
+		}
+		if _, err := requireWorkspaceRole(ctx, l.db, workspaceId, l.userId, models.WorkspaceRoleEditor); err != nil {
+			return uuid.Nil, err
+		}
+		return workspaceId, nil
+	})
+}
+
+func (l *bulkLookups) campaign(ctx context.Context, id string) (pgstore.Campaign, error) {
+	return cachedLookup(l.campaigns, id, func() (pgstore.Campaign, error) {
+		return getUserCampaign(ctx, l.db, l.userId, id)
+	})
+}
+
+func (l *bulkLookups) folder(ctx context.Context, id string) (pgstore.Folder, error) {
+	return cachedLookup(l.folders, id, func() (pgstore.Folder, error) {
+		return getUserFolder(ctx, l.db, l.userId, id)
+	})
+}
+
+func (l *bulkLookups) tag(ctx context.Context, id string) (pgstore.Tag, error) {
+	return cachedLookup(l.tags, id, func() (pgstore.Tag, error) {
+		return getUserTag(ctx, l.db, l.userId, id)
+	})
+}
+
+// onField points a client error that names no field at the input field that
+// caused it
+func onField(err error, field string, value string) error {
+	var appErr *wraperrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code >= 500 || len(appErr.Fields) > 0 {
+		return err
+	}
+
+	fieldErr := *appErr
+	fieldErr.Fields = []wraperrors.FieldError{{Field: field, Tag: errorTag(appErr), Value: value}}
+	return &fieldErr
+}
+
+// rowErrors turns the client error refusing a row into its validation
+// errors, any other error is returned as is
+func rowErrors(err error) ([]models.ValidationError, error) {
+	var appErr *wraperrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code == 0 || appErr.Code >= 500 {
+		return nil, err
+	}
+
+	if len(appErr.Fields) == 0 {
+		return []models.ValidationError{{Tag: errorTag(appErr), Value: appErr.Message}}, nil
+	}
+	errs := make([]models.ValidationError, 0, len(appErr.Fields))
+	for _, field := range appErr.Fields {
+		errs = append(errs, models.ValidationError{Field: field.Field, Tag: field.Tag, Value: field.Value})
+	}
+	return errs, nil
+}
+
+func errorTag(appErr *wraperrors.AppError) string {
+	switch appErr.Code {
+	case 403:
+		return "forbidden"
+	case 404:
+		return "not_found"
+	default:
+		return "invalid"
+	}
+}
+
+// genSlugs generates count unique slugs, checking each round of candidates
+// against the taken slugs with a single query
+func genSlugs(ctx context.Context, db *pgstore.Queries, count int) ([]string, error) {
+	slugs := make([]string, 0, count)
+	seen := make(map[string]bool, count)
+	for attempt := 0; len(slugs) < count; attempt++ {
+		if attempt == 5 {
+			return nil, wraperrors.InternalErr("Failed to generate unique slugs after several attempts", nil)
+		}
+
+		candidates := make([]string, 0, count-len(slugs))
+		for len(candidates) < count-len(slugs) {
+			slug, err := utils.CreateHashSlug()
+			if err != nil {
+				return nil, wraperrors.InternalErr("Failed to create hash slug", err)
+			}
+			if !seen[slug] {
+				seen[slug] = true
+				candidates = append(candidates, slug)
+			}
+		}
+
+		// Deleted and expired links keep their slug until they are purged
+		taken, err := db.ListExistingSlugs(ctx, candidates)
+		if err != nil {
+			return nil, wraperrors.InternalErr("error checking slug existence", err)
+		}
+		takenSet := make(map[string]bool, len(taken))
+		for _, slug := range taken {
+			takenSet[slug] = true
+		}
+		for _, slug := range candidates {
+			if !takenSet[slug] {
+				slugs = append(slugs, slug)
+			}
+		}
+	}
+
+	return slugs, nil
+}
+
+// recordBulkAudit records the creation of every link like recordAudit, with
+// a single statement
+func recordBulkAudit(ctx context.Context, q *pgstore.Queries, userId uuid.UUID, action string, shortUrls []*models.ShortUrl) error {
+	meta := models.RequestMetaFromContext(ctx)
+	logs := make([]pgstore.CreateAuditLogsParams, 0, len(shortUrls))
+	for _, shortUrl := range shortUrls {
+		changes, err := auditDiff(nil, shortUrl)
+		if err != nil {
+			return wraperrors.InternalErr("Failed to record audit log", err)
+		}
+		logs = append(logs, pgstore.CreateAuditLogsParams{
+			ActorID:    pgtype.UUID{Bytes: userId, Valid: true},
+			EntityType: models.AuditEntityShortUrl,
+			EntityID:   uuid.MustParse(shortUrl.ID),
+			Action:     action,
+			Changes:    changes,
+			RequestID:  pgtype.Text{String: meta.RequestID, Valid: meta.RequestID != ""},
+			IpAddress:  pgtype.Text{String: meta.ClientIP, Valid: meta.ClientIP != ""},
+		})
+	}
+
+	if _, err := q.CreateAuditLogs(ctx, logs); err != nil {
+		return wraperrors.InternalErr("Failed to record audit log", err)
+	}
+	return nil
+}
+
+// checkUpload refuses empty or too large uploads and accounts that can't
+// create links yet
+func checkUpload(ctx context.Context, db *pgstore.Queries, rawUserId string, rows []*models.BulkRowInput) (uuid.UUID, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return uuid.Nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	if len(rows) == 0 {
+		return uuid.Nil, wraperrors.ValidationErr("The upload has no rows")
+	}
+	if len(rows) > models.MaxBulkRows {
+		return uuid.Nil, wraperrors.ValidationErr(fmt.Sprintf("An upload may have at most %d rows", models.MaxBulkRows))
+	}
+
+	if err := checkEmailVerified(ctx, db, userId); err != nil {
+		return uuid.Nil, err
+	}
+
+	return userId, nil
+}
+
+// getUserBulkJob loads a job of the user, other users' jobs are not found
+func getUserBulkJob(ctx context.Context, db *pgstore.Queries, rawUserId string, id string) (pgstore.BulkJob, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return pgstore.BulkJob{}, wraperrors.ValidationErr("Invalid user ID format")
+	}
+	jobId, err := uuid.Parse(id)
+	if err != nil {
+		return pgstore.BulkJob{}, wraperrors.ValidationErr("Invalid bulk job ID format")
+	}
+
+	dbJob, err := db.GetBulkJob(ctx, pgstore.GetBulkJobParams{ID: jobId, UserID: userId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dbJob, wraperrors.NotFoundErr("Bulk job not found")
+		}
+		return dbJob, wraperrors.InternalErr("Failed to get bulk job", err)
+	}
+
+	return dbJob, nil
+}
+
+func bulkJobLockedUntil() pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: time.Now().Add(bulkJobLease), Valid: true}
+}
+
+func toBulkJobModel(dbJob pgstore.BulkJob) *models.BulkJob {
+	job := &models.BulkJob{
+		ID:            dbJob.ID.String(),
+		Status:        dbJob.Status,
+		TotalRows:     int(dbJob.TotalRows),
+		ProcessedRows: int(dbJob.ProcessedRows),
+		CreatedRows:   int(dbJob.CreatedRows),
+		FailedRows:    int(dbJob.FailedRows),
+		Error:         dbJob.Error.String,
+		CreatedAt:     dbJob.CreatedAt.Time,
+	}
+	if dbJob.StartedAt.Valid {
+		job.StartedAt = &dbJob.StartedAt.Time
+	}
+	if dbJob.FinishedAt.Valid {
+		job.FinishedAt = &dbJob.FinishedAt.Time
+	}
+
+	return job
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+type campaignService struct {
+	db *pgstore.Queries
+}
+
+func NewCampaignService(queries *pgstore.Queries) ports.CampaignUseCase {
+	return &campaignService{
+		db: queries,
+	}
+}
+
+// ListCampaigns returns the user's campaigns with the links and clicks of
+// each one, deleted links aren't counted
+func (s *campaignService) ListCampaigns(ctx context.Context, rawUserId string) ([]*models.Campaign, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbCampaigns, err := s.db.ListCampaigns(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list campaigns", err)
+	}
+
+	stats, err := s.campaignStats(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	campaigns := make([]*models.Campaign, 0, len(dbCampaigns))
+	for _, dbCampaign := range dbCampaigns {
+		campaign := toCampaignModel(dbCampaign)
+		campaign.Stats = statsOf(stats, dbCampaign.ID)
+		campaigns = append(campaigns, campaign)
+	}
+
+	return campaigns, nil
+}
+
+func (s *campaignService) GetCampaign(ctx context.Context, rawUserId string, id string) (*models.Campaign, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbCampaign, err := getUserCampaign(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.campaignStats(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := toCampaignModel(dbCampaign)
+	campaign.Stats = statsOf(stats, dbCampaign.ID)
+
+	return campaign, nil
+}
+
+func (s *campaignService) CreateCampaign(ctx context.Context, rawUserId string, input *models.CreateCampaignInput) (*models.Campaign, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbCampaign, err := s.db.CreateCampaign(ctx, pgstore.CreateCampaignParams{
+		UserID:      userId,
+		Name:        input.Name,
+		UtmSource:   utmText(input.UtmSource),
+		UtmMedium:   utmText(input.UtmMedium),
+		UtmCampaign: utmText(input.UtmCampaign),
+		UtmTerm:     utmText(input.UtmTerm),
+		UtmContent:  utmText(input.UtmContent),
+	})
+	if err != nil {
+		if wraperrors.IsUniqueViolation(err) {
+			return nil, wraperrors.AlreadyExistsErr("A campaign with this name already exists")
+		}
+		return nil, wraperrors.InternalErr("Failed to create campaign", err)
+	}
+
+	campaign := toCampaignModel(dbCampaign)
+	campaign.Stats = &models.CampaignStats{}
+
+	return campaign, nil
+}
+
+func (s *campaignService) UpdateCampaign(ctx context.Context, rawUserId string, id string, input *models.UpdateCampaignInput) (*models.Campaign, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbCampaign, err := getUserCampaign(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbCampaign, err = s.db.UpdateCampaign(ctx, pgstore.UpdateCampaignParams{
+		ID:          dbCampaign.ID,
+		UserID:      userId,
+		Name:        input.Name,
+		UtmSource:   utmText(input.UtmSource),
+		UtmMedium:   utmText(input.UtmMedium),
+		UtmCampaign: utmText(input.UtmCampaign),
+		UtmTerm:     utmText(input.UtmTerm),
+		UtmContent:  utmText(input.UtmContent),
+	})
+	if err != nil {
+		if wraperrors.IsUniqueViolation(err) {
+			return nil, wraperrors.AlreadyExistsErr("A campaign with this name already exists")
+		}
+		return nil, wraperrors.InternalErr("Failed to update campaign", err)
+	}
+
+	stats, err := s.campaignStats(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := toCampaignModel(dbCampaign)
+	campaign.Stats = statsOf(stats, dbCampaign.ID)
+
+	return campaign, nil
+}
+
+// DeleteCampaign keeps the links, they just stop belonging to a campaign
+func (s *campaignService) DeleteCampaign(ctx context.Context, rawUserId string, id string) error {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	campaignId, err := uuid.Parse(id)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid campaign ID format")
+	}
+
+	deleted, err := s.db.DeleteCampaign(ctx, pgstore.DeleteCampaignParams{ID: campaignId, UserID: userId})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to delete campaign", err)
+	}
+	if deleted == 0 {
+		return wraperrors.NotFoundErr("Campaign not found")
+	}
+
+	return nil
+}
+
+// campaignStats maps each campaign of the user to its stats, campaigns
+// without links are missing from the map
+func (s *campaignService) campaignStats(ctx context.Context, userId uuid.UUID) (map[uuid.UUID]*models.CampaignStats, error) {
+	rows, err := s.db.ListCampaignStats(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to get campaign stats", err)
+	}
+
+	stats := make(map[uuid.UUID]*models.CampaignStats, len(rows))
+	for _, row := range rows {
+		if row.CampaignID.Valid {
+			stats[row.CampaignID.Bytes] = &models.CampaignStats{LinkCount: row.LinkCount, Clicks: row.Clicks}
+		}
+	}
+
+	return stats, nil
+}
+
+func statsOf(stats map[uuid.UUID]*models.CampaignStats, id uuid.UUID) *models.CampaignStats {
+	if campaignStats, ok := stats[id]; ok {
+		return campaignStats
+	}
+	return &models.CampaignStats{}
+}
+
+// getUserCampaign loads a campaign owned by the user
+func getUserCampaign(ctx context.Context, db *pgstore.Queries, userId uuid.UUID, id string) (pgstore.Campaign, error) {
+	campaignId, err := uuid.Parse(id)
+	if err != nil {
+		return pgstore.Campaign{}, wraperrors.ValidationErr("Invalid campaign ID format")
+	}
+
+	dbCampaign, err := db.GetCampaign(ctx, pgstore.GetCampaignParams{ID: campaignId, UserID: userId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dbCampaign, wraperrors.NotFoundErr("Campaign not found")
+		}
+		return dbCampaign, wraperrors.InternalErr("Failed to get campaign", err)
+	}
+
+	return dbCampaign, nil
+}
+
+func toCampaignModel(dbCampaign pgstore.Campaign) *models.Campaign {
+	return &models.Campaign{
+		ID:   dbCampaign.ID.String(),
+		Name: dbCampaign.Name,
+		UtmParams: models.UtmParams{
+			UtmSource:   dbCampaign.UtmSource.String,
+			UtmMedium:   dbCampaign.UtmMedium.String,
+			UtmCampaign: dbCampaign.UtmCampaign.String,
+			UtmTerm:     dbCampaign.UtmTerm.String,
+			UtmContent:  dbCampaign.UtmContent.String,
+		},
+		CreatedAt: dbCampaign.CreatedAt.Time,
+	}
+}
+
+func utmText(value string) pgtype.Text {
+	return pgtype.Text{String: value, Valid: value != ""}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// destination is a URL a link may redirect to and the input field it came from
+type destination struct {
+	field string
+	url   string
+}
+
+// checkDestinations runs every destination through the policy and reports
+// all the refused ones at once, tagged with the reason
+func checkDestinations(ctx context.Context, policy ports.DestinationPolicy, destinations ...destination) error {
+	if policy == nil {
+		return nil
+	}
+
+	var first *models.DestinationViolation
+	var fields []wraperrors.FieldError
+	for _, d := range destinations {
+		violation := policy.Check(ctx, d.url)
+		if violation == nil {
+			continue
+		}
+		if first == nil {
+			first = violation
+		}
+		fields = append(fields, wraperrors.FieldError{Field: d.field, Tag: violation.Reason, Value: d.url})
+	}
+	if first == nil {
+		return nil
+	}
+
+	err := wraperrors.FieldValidationErr("Destination URL not allowed", fields...)
+	err.Err = first
+	return err
+}
+
+// linkDestinations lists the destinations set on a create input
+func linkDestinations(originalUrl string, inactiveUrl *string, variants []*models.ShortUrlVariantInput) []destination {
+	destinations := []destination{{field: "original_url", url: originalUrl}}
+	if inactiveUrl != nil && *inactiveUrl != "" {
+		destinations = append(destinations, destination{field: "inactive_url", url: *inactiveUrl})
+	}
+
+	return append(destinations, variantDestinations(variants)...)
+}
+
+func variantDestinations(variants []*models.ShortUrlVariantInput) []destination {
+	destinations := make([]destination, 0, len(variants))
+	for i, variant := range variants {
+		if variant != nil {
+			destinations = append(destinations, destination{field: fmt.Sprintf("variants[%d].url", i), url: variant.Url})
+		}
+	}
+
+	return destinations
+}
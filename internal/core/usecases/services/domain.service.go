@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+type domainService struct {
+	db       *pgstore.Queries
+	policy   ports.DestinationPolicy
+	resolver ports.TxtResolver
+}
+
+func NewDomainService(queries *pgstore.Queries, policy ports.DestinationPolicy, resolver ports.TxtResolver) ports.DomainUseCase {
+	return &domainService{
+		db:       queries,
+		policy:   policy,
+		resolver: resolver,
+	}
+}
+
+func (s *domainService) ListDomains(ctx context.Context, rawUserId string) ([]*models.Domain, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbDomains, err := s.db.ListDomainsByUserId(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list domains", err)
+	}
+
+	domains := make([]*models.Domain, 0, len(dbDomains))
+	for _, dbDomain := range dbDomains {
+		domains = append(domains, toDomainModel(dbDomain))
+	}
+
+	return domains, nil
+}
+
+// CreateDomain claims a host for the user, it serves links once the TXT
+// record given back is found by VerifyDomain
+func (s *domainService) CreateDomain(ctx context.Context, rawUserId string, input *models.CreateDomainInput) (*models.Domain, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	host, err := models.NormalizeHost(input.Host)
+	if err != nil {
+		return nil, wraperrors.FieldValidationErr("Invalid host", wraperrors.FieldError{Field: "host", Tag: "hostname", Value: input.Host})
+	}
+
+	notFoundUrl, err := s.checkNotFoundUrl(ctx, input.NotFoundUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	dbDomains, err := s.db.ListDomainsByUserId(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list domains", err)
+	}
+	if len(dbDomains) >= models.MaxDomainsPerUser {
+		return nil, wraperrors.ValidationErr("Domain limit reached")
+	}
+
+	token, err := generateVerificationToken()
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to generate verification token", err)
+	}
+
+	dbDomain, err := s.db.CreateDomain(ctx, pgstore.CreateDomainParams{
+		UserID:            userId,
+		Host:              host,
+		VerificationToken: token,
+		NotFoundUrl:       notFoundUrl,
+	})
+	if err != nil {
+		if wraperrors.IsUniqueViolation(err) {
+			return nil, wraperrors.AlreadyExistsErr("This domain was already added")
+		}
+		return nil, wraperrors.InternalErr("Failed to create domain", err)
+	}
+
+	return toDomainModel(dbDomain), nil
+}
+
+func (s *domainService) GetDomain(ctx context.Context, rawUserId string, id string) (*models.Domain, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbDomain, err := getUserDomain(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModel(dbDomain), nil
+}
+
+func (s *domainService) UpdateDomain(ctx context.Context, rawUserId string, id string, input *models.UpdateDomainInput) (*models.Domain, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbDomain, err := getUserDomain(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	notFoundUrl, err := s.checkNotFoundUrl(ctx, input.NotFoundUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	dbDomain, err = s.db.UpdateDomainNotFoundUrl(ctx, pgstore.UpdateDomainNotFoundUrlParams{ID: dbDomain.ID, NotFoundUrl: notFoundUrl})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to update domain", err)
+	}
+
+	return toDomainModel(dbDomain), nil
+}
+
+// VerifyDomain looks the verification TXT record of the domain up, once
+// verified a domain stays so. Only one user can have a host verified
+func (s *domainService) VerifyDomain(ctx context.Context, rawUserId string, id string) (*models.Domain, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbDomain, err := getUserDomain(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if dbDomain.VerifiedAt.Valid {
+		return toDomainModel(dbDomain), nil
+	}
+
+	// a failed lookup is reported as a missing record, the DNS error says
+	// nothing the user can act on besides creating the record
+	values, err := s.resolver.LookupTXT(ctx, models.DomainVerificationPrefix+dbDomain.Host)
+	if err != nil || !slices.Contains(values, models.DomainVerificationValue+dbDomain.VerificationToken) {
+		return nil, wraperrors.ValidationErr("The verification TXT record was not found")
+	}
+
+	dbDomain, err = s.db.VerifyDomain(ctx, dbDomain.ID)
+	if err != nil {
+		if wraperrors.IsUniqueViolation(err) {
+			return nil, wraperrors.AlreadyExistsErr("This domain is already verified by another account")
+		}
+		return nil, wraperrors.InternalErr("Failed to verify domain", err)
+	}
+
+	s.reloadPolicy(ctx)
+	return toDomainModel(dbDomain), nil
+}
+
+// DeleteDomain removes a domain without live links, the links in the trash
+// are deleted with it
+func (s *domainService) DeleteDomain(ctx context.Context, rawUserId string, id string) error {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbDomain, err := getUserDomain(ctx, s.db, userId, id)
+	if err != nil {
+		return err
+	}
+
+	count, err := s.db.CountDomainShortUrls(ctx, pgtype.UUID{Bytes: dbDomain.ID, Valid: true})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to count domain links", err)
+	}
+	if count > 0 {
+		return wraperrors.ValidationErr("The domain still has links, delete them first")
+	}
+
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		if err := q.DeleteTrashedDomainShortUrls(ctx, pgtype.UUID{Bytes: dbDomain.ID, Valid: true}); err != nil {
+			return wraperrors.InternalErr("Failed to delete domain links", err)
+		}
+		if _, err := q.DeleteDomain(ctx, pgstore.DeleteDomainParams{ID: dbDomain.ID, UserID: userId}); err != nil {
+			return wraperrors.InternalErr("Failed to delete domain", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.reloadPolicy(ctx)
+	return nil
+}
+
+func (s *domainService) ResolveHost(ctx context.Context, host string) (*models.Domain, error) {
+	dbDomain, err := s.db.GetVerifiedDomainByHost(ctx, host)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, wraperrors.InternalErr("Failed to get domain", err)
+	}
+
+	return toDomainModel(dbDomain), nil
+}
+
+// checkNotFoundUrl screens the 404 fallback like any other destination, an
+// empty one removes it
+func (s *domainService) checkNotFoundUrl(ctx context.Context, notFoundUrl *string) (pgtype.Text, error) {
+	if notFoundUrl == nil || *notFoundUrl == "" {
+		return pgtype.Text{}, nil
+	}
+	if err := checkDestinations(ctx, s.policy, destination{field: "not_found_url", url: *notFoundUrl}); err != nil {
+		return pgtype.Text{}, err
+	}
+
+	return pgtype.Text{String: *notFoundUrl, Valid: true}, nil
+}
+
+// reloadPolicy makes the policy refuse or accept the domain as a destination
+// right away, failing only delays it to the next refresh
+func (s *domainService) reloadPolicy(ctx context.Context) {
+	if s.policy == nil {
+		return
+	}
+	if err := s.policy.ReloadCustomDomains(ctx); err != nil {
+		slog.Error("failed to reload custom domains", "error", err)
+	}
+}
+
+func generateVerificationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func getUserDomain(ctx context.Context, db *pgstore.Queries, userId uuid.UUID, id string) (pgstore.Domain, error) {
+	domainId, err := uuid.Parse(id)
+	if err != nil {
+		return pgstore.Domain{}, wraperrors.ValidationErr("Invalid domain ID format")
+	}
+
+	dbDomain, err := db.GetDomain(ctx, pgstore.GetDomainParams{ID: domainId, UserID: userId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dbDomain, wraperrors.NotFoundErr("Domain not found")
+		}
+		return dbDomain, wraperrors.InternalErr("Failed to get domain", err)
+	}
+
+	return dbDomain, nil
+}
+
+// attachDomain fills the host of the links put on a custom domain, each
+// domain is loaded once
+func attachDomain(ctx context.Context, db *pgstore.Queries, shortUrls ...*models.ShortUrl) error {
+	hosts := map[string]string{}
+	for _, shortUrl := range shortUrls {
+		if shortUrl.DomainID == nil {
+			continue
+		}
+
+		host, ok := hosts[*shortUrl.DomainID]
+		if !ok {
+			domainId, err := uuid.Parse(*shortUrl.DomainID)
+			if err != nil {
+				return wraperrors.InternalErr("Invalid domain ID", err)
+			}
+			dbDomain, err := db.GetDomainById(ctx, domainId)
+			if err != nil {
+				return wraperrors.InternalErr("Failed to get domain", err)
+			}
+			host = dbDomain.Host
+			hosts[*shortUrl.DomainID] = host
+		}
+		shortUrl.Domain = host
+	}
+
+	return nil
+}
+
+func toDomainModel(dbDomain pgstore.Domain) *models.Domain {
+	domain := &models.Domain{
+		ID:                 dbDomain.ID.String(),
+		Host:               dbDomain.Host,
+		Verified:           dbDomain.VerifiedAt.Valid,
+		VerificationRecord: models.DomainVerificationPrefix + dbDomain.Host,
+		VerificationValue:  models.DomainVerificationValue + dbDomain.VerificationToken,
+		CreatedAt:          dbDomain.CreatedAt.Time,
+	}
+	if dbDomain.VerifiedAt.Valid {
+		domain.VerifiedAt = &dbDomain.VerifiedAt.Time
+	}
+	if dbDomain.NotFoundUrl.Valid {
+		domain.NotFoundUrl = &dbDomain.NotFoundUrl.String
+	}
+
+	return domain
+}
@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+type folderService struct {
+	db *pgstore.Queries
+}
+
+func NewFolderService(queries *pgstore.Queries) ports.FolderUseCase {
+	return &folderService{
+		db: queries,
+	}
+}
+
+// ListFolders returns all the user's folders with the live links directly in
+// each one, the hierarchy is given by parent_id
+func (s *folderService) ListFolders(ctx context.Context, rawUserId string) ([]*models.Folder, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbFolders, err := s.db.ListFolders(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list folders", err)
+	}
+
+	counts, err := s.folderCounts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := make([]*models.Folder, 0, len(dbFolders))
+	for _, dbFolder := range dbFolders {
+		folder := toFolderModel(dbFolder)
+		folder.LinkCount = counts[dbFolder.ID]
+		folders = append(folders, folder)
+	}
+
+	return folders, nil
+}
+
+func (s *folderService) CreateFolder(ctx context.Context, rawUserId string, input *models.CreateFolderInput) (*models.Folder, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	var parentId pgtype.UUID
+	if input.ParentID != nil {
+		tree, err := s.folderTree(ctx, userId)
+		if err != nil {
+			return nil, err
+		}
+		parent, err := tree.get(*input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if tree.depth(parent.ID)+1 > models.MaxFolderDepth {
+			return nil, wraperrors.ValidationErr("Folders cannot be nested this deep")
+		}
+		parentId = pgtype.UUID{Bytes: parent.ID, Valid: true}
+	}
+
+	dbFolder, err := s.db.CreateFolder(ctx, pgstore.CreateFolderParams{UserID: userId, ParentID: parentId, Name: input.Name})
+	if err != nil {
+		if wraperrors.IsUniqueViolation(err) {
+			return nil, wraperrors.AlreadyExistsErr("A folder with this name already exists here")
+		}
+		return nil, wraperrors.InternalErr("Failed to create folder", err)
+	}
+
+	return toFolderModel(dbFolder), nil
+}
+
+// UpdateFolder renames a folder or moves it with its content under another
+// folder, which cannot be one of its own subfolders
+func (s *folderService) UpdateFolder(ctx context.Context, rawUserId string, id string, input *models.UpdateFolderInput) (*models.Folder, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	tree, err := s.folderTree(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	dbFolder, err := tree.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	params := pgstore.UpdateFolderParams{
+		ID:       dbFolder.ID,
+		UserID:   userId,
+		Name:     dbFolder.Name,
+		ParentID: dbFolder.ParentID,
+	}
+	if input.Name != nil {
+		params.Name = *input.Name
+	}
+	if input.ParentID != nil {
+		params.ParentID = pgtype.UUID{}
+		if *input.ParentID != "" {
+			parent, err := tree.get(*input.ParentID)
+			if err != nil {
+				return nil, err
+			}
+			if parent.ID == dbFolder.ID || tree.isDescendant(parent.ID, dbFolder.ID) {
+				return nil, wraperrors.ValidationErr("A folder cannot be moved into itself or one of its subfolders")
+			}
+			if tree.depth(parent.ID)+tree.height(dbFolder.ID) > models.MaxFolderDepth {
+				return nil, wraperrors.ValidationErr("Folders cannot be nested this deep")
+			}
+			params.ParentID = pgtype.UUID{Bytes: parent.ID, Valid: true}
+		}
+	}
+
+	dbFolder, err = s.db.UpdateFolder(ctx, params)
+	if err != nil {
+		if wraperrors.IsUniqueViolation(err) {
+			return nil, wraperrors.AlreadyExistsErr("A folder with this name already exists here")
+		}
+		return nil, wraperrors.InternalErr("Failed to update folder", err)
+	}
+
+	return s.withCount(ctx, userId, dbFolder)
+}
+
+// DeleteFolder moves the links and subfolders of a folder up to its parent
+// before deleting it, no link is deleted
+func (s *folderService) DeleteFolder(ctx context.Context, rawUserId string, id string) error {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbFolder, err := getUserFolder(ctx, s.db, userId, id)
+	if err != nil {
+		return err
+	}
+
+	return execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		return moveFolderContent(ctx, q, userId, dbFolder, dbFolder.ParentID)
+	})
+}
+
+// MergeFolder moves the links and subfolders of a folder into the target
+// folder and deletes it
+func (s *folderService) MergeFolder(ctx context.Context, rawUserId string, id string, input *models.MergeFolderInput) (*models.Folder, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	tree, err := s.folderTree(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	dbFolder, err := tree.get(id)
+	if err != nil {
+		return nil, err
+	}
+	target, err := tree.get(input.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.ID == dbFolder.ID || tree.isDescendant(target.ID, dbFolder.ID) {
+		return nil, wraperrors.ValidationErr("A folder cannot be merged into itself or one of its subfolders")
+	}
+	// the subfolders end up one level below the target
+	if tree.depth(target.ID)+tree.height(dbFolder.ID)-1 > models.MaxFolderDepth {
+		return nil, wraperrors.ValidationErr("Folders cannot be nested this deep")
+	}
+
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		return moveFolderContent(ctx, q, userId, dbFolder, pgtype.UUID{Bytes: target.ID, Valid: true})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withCount(ctx, userId, target)
+}
+
+// AddFolderLinks moves links the user can edit into the folder, out of the
+// folder they were in
+func (s *folderService) AddFolderLinks(ctx context.Context, rawUserId string, id string, input *models.AssignLinksInput) (*models.Folder, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbFolder, err := getUserFolder(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	shortUrlIds, err := getAuthorizedShortUrlIds(ctx, s.db, rawUserId, input.ShortUrlIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.SetShortUrlsFolder(ctx, pgstore.SetShortUrlsFolderParams{
+		FolderID:    pgtype.UUID{Bytes: dbFolder.ID, Valid: true},
+		ShortUrlIds: shortUrlIds,
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to move short URLs", err)
+	}
+
+	return s.withCount(ctx, userId, dbFolder)
+}
+
+// moveFolderContent hands the links and subfolders of a folder to target and
+// deletes the folder. A subfolder whose name is taken in the target fails
+// the whole move
+func moveFolderContent(ctx context.Context, q *pgstore.Queries, userId uuid.UUID, dbFolder pgstore.Folder, target pgtype.UUID) error {
+	folderId := pgtype.UUID{Bytes: dbFolder.ID, Valid: true}
+
+	err := q.MoveFolderChildren(ctx, pgstore.MoveFolderChildrenParams{TargetID: target, FolderID: folderId})
+	if err != nil {
+		if wraperrors.IsUniqueViolation(err) {
+			return wraperrors.AlreadyExistsErr("A subfolder has the same name as a folder in the destination")
+		}
+		return wraperrors.InternalErr("Failed to move subfolders", err)
+	}
+	if err := q.MoveFolderLinks(ctx, pgstore.MoveFolderLinksParams{TargetID: target, FolderID: folderId}); err != nil {
+		return wraperrors.InternalErr("Failed to move folder links", err)
+	}
+	if _, err := q.DeleteFolder(ctx, pgstore.DeleteFolderParams{ID: dbFolder.ID, UserID: userId}); err != nil {
+		return wraperrors.InternalErr("Failed to delete folder", err)
+	}
+
+	return nil
+}
+
+// folderCounts maps each folder of the user to the live links directly in
+// it, empty folders are missing from the map
+func (s *folderService) folderCounts(ctx context.Context, userId uuid.UUID) (map[uuid.UUID]int64, error) {
+	rows, err := s.db.ListFolderStats(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to count folder links", err)
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		if row.FolderID.Valid {
+			counts[row.FolderID.Bytes] = row.LinkCount
+		}
+	}
+
+	return counts, nil
+}
+
+func (s *folderService) withCount(ctx context.Context, userId uuid.UUID, dbFolder pgstore.Folder) (*models.Folder, error) {
+	counts, err := s.folderCounts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := toFolderModel(dbFolder)
+	folder.LinkCount = counts[dbFolder.ID]
+
+	return folder, nil
+}
+
+func (s *folderService) folderTree(ctx context.Context, userId uuid.UUID) (folderTree, error) {
+	dbFolders, err := s.db.ListFolders(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list folders", err)
+	}
+
+	tree := make(folderTree, len(dbFolders))
+	for _, dbFolder := range dbFolders {
+		tree[dbFolder.ID] = dbFolder
+	}
+
+	return tree, nil
+}
+
+// folderTree holds all the folders of a user to check moves against the
+// hierarchy without one query per level
+type folderTree map[uuid.UUID]pgstore.Folder
+
+func (t folderTree) get(id string) (pgstore.Folder, error) {
+	folderId, err := uuid.Parse(id)
+	if err != nil {
+		return pgstore.Folder{}, wraperrors.ValidationErr("Invalid folder ID format")
+	}
+
+	dbFolder, ok := t[folderId]
+	if !ok {
+		return dbFolder, wraperrors.NotFoundErr("Folder not found")
+	}
+
+	return dbFolder, nil
+}
+
+// depth is 1 for a top level folder
+func (t folderTree) depth(id uuid.UUID) int {
+	depth := 0
+	for current, ok := t[id]; ok && depth < len(t); current, ok = t[current.ParentID.Bytes] {
+		depth++
+	}
+
+	return depth
+}
+
+func (t folderTree) isDescendant(id uuid.UUID, ancestor uuid.UUID) bool {
+	for current, ok := t[id]; ok && current.ParentID.Valid; current, ok = t[current.ParentID.Bytes] {
+		if current.ParentID.Bytes == ancestor {
+			return true
+		}
+	}
+
+	return false
+}
+
+// height is how many levels the folder and its deepest subfolder take, 1 for
+// a folder without subfolders
+func (t folderTree) height(id uuid.UUID) int {
+	height := 1
+	base := t.depth(id)
+	for folderId := range t {
+		if t.isDescendant(folderId, id) {
+			height = max(height, t.depth(folderId)-base+1)
+		}
+	}
+
+	return height
+}
+
+// getUserFolder loads a folder owned by the user
+func getUserFolder(ctx context.Context, db *pgstore.Queries, userId uuid.UUID, id string) (pgstore.Folder, error) {
+	folderId, err := uuid.Parse(id)
+	if err != nil {
+		return pgstore.Folder{}, wraperrors.ValidationErr("Invalid folder ID format")
+	}
+
+	dbFolder, err := db.GetFolder(ctx, pgstore.GetFolderParams{ID: folderId, UserID: userId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dbFolder, wraperrors.NotFoundErr("Folder not found")
+		}
+		return dbFolder, wraperrors.InternalErr("Failed to get folder", err)
+	}
+
+	return dbFolder, nil
+}
+
+func toFolderModel(dbFolder pgstore.Folder) *models.Folder {
+	folder := &models.Folder{
+		ID:        dbFolder.ID.String(),
+		Name:      dbFolder.Name,
+		CreatedAt: dbFolder.CreatedAt.Time,
+	}
+	if dbFolder.ParentID.Valid {
+		parentId := uuid.UUID(dbFolder.ParentID.Bytes).String()
+		folder.ParentID = &parentId
+	}
+
+	return folder
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// cursorInfinity is the cursor value of a missing timestamp that sorts last,
+// like a link that never expires
+const cursorInfinity = "infinity"
+
+// normalizePage applies the page defaults and validates the order
+func normalizePage(page models.CursorPageInput) (models.CursorPageInput, error) {
+	page = page.Normalize()
+	if page.Order != models.SortOrderAsc && page.Order != models.SortOrderDesc {
+		return page, wraperrors.ValidationErr("Invalid order, expected asc or desc")
+	}
+	return page, nil
+}
+
+// decodePageCursor reads the cursor of a normalized page, it is nil for the
+// first page
+func decodePageCursor(page models.CursorPageInput, sort string) (*models.Cursor, pgtype.UUID, error) {
+	if page.Cursor == "" {
+		return nil, pgtype.UUID{}, nil
+	}
+
+	cursor, ok := models.DecodeCursor(page.Cursor, sort, page.Order)
+	if !ok {
+		return nil, pgtype.UUID{}, wraperrors.ValidationErr("Invalid cursor for this sort and order")
+	}
+	id, err := uuid.Parse(cursor.ID)
+	if err != nil {
+		return nil, pgtype.UUID{}, wraperrors.ValidationErr("Invalid cursor for this sort and order")
+	}
+
+	return cursor, pgtype.UUID{Bytes: id, Valid: true}, nil
+}
+
+// paginate drops the extra row fetched to tell whether there is a next page
+func paginate[T any](rows []T, page models.CursorPageInput) ([]T, *models.Pagination) {
+	pagination := &models.Pagination{Limit: page.Limit, HasMore: len(rows) > page.Limit}
+	if pagination.HasMore {
+		rows = rows[:page.Limit]
+	}
+	return rows, pagination
+}
+
+func formatCursorTime(t pgtype.Timestamptz) string {
+	if !t.Valid {
+		return cursorInfinity
+	}
+	return t.Time.UTC().Format(time.RFC3339Nano)
+}
+
+func parseCursorTime(value string) (pgtype.Timestamptz, error) {
+	if value == cursorInfinity {
+		return pgtype.Timestamptz{InfinityModifier: pgtype.Infinity, Valid: true}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return pgtype.Timestamptz{}, wraperrors.ValidationErr("Invalid cursor for this sort and order")
+	}
+	return pgtype.Timestamptz{Time: t, Valid: true}, nil
+}
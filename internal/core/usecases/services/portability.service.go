@@ -0,0 +1,519 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+type portabilityService struct {
+	db     *pgstore.Queries
+	policy ports.DestinationPolicy
+}
+
+func NewPortabilityService(queries *pgstore.Queries, policy ports.DestinationPolicy) ports.PortabilityUseCase {
+	return &portabilityService{
+		db:     queries,
+		policy: policy,
+	}
+}
+
+// ImportShortUrls creates the links of another shortener's export with their
+// slug, creation date and click total, tags are created by name as needed.
+// A slug already taken, here or earlier in the same import, is a conflict:
+// the link is refused or, with ImportOnConflictRename, gets a generated
+// slug. Links without a slug get a generated one. The whole import is one
+// transaction, checked in chunks
+func (s *portabilityService) ImportShortUrls(ctx context.Context, rawUserId string, links []*models.ImportedLink, onConflict string) (*models.ImportResult, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	if onConflict == "" {
+		onConflict = models.ImportOnConflictSkip
+	}
+	if onConflict != models.ImportOnConflictSkip && onConflict != models.ImportOnConflictRename {
+		return nil, wraperrors.ValidationErr("on_conflict must be skip or rename")
+	}
+	if len(links) == 0 {
+		return nil, wraperrors.ValidationErr("The import has no links")
+	}
+	if len(links) > models.MaxBulkRows {
+		return nil, wraperrors.ValidationErr(fmt.Sprintf("An import may have at most %d links", models.MaxBulkRows))
+	}
+
+	if err := checkEmailVerified(ctx, s.db, userId); err != nil {
+		return nil, err
+	}
+
+	var result *models.ImportResult
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		result = &models.ImportResult{Rows: []*models.ImportRowResult{}}
+		for start := 0; start < len(links); start += bulkChunkSize {
+			chunk := links[start:min(start+bulkChunkSize, len(links))]
+			if err := s.importChunk(ctx, q, userId, chunk, onConflict, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result.Rows, func(i, j int) bool { return result.Rows[i].Row < result.Rows[j].Row })
+	return result, nil
+}
+
+// importedRow is a link ready to be inserted
+type importedRow struct {
+	link   *models.ImportedLink
+	params pgstore.ImportShortUrlsParams
+	result *models.ImportRowResult
+}
+
+// importChunk inserts the links that pass the checks, the earlier chunks are
+// already inserted in the transaction so their slugs count as taken
+func (s *portabilityService) importChunk(ctx context.Context, q *pgstore.Queries, userId uuid.UUID, links []*models.ImportedLink, onConflict string, result *models.ImportResult) error {
+	fail := func(row *models.ImportRowResult) {
+		result.Failed++
+		result.Rows = append(result.Rows, row)
+	}
+
+	var checked []*importedRow
+	var slugs []string
+	for _, link := range links {
+		row := &models.ImportRowResult{Row: link.Row, OriginalSlug: link.Slug, OriginalUrl: link.OriginalUrl, Errors: link.Errors}
+		if len(row.Errors) > 0 {
+			fail(row)
+			continue
+		}
+		if !utils.IsValidUrl(link.OriginalUrl) {
+			row.Errors = []models.ValidationError{{Field: "original_url", Tag: "url", Value: link.OriginalUrl}}
+			fail(row)
+			continue
+		}
+		if err := checkDestinations(ctx, s.policy, destination{field: "original_url", url: link.OriginalUrl}); err != nil {
+			if row.Errors, err = rowErrors(err); err != nil {
+				return err
+			}
+			fail(row)
+			continue
+		}
+
+		createdAt := time.Now()
+		if link.CreatedAt != nil {
+			createdAt = *link.CreatedAt
+		}
+		checked = append(checked, &importedRow{
+			link: link,
+			params: pgstore.ImportShortUrlsParams{
+				ID:          uuid.New(),
+				UserID:      pgtype.UUID{Bytes: userId, Valid: true},
+				OriginalUrl: link.OriginalUrl,
+				CreatedAt:   pgtype.Timestamptz{Time: createdAt, Valid: true},
+				AccessCount: pgtype.Int4{Int32: int32(link.Clicks), Valid: true},
+			},
+			result: row,
+		})
+		if link.Slug != "" {
+			slugs = append(slugs, link.Slug)
+		}
+	}
+	if len(checked) == 0 {
+		return nil
+	}
+
+	// Deleted and expired links keep their slug until they are purged
+	taken := make(map[string]bool, len(slugs))
+	if len(slugs) > 0 {
+		existing, err := q.ListExistingSlugs(ctx, slugs)
+		if err != nil {
+			return wraperrors.InternalErr("error checking slug existence", err)
+		}
+		for _, slug := range existing {
+			taken[slug] = true
+		}
+	}
+
+	var kept, generated []*importedRow
+	for _, row := range checked {
+		switch {
+		case row.link.Slug == "":
+			generated = append(generated, row)
+		case !taken[row.link.Slug]:
+			taken[row.link.Slug] = true
+			row.params.Slug = row.link.Slug
+			kept = append(kept, row)
+		case onConflict == models.ImportOnConflictRename:
+			row.result.Conflict = true
+			generated = append(generated, row)
+		default:
+			row.result.Conflict = true
+			row.result.Errors = []models.ValidationError{{Field: "slug", Tag: "conflict", Value: row.link.Slug}}
+			fail(row.result)
+		}
+	}
+
+	// the slugs are generated once the kept ones are inserted, so the check
+	// against the taken slugs sees them
+	if err := insertImportedRows(ctx, q, kept); err != nil {
+		return err
+	}
+	if len(generated) > 0 {
+		newSlugs, err := genSlugs(ctx, q, len(generated))
+		if err != nil {
+			return err
+		}
+		for i, row := range generated {
+			row.params.Slug = newSlugs[i]
+		}
+		if err := insertImportedRows(ctx, q, generated); err != nil {
+			return err
+		}
+	}
+
+	inserted := append(kept, generated...)
+	if len(inserted) == 0 {
+		return nil
+	}
+	if err := tagImportedRows(ctx, q, userId, inserted); err != nil {
+		return err
+	}
+
+	ids := make([]uuid.UUID, 0, len(inserted))
+	for _, row := range inserted {
+		ids = append(ids, row.params.ID)
+		row.result.ID = row.params.ID.String()
+		row.result.Slug = row.params.Slug
+		result.Imported++
+		if row.result.Conflict {
+			result.Renamed++
+		}
+		if row.params.Slug != row.link.Slug {
+			result.Rows = append(result.Rows, row.result)
+		}
+	}
+
+	dbShortUrls, err := q.ListShortUrlsByIds(ctx, ids)
+	if err != nil {
+		return wraperrors.InternalErr("Failed to get short URLs", err)
+	}
+	shortUrls := make([]*models.ShortUrl, 0, len(dbShortUrls))
+	for _, dbShortUrl := range dbShortUrls {
+		shortUrls = append(shortUrls, toShortUrlModel(dbShortUrl))
+	}
+	if err := attachTags(ctx, q, shortUrls...); err != nil {
+		return err
+	}
+
+	return recordBulkAudit(ctx, q, userId, models.AuditActionImport, shortUrls)
+}
+
+func insertImportedRows(ctx context.Context, q *pgstore.Queries, rows []*importedRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	params := make([]pgstore.ImportShortUrlsParams, 0, len(rows))
+	metadata := make([]pgstore.RequestShortUrlsMetadataParams, 0, len(rows))
+	for _, row := range rows {
+		params = append(params, row.params)
+		metadata = append(metadata, pgstore.RequestShortUrlsMetadataParams{ShortUrlID: row.params.ID, Url: row.params.OriginalUrl})
+	}
+	if _, err := q.ImportShortUrls(ctx, params); err != nil {
+		return wraperrors.InternalErr("Failed to import short URLs", err)
+	}
+	if _, err := q.RequestShortUrlsMetadata(ctx, metadata); err != nil {
+		return wraperrors.InternalErr("Failed to request short URL metadata", err)
+	}
+
+	return nil
+}
+
+// tagImportedRows creates the tags the links name that the user doesn't have
+// yet and tags the links with them
+func tagImportedRows(ctx context.Context, q *pgstore.Queries, userId uuid.UUID, rows []*importedRow) error {
+	var names []string
+	seen := map[string]bool{}
+	for _, row := range rows {
+		for _, name := range row.link.Tags {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	dbTags, err := q.UpsertTagsByName(ctx, pgstore.UpsertTagsByNameParams{UserID: userId, Names: names})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to create tags", err)
+	}
+	tagIds := make(map[string]uuid.UUID, len(dbTags))
+	for _, dbTag := range dbTags {
+		tagIds[dbTag.Name] = dbTag.ID
+	}
+
+	tagged := map[uuid.UUID][]uuid.UUID{}
+	for _, row := range rows {
+		linkTags := map[uuid.UUID]bool{}
+		for _, name := range row.link.Tags {
+			tagId := tagIds[name]
+			if !linkTags[tagId] {
+				linkTags[tagId] = true
+				tagged[tagId] = append(tagged[tagId], row.params.ID)
+			}
+		}
+	}
+	for tagId, shortUrlIds := range tagged {
+		err := q.AddShortUrlTags(ctx, pgstore.AddShortUrlTagsParams{ShortUrlIds: shortUrlIds, TagID: tagId})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to tag short URLs", err)
+		}
+	}
+
+	return nil
+}
+
+// ExportAccount writes a zip archive with the user's profile, live links,
+// tags and daily link stats, each list both as JSON and as CSV. Nothing is
+// written when the user can't be loaded, an error after that leaves the
+// archive cut short
+func (s *portabilityService) ExportAccount(ctx context.Context, rawUserId string, w io.Writer) error {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbUser, err := s.db.GetUser(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return wraperrors.NotFoundErr("User not found")
+		}
+		return wraperrors.InternalErr("Failed to get user", err)
+	}
+	profile := &models.User{
+		ID:            dbUser.ID.String(),
+		Name:          dbUser.Name,
+		Email:         dbUser.Email,
+		EmailVerified: dbUser.EmailVerifiedAt.Valid,
+		TotpEnabled:   dbUser.TotpEnabled,
+		CreatedAt:     dbUser.CreatedAt.Time,
+	}
+
+	tags, err := s.exportTags(ctx, userId)
+	if err != nil {
+		return err
+	}
+	eachTag := func(fn func(*models.Tag) error) error {
+		for _, tag := range tags {
+			if err := fn(tag); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	eachShortUrl := func(fn func(*models.ShortUrl) error) error {
+		return s.eachShortUrl(ctx, userId, fn)
+	}
+	eachDailyStat := func(fn func(*models.DailyStat) error) error {
+		return s.eachDailyStat(ctx, userId, fn)
+	}
+
+	archive := zip.NewWriter(w)
+	file, err := archive.Create("profile.json")
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(file).Encode(profile); err != nil {
+		return err
+	}
+
+	files := []func() error{
+		func() error { return exportJson(archive, "links.json", eachShortUrl) },
+		func() error {
+			return exportCsv(archive, "links.csv", models.ExportLinksCsvHeader, (*models.ShortUrl).ExportCsvRecord, eachShortUrl)
+		},
+		func() error { return exportJson(archive, "tags.json", eachTag) },
+		func() error {
+			return exportCsv(archive, "tags.csv", models.ExportTagsCsvHeader, (*models.Tag).CsvRecord, eachTag)
+		},
+		func() error { return exportJson(archive, "daily_stats.json", eachDailyStat) },
+		func() error {
+			return exportCsv(archive, "daily_stats.csv", models.ExportDailyStatsCsvHeader, (*models.DailyStat).CsvRecord, eachDailyStat)
+		},
+	}
+	for _, exportFile := range files {
+		if err := exportFile(); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func (s *portabilityService) exportTags(ctx context.Context, userId uuid.UUID) ([]*models.Tag, error) {
+	dbTags, err := s.db.ListTags(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list tags", err)
+	}
+
+	stats, err := s.db.ListTagStats(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to count tagged links", err)
+	}
+	counts := make(map[uuid.UUID]int64, len(stats))
+	for _, stat := range stats {
+		counts[stat.TagID] = stat.LinkCount
+	}
+
+	tags := make([]*models.Tag, 0, len(dbTags))
+	for _, dbTag := range dbTags {
+		tag := toTagModel(dbTag)
+		tag.LinkCount = counts[dbTag.ID]
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// eachShortUrl passes fn the user's live links with their tags, a page at a
+// time
+func (s *portabilityService) eachShortUrl(ctx context.Context, userId uuid.UUID, fn func(*models.ShortUrl) error) error {
+	var after uuid.UUID
+	for {
+		dbShortUrls, err := s.db.ListUserShortUrls(ctx, pgstore.ListUserShortUrlsParams{
+			UserID: pgtype.UUID{Bytes: userId, Valid: true},
+			ID:     after,
+			Limit:  bulkChunkSize,
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to list short URLs", err)
+		}
+
+		shortUrls := make([]*models.ShortUrl, 0, len(dbShortUrls))
+		for _, dbShortUrl := range dbShortUrls {
+			shortUrls = append(shortUrls, toShortUrlModel(dbShortUrl))
+		}
+		if err := attachTags(ctx, s.db, shortUrls...); err != nil {
+			return err
+		}
+		for _, shortUrl := range shortUrls {
+			if err := fn(shortUrl); err != nil {
+				return err
+			}
+		}
+
+		if len(dbShortUrls) < bulkChunkSize {
+			return nil
+		}
+		after = dbShortUrls[len(dbShortUrls)-1].ID
+	}
+}
+
+// eachDailyStat passes fn the daily stats of the user's live links, a page
+// at a time
+func (s *portabilityService) eachDailyStat(ctx context.Context, userId uuid.UUID, fn func(*models.DailyStat) error) error {
+	params := pgstore.ListUserDailyStatsParams{
+		UserID:   pgtype.UUID{Bytes: userId, Valid: true},
+		PageSize: bulkChunkSize,
+	}
+	for {
+		dbStats, err := s.db.ListUserDailyStats(ctx, params)
+		if err != nil {
+			return wraperrors.InternalErr("Failed to list daily stats", err)
+		}
+
+		for _, dbStat := range dbStats {
+			err := fn(&models.DailyStat{
+				ShortUrlID: dbStat.ShortUrlID.String(),
+				Slug:       dbStat.Slug,
+				Day:        dbStat.Day.Time.Format(time.DateOnly),
+				Clicks:     int(dbStat.Clicks),
+				Scans:      int(dbStat.Scans),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(dbStats) < bulkChunkSize {
+			return nil
+		}
+		last := dbStats[len(dbStats)-1]
+		params.AfterID = pgtype.UUID{Bytes: last.ShortUrlID, Valid: true}
+		params.AfterDay = last.Day
+	}
+}
+
+// exportJson writes the items each passes into a JSON array file of the
+// archive, one item at a time
+func exportJson[T any](archive *zip.Writer, name string, each func(func(T) error) error) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	separator := "["
+	err = each(func(item T) error {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(file, separator); err != nil {
+			return err
+		}
+		separator = ","
+		_, err = file.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if separator == "[" {
+		_, err = io.WriteString(file, "[]\n")
+	} else {
+		_, err = io.WriteString(file, "]\n")
+	}
+	return err
+}
+
+// exportCsv writes the items each passes into a CSV file of the archive
+func exportCsv[T any](archive *zip.Writer, name string, header []string, record func(T) []string, each func(func(T) error) error) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(file)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	err = each(func(item T) error {
+		return writer.Write(record(item))
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+type redirectRuleService struct {
+	db     *pgstore.Queries
+	policy ports.DestinationPolicy
+}
+
+func NewRedirectRuleService(queries *pgstore.Queries, policy ports.DestinationPolicy) ports.RedirectRuleUseCase {
+	return &redirectRuleService{
+		db:     queries,
+		policy: policy,
+	}
+}
+
+func (s *redirectRuleService) ListRedirectRules(ctx context.Context, rawUserId string, shortUrlId string) ([]*models.RedirectRule, error) {
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, shortUrlId, models.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return listRedirectRules(ctx, s.db, dbShortUrl.ID)
+}
+
+func (s *redirectRuleService) CreateRedirectRule(ctx context.Context, rawUserId string, shortUrlId string, input *models.CreateRedirectRuleInput) (*models.RedirectRule, error) {
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, shortUrlId, models.WorkspaceRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRedirectRule(&input.Conditions, input.TargetUrl); err != nil {
+		return nil, err
+	}
+	if err := checkDestinations(ctx, s.policy, destination{field: "target_url", url: input.TargetUrl}); err != nil {
+		return nil, err
+	}
+	conditions, err := json.Marshal(input.Conditions)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to encode rule conditions", err)
+	}
+
+	var rule *models.RedirectRule
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		before, err := listRedirectRules(ctx, q, dbShortUrl.ID)
+		if err != nil {
+			return err
+		}
+		if len(before) >= models.MaxRedirectRulesPerLink {
+			return wraperrors.ValidationErr(fmt.Sprintf("A short URL can have at most %d redirect rules", models.MaxRedirectRulesPerLink))
+		}
+
+		var position int32
+		if input.Position != nil {
+			position = int32(*input.Position)
+		} else if position, err = q.NextRedirectRulePosition(ctx, dbShortUrl.ID); err != nil {
+			return wraperrors.InternalErr("Failed to create redirect rule", err)
+		}
+
+		dbRule, err := q.CreateRedirectRule(ctx, pgstore.CreateRedirectRuleParams{
+			ShortUrlID: dbShortUrl.ID,
+			Position:   position,
+			Conditions: conditions,
+			TargetUrl:  input.TargetUrl,
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to create redirect rule", err)
+		}
+		if rule, err = toRedirectRuleModel(dbRule); err != nil {
+			return err
+		}
+
+		return recordRedirectRulesAudit(ctx, q, rawUserId, dbShortUrl.ID, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *redirectRuleService) UpdateRedirectRule(ctx context.Context, rawUserId string, shortUrlId string, id string, input *models.UpdateRedirectRuleInput) (*models.RedirectRule, error) {
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, shortUrlId, models.WorkspaceRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleId, err := uuid.Parse(id)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid redirect rule ID format")
+	}
+
+	dbRule, err := s.db.GetRedirectRule(ctx, pgstore.GetRedirectRuleParams{ID: ruleId, ShortUrlID: dbShortUrl.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("Redirect rule not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get redirect rule", err)
+	}
+	rule, err := toRedirectRuleModel(dbRule)
+	if err != nil {
+		return nil, err
+	}
+
+	input.ApplyTo(rule)
+	if err := checkRedirectRule(&rule.Conditions, rule.TargetUrl); err != nil {
+		return nil, err
+	}
+	if input.TargetUrl != nil {
+		if err := checkDestinations(ctx, s.policy, destination{field: "target_url", url: rule.TargetUrl}); err != nil {
+			return nil, err
+		}
+	}
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to encode rule conditions", err)
+	}
+
+	var updated *models.RedirectRule
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		before, err := listRedirectRules(ctx, q, dbShortUrl.ID)
+		if err != nil {
+			return err
+		}
+
+		dbRule, err := q.UpdateRedirectRule(ctx, pgstore.UpdateRedirectRuleParams{
+			ID:         ruleId,
+			ShortUrlID: dbShortUrl.ID,
+			Position:   int32(rule.Position),
+			Conditions: conditions,
+			TargetUrl:  rule.TargetUrl,
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to update redirect rule", err)
+		}
+		if updated, err = toRedirectRuleModel(dbRule); err != nil {
+			return err
+		}
+
+		return recordRedirectRulesAudit(ctx, q, rawUserId, dbShortUrl.ID, before)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (s *redirectRuleService) DeleteRedirectRule(ctx context.Context, rawUserId string, shortUrlId string, id string) error {
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, shortUrlId, models.WorkspaceRoleEditor)
+	if err != nil {
+		return err
+	}
+
+	ruleId, err := uuid.Parse(id)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid redirect rule ID format")
+	}
+
+	return execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		before, err := listRedirectRules(ctx, q, dbShortUrl.ID)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := q.DeleteRedirectRule(ctx, pgstore.DeleteRedirectRuleParams{ID: ruleId, ShortUrlID: dbShortUrl.ID})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to delete redirect rule", err)
+		}
+		if deleted == 0 {
+			return wraperrors.NotFoundErr("Redirect rule not found")
+		}
+
+		return recordRedirectRulesAudit(ctx, q, rawUserId, dbShortUrl.ID, before)
+	})
+}
+
+// GetRedirectRules loads the rules evaluated when the link is visited, it
+// doesn't check permissions and must only be used by the redirect
+func (s *redirectRuleService) GetRedirectRules(ctx context.Context, shortUrlId string) ([]*models.RedirectRule, error) {
+	id, err := uuid.Parse(shortUrlId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid short URL ID format")
+	}
+
+	return listRedirectRules(ctx, s.db, id)
+}
+
+func listRedirectRules(ctx context.Context, db *pgstore.Queries, shortUrlId uuid.UUID) ([]*models.RedirectRule, error) {
+	dbRules, err := db.ListRedirectRules(ctx, shortUrlId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list redirect rules", err)
+	}
+
+	rules := make([]*models.RedirectRule, 0, len(dbRules))
+	for _, dbRule := range dbRules {
+		rule, err := toRedirectRuleModel(dbRule)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// recordRedirectRulesAudit logs rule changes as an update of the link with
+// the whole rule list before and after, the order matters as much as the rules
+func recordRedirectRulesAudit(ctx context.Context, q *pgstore.Queries, rawUserId string, shortUrlId uuid.UUID, before []*models.RedirectRule) error {
+	after, err := listRedirectRules(ctx, q, shortUrlId)
+	if err != nil {
+		return err
+	}
+
+	return recordAudit(ctx, q, auditActor(rawUserId), models.AuditEntityShortUrl, shortUrlId, models.AuditActionUpdate,
+		map[string]any{"redirect_rules": before},
+		map[string]any{"redirect_rules": after},
+	)
+}
+
+// checkRedirectRule validates what the input tags can't and normalizes the
+// conditions before they are stored
+func checkRedirectRule(conditions *models.RuleConditions, targetUrl string) error {
+	if err := conditions.Normalize(); err != nil {
+		return wraperrors.ValidationErr(err.Error())
+	}
+
+	u, err := url.ParseRequestURI(targetUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return wraperrors.ValidationErr("Invalid target_url")
+	}
+
+	return nil
+}
+
+func toRedirectRuleModel(dbRule pgstore.RedirectRule) (*models.RedirectRule, error) {
+	rule := &models.RedirectRule{
+		ID:         dbRule.ID.String(),
+		ShortUrlID: dbRule.ShortUrlID.String(),
+		Position:   int(dbRule.Position),
+		TargetUrl:  dbRule.TargetUrl,
+		CreatedAt:  dbRule.CreatedAt.Time,
+	}
+	if err := json.Unmarshal(dbRule.Conditions, &rule.Conditions); err != nil {
+		return nil, wraperrors.InternalErr("Failed to decode rule conditions", err)
+	}
+
+	return rule, nil
+}
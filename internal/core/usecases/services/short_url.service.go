@@ -3,10 +3,12 @@ package services
 import (
 	"context"
 	"errors"
+	"net/url"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
 	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
 	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
@@ -15,175 +17,911 @@ import (
 )
 
 type shortUrlService struct {
-	db *pgstore.Queries
+	db     *pgstore.Queries
+	policy ports.DestinationPolicy
 }
 
-func NewShortUrlService(queries *pgstore.Queries) ports.ShortUrlUseCase {
+func NewShortUrlService(queries *pgstore.Queries, policy ports.DestinationPolicy) ports.ShortUrlUseCase {
 	return &shortUrlService{
-		db: queries,
+		db:     queries,
+		policy: policy,
 	}
 }
 
-func (s *shortUrlService) ListShortUrl(ctx context.Context, rawUserId string) ([]*models.ShortUrl, error) {
+// ListShortUrl lists a page of links with the user's tags and folders,
+// counting the links of each one among the personal or workspace links
+func (s *shortUrlService) ListShortUrl(ctx context.Context, rawUserId string, filter models.ShortUrlFilter) (*models.ShortUrlList, *models.Pagination, error) {
 	userId, err := uuid.Parse(rawUserId)
 	if err != nil {
-		return nil, wraperrors.ValidationErr("Invalid user ID format")
+		return nil, nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	var workspaceId pgtype.UUID
+	if filter.WorkspaceID != "" {
+		id, err := uuid.Parse(filter.WorkspaceID)
+		if err != nil {
+			return nil, nil, wraperrors.ValidationErr("Invalid workspace ID format")
+		}
+		if _, err := requireWorkspaceRole(ctx, s.db, id, userId, models.WorkspaceRoleViewer); err != nil {
+			return nil, nil, err
+		}
+		workspaceId = pgtype.UUID{Bytes: id, Valid: true}
 	}
 
-	dbShortUrls, err := s.db.GetShortUrlsByUserId(ctx, userId)
+	page, err := normalizePage(filter.Page)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, wraperrors.NotFoundErr("Short URLs not found for this user ID")
+		return nil, nil, err
+	}
+	if filter.Sort == "" {
+		filter.Sort = models.ShortUrlSortCreatedAt
+	}
+	params, err := listShortUrlsParams(userId, workspaceId, filter, page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if filter.CampaignID != "" {
+		dbCampaign, err := getUserCampaign(ctx, s.db, userId, filter.CampaignID)
+		if err != nil {
+			return nil, nil, err
+		}
+		params.CampaignID = pgtype.UUID{Bytes: dbCampaign.ID, Valid: true}
+	}
+	if filter.TagID != "" {
+		dbTag, err := getUserTag(ctx, s.db, userId, filter.TagID)
+		if err != nil {
+			return nil, nil, err
+		}
+		params.TagID = pgtype.UUID{Bytes: dbTag.ID, Valid: true}
+	}
+	if filter.FolderID != "" {
+		dbFolder, err := getUserFolder(ctx, s.db, userId, filter.FolderID)
+		if err != nil {
+			return nil, nil, err
 		}
-		return nil, wraperrors.InternalErr("Failed to list short URLs", err)
+		params.FolderID = pgtype.UUID{Bytes: dbFolder.ID, Valid: true}
+	}
+
+	dbShortUrls, err := s.db.ListShortUrls(ctx, params)
+	if err != nil {
+		return nil, nil, wraperrors.InternalErr("Failed to list short URLs", err)
+	}
+	dbShortUrls, pagination := paginate(dbShortUrls, page)
+	if pagination.HasMore {
+		pagination.NextCursor = shortUrlCursor(dbShortUrls[len(dbShortUrls)-1], filter.Sort, page.Order).Encode()
 	}
 
 	shortUrls := make([]*models.ShortUrl, 0, len(dbShortUrls))
 	for _, dbShortUrl := range dbShortUrls {
-		shortUrls = append(shortUrls, &models.ShortUrl{
-			ID:          dbShortUrl.ID.String(),
-			Slug:        dbShortUrl.Slug,
-			OriginalUrl: dbShortUrl.OriginalUrl,
-			ExpiresAt:   &dbShortUrl.ExpiresAt.Time,
-			CreatedAt:   dbShortUrl.CreatedAt.Time,
-		})
+		shortUrls = append(shortUrls, toShortUrlModel(dbShortUrl))
+	}
+	if err := attachTags(ctx, s.db, shortUrls...); err != nil {
+		return nil, nil, err
+	}
+	if err := attachVariants(ctx, s.db, shortUrls...); err != nil {
+		return nil, nil, err
+	}
+	if err := attachMetadata(ctx, s.db, shortUrls...); err != nil {
+		return nil, nil, err
+	}
+	if err := attachHealth(ctx, s.db, shortUrls...); err != nil {
+		return nil, nil, err
+	}
+	if err := attachDomain(ctx, s.db, shortUrls...); err != nil {
+		return nil, nil, err
+	}
+
+	tags, folders, err := listOrganizers(ctx, s.db, userId, workspaceId)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return shortUrls, nil
+	return &models.ShortUrlList{ShortUrls: shortUrls, Tags: tags, Folders: folders}, pagination, nil
 }
 
-func (s *shortUrlService) GetShortUrl(ctx context.Context, id string) (*models.ShortUrl, error) {
-	shortUrlId, err := uuid.Parse(id)
+func (s *shortUrlService) GetShortUrl(ctx context.Context, rawUserId string, id string) (*models.ShortUrl, error) {
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, id, models.WorkspaceRoleViewer)
 	if err != nil {
-		return nil, wraperrors.ValidationErr("Invalid short URL ID format")
+		return nil, err
+	}
+
+	shortUrl := toShortUrlModel(*dbShortUrl)
+	if err := attachVariants(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachTags(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachMetadata(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachHealth(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachDomain(ctx, s.db, shortUrl); err != nil {
+		return nil, err
 	}
 
-	dbShortUrl, err := s.db.GetShortUrlById(ctx, shortUrlId)
+	return shortUrl, nil
+}
+
+// GetShortUrlBySlug loads a link that still redirects by its key, the slug on
+// the shortener host or <domain id>/<slug> on a custom domain
+func (s *shortUrlService) GetShortUrlBySlug(ctx context.Context, key string) (*models.ShortUrl, error) {
+	dbShortUrl, err := s.getRedirectableShortUrl(ctx, key)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, wraperrors.NotFoundErr("Short URL not found")
-		}
-		return nil, wraperrors.InternalErr("Failed to get short URL", err)
+		return nil, err
+	}
+
+	shortUrl := toShortUrlModel(dbShortUrl)
+	if err := attachVariants(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachMetadata(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachDomain(ctx, s.db, shortUrl); err != nil {
+		return nil, err
 	}
 
-	return &models.ShortUrl{
-		ID:          dbShortUrl.ID.String(),
-		Slug:        dbShortUrl.Slug,
-		OriginalUrl: dbShortUrl.OriginalUrl,
-		ExpiresAt:   &dbShortUrl.ExpiresAt.Time,
-		CreatedAt:   dbShortUrl.CreatedAt.Time,
-	}, nil
+	return shortUrl, nil
 }
 
-func (s *shortUrlService) GetShortUrlBySlug(ctx context.Context, slug string) (*models.ShortUrl, error) {
-	dbShortUrl, err := s.db.GetShortUrlBySlug(ctx, slug)
+// CreateShortUrl creates a link, the boolean tells that an existing link was
+// returned instead because the input asked to reuse it
+func (s *shortUrlService) CreateShortUrl(ctx context.Context, rawUserId string, input *models.CreateShortUrlInput) (*models.ShortUrl, bool, error) {
+	userId, err := uuid.Parse(rawUserId)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, wraperrors.NotFoundErr("Short URL not found")
+		return nil, false, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	if err := checkEmailVerified(ctx, s.db, userId); err != nil {
+		return nil, false, err
+	}
+
+	var domainId pgtype.UUID
+	if input.DomainID != nil {
+		dbDomain, err := getUserDomain(ctx, s.db, userId, *input.DomainID)
+		if err != nil {
+			return nil, false, err
 		}
-		return nil, wraperrors.InternalErr("Failed to get short URL", err)
+		if !dbDomain.VerifiedAt.Valid {
+			return nil, false, wraperrors.ValidationErr("The domain must be verified before links are put on it")
+		}
+		// a domain belongs to one user and goes away with them, the links
+		// of a workspace must not depend on it
+		if input.WorkspaceID != nil {
+			return nil, false, wraperrors.ValidationErr("Workspace links can't be put on a personal domain")
+		}
+		domainId = pgtype.UUID{Bytes: dbDomain.ID, Valid: true}
+	}
+	slug, err := s.chooseSlug(ctx, domainId, input.Slug)
+	if err != nil {
+		return nil, false, err
 	}
 
-	if dbShortUrl.ExpiresAt.Valid && dbShortUrl.ExpiresAt.Time.Before(time.Now()) {
-		return nil, wraperrors.NotFoundErr("Short URL has expired")
+	var workspaceId pgtype.UUID
+	if input.WorkspaceID != nil {
+		id, err := uuid.Parse(*input.WorkspaceID)
+		if err != nil {
+			return nil, false, wraperrors.ValidationErr("Invalid workspace ID format")
+		}
+		if _, err := requireWorkspaceRole(ctx, s.db, id, userId, models.WorkspaceRoleEditor); err != nil {
+			return nil, false, err
+		}
+		workspaceId = pgtype.UUID{Bytes: id, Valid: true}
 	}
 
-	return &models.ShortUrl{
-		ID:          dbShortUrl.ID.String(),
-		Slug:        dbShortUrl.Slug,
-		OriginalUrl: dbShortUrl.OriginalUrl,
-		ExpiresAt:   &dbShortUrl.ExpiresAt.Time,
-		CreatedAt:   dbShortUrl.CreatedAt.Time,
-	}, nil
+	startsAt, err := models.ParseTimestamp(input.StartsAt)
+	if err != nil {
+		return nil, false, wraperrors.ValidationErr("Invalid starts_at, expected an RFC3339 date")
+	}
+	expiresAt, _ := models.ParseTimestamp(input.ExpiresAt)
+	if err := checkActivation(startsAt, expiresAt, input.InactiveFallback, input.InactiveUrl); err != nil {
+		return nil, false, err
+	}
+
+	passwordHash, err := hashLinkPassword(input.Password)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := models.CheckVariants(input.Variants); err != nil {
+		return nil, false, wraperrors.ValidationErr(err.Error())
+	}
+
+	var campaignId pgtype.UUID
+	utm := input.UtmParams
+	if input.CampaignID != nil {
+		dbCampaign, err := getUserCampaign(ctx, s.db, userId, *input.CampaignID)
+		if err != nil {
+			return nil, false, err
+		}
+		campaignId = pgtype.UUID{Bytes: dbCampaign.ID, Valid: true}
+		utm = utm.WithDefaults(toCampaignModel(dbCampaign).UtmParams)
+	}
+	if input.OriginalUrl, err = utm.ApplyTo(input.OriginalUrl); err != nil {
+		return nil, false, wraperrors.ValidationErr("Invalid original_url")
+	}
+	if err := checkDestinations(ctx, s.policy, linkDestinations(input.OriginalUrl, input.InactiveUrl, input.Variants)...); err != nil {
+		return nil, false, err
+	}
+
+	if input.ReuseExisting && !hasLinkSettings(input) {
+		existing, err := s.findReusableShortUrl(ctx, userId, workspaceId, domainId, input.OriginalUrl, input.IgnoreTrackingParams)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing != nil {
+			return existing, true, nil
+		}
+	}
+
+	var folderId pgtype.UUID
+	if input.FolderID != nil {
+		dbFolder, err := getUserFolder(ctx, s.db, userId, *input.FolderID)
+		if err != nil {
+			return nil, false, err
+		}
+		folderId = pgtype.UUID{Bytes: dbFolder.ID, Valid: true}
+	}
+	dbTags, err := getUserTags(ctx, s.db, userId, input.TagIDs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	pgShortUrl := input.ToPgCreateShortUrl(userId, workspaceId, slug, passwordHash)
+	pgShortUrl.CampaignID = campaignId
+	pgShortUrl.FolderID = folderId
+	pgShortUrl.DomainID = domainId
+	var shortUrl *models.ShortUrl
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		dbShortUrl, err := q.CreateShortUrl(ctx, *pgShortUrl)
+		if err != nil {
+			// a chosen slug may be taken between the check and the insert
+			if wraperrors.IsUniqueViolation(err) {
+				return wraperrors.AlreadyExistsErr("The slug is already taken on this domain")
+			}
+			return wraperrors.InternalErr("Failed to create short URL", err)
+		}
+		shortUrl = toShortUrlModel(dbShortUrl)
+
+		if err := requestMetadata(ctx, q, dbShortUrl.ID, dbShortUrl.OriginalUrl); err != nil {
+			return err
+		}
+
+		if len(input.Variants) > 0 {
+			if shortUrl.Variants, err = replaceVariants(ctx, q, dbShortUrl.ID, nil, input.Variants); err != nil {
+				return err
+			}
+		}
+
+		if len(dbTags) > 0 {
+			if err := replaceTags(ctx, q, userId, dbShortUrl.ID, dbTags); err != nil {
+				return err
+			}
+			if err := attachTags(ctx, q, shortUrl); err != nil {
+				return err
+			}
+		}
+
+		return recordAudit(ctx, q, userId, models.AuditEntityShortUrl, dbShortUrl.ID, models.AuditActionCreate, nil, shortUrl)
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	shortUrl.Metadata = &models.ShortUrlMetadata{Status: models.MetadataStatusPending}
+	if err := attachDomain(ctx, s.db, shortUrl); err != nil {
+		return nil, false, err
+	}
+
+	return shortUrl, false, nil
+}
+
+// chooseSlug returns the slug asked for a link on a custom domain once it is
+// known to be free there, or generates one. Links on the shortener host always
+// get a generated slug
+func (s *shortUrlService) chooseSlug(ctx context.Context, domainId pgtype.UUID, slug string) (string, error) {
+	if slug == "" {
+		return s.genSlug(ctx, domainId, 1)
+	}
+	if !domainId.Valid {
+		return "", wraperrors.ValidationErr("A slug can only be chosen for links on a custom domain")
+	}
+	if tag := models.CheckImportSlug(slug); tag != "" {
+		return "", wraperrors.FieldValidationErr("Invalid slug", wraperrors.FieldError{Field: "slug", Tag: tag, Value: slug})
+	}
+
+	_, err := s.db.GetShortUrlBySlug(ctx, pgstore.GetShortUrlBySlugParams{Slug: slug, DomainID: domainId})
+	if err == nil {
+		return "", wraperrors.AlreadyExistsErr("The slug is already taken on this domain")
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", wraperrors.InternalErr("error checking slug existence", err)
+	}
+
+	return slug, nil
+}
+
+// hasLinkSettings reports whether the input asks for more than a plain link,
+// such a link is always created: an existing one can't be checked to hold the
+// same password and would lose the other settings
+func hasLinkSettings(input *models.CreateShortUrlInput) bool {
+	return input.Password != "" || input.ExpiresAt != nil || input.MaxClicks != nil || input.StartsAt != nil ||
+		input.Interstitial || len(input.Variants) > 0 ||
+		(input.InactiveFallback != "" && input.InactiveFallback != models.InactiveFallbackNotFound) || input.InactiveUrl != nil ||
+		(input.RedirectStatus != 0 && input.RedirectStatus != models.DefaultRedirectStatus) || input.ForwardQuery ||
+		(input.QueryMerge != "" && input.QueryMerge != models.QueryMergeKeepDestination) ||
+		input.CampaignID != nil || input.FolderID != nil || len(input.TagIDs) > 0
+}
+
+// isPlainShortUrl reports whether a stored link behaves like one created with
+// nothing but its original URL
+func isPlainShortUrl(dbShortUrl pgstore.ShortUrl) bool {
+	return !dbShortUrl.PasswordHash.Valid && !dbShortUrl.ExpiresAt.Valid && !dbShortUrl.MaxClicks.Valid && !dbShortUrl.StartsAt.Valid &&
+		!dbShortUrl.Interstitial &&
+		dbShortUrl.InactiveFallback == models.InactiveFallbackNotFound && !dbShortUrl.InactiveUrl.Valid &&
+		dbShortUrl.RedirectStatus == models.DefaultRedirectStatus && !dbShortUrl.ForwardQuery &&
+		dbShortUrl.QueryMerge == models.QueryMergeKeepDestination
 }
 
-func (s *shortUrlService) CreateShortUrl(ctx context.Context, rawUserId string, input *models.CreateShortUrlInput) (*models.ShortUrl, error) {
-	slug, err := s.genSlug(ctx, 1)
+// findReusableShortUrl returns the oldest active link of the user, or of the
+// workspace, on the same domain leading to the same URL as originalUrl once
+// both are normalized, nil when there is none. Only plain links are handed
+// out, without variants or rules sending some visitors elsewhere
+func (s *shortUrlService) findReusableShortUrl(ctx context.Context, userId uuid.UUID, workspaceId pgtype.UUID, domainId pgtype.UUID, originalUrl string, ignoreTracking bool) (*models.ShortUrl, error) {
+	normalized, err := models.NormalizeUrl(originalUrl, ignoreTracking)
 	if err != nil {
-		return nil, err
+		return nil, wraperrors.ValidationErr("Invalid original_url")
 	}
+	parsed, _ := url.Parse(normalized)
+
+	// the host narrows the links down, the comparison is made on the
+	// normalized URLs as the stored ones keep the spelling they were given in
+	candidates, err := s.db.ListShortUrlsByDestinationHost(ctx, pgstore.ListShortUrlsByDestinationHostParams{
+		WorkspaceID: workspaceId,
+		UserID:      userId,
+		Host:        parsed.Hostname(),
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to look up existing short URLs", err)
+	}
+
+	for _, candidate := range candidates {
+		if candidate.DomainID != domainId {
+			continue
+		}
+		if candidateUrl, err := models.NormalizeUrl(candidate.OriginalUrl, ignoreTracking); err != nil || candidateUrl != normalized {
+			continue
+		}
+
+		if !isPlainShortUrl(candidate) {
+			continue
+		}
+		rules, err := s.db.CountRedirectRules(ctx, candidate.ID)
+		if err != nil {
+			return nil, wraperrors.InternalErr("Failed to count redirect rules", err)
+		}
+		if rules > 0 {
+			continue
+		}
+
+		shortUrl := toShortUrlModel(candidate)
+		if err := attachVariants(ctx, s.db, shortUrl); err != nil {
+			return nil, err
+		}
+		if len(shortUrl.Variants) > 0 {
+			continue
+		}
+		if err := attachTags(ctx, s.db, shortUrl); err != nil {
+			return nil, err
+		}
+		if err := attachMetadata(ctx, s.db, shortUrl); err != nil {
+			return nil, err
+		}
+		if err := attachHealth(ctx, s.db, shortUrl); err != nil {
+			return nil, err
+		}
+		if err := attachDomain(ctx, s.db, shortUrl); err != nil {
+			return nil, err
+		}
+		return shortUrl, nil
+	}
+
+	return nil, nil
+}
 
+func (s *shortUrlService) UpdateShortUrl(ctx context.Context, rawUserId string, id string, input *models.UpdateShortUrlInput) (*models.ShortUrl, error) {
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, id, models.WorkspaceRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	before := toShortUrlModel(*dbShortUrl)
+	if err := attachVariants(ctx, s.db, before); err != nil {
+		return nil, err
+	}
+	if err := attachTags(ctx, s.db, before); err != nil {
+		return nil, err
+	}
+	shortUrl := toShortUrlModel(*dbShortUrl)
 	userId, err := uuid.Parse(rawUserId)
 	if err != nil {
 		return nil, wraperrors.ValidationErr("Invalid user ID format")
 	}
 
-	pgShortUrl := input.ToPgCreateShortUrl(userId, slug)
-	dbShortUrl, err := s.db.CreateShortUrl(ctx, *pgShortUrl)
+	// the slug chosen for a custom domain is kept, the shortener host ones are
+	// regenerated on every update
+	slug := dbShortUrl.Slug
+	if !dbShortUrl.DomainID.Valid {
+		if slug, err = s.genSlug(ctx, dbShortUrl.DomainID, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	passwordHash := dbShortUrl.PasswordHash
+	if input.Password != nil {
+		passwordHash, err = hashLinkPassword(*input.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := models.ParseTimestamp(input.StartsAt); err != nil {
+		return nil, wraperrors.ValidationErr("Invalid starts_at, expected an RFC3339 date")
+	}
+
+	if input.CampaignID != nil && *input.CampaignID != "" {
+		if _, err := getUserCampaign(ctx, s.db, userId, *input.CampaignID); err != nil {
+			return nil, err
+		}
+	}
+	if input.FolderID != nil && *input.FolderID != "" {
+		if _, err := getUserFolder(ctx, s.db, userId, *input.FolderID); err != nil {
+			return nil, err
+		}
+	}
+	var dbTags []pgstore.Tag
+	if input.TagIDs != nil {
+		if dbTags, err = getUserTags(ctx, s.db, userId, *input.TagIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	input.ApplyTo(shortUrl)
+	if err := checkActivation(shortUrl.StartsAt, shortUrl.ExpiresAt, shortUrl.InactiveFallback, shortUrl.InactiveUrl); err != nil {
+		return nil, err
+	}
+
+	if input.Variants != nil {
+		if err := models.CheckVariants(*input.Variants); err != nil {
+			return nil, wraperrors.ValidationErr(err.Error())
+		}
+	}
+
+	// only the destinations being changed are screened, so links created
+	// before a policy change can still be edited
+	var destinations []destination
+	if input.OriginalUrl != nil {
+		destinations = append(destinations, destination{field: "original_url", url: *input.OriginalUrl})
+	}
+	if input.InactiveUrl != nil && *input.InactiveUrl != "" {
+		destinations = append(destinations, destination{field: "inactive_url", url: *input.InactiveUrl})
+	}
+	if input.Variants != nil {
+		destinations = append(destinations, variantDestinations(*input.Variants)...)
+	}
+	if err := checkDestinations(ctx, s.policy, destinations...); err != nil {
+		return nil, err
+	}
+
+	pgShortUrl := input.ToPgUpdateShortUrl(dbShortUrl.ID, shortUrl, slug, passwordHash)
+
+	var updated *models.ShortUrl
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		if err := recordInitialVersion(ctx, q, dbShortUrl.ID); err != nil {
+			return err
+		}
+		updatedShortUrl, err := q.UpdateShortUrl(ctx, *pgShortUrl)
+		if err != nil {
+			return wraperrors.InternalErr("Failed to update short URL", err)
+		}
+		updated = toShortUrlModel(updatedShortUrl)
+
+		if updatedShortUrl.OriginalUrl != dbShortUrl.OriginalUrl {
+			if err := requestMetadata(ctx, q, dbShortUrl.ID, updatedShortUrl.OriginalUrl); err != nil {
+				return err
+			}
+			// the checks of the old destination say nothing about the new one
+			if err := q.DeleteShortUrlHealth(ctx, dbShortUrl.ID); err != nil {
+				return wraperrors.InternalErr("Failed to reset destination health", err)
+			}
+		}
+
+		updated.Variants = before.Variants
+		if input.Variants != nil {
+			if updated.Variants, err = replaceVariants(ctx, q, dbShortUrl.ID, before.Variants, *input.Variants); err != nil {
+				return err
+			}
+		}
+
+		updated.Tags = before.Tags
+		if input.TagIDs != nil {
+			if err := replaceTags(ctx, q, userId, dbShortUrl.ID, dbTags); err != nil {
+				return err
+			}
+			if err := attachTags(ctx, q, updated); err != nil {
+				return err
+			}
+		}
+
+		if err := recordVersion(ctx, q, rawUserId, dbShortUrl.ID, pgtype.Int4{}); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, auditActor(rawUserId), models.AuditEntityShortUrl, dbShortUrl.ID, models.AuditActionUpdate, before, updated)
+	})
 	if err != nil {
-		return nil, wraperrors.InternalErr("Failed to create short URL", err)
+		return nil, err
+	}
+	if err := attachMetadata(ctx, s.db, updated); err != nil {
+		return nil, err
+	}
+	if err := attachHealth(ctx, s.db, updated); err != nil {
+		return nil, err
+	}
+	if err := attachDomain(ctx, s.db, updated); err != nil {
+		return nil, err
 	}
 
-	var expiresAt *time.Time
-	if dbShortUrl.ExpiresAt.Valid {
-		expiresAt = &dbShortUrl.ExpiresAt.Time
+	return updated, nil
+}
+
+// DeleteShortUrl only marks the link as deleted, it can be restored within
+// the restore window and is purged after the retention period
+func (s *shortUrlService) DeleteShortUrl(ctx context.Context, rawUserId string, id string) error {
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, id, models.WorkspaceRoleEditor)
+	if err != nil {
+		return err
 	}
 
-	return &models.ShortUrl{
-		ID:          dbShortUrl.ID.String(),
-		Slug:        dbShortUrl.Slug,
-		OriginalUrl: dbShortUrl.OriginalUrl,
-		ExpiresAt:   expiresAt,
-		CreatedAt:   dbShortUrl.CreatedAt.Time,
-	}, nil
+	return execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		deleted, err := q.SoftDeleteShortUrl(ctx, dbShortUrl.ID)
+		if err != nil {
+			return wraperrors.InternalErr("Failed to delete short URL", err)
+		}
+		if deleted == 0 {
+			return wraperrors.NotFoundErr("Short URL not found")
+		}
+
+		return recordAudit(ctx, q, auditActor(rawUserId), models.AuditEntityShortUrl, dbShortUrl.ID, models.AuditActionDelete, toShortUrlModel(*dbShortUrl), nil)
+	})
 }
 
-func (s *shortUrlService) UpdateShortUrl(ctx context.Context, id string, input *models.UpdateShortUrlInput) (*models.ShortUrl, error) {
+func (s *shortUrlService) RestoreShortUrl(ctx context.Context, rawUserId string, id string) (*models.ShortUrl, error) {
 	shortUrlId, err := uuid.Parse(id)
 	if err != nil {
 		return nil, wraperrors.ValidationErr("Invalid short URL ID format")
 	}
 
-	shortUrl, err := s.GetShortUrl(ctx, id)
+	dbShortUrl, err := s.db.GetDeletedShortUrlById(ctx, shortUrlId)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("Deleted short URL not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get short URL", err)
+	}
+
+	if err := authorizeShortUrl(ctx, s.db, rawUserId, dbShortUrl, models.WorkspaceRoleEditor); err != nil {
 		return nil, err
 	}
 
-	slug, err := s.genSlug(ctx, 1)
+	if err := checkRestoreWindow(dbShortUrl.DeletedAt); err != nil {
+		return nil, err
+	}
+
+	var shortUrl *models.ShortUrl
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		restored, err := q.RestoreShortUrl(ctx, dbShortUrl.ID)
+		if err != nil {
+			return wraperrors.InternalErr("Failed to restore short URL", err)
+		}
+		shortUrl = toShortUrlModel(restored)
+
+		return recordAudit(ctx, q, auditActor(rawUserId), models.AuditEntityShortUrl, dbShortUrl.ID, models.AuditActionRestore, nil, shortUrl)
+	})
 	if err != nil {
 		return nil, err
 	}
+	if err := attachVariants(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachTags(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachMetadata(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachHealth(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachDomain(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
 
-	input.ApplyTo(shortUrl)
-	pgShortUrl := input.ToPgUpdateShortUrl(shortUrlId, slug)
+	return shortUrl, nil
+}
+
+// TransferShortUrl hands a link over to a workspace or to a user's personal
+// links. Moving a link out of a workspace requires being one of its owners.
+// Another user only receives links from a workspace they are a member of, no
+// one gets links pushed into their account by a stranger. Folders, campaigns
+// and tags belong to the previous owner, they are dropped when the owner
+// changes. Links on a custom domain stay with the domain owner
+func (s *shortUrlService) TransferShortUrl(ctx context.Context, rawUserId string, id string, input *models.TransferShortUrlInput) (*models.ShortUrl, error) {
+	if (input.WorkspaceID == nil) == (input.UserEmail == nil) {
+		return nil, wraperrors.ValidationErr("Provide either workspace_id or user_email")
+	}
+
+	sourceRole := models.WorkspaceRoleEditor
+	if input.UserEmail != nil {
+		sourceRole = models.WorkspaceRoleOwner
+	}
 
-	dbShortUrl, err := s.db.UpdateShortUrl(ctx, *pgShortUrl)
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, id, sourceRole)
 	if err != nil {
-		return nil, wraperrors.InternalErr("Failed to update short URL", err)
+		return nil, err
+	}
+	if dbShortUrl.DomainID.Valid {
+		return nil, wraperrors.ValidationErr("Links on a custom domain can't be transferred")
 	}
+	userId, _ := uuid.Parse(rawUserId)
 
-	var expiresAt *time.Time
-	if dbShortUrl.ExpiresAt.Valid {
-		expiresAt = &dbShortUrl.ExpiresAt.Time
+	params := pgstore.TransferShortUrlParams{ID: dbShortUrl.ID}
+	if input.WorkspaceID != nil {
+		workspaceId, err := uuid.Parse(*input.WorkspaceID)
+		if err != nil {
+			return nil, wraperrors.ValidationErr("Invalid workspace ID format")
+		}
+		if _, err := requireWorkspaceRole(ctx, s.db, workspaceId, userId, models.WorkspaceRoleEditor); err != nil {
+			return nil, err
+		}
+		params.UserID = dbShortUrl.UserID
+		params.WorkspaceID = pgtype.UUID{Bytes: workspaceId, Valid: true}
+	} else {
+		dbUser, err := s.db.GetUserByEmail(ctx, *input.UserEmail)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, wraperrors.NotFoundErr("User not found")
+			}
+			return nil, wraperrors.InternalErr("Failed to get user", err)
+		}
+		if dbUser.ID != userId {
+			if !dbShortUrl.WorkspaceID.Valid {
+				return nil, wraperrors.ForbiddenErr("Links can only be transferred to members of their workspace")
+			}
+			if _, err := requireWorkspaceRole(ctx, s.db, dbShortUrl.WorkspaceID.Bytes, dbUser.ID, models.WorkspaceRoleViewer); err != nil {
+				if wraperrors.IsNotFoundError(err) {
+					return nil, wraperrors.ForbiddenErr("Links can only be transferred to members of their workspace")
+				}
+				return nil, err
+			}
+		}
+		params.UserID = pgtype.UUID{Bytes: dbUser.ID, Valid: true}
 	}
 
-	return &models.ShortUrl{
-		ID:          dbShortUrl.ID.String(),
-		Slug:        dbShortUrl.Slug,
-		OriginalUrl: dbShortUrl.OriginalUrl,
-		ExpiresAt:   expiresAt,
-		CreatedAt:   dbShortUrl.CreatedAt.Time,
-	}, nil
+	var shortUrl *models.ShortUrl
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		transferred, err := q.TransferShortUrl(ctx, params)
+		if err != nil {
+			return wraperrors.InternalErr("Failed to transfer short URL", err)
+		}
+		shortUrl = toShortUrlModel(transferred)
+
+		if params.UserID != dbShortUrl.UserID {
+			if err := q.ClearShortUrlTags(ctx, dbShortUrl.ID); err != nil {
+				return wraperrors.InternalErr("Failed to clear short URL tags", err)
+			}
+		}
+
+		return recordAudit(ctx, q, userId, models.AuditEntityShortUrl, dbShortUrl.ID, models.AuditActionTransfer, toShortUrlModel(*dbShortUrl), shortUrl)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := attachVariants(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachTags(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachMetadata(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+	if err := attachHealth(ctx, s.db, shortUrl); err != nil {
+		return nil, err
+	}
+
+	return shortUrl, nil
 }
 
-func (s *shortUrlService) DeleteShortUrl(ctx context.Context, id string) error {
+// getAuthorizedShortUrl loads a link the user may act on with at least minRole
+func getAuthorizedShortUrl(ctx context.Context, db *pgstore.Queries, rawUserId string, id string, minRole string) (*pgstore.ShortUrl, error) {
 	shortUrlId, err := uuid.Parse(id)
 	if err != nil {
-		return wraperrors.InternalErr("something went wrong", err)
+		return nil, wraperrors.ValidationErr("Invalid short URL ID format")
 	}
 
-	err = s.db.DeleteShortUrl(ctx, shortUrlId)
+	dbShortUrl, err := db.GetShortUrlById(ctx, shortUrlId)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("Short URL not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get short URL", err)
+	}
+
+	if err := authorizeShortUrl(ctx, db, rawUserId, dbShortUrl, minRole); err != nil {
+		return nil, err
+	}
+
+	return &dbShortUrl, nil
+}
+
+// authorizeShortUrl allows personal links only to their owner and workspace
+// links to members holding at least minRole
+func authorizeShortUrl(ctx context.Context, db *pgstore.Queries, rawUserId string, dbShortUrl pgstore.ShortUrl, minRole string) error {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	if !dbShortUrl.WorkspaceID.Valid {
+		if dbShortUrl.UserID.Valid && uuid.UUID(dbShortUrl.UserID.Bytes) == userId {
+			return nil
+		}
+		return wraperrors.NotFoundErr("Short URL not found")
+	}
+
+	if _, err := requireWorkspaceRole(ctx, db, dbShortUrl.WorkspaceID.Bytes, userId, minRole); err != nil {
+		if wraperrors.IsNotFoundError(err) {
 			return wraperrors.NotFoundErr("Short URL not found")
 		}
-		return wraperrors.InternalErr("Failed to update short URL", err)
+		return err
 	}
 
 	return nil
 }
 
-func (s *shortUrlService) genSlug(ctx context.Context, count int) (string, error) {
+func toShortUrlModel(dbShortUrl pgstore.ShortUrl) *models.ShortUrl {
+	shortUrl := &models.ShortUrl{
+		ID:               dbShortUrl.ID.String(),
+		Slug:             dbShortUrl.Slug,
+		OriginalUrl:      dbShortUrl.OriginalUrl,
+		CreatedAt:        dbShortUrl.CreatedAt.Time,
+		AccessCount:      int(dbShortUrl.AccessCount.Int32),
+		ScanCount:        int(dbShortUrl.ScanCount),
+		HasPassword:      dbShortUrl.PasswordHash.Valid,
+		InactiveFallback: dbShortUrl.InactiveFallback,
+		StickyVariants:   dbShortUrl.StickyVariants,
+		RedirectStatus:   int(dbShortUrl.RedirectStatus),
+		ForwardQuery:     dbShortUrl.ForwardQuery,
+		QueryMerge:       dbShortUrl.QueryMerge,
+		Interstitial:     dbShortUrl.Interstitial,
+	}
+	if dbShortUrl.StartsAt.Valid {
+		shortUrl.StartsAt = &dbShortUrl.StartsAt.Time
+	}
+	if dbShortUrl.InactiveUrl.Valid {
+		shortUrl.InactiveUrl = &dbShortUrl.InactiveUrl.String
+	}
+	if dbShortUrl.MaxClicks.Valid {
+		maxClicks := int(dbShortUrl.MaxClicks.Int32)
+		remainingClicks := max(maxClicks-shortUrl.AccessCount, 0)
+		shortUrl.MaxClicks = &maxClicks
+		shortUrl.RemainingClicks = &remainingClicks
+	}
+	if dbShortUrl.UserID.Valid {
+		shortUrl.UserID = uuid.UUID(dbShortUrl.UserID.Bytes).String()
+	}
+	if dbShortUrl.WorkspaceID.Valid {
+		workspaceId := uuid.UUID(dbShortUrl.WorkspaceID.Bytes).String()
+		shortUrl.WorkspaceID = &workspaceId
+	}
+	if dbShortUrl.ExpiresAt.Valid {
+		shortUrl.ExpiresAt = &dbShortUrl.ExpiresAt.Time
+	}
+	if dbShortUrl.CampaignID.Valid {
+		campaignId := uuid.UUID(dbShortUrl.CampaignID.Bytes).String()
+		shortUrl.CampaignID = &campaignId
+	}
+	if dbShortUrl.FolderID.Valid {
+		folderId := uuid.UUID(dbShortUrl.FolderID.Bytes).String()
+		shortUrl.FolderID = &folderId
+	}
+	if dbShortUrl.DomainID.Valid {
+		domainId := uuid.UUID(dbShortUrl.DomainID.Bytes).String()
+		shortUrl.DomainID = &domainId
+	}
+
+	return shortUrl
+}
+
+// getRedirectableShortUrl loads a link by key as long as it still redirects
+func (s *shortUrlService) getRedirectableShortUrl(ctx context.Context, key string) (pgstore.ShortUrl, error) {
+	var dbShortUrl pgstore.ShortUrl
+	rawDomainId, slug := models.ParseLinkKey(key)
+	var domainId pgtype.UUID
+	if rawDomainId != nil {
+		id, err := uuid.Parse(*rawDomainId)
+		if err != nil {
+			return dbShortUrl, wraperrors.NotFoundErr("Short URL not found")
+		}
+		domainId = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	dbShortUrl, err := s.db.GetShortUrlBySlug(ctx, pgstore.GetShortUrlBySlugParams{Slug: slug, DomainID: domainId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dbShortUrl, wraperrors.NotFoundErr("Short URL not found")
+		}
+		return dbShortUrl, wraperrors.InternalErr("Failed to get short URL", err)
+	}
+
+	if dbShortUrl.DeletedAt.Valid {
+		return dbShortUrl, wraperrors.GoneErr("Short URL has been deleted")
+	}
+
+	if dbShortUrl.ExpiresAt.Valid && dbShortUrl.ExpiresAt.Time.Before(time.Now()) {
+		return dbShortUrl, wraperrors.NotFoundErr("Short URL has expired")
+	}
+
+	if dbShortUrl.MaxClicks.Valid && dbShortUrl.AccessCount.Int32 >= dbShortUrl.MaxClicks.Int32 {
+		return dbShortUrl, wraperrors.GoneErr("Short URL has reached its click limit")
+	}
+
+	return dbShortUrl, nil
+}
+
+// checkActivation validates the activation window and the fallback shown
+// before it opens
+func checkActivation(startsAt, expiresAt *time.Time, fallback string, inactiveUrl *string) error {
+	if startsAt != nil && expiresAt != nil && !startsAt.Before(*expiresAt) {
+		return wraperrors.ValidationErr("starts_at must be before expires_at")
+	}
+
+	if fallback == models.InactiveFallbackHoldingPage && (inactiveUrl == nil || *inactiveUrl == "") {
+		return wraperrors.ValidationErr("inactive_url is required for the holding_page fallback")
+	}
+
+	if inactiveUrl != nil && *inactiveUrl != "" {
+		u, err := url.ParseRequestURI(*inactiveUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return wraperrors.ValidationErr("Invalid inactive_url")
+		}
+	}
+
+	return nil
+}
+
+// checkEmailVerified keeps unverified accounts from creating links
+func checkEmailVerified(ctx context.Context, db *pgstore.Queries, userId uuid.UUID) error {
+	dbUser, err := db.GetUser(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return wraperrors.NotFoundErr("User not found")
+		}
+		return wraperrors.InternalErr("Failed to get user", err)
+	}
+
+	if !dbUser.EmailVerifiedAt.Valid {
+		return wraperrors.ForbiddenErr("Email address must be verified before creating short URLs")
+	}
+
+	return nil
+}
+
+// genSlug generates a slug free in the namespace of the domain, the shortener
+// host when domainId is not valid
+func (s *shortUrlService) genSlug(ctx context.Context, domainId pgtype.UUID, count int) (string, error) {
 	if count > 5 {
 		return "", wraperrors.InternalErr("Failed to generate unique slug after several attempts", nil)
 	}
@@ -193,14 +931,14 @@ func (s *shortUrlService) genSlug(ctx context.Context, count int) (string, error
 		return "", wraperrors.InternalErr("Failed to create hash slug", err)
 	}
 
-	shortUrl, err := s.GetShortUrlBySlug(ctx, slug)
-
-	if err != nil && wraperrors.IsNotFoundError(err) {
+	// Deleted and expired links keep their slug until they are purged
+	_, err = s.db.GetShortUrlBySlug(ctx, pgstore.GetShortUrlBySlugParams{Slug: slug, DomainID: domainId})
+	if errors.Is(err, pgx.ErrNoRows) {
 		return slug, nil
 	}
 
-	if err == nil && shortUrl != nil {
-		return s.genSlug(ctx, count+1)
+	if err == nil {
+		return s.genSlug(ctx, domainId, count+1)
 	}
 
 	return "", wraperrors.InternalErr("error checking slug existence", err)
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// attachHealth loads the latest destination check of all given links with a
+// single query, links not checked yet have none
+func attachHealth(ctx context.Context, db *pgstore.Queries, shortUrls ...*models.ShortUrl) error {
+	if len(shortUrls) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(shortUrls))
+	byId := make(map[string]*models.ShortUrl, len(shortUrls))
+	for _, shortUrl := range shortUrls {
+		id, err := uuid.Parse(shortUrl.ID)
+		if err != nil {
+			return wraperrors.InternalErr("Invalid short URL ID", err)
+		}
+		ids = append(ids, id)
+		byId[shortUrl.ID] = shortUrl
+	}
+
+	dbHealth, err := db.ListShortUrlHealth(ctx, ids)
+	if err != nil {
+		return wraperrors.InternalErr("Failed to list destination health", err)
+	}
+
+	for _, dbCheck := range dbHealth {
+		if shortUrl, ok := byId[dbCheck.ShortUrlID.String()]; ok {
+			shortUrl.DestinationHealth = models.NewDestinationHealth(dbCheck)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// maxSearchLength keeps search patterns short enough for the trigram indexes
+const maxSearchLength = 200
+
+// listShortUrlsParams turns the filter into the query parameters, the page
+// must be normalized and the campaign, tag and folder are resolved by the
+// caller
+func listShortUrlsParams(userId uuid.UUID, workspaceId pgtype.UUID, filter models.ShortUrlFilter, page models.CursorPageInput) (pgstore.ListShortUrlsParams, error) {
+	params := pgstore.ListShortUrlsParams{
+		WorkspaceID: workspaceId,
+		UserID:      userId,
+		SortBy:      filter.Sort,
+		Descending:  page.Descending(),
+		PageSize:    int32(page.Limit + 1),
+	}
+
+	switch filter.Sort {
+	case models.ShortUrlSortCreatedAt, models.ShortUrlSortClicks, models.ShortUrlSortExpiresAt:
+	default:
+		return params, wraperrors.ValidationErr("Invalid sort, expected created_at, clicks or expires_at")
+	}
+
+	switch filter.Status {
+	case "":
+	case models.ShortUrlStatusActive, models.ShortUrlStatusExpired, models.ShortUrlStatusScheduled:
+		params.Status = pgtype.Text{String: filter.Status, Valid: true}
+	default:
+		return params, wraperrors.ValidationErr("Invalid status, expected active, expired or scheduled")
+	}
+
+	var err error
+	if params.CreatedAfter, err = parseDateBound(filter.CreatedAfter, false); err != nil {
+		return params, wraperrors.ValidationErr("Invalid created_after, expected an RFC3339 timestamp or a YYYY-MM-DD date")
+	}
+	if params.CreatedBefore, err = parseDateBound(filter.CreatedBefore, true); err != nil {
+		return params, wraperrors.ValidationErr("Invalid created_before, expected an RFC3339 timestamp or a YYYY-MM-DD date")
+	}
+
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		if len(search) > maxSearchLength {
+			return params, wraperrors.ValidationErr("Search is too long")
+		}
+		params.Search = pgtype.Text{String: "%" + escapeLike(search) + "%", Valid: true}
+	}
+
+	cursor, cursorId, err := decodePageCursor(page, filter.Sort)
+	if err != nil || cursor == nil {
+		return params, err
+	}
+	params.CursorID = cursorId
+	if filter.Sort == models.ShortUrlSortClicks {
+		clicks, err := strconv.Atoi(cursor.Value)
+		if err != nil {
+			return params, wraperrors.ValidationErr("Invalid cursor for this sort and order")
+		}
+		params.CursorClicks = pgtype.Int4{Int32: int32(clicks), Valid: true}
+	} else if params.CursorTime, err = parseCursorTime(cursor.Value); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
+// shortUrlCursor points after the given link in a listing with this sort
+func shortUrlCursor(dbShortUrl pgstore.ShortUrl, sort string, order string) models.Cursor {
+	cursor := models.Cursor{Sort: sort, Order: order, ID: dbShortUrl.ID.String()}
+	switch sort {
+	case models.ShortUrlSortClicks:
+		cursor.Value = strconv.Itoa(int(dbShortUrl.AccessCount.Int32))
+	case models.ShortUrlSortExpiresAt:
+		cursor.Value = formatCursorTime(dbShortUrl.ExpiresAt)
+	default:
+		cursor.Value = formatCursorTime(dbShortUrl.CreatedAt)
+	}
+	return cursor
+}
+
+// listOrganizers returns all the user's tags and folders, counting their
+// links among the personal or workspace links being listed
+func listOrganizers(ctx context.Context, db *pgstore.Queries, userId uuid.UUID, workspaceId pgtype.UUID) ([]*models.Tag, []*models.Folder, error) {
+	dbTags, err := db.ListTags(ctx, userId)
+	if err != nil {
+		return nil, nil, wraperrors.InternalErr("Failed to list tags", err)
+	}
+	tagStats, err := db.ListTagStatsInScope(ctx, pgstore.ListTagStatsInScopeParams{UserID: userId, WorkspaceID: workspaceId})
+	if err != nil {
+		return nil, nil, wraperrors.InternalErr("Failed to count tag links", err)
+	}
+	tagCounts := make(map[uuid.UUID]int64, len(tagStats))
+	for _, stat := range tagStats {
+		tagCounts[stat.TagID] = stat.LinkCount
+	}
+
+	tags := make([]*models.Tag, 0, len(dbTags))
+	for _, dbTag := range dbTags {
+		tag := toTagModel(dbTag)
+		tag.LinkCount = tagCounts[dbTag.ID]
+		tags = append(tags, tag)
+	}
+
+	dbFolders, err := db.ListFolders(ctx, userId)
+	if err != nil {
+		return nil, nil, wraperrors.InternalErr("Failed to list folders", err)
+	}
+	folderStats, err := db.ListFolderStatsInScope(ctx, pgstore.ListFolderStatsInScopeParams{UserID: userId, WorkspaceID: workspaceId})
+	if err != nil {
+		return nil, nil, wraperrors.InternalErr("Failed to count folder links", err)
+	}
+	folderCounts := make(map[uuid.UUID]int64, len(folderStats))
+	for _, stat := range folderStats {
+		folderCounts[stat.FolderID.Bytes] = stat.LinkCount
+	}
+
+	folders := make([]*models.Folder, 0, len(dbFolders))
+	for _, dbFolder := range dbFolders {
+		folder := toFolderModel(dbFolder)
+		folder.LinkCount = folderCounts[dbFolder.ID]
+		folders = append(folders, folder)
+	}
+
+	return tags, folders, nil
+}
+
+// parseDateBound reads an RFC3339 timestamp or a date, a date used as the
+// upper bound includes the whole day
+func parseDateBound(raw string, upper bool) (pgtype.Timestamptz, error) {
+	if raw == "" {
+		return pgtype.Timestamptz{}, nil
+	}
+
+	if t, err := models.ParseTimestamp(&raw); err == nil {
+		return pgtype.Timestamptz{Time: *t, Valid: true}, nil
+	}
+	day, err := time.Parse(time.DateOnly, raw)
+	if err != nil {
+		return pgtype.Timestamptz{}, err
+	}
+	if upper {
+		day = day.AddDate(0, 0, 1)
+	}
+	return pgtype.Timestamptz{Time: day, Valid: true}, nil
+}
+
+// escapeLike makes the wildcards of a search term match literally
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
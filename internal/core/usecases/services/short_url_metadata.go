@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// attachMetadata loads the destination metadata of all given links with a
+// single query, links created before the fetcher existed have none
+func attachMetadata(ctx context.Context, db *pgstore.Queries, shortUrls ...*models.ShortUrl) error {
+	if len(shortUrls) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(shortUrls))
+	byId := make(map[string]*models.ShortUrl, len(shortUrls))
+	for _, shortUrl := range shortUrls {
+		id, err := uuid.Parse(shortUrl.ID)
+		if err != nil {
+			return wraperrors.InternalErr("Invalid short URL ID", err)
+		}
+		ids = append(ids, id)
+		byId[shortUrl.ID] = shortUrl
+	}
+
+	dbMetadata, err := db.ListShortUrlMetadata(ctx, ids)
+	if err != nil {
+		return wraperrors.InternalErr("Failed to list short URL metadata", err)
+	}
+
+	for _, dbMetadatum := range dbMetadata {
+		if shortUrl, ok := byId[dbMetadatum.ShortUrlID.String()]; ok {
+			shortUrl.Metadata = toShortUrlMetadataModel(dbMetadatum)
+		}
+	}
+
+	return nil
+}
+
+// requestMetadata queues the fetch of the destination metadata, replacing
+// what was fetched for a previous destination
+func requestMetadata(ctx context.Context, q *pgstore.Queries, shortUrlId uuid.UUID, originalUrl string) error {
+	err := q.RequestShortUrlMetadata(ctx, pgstore.RequestShortUrlMetadataParams{
+		ShortUrlID: shortUrlId,
+		Url:        originalUrl,
+	})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to request short URL metadata", err)
+	}
+
+	return nil
+}
+
+func toShortUrlMetadataModel(dbMetadatum pgstore.ShortUrlMetadatum) *models.ShortUrlMetadata {
+	metadata := &models.ShortUrlMetadata{
+		Status: dbMetadatum.Status,
+		PageMetadata: models.PageMetadata{
+			Title:       dbMetadatum.Title.String,
+			Description: dbMetadatum.Description.String,
+			ImageUrl:    dbMetadatum.ImageUrl.String,
+			FaviconUrl:  dbMetadatum.FaviconUrl.String,
+		},
+	}
+	if dbMetadatum.FetchedAt.Valid {
+		metadata.FetchedAt = &dbMetadatum.FetchedAt.Time
+	}
+
+	return metadata
+}
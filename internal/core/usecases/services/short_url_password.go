@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	linkAccessTTL         = 30 * time.Minute
+	minLinkPasswordLength = 4
+)
+
+// UnlockShortUrl checks the password of a protected link and returns a token
+// that lets the visitor through until it expires
+func (s *shortUrlService) UnlockShortUrl(ctx context.Context, key string, password string) (*models.ShortUrlAccess, error) {
+	dbShortUrl, err := s.getRedirectableShortUrl(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dbShortUrl.PasswordHash.Valid {
+		return nil, wraperrors.ValidationErr("Short URL is not password protected")
+	}
+
+	if toShortUrlModel(dbShortUrl).NotYetActive(time.Now()) {
+		return nil, wraperrors.NotFoundErr("Short URL is not active yet")
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(dbShortUrl.PasswordHash.String), []byte(password)) != nil {
+		return nil, wraperrors.UnauthorizedErr("Invalid password")
+	}
+
+	expiresAt := time.Now().Add(linkAccessTTL)
+	return &models.ShortUrlAccess{
+		Token:     signLinkAccess(dbShortUrl, expiresAt.Unix()),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyShortUrlAccess accepts tokens issued by UnlockShortUrl for the same
+// link and password, changing the password invalidates the ones already issued
+func (s *shortUrlService) VerifyShortUrlAccess(ctx context.Context, key string, token string) error {
+	dbShortUrl, err := s.getRedirectableShortUrl(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	if !dbShortUrl.PasswordHash.Valid {
+		return nil
+	}
+
+	rawExpiresAt, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return wraperrors.UnauthorizedErr("Invalid access token")
+	}
+
+	expiresAt, err := strconv.ParseInt(rawExpiresAt, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return wraperrors.UnauthorizedErr("Invalid access token")
+	}
+
+	if !hmac.Equal([]byte(token), []byte(signLinkAccess(dbShortUrl, expiresAt))) {
+		return wraperrors.UnauthorizedErr("Invalid access token")
+	}
+
+	return nil
+}
+
+// signLinkAccess returns "<expires_at>.<mac>", the MAC covers the password
+// hash so the token is bound to the current password
+func signLinkAccess(dbShortUrl pgstore.ShortUrl, expiresAt int64) string {
+	mac := hmac.New(sha256.New, tokenSecret())
+	fmt.Fprintf(mac, "link_access|%s|%d|%s", dbShortUrl.ID, expiresAt, dbShortUrl.PasswordHash.String)
+
+	return fmt.Sprintf("%d.%s", expiresAt, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+// hashLinkPassword hashes a new link password, an empty one removes it
+func hashLinkPassword(password string) (pgtype.Text, error) {
+	if password == "" {
+		return pgtype.Text{}, nil
+	}
+	if len(password) < minLinkPasswordLength {
+		return pgtype.Text{}, wraperrors.ValidationErr(fmt.Sprintf("Password must have at least %d characters", minLinkPasswordLength))
+	}
+
+	return hashPassword(password)
+}
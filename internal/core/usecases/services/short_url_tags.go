@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// attachTags loads the tags of all given links with a single query, tags put
+// on a workspace link by other members are included
+func attachTags(ctx context.Context, db *pgstore.Queries, shortUrls ...*models.ShortUrl) error {
+	if len(shortUrls) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(shortUrls))
+	byId := make(map[string]*models.ShortUrl, len(shortUrls))
+	for _, shortUrl := range shortUrls {
+		id, err := uuid.Parse(shortUrl.ID)
+		if err != nil {
+			return wraperrors.InternalErr("Invalid short URL ID", err)
+		}
+		ids = append(ids, id)
+		byId[shortUrl.ID] = shortUrl
+		shortUrl.Tags = nil
+	}
+
+	dbTags, err := db.ListShortUrlTags(ctx, ids)
+	if err != nil {
+		return wraperrors.InternalErr("Failed to list short URL tags", err)
+	}
+
+	for _, dbTag := range dbTags {
+		if shortUrl, ok := byId[dbTag.ShortUrlID.String()]; ok {
+			shortUrl.Tags = append(shortUrl.Tags, &models.ShortUrlTag{ID: dbTag.ID.String(), Name: dbTag.Name})
+		}
+	}
+
+	return nil
+}
+
+// getUserTags loads the user's tags with the given IDs, repeated IDs are
+// ignored
+func getUserTags(ctx context.Context, db *pgstore.Queries, userId uuid.UUID, ids []string) ([]pgstore.Tag, error) {
+	seen := make(map[string]bool, len(ids))
+	dbTags := make([]pgstore.Tag, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		dbTag, err := getUserTag(ctx, db, userId, id)
+		if err != nil {
+			return nil, err
+		}
+		dbTags = append(dbTags, dbTag)
+	}
+
+	return dbTags, nil
+}
+
+// replaceTags makes dbTags the tags the user put on a link, tags of other
+// workspace members are kept
+func replaceTags(ctx context.Context, q *pgstore.Queries, userId uuid.UUID, shortUrlId uuid.UUID, dbTags []pgstore.Tag) error {
+	err := q.DeleteShortUrlTags(ctx, pgstore.DeleteShortUrlTagsParams{ShortUrlID: shortUrlId, UserID: userId})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to remove short URL tags", err)
+	}
+
+	for _, dbTag := range dbTags {
+		err := q.AddShortUrlTags(ctx, pgstore.AddShortUrlTagsParams{ShortUrlIds: []uuid.UUID{shortUrlId}, TagID: dbTag.ID})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to tag short URL", err)
+		}
+	}
+
+	return nil
+}
+
+// getAuthorizedShortUrlIds checks the user may edit every given link, the
+// same link listed twice is kept once
+func getAuthorizedShortUrlIds(ctx context.Context, db *pgstore.Queries, rawUserId string, ids []string) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	shortUrlIds := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		dbShortUrl, err := getAuthorizedShortUrl(ctx, db, rawUserId, id, models.WorkspaceRoleEditor)
+		if err != nil {
+			return nil, err
+		}
+		if seen[dbShortUrl.ID] {
+			continue
+		}
+		seen[dbShortUrl.ID] = true
+		shortUrlIds = append(shortUrlIds, dbShortUrl.ID)
+	}
+
+	return shortUrlIds, nil
+}
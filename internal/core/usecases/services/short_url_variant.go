@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// attachVariants loads the variants of all given links with a single query
+func attachVariants(ctx context.Context, db *pgstore.Queries, shortUrls ...*models.ShortUrl) error {
+	if len(shortUrls) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(shortUrls))
+	byId := make(map[string]*models.ShortUrl, len(shortUrls))
+	for _, shortUrl := range shortUrls {
+		id, err := uuid.Parse(shortUrl.ID)
+		if err != nil {
+			return wraperrors.InternalErr("Invalid short URL ID", err)
+		}
+		ids = append(ids, id)
+		byId[shortUrl.ID] = shortUrl
+	}
+
+	dbVariants, err := db.ListShortUrlVariants(ctx, ids)
+	if err != nil {
+		return wraperrors.InternalErr("Failed to list short URL variants", err)
+	}
+
+	for _, dbVariant := range dbVariants {
+		if shortUrl, ok := byId[dbVariant.ShortUrlID.String()]; ok {
+			shortUrl.Variants = append(shortUrl.Variants, toShortUrlVariantModel(dbVariant))
+		}
+	}
+
+	return nil
+}
+
+// replaceVariants makes inputs the variant list of a link. Variants whose
+// URL is kept only have their weight updated so their clicks are preserved
+func replaceVariants(ctx context.Context, q *pgstore.Queries, shortUrlId uuid.UUID, current []*models.ShortUrlVariant, inputs []*models.ShortUrlVariantInput) ([]*models.ShortUrlVariant, error) {
+	byUrl := make(map[string]*models.ShortUrlVariant, len(current))
+	for _, variant := range current {
+		byUrl[variant.Url] = variant
+	}
+
+	variants := make([]*models.ShortUrlVariant, 0, len(inputs))
+	for _, input := range inputs {
+		if existing, ok := byUrl[input.Url]; ok {
+			delete(byUrl, input.Url)
+			if existing.Weight != input.Weight {
+				err := q.UpdateShortUrlVariantWeight(ctx, pgstore.UpdateShortUrlVariantWeightParams{
+					ID:     uuid.MustParse(existing.ID),
+					Weight: int32(input.Weight),
+				})
+				if err != nil {
+					return nil, wraperrors.InternalErr("Failed to update short URL variant", err)
+				}
+			}
+			variants = append(variants, &models.ShortUrlVariant{
+				ID:          existing.ID,
+				Url:         existing.Url,
+				Weight:      input.Weight,
+				AccessCount: existing.AccessCount,
+			})
+			continue
+		}
+
+		dbVariant, err := q.CreateShortUrlVariant(ctx, pgstore.CreateShortUrlVariantParams{
+			ShortUrlID: shortUrlId,
+			Url:        input.Url,
+			Weight:     int32(input.Weight),
+		})
+		if err != nil {
+			return nil, wraperrors.InternalErr("Failed to create short URL variant", err)
+		}
+		variants = append(variants, toShortUrlVariantModel(dbVariant))
+	}
+
+	for _, removed := range byUrl {
+		if err := q.DeleteShortUrlVariant(ctx, uuid.MustParse(removed.ID)); err != nil {
+			return nil, wraperrors.InternalErr("Failed to delete short URL variant", err)
+		}
+	}
+
+	return variants, nil
+}
+
+func toShortUrlVariantModel(dbVariant pgstore.ShortUrlVariant) *models.ShortUrlVariant {
+	return &models.ShortUrlVariant{
+		ID:          dbVariant.ID.String(),
+		Url:         dbVariant.Url,
+		Weight:      int(dbVariant.Weight),
+		AccessCount: int(dbVariant.AccessCount),
+	}
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+// ListShortUrlVersions returns the versions of a link, newest first. Links
+// never edited since versions are kept have none
+func (s *shortUrlService) ListShortUrlVersions(ctx context.Context, rawUserId string, id string) ([]*models.ShortUrlVersion, error) {
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, id, models.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	dbVersions, err := s.db.ListShortUrlVersions(ctx, dbShortUrl.ID)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list short URL versions", err)
+	}
+
+	versions := make([]*models.ShortUrlVersion, 0, len(dbVersions))
+	for _, dbVersion := range dbVersions {
+		versions = append(versions, toShortUrlVersionModel(dbVersion))
+	}
+
+	return versions, nil
+}
+
+// RestoreShortUrlVersion puts a link back in the state of one of its
+// versions and records that state as a new version. The slug of the version
+// is only taken back while no other link uses it, the folder and campaign
+// while they belong to the owner, variants and tags are not versioned and
+// stay as they are
+func (s *shortUrlService) RestoreShortUrlVersion(ctx context.Context, rawUserId string, id string, version int) (*models.ShortUrl, error) {
+	dbShortUrl, err := getAuthorizedShortUrl(ctx, s.db, rawUserId, id, models.WorkspaceRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	dbVersion, err := s.db.GetShortUrlVersion(ctx, pgstore.GetShortUrlVersionParams{ShortUrlID: dbShortUrl.ID, Version: int32(version)})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("Short URL version not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get short URL version", err)
+	}
+
+	before := toShortUrlModel(*dbShortUrl)
+	if err := attachVariants(ctx, s.db, before); err != nil {
+		return nil, err
+	}
+	if err := attachTags(ctx, s.db, before); err != nil {
+		return nil, err
+	}
+
+	// like an update, only the destinations being changed are screened
+	var destinations []destination
+	if dbVersion.OriginalUrl != dbShortUrl.OriginalUrl {
+		destinations = append(destinations, destination{field: "original_url", url: dbVersion.OriginalUrl})
+	}
+	if dbVersion.InactiveUrl.Valid && dbVersion.InactiveUrl != dbShortUrl.InactiveUrl {
+		destinations = append(destinations, destination{field: "inactive_url", url: dbVersion.InactiveUrl.String})
+	}
+	if err := checkDestinations(ctx, s.policy, destinations...); err != nil {
+		return nil, err
+	}
+
+	slug := dbShortUrl.Slug
+	if dbVersion.Slug != slug {
+		_, err := s.db.GetShortUrlBySlug(ctx, pgstore.GetShortUrlBySlugParams{Slug: dbVersion.Slug, DomainID: dbShortUrl.DomainID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			slug = dbVersion.Slug
+		} else if err != nil {
+			return nil, wraperrors.InternalErr("error checking slug existence", err)
+		}
+	}
+
+	// the folder and campaign of the version are only taken back while they
+	// belong to the link's owner or to the user restoring it, a link
+	// transferred since then must not return to the previous owner's ones
+	userId, _ := uuid.Parse(rawUserId)
+	owners := []uuid.UUID{userId}
+	if dbShortUrl.UserID.Valid && dbShortUrl.UserID.Bytes != userId {
+		owners = append(owners, dbShortUrl.UserID.Bytes)
+	}
+	folderId, err := keepOwned(owners, dbVersion.FolderID, func(owner uuid.UUID, id string) error {
+		_, err := getUserFolder(ctx, s.db, owner, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	campaignId, err := keepOwned(owners, dbVersion.CampaignID, func(owner uuid.UUID, id string) error {
+		_, err := getUserCampaign(ctx, s.db, owner, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var restored *models.ShortUrl
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		dbRestored, err := q.RestoreShortUrlVersion(ctx, pgstore.RestoreShortUrlVersionParams{
+			Slug:       slug,
+			CampaignID: campaignId,
+			FolderID:   folderId,
+			ID:         dbShortUrl.ID,
+			Version:    dbVersion.Version,
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to restore short URL version", err)
+		}
+		restored = toShortUrlModel(dbRestored)
+		restored.Variants = before.Variants
+		restored.Tags = before.Tags
+
+		if dbRestored.OriginalUrl != dbShortUrl.OriginalUrl {
+			if err := requestMetadata(ctx, q, dbShortUrl.ID, dbRestored.OriginalUrl); err != nil {
+				return err
+			}
+			if err := q.DeleteShortUrlHealth(ctx, dbShortUrl.ID); err != nil {
+				return wraperrors.InternalErr("Failed to reset destination health", err)
+			}
+		}
+
+		restoredFrom := pgtype.Int4{Int32: dbVersion.Version, Valid: true}
+		if err := recordVersion(ctx, q, rawUserId, dbShortUrl.ID, restoredFrom); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, q, auditActor(rawUserId), models.AuditEntityShortUrl, dbShortUrl.ID, models.AuditActionUpdate, before, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := attachMetadata(ctx, s.db, restored); err != nil {
+		return nil, err
+	}
+	if err := attachHealth(ctx, s.db, restored); err != nil {
+		return nil, err
+	}
+	if err := attachDomain(ctx, s.db, restored); err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
+
+// keepOwned returns id when get finds it for one of the owners and NULL when
+// none of them has it
+func keepOwned(owners []uuid.UUID, id pgtype.UUID, get func(owner uuid.UUID, id string) error) (pgtype.UUID, error) {
+	if !id.Valid {
+		return id, nil
+	}
+
+	for _, owner := range owners {
+		err := get(owner, uuid.UUID(id.Bytes).String())
+		if err == nil {
+			return id, nil
+		}
+		if !wraperrors.IsNotFoundError(err) {
+			return pgtype.UUID{}, err
+		}
+	}
+
+	return pgtype.UUID{}, nil
+}
+
+// recordInitialVersion keeps the state of a link about to be edited for the
+// first time as its version 1, it must run before the edit in the same
+// transaction
+func recordInitialVersion(ctx context.Context, q *pgstore.Queries, shortUrlId uuid.UUID) error {
+	if err := q.CreateInitialShortUrlVersion(ctx, shortUrlId); err != nil {
+		return wraperrors.InternalErr("Failed to record short URL version", err)
+	}
+	return nil
+}
+
+// recordVersion keeps the state a link was just left in as its next version
+func recordVersion(ctx context.Context, q *pgstore.Queries, rawUserId string, shortUrlId uuid.UUID, restoredFrom pgtype.Int4) error {
+	authorId := auditActor(rawUserId)
+	_, err := q.CreateShortUrlVersion(ctx, pgstore.CreateShortUrlVersionParams{
+		AuthorID:     pgtype.UUID{Bytes: authorId, Valid: authorId != uuid.Nil},
+		RestoredFrom: restoredFrom,
+		ShortUrlID:   shortUrlId,
+	})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to record short URL version", err)
+	}
+	return nil
+}
+
+func toShortUrlVersionModel(dbVersion pgstore.ListShortUrlVersionsRow) *models.ShortUrlVersion {
+	version := &models.ShortUrlVersion{
+		Version:          int(dbVersion.Version),
+		Slug:             dbVersion.Slug,
+		OriginalUrl:      dbVersion.OriginalUrl,
+		HasPassword:      dbVersion.PasswordHash.Valid,
+		InactiveFallback: dbVersion.InactiveFallback,
+		StickyVariants:   dbVersion.StickyVariants,
+		RedirectStatus:   int(dbVersion.RedirectStatus),
+		ForwardQuery:     dbVersion.ForwardQuery,
+		QueryMerge:       dbVersion.QueryMerge,
+		Interstitial:     dbVersion.Interstitial,
+		CreatedAt:        dbVersion.CreatedAt.Time,
+	}
+	if dbVersion.ExpiresAt.Valid {
+		version.ExpiresAt = &dbVersion.ExpiresAt.Time
+	}
+	if dbVersion.MaxClicks.Valid {
+		maxClicks := int(dbVersion.MaxClicks.Int32)
+		version.MaxClicks = &maxClicks
+	}
+	if dbVersion.StartsAt.Valid {
+		version.StartsAt = &dbVersion.StartsAt.Time
+	}
+	if dbVersion.InactiveUrl.Valid {
+		version.InactiveUrl = &dbVersion.InactiveUrl.String
+	}
+	if dbVersion.CampaignID.Valid {
+		campaignId := uuid.UUID(dbVersion.CampaignID.Bytes).String()
+		version.CampaignID = &campaignId
+	}
+	if dbVersion.FolderID.Valid {
+		folderId := uuid.UUID(dbVersion.FolderID.Bytes).String()
+		version.FolderID = &folderId
+	}
+	if dbVersion.AuthorID.Valid {
+		version.Author = &models.ShortUrlVersionAuthor{
+			ID:    uuid.UUID(dbVersion.AuthorID.Bytes).String(),
+			Name:  dbVersion.AuthorName.String,
+			Email: dbVersion.AuthorEmail.String,
+		}
+	}
+	if dbVersion.RestoredFrom.Valid {
+		restoredFrom := int(dbVersion.RestoredFrom.Int32)
+		version.RestoredFrom = &restoredFrom
+	}
+
+	return version
+}
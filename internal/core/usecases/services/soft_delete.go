@@ -0,0 +1,29 @@
+package services
+
+import (
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const defaultRestoreWindow = 7 * 24 * time.Hour
+
+// restoreWindow is how long after deletion a user or link can still be
+// restored, rows are purged later by the worker after SOFT_DELETE_RETENTION
+func restoreWindow() time.Duration {
+	if raw := os.Getenv("SOFT_DELETE_RESTORE_WINDOW"); raw != "" {
+		if window, err := time.ParseDuration(raw); err == nil && window > 0 {
+			return window
+		}
+	}
+	return defaultRestoreWindow
+}
+
+func checkRestoreWindow(deletedAt pgtype.Timestamptz) error {
+	if time.Since(deletedAt.Time) > restoreWindow() {
+		return wraperrors.GoneErr("Restore window has expired")
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+type tagService struct {
+	db *pgstore.Queries
+}
+
+func NewTagService(queries *pgstore.Queries) ports.TagUseCase {
+	return &tagService{
+		db: queries,
+	}
+}
+
+// ListTags returns the user's tags with the live links of each one
+func (s *tagService) ListTags(ctx context.Context, rawUserId string) ([]*models.Tag, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbTags, err := s.db.ListTags(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list tags", err)
+	}
+
+	counts, err := s.tagCounts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]*models.Tag, 0, len(dbTags))
+	for _, dbTag := range dbTags {
+		tag := toTagModel(dbTag)
+		tag.LinkCount = counts[dbTag.ID]
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (s *tagService) CreateTag(ctx context.Context, rawUserId string, input *models.CreateTagInput) (*models.Tag, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbTag, err := s.db.CreateTag(ctx, pgstore.CreateTagParams{UserID: userId, Name: input.Name})
+	if err != nil {
+		if wraperrors.IsUniqueViolation(err) {
+			return nil, wraperrors.AlreadyExistsErr("A tag with this name already exists")
+		}
+		return nil, wraperrors.InternalErr("Failed to create tag", err)
+	}
+
+	return toTagModel(dbTag), nil
+}
+
+// UpdateTag renames a tag, the links keep it
+func (s *tagService) UpdateTag(ctx context.Context, rawUserId string, id string, input *models.UpdateTagInput) (*models.Tag, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbTag, err := getUserTag(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTag, err = s.db.UpdateTag(ctx, pgstore.UpdateTagParams{ID: dbTag.ID, UserID: userId, Name: input.Name})
+	if err != nil {
+		if wraperrors.IsUniqueViolation(err) {
+			return nil, wraperrors.AlreadyExistsErr("A tag with this name already exists")
+		}
+		return nil, wraperrors.InternalErr("Failed to update tag", err)
+	}
+
+	return s.withCount(ctx, userId, dbTag)
+}
+
+// DeleteTag removes the tag from its links, the links are kept
+func (s *tagService) DeleteTag(ctx context.Context, rawUserId string, id string) error {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	tagId, err := uuid.Parse(id)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid tag ID format")
+	}
+
+	deleted, err := s.db.DeleteTag(ctx, pgstore.DeleteTagParams{ID: tagId, UserID: userId})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to delete tag", err)
+	}
+	if deleted == 0 {
+		return wraperrors.NotFoundErr("Tag not found")
+	}
+
+	return nil
+}
+
+// MergeTag puts the target tag on every link of the tag and deletes it
+func (s *tagService) MergeTag(ctx context.Context, rawUserId string, id string, input *models.MergeTagInput) (*models.Tag, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbTag, err := getUserTag(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	target, err := getUserTag(ctx, s.db, userId, input.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.ID == dbTag.ID {
+		return nil, wraperrors.ValidationErr("A tag cannot be merged into itself")
+	}
+
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		if err := q.MoveShortUrlTags(ctx, pgstore.MoveShortUrlTagsParams{TargetID: target.ID, TagID: dbTag.ID}); err != nil {
+			return wraperrors.InternalErr("Failed to move tagged links", err)
+		}
+		if _, err := q.DeleteTag(ctx, pgstore.DeleteTagParams{ID: dbTag.ID, UserID: userId}); err != nil {
+			return wraperrors.InternalErr("Failed to delete merged tag", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withCount(ctx, userId, target)
+}
+
+// AddTagLinks tags links the user can edit, links already tagged are left
+// as they are
+func (s *tagService) AddTagLinks(ctx context.Context, rawUserId string, id string, input *models.AssignLinksInput) (*models.Tag, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbTag, err := getUserTag(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	shortUrlIds, err := getAuthorizedShortUrlIds(ctx, s.db, rawUserId, input.ShortUrlIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.AddShortUrlTags(ctx, pgstore.AddShortUrlTagsParams{ShortUrlIds: shortUrlIds, TagID: dbTag.ID}); err != nil {
+		return nil, wraperrors.InternalErr("Failed to tag short URLs", err)
+	}
+
+	return s.withCount(ctx, userId, dbTag)
+}
+
+func (s *tagService) RemoveTagLinks(ctx context.Context, rawUserId string, id string, input *models.AssignLinksInput) (*models.Tag, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbTag, err := getUserTag(ctx, s.db, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	shortUrlIds, err := getAuthorizedShortUrlIds(ctx, s.db, rawUserId, input.ShortUrlIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.RemoveShortUrlTags(ctx, pgstore.RemoveShortUrlTagsParams{TagID: dbTag.ID, ShortUrlIds: shortUrlIds}); err != nil {
+		return nil, wraperrors.InternalErr("Failed to untag short URLs", err)
+	}
+
+	return s.withCount(ctx, userId, dbTag)
+}
+
+// tagCounts maps each tag of the user to its live links, tags without links
+// are missing from the map
+func (s *tagService) tagCounts(ctx context.Context, userId uuid.UUID) (map[uuid.UUID]int64, error) {
+	rows, err := s.db.ListTagStats(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to count tagged links", err)
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TagID] = row.LinkCount
+	}
+
+	return counts, nil
+}
+
+func (s *tagService) withCount(ctx context.Context, userId uuid.UUID, dbTag pgstore.Tag) (*models.Tag, error) {
+	counts, err := s.tagCounts(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := toTagModel(dbTag)
+	tag.LinkCount = counts[dbTag.ID]
+
+	return tag, nil
+}
+
+// getUserTag loads a tag owned by the user
+func getUserTag(ctx context.Context, db *pgstore.Queries, userId uuid.UUID, id string) (pgstore.Tag, error) {
+	tagId, err := uuid.Parse(id)
+	if err != nil {
+		return pgstore.Tag{}, wraperrors.ValidationErr("Invalid tag ID format")
+	}
+
+	dbTag, err := db.GetTag(ctx, pgstore.GetTagParams{ID: tagId, UserID: userId})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return dbTag, wraperrors.NotFoundErr("Tag not found")
+		}
+		return dbTag, wraperrors.InternalErr("Failed to get tag", err)
+	}
+
+	return dbTag, nil
+}
+
+func toTagModel(dbTag pgstore.Tag) *models.Tag {
+	return &models.Tag{
+		ID:        dbTag.ID.String(),
+		Name:      dbTag.Name,
+		CreatedAt: dbTag.CreatedAt.Time,
+	}
+}
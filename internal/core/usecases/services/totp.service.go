@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	defaultTotpIssuer  = "URL Shortener"
+	recoveryCodesCount = 10
+	totpPeriod         = 30
+)
+
+type totpService struct {
+	db    *pgstore.Queries
+	steps ports.TotpStepStore
+}
+
+func NewTotpService(queries *pgstore.Queries, steps ports.TotpStepStore) ports.TotpUseCase {
+	return &totpService{
+		db:    queries,
+		steps: steps,
+	}
+}
+
+func (s *totpService) Enroll(ctx context.Context, rawUserId string) (*models.TotpEnrollment, error) {
+	dbUser, err := s.getUser(ctx, rawUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbUser.TotpEnabled {
+		return nil, wraperrors.AlreadyExistsErr("TOTP is already enabled")
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      totpIssuer(),
+		AccountName: dbUser.Email,
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to generate TOTP secret", err)
+	}
+
+	err = s.db.SetUserTotpSecret(ctx, pgstore.SetUserTotpSecretParams{
+		ID:         dbUser.ID,
+		TotpSecret: pgtype.Text{String: key.Secret(), Valid: true},
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to save TOTP secret", err)
+	}
+
+	return &models.TotpEnrollment{
+		Secret:     key.Secret(),
+		OtpAuthURI: key.URL(),
+	}, nil
+}
+
+func (s *totpService) Activate(ctx context.Context, rawUserId string, code string) (*models.RecoveryCodes, error) {
+	dbUser, err := s.getUser(ctx, rawUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbUser.TotpEnabled {
+		return nil, wraperrors.AlreadyExistsErr("TOTP is already enabled")
+	}
+	if !dbUser.TotpSecret.Valid {
+		return nil, wraperrors.ValidationErr("TOTP enrollment has not been started")
+	}
+
+	if err := s.verifyTotpCode(ctx, dbUser, code); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.EnableUserTotp(ctx, dbUser.ID); err != nil {
+		return nil, wraperrors.InternalErr("Failed to enable TOTP", err)
+	}
+
+	return s.generateRecoveryCodes(ctx, dbUser.ID)
+}
+
+func (s *totpService) Disable(ctx context.Context, rawUserId string, code string) error {
+	dbUser, err := s.getUser(ctx, rawUserId)
+	if err != nil {
+		return err
+	}
+
+	if !dbUser.TotpEnabled {
+		return wraperrors.ValidationErr("TOTP is not enabled")
+	}
+
+	if err := s.verifyCode(ctx, dbUser, code); err != nil {
+		return err
+	}
+
+	if err := s.db.DisableUserTotp(ctx, dbUser.ID); err != nil {
+		return wraperrors.InternalErr("Failed to disable TOTP", err)
+	}
+
+	if err := s.db.DeleteRecoveryCodesByUserId(ctx, dbUser.ID); err != nil {
+		return wraperrors.InternalErr("Failed to delete recovery codes", err)
+	}
+
+	return nil
+}
+
+func (s *totpService) RegenerateRecoveryCodes(ctx context.Context, rawUserId string, code string) (*models.RecoveryCodes, error) {
+	dbUser, err := s.getUser(ctx, rawUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dbUser.TotpEnabled {
+		return nil, wraperrors.ValidationErr("TOTP is not enabled")
+	}
+
+	if err := s.verifyTotpCode(ctx, dbUser, code); err != nil {
+		return nil, err
+	}
+
+	return s.generateRecoveryCodes(ctx, dbUser.ID)
+}
+
+func (s *totpService) VerifyLogin(ctx context.Context, rawUserId string, code string) error {
+	dbUser, err := s.getUser(ctx, rawUserId)
+	if err != nil {
+		return err
+	}
+
+	if !dbUser.TotpEnabled {
+		return wraperrors.ValidationErr("TOTP is not enabled")
+	}
+
+	return s.verifyCode(ctx, dbUser, code)
+}
+
+func (s *totpService) getUser(ctx context.Context, rawUserId string) (*pgstore.User, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbUser, err := s.db.GetUser(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("User not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get user", err)
+	}
+
+	return &dbUser, nil
+}
+
+// verifyTotpCode accepts a current TOTP code once, its time step and the ones
+// before it can't be used again
+func (s *totpService) verifyTotpCode(ctx context.Context, dbUser *pgstore.User, code string) error {
+	step, ok := matchTotpStep(strings.TrimSpace(code), dbUser.TotpSecret.String, time.Now())
+	if !ok {
+		return wraperrors.UnauthorizedErr("Invalid TOTP code")
+	}
+
+	fresh, err := s.steps.UseStep(ctx, dbUser.ID.String(), step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return wraperrors.UnauthorizedErr("TOTP code already used")
+	}
+	return nil
+}
+
+// verifyCode accepts either a current TOTP code, like verifyTotpCode, or one
+// of the unused recovery codes, burning the recovery code when it matches
+func (s *totpService) verifyCode(ctx context.Context, dbUser *pgstore.User, code string) error {
+	code = strings.TrimSpace(code)
+	if _, ok := matchTotpStep(code, dbUser.TotpSecret.String, time.Now()); ok {
+		return s.verifyTotpCode(ctx, dbUser, code)
+	}
+
+	used, err := s.db.UseRecoveryCode(ctx, pgstore.UseRecoveryCodeParams{
+		UserID:   dbUser.ID,
+		CodeHash: utils.HashToken(code),
+	})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to check recovery code", err)
+	}
+	if used == 0 {
+		return wraperrors.UnauthorizedErr("Invalid TOTP or recovery code")
+	}
+
+	return nil
+}
+
+func (s *totpService) generateRecoveryCodes(ctx context.Context, userId uuid.UUID) (*models.RecoveryCodes, error) {
+	if err := s.db.DeleteRecoveryCodesByUserId(ctx, userId); err != nil {
+		return nil, wraperrors.InternalErr("Failed to delete recovery codes", err)
+	}
+
+	codes := make([]string, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		code, err := utils.CreateHashSlug()
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.db.CreateRecoveryCode(ctx, pgstore.CreateRecoveryCodeParams{
+			UserID:   userId,
+			CodeHash: utils.HashToken(code),
+		})
+		if err != nil {
+			return nil, wraperrors.InternalErr("Failed to save recovery code", err)
+		}
+
+		codes = append(codes, code)
+	}
+
+	return &models.RecoveryCodes{Codes: codes}, nil
+}
+
+// matchTotpStep returns the time step the code was generated for, allowing
+// one step of clock skew each way like totp.Validate
+func matchTotpStep(code string, secret string, now time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current, current - 1, current + 1} {
+		valid, err := totp.ValidateCustom(code, secret, time.Unix(step*totpPeriod, 0).UTC(), opts)
+		if err == nil && valid {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return defaultTotpIssuer
+}
@@ -3,42 +3,88 @@ package services
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
 	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
 	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
 	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// userSortCreatedAt is the only sort of the user list, cursors carry it like
+// the link list cursors do
+const userSortCreatedAt = "created_at"
+
 type userService struct {
-	db *pgstore.Queries
+	db     *pgstore.Queries
+	mailer ports.Mailer
+	logger *slog.Logger
 }
 
-func NewUserService(queries *pgstore.Queries) ports.UserUseCase {
+func NewUserService(queries *pgstore.Queries, mailer ports.Mailer) ports.UserUseCase {
 	return &userService{
-		db: queries,
+		db:     queries,
+		mailer: mailer,
+		logger: slog.Default().With("component", "user_service"),
 	}
 }
 
-func (s *userService) ListUsers(ctx context.Context) ([]*models.User, error) {
-	dbUsers, err := s.db.GetUsers(ctx)
+// ListUsers pages through the users by creation date, newest first unless
+// the page asks for asc
+func (s *userService) ListUsers(ctx context.Context, page models.CursorPageInput) ([]*models.User, *models.Pagination, error) {
+	page, err := normalizePage(page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params := pgstore.GetUsersParams{
+		Descending: page.Descending(),
+		PageSize:   int32(page.Limit + 1),
+	}
+	cursor, cursorId, err := decodePageCursor(page, userSortCreatedAt)
 	if err != nil {
-		return nil, wraperrors.InternalErr("Failed to list users", err)
+		return nil, nil, err
+	}
+	if cursor != nil {
+		params.CursorID = cursorId
+		if params.CursorTime, err = parseCursorTime(cursor.Value); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	dbUsers, err := s.db.GetUsers(ctx, params)
+	if err != nil {
+		return nil, nil, wraperrors.InternalErr("Failed to list users", err)
+	}
+	dbUsers, pagination := paginate(dbUsers, page)
+	if pagination.HasMore {
+		last := dbUsers[len(dbUsers)-1]
+		pagination.NextCursor = models.Cursor{
+			Sort:  userSortCreatedAt,
+			Order: page.Order,
+			Value: formatCursorTime(last.CreatedAt),
+			ID:    last.ID.String(),
+		}.Encode()
 	}
 
 	users := make([]*models.User, 0, len(dbUsers))
 	for _, dbUser := range dbUsers {
 		users = append(users, &models.User{
-			ID:        dbUser.ID.String(),
-			Name:      dbUser.Name,
-			Email:     dbUser.Email,
-			CreatedAt: dbUser.CreatedAt.Time,
+			ID:            dbUser.ID.String(),
+			Name:          dbUser.Name,
+			Email:         dbUser.Email,
+			EmailVerified: dbUser.EmailVerifiedAt.Valid,
+			TotpEnabled:   dbUser.TotpEnabled,
+			CreatedAt:     dbUser.CreatedAt.Time,
 		})
 	}
 
-	return users, nil
+	return users, pagination, nil
 }
 
 func (s *userService) GetUser(ctx context.Context, id string) (*models.User, error) {
@@ -56,10 +102,12 @@ func (s *userService) GetUser(ctx context.Context, id string) (*models.User, err
 	}
 
 	return &models.User{
-		ID:        dbUser.ID.String(),
-		Name:      dbUser.Name,
-		Email:     dbUser.Email,
-		CreatedAt: dbUser.CreatedAt.Time,
+		ID:            dbUser.ID.String(),
+		Name:          dbUser.Name,
+		Email:         dbUser.Email,
+		EmailVerified: dbUser.EmailVerifiedAt.Valid,
+		TotpEnabled:   dbUser.TotpEnabled,
+		CreatedAt:     dbUser.CreatedAt.Time,
 	}, nil
 }
 
@@ -73,10 +121,12 @@ func (s *userService) GetUserByEmail(ctx context.Context, email string) (*models
 	}
 
 	return &models.User{
-		ID:        dbUser.ID.String(),
-		Name:      dbUser.Name,
-		Email:     dbUser.Email,
-		CreatedAt: dbUser.CreatedAt.Time,
+		ID:            dbUser.ID.String(),
+		Name:          dbUser.Name,
+		Email:         dbUser.Email,
+		EmailVerified: dbUser.EmailVerifiedAt.Valid,
+		TotpEnabled:   dbUser.TotpEnabled,
+		CreatedAt:     dbUser.CreatedAt.Time,
 	}, nil
 }
 
@@ -89,17 +139,61 @@ func (s *userService) CreateUser(ctx context.Context, input *models.CreateUserIn
 		return nil, wraperrors.AlreadyExistsErr("Email already in use")
 	}
 
-	pgUser := input.ToPgCreateUser()
-	user, err := s.db.CreateUser(ctx, *pgUser)
+	passwordHash, err := hashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	pgUser := input.ToPgCreateUser(passwordHash)
+	var dbUser pgstore.CreateUserRow
+	var user *models.User
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		dbUser, err = q.CreateUser(ctx, *pgUser)
+		if err != nil {
+			if wraperrors.IsUniqueViolation(err) {
+				return wraperrors.AlreadyExistsErr("Email already in use")
+			}
+			return wraperrors.InternalErr("Failed to create user", err)
+		}
+		user = &models.User{
+			ID:            dbUser.ID.String(),
+			Name:          dbUser.Name,
+			Email:         dbUser.Email,
+			EmailVerified: dbUser.EmailVerifiedAt.Valid,
+			CreatedAt:     dbUser.CreatedAt.Time,
+		}
+
+		return recordAudit(ctx, q, dbUser.ID, models.AuditEntityUser, dbUser.ID, models.AuditActionCreate, nil, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendVerification(ctx, dbUser.ID, dbUser.Email)
+
+	return user, nil
+}
+
+func (s *userService) Login(ctx context.Context, input *models.GetUserByEmailInput) (*models.User, error) {
+	dbUser, err := s.db.GetUserByEmail(ctx, input.Email)
 	if err != nil {
-		return nil, wraperrors.InternalErr("Failed to create user", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("User not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get user by email", err)
+	}
+
+	if err := checkPassword(dbUser.PasswordHash, input.Password); err != nil {
+		return nil, err
 	}
 
 	return &models.User{
-		ID:        user.ID.String(),
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt.Time,
+		ID:            dbUser.ID.String(),
+		Name:          dbUser.Name,
+		Email:         dbUser.Email,
+		EmailVerified: dbUser.EmailVerifiedAt.Valid,
+		TotpEnabled:   dbUser.TotpEnabled,
+		CreatedAt:     dbUser.CreatedAt.Time,
 	}, nil
 }
 
@@ -114,45 +208,319 @@ func (s *userService) UpdateUser(ctx context.Context, id string, input *models.U
 		return nil, err
 	}
 
-	input.ApplyTo(user)
-
-	if input.Email != nil && *input.Email != user.Email {
+	emailChanged := input.Email != nil && *input.Email != user.Email
+	if emailChanged {
 		existingUser, err := s.GetUserByEmail(ctx, *input.Email)
 		if err == nil && existingUser != nil && existingUser.ID != user.ID {
 			return nil, wraperrors.AlreadyExistsErr("Email already in use by another user")
 		}
 	}
 
-	newUser, err := s.db.UpdateUser(ctx, pgstore.UpdateUserParams{
-		ID:    userId,
-		Name:  user.Name,
-		Email: user.Email,
+	before := *user
+	input.ApplyTo(user)
+
+	var updated *models.User
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		newUser, err := q.UpdateUser(ctx, pgstore.UpdateUserParams{
+			ID:    userId,
+			Name:  user.Name,
+			Email: user.Email,
+		})
+		if err != nil {
+			if wraperrors.IsUniqueViolation(err) {
+				return wraperrors.AlreadyExistsErr("Email already in use by another user")
+			}
+			return wraperrors.InternalErr("Failed to update user", err)
+		}
+		updated = &models.User{
+			ID:            newUser.ID.String(),
+			Name:          newUser.Name,
+			Email:         newUser.Email,
+			EmailVerified: newUser.EmailVerifiedAt.Valid,
+			TotpEnabled:   user.TotpEnabled,
+			CreatedAt:     newUser.CreatedAt.Time,
+		}
+
+		return recordAudit(ctx, q, userId, models.AuditEntityUser, userId, models.AuditActionUpdate, &before, updated)
 	})
 	if err != nil {
-		return nil, wraperrors.InternalErr("Failed to update user", err)
+		return nil, err
 	}
 
-	return &models.User{
-		ID:        newUser.ID.String(),
-		Name:      newUser.Name,
-		Email:     newUser.Email,
-		CreatedAt: newUser.CreatedAt.Time,
-	}, nil
+	if emailChanged {
+		s.sendVerification(ctx, userId, updated.Email)
+	}
+
+	return updated, nil
 }
 
-func (s *userService) DeleteUser(ctx context.Context, id string) error {
+// DeleteUser returns the keys of the personal links deleted with the user so
+// their cached redirects can be dropped
+func (s *userService) DeleteUser(ctx context.Context, id string) ([]string, error) {
 	userId, err := uuid.Parse(id)
 	if err != nil {
-		return wraperrors.ValidationErr("Invalid user ID format")
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
 	}
 
-	err = s.db.DeleteUser(ctx, userId)
+	user, err := s.GetUser(ctx, id)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	// Workspace links must not be left without anyone who can manage them
+	soleOwned, err := s.db.CountSoleOwnedWorkspaces(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to check workspace ownership", err)
+	}
+	if soleOwned > 0 {
+		return nil, wraperrors.AlreadyExistsErr("User is the only owner of a workspace, transfer ownership or delete it first")
+	}
+
+	// The domains of the user are removed with them, workspace links served
+	// by one of them would go too
+	domainLinks, err := s.db.CountUserDomainWorkspaceShortUrls(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to check domain links", err)
+	}
+	if domainLinks > 0 {
+		return nil, wraperrors.AlreadyExistsErr("Workspace links are served by one of your domains, delete them first")
+	}
+
+	// Personal links are deleted together with the user so a restore brings
+	// back exactly the links this deletion removed
+	deletedAt := pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	var keys []string
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		deleted, err := q.SoftDeleteUser(ctx, pgstore.SoftDeleteUserParams{
+			ID:        userId,
+			DeletedAt: deletedAt,
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to delete user", err)
+		}
+		if deleted == 0 {
 			return wraperrors.NotFoundErr("User not found")
 		}
-		return wraperrors.InternalErr("Failed to delete user", err)
+
+		deletedLinks, err := q.SoftDeletePersonalShortUrls(ctx, pgstore.SoftDeletePersonalShortUrlsParams{
+			UserID:    pgtype.UUID{Bytes: userId, Valid: true},
+			DeletedAt: deletedAt,
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to delete user short URLs", err)
+		}
+		for _, link := range deletedLinks {
+			var domainId *string
+			if link.DomainID.Valid {
+				id := uuid.UUID(link.DomainID.Bytes).String()
+				domainId = &id
+			}
+			keys = append(keys, models.LinkKey(domainId, link.Slug))
+		}
+
+		return recordAudit(ctx, q, userId, models.AuditEntityUser, userId, models.AuditActionDelete, user, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RestoreUser undoes a deletion within the restore window, the credentials
+// are checked the same way as on login. The most recently deleted account of
+// the email is restored, unless a new account took the address meanwhile
+func (s *userService) RestoreUser(ctx context.Context, input *models.GetUserByEmailInput) (*models.User, error) {
+	dbUser, err := s.db.GetDeletedUserByEmail(ctx, input.Email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("Deleted user not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get user by email", err)
+	}
+
+	if err := checkPassword(dbUser.PasswordHash, input.Password); err != nil {
+		return nil, err
+	}
+
+	if err := checkRestoreWindow(dbUser.DeletedAt); err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		ID:            dbUser.ID.String(),
+		Name:          dbUser.Name,
+		Email:         dbUser.Email,
+		EmailVerified: dbUser.EmailVerifiedAt.Valid,
+		TotpEnabled:   dbUser.TotpEnabled,
+		CreatedAt:     dbUser.CreatedAt.Time,
+	}
+
+	err = execTx(ctx, s.db, func(q *pgstore.Queries) error {
+		if err := q.RestoreUser(ctx, dbUser.ID); err != nil {
+			if wraperrors.IsUniqueViolation(err) {
+				return wraperrors.AlreadyExistsErr("Email now belongs to another account")
+			}
+			return wraperrors.InternalErr("Failed to restore user", err)
+		}
+
+		err := q.RestorePersonalShortUrls(ctx, pgstore.RestorePersonalShortUrlsParams{
+			UserID:    pgtype.UUID{Bytes: dbUser.ID, Valid: true},
+			DeletedAt: dbUser.DeletedAt,
+		})
+		if err != nil {
+			return wraperrors.InternalErr("Failed to restore user short URLs", err)
+		}
+
+		return recordAudit(ctx, q, dbUser.ID, models.AuditEntityUser, dbUser.ID, models.AuditActionRestore, nil, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (s *userService) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	row, err := consumeUserToken(ctx, s.db, token, emailVerificationPurpose)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := s.db.MarkUserEmailVerified(ctx, pgstore.MarkUserEmailVerifiedParams{
+		ID:    row.UserID,
+		Email: row.Email,
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to verify email", err)
+	}
+	if verified == 0 {
+		return nil, wraperrors.ValidationErr("Token does not match the current email address")
+	}
+
+	return s.GetUser(ctx, row.UserID.String())
+}
+
+func (s *userService) ResendVerification(ctx context.Context, id string) error {
+	user, err := s.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerified {
+		return wraperrors.ValidationErr("Email is already verified")
+	}
+
+	userId, _ := uuid.Parse(user.ID)
+	if err := s.db.InvalidateUserTokens(ctx, pgstore.InvalidateUserTokensParams{
+		UserID:  userId,
+		Purpose: emailVerificationPurpose,
+	}); err != nil {
+		return wraperrors.InternalErr("Failed to invalidate previous tokens", err)
+	}
+
+	token, err := issueUserToken(ctx, s.db, userId, user.Email, emailVerificationPurpose, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	if err := s.mailer.Send(ctx, verificationEmail(user.Email, token)); err != nil {
+		return wraperrors.InternalErr("Failed to send verification email", err)
 	}
 
 	return nil
 }
+
+// RequestPasswordReset never reports whether the email exists, it only logs failures
+func (s *userService) RequestPasswordReset(ctx context.Context, email string) error {
+	dbUser, err := s.db.GetUserByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Error("failed to look up user for password reset", "error", err)
+		}
+		return nil
+	}
+
+	token, err := issueUserToken(ctx, s.db, dbUser.ID, dbUser.Email, passwordResetPurpose, passwordResetTTL)
+	if err != nil {
+		s.logger.Error("failed to issue password reset token", "user_id", dbUser.ID, "error", err)
+		return nil
+	}
+
+	if err := s.mailer.Send(ctx, passwordResetEmail(dbUser.Email, token)); err != nil {
+		s.logger.Error("failed to send password reset email", "user_id", dbUser.ID, "error", err)
+	}
+
+	return nil
+}
+
+func (s *userService) ResetPassword(ctx context.Context, input *models.ResetPasswordInput) error {
+	row, err := consumeUserToken(ctx, s.db, input.Token, passwordResetPurpose)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := hashPassword(input.Password)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.UpdateUserPassword(ctx, pgstore.UpdateUserPasswordParams{
+		ID:           row.UserID,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to update password", err)
+	}
+
+	// Any other reset link sent before this one stops working
+	if err := s.db.InvalidateUserTokens(ctx, pgstore.InvalidateUserTokensParams{
+		UserID:  row.UserID,
+		Purpose: passwordResetPurpose,
+	}); err != nil {
+		return wraperrors.InternalErr("Failed to invalidate reset tokens", err)
+	}
+
+	// Receiving the reset email proves ownership of the address
+	if _, err := s.db.MarkUserEmailVerified(ctx, pgstore.MarkUserEmailVerifiedParams{
+		ID:    row.UserID,
+		Email: row.Email,
+	}); err != nil {
+		return wraperrors.InternalErr("Failed to verify email", err)
+	}
+
+	return nil
+}
+
+// sendVerification is best effort: the user can ask for a new email later
+func (s *userService) sendVerification(ctx context.Context, userId uuid.UUID, email string) {
+	token, err := issueUserToken(ctx, s.db, userId, email, emailVerificationPurpose, emailVerificationTTL)
+	if err != nil {
+		s.logger.Error("failed to issue verification token", "user_id", userId, "error", err)
+		return
+	}
+
+	if err := s.mailer.Send(ctx, verificationEmail(email, token)); err != nil {
+		s.logger.Error("failed to send verification email", "user_id", userId, "error", err)
+	}
+}
+
+// checkPassword refuses the accounts created before passwords were required,
+// they have to set one through the password reset email
+func checkPassword(passwordHash pgtype.Text, password string) error {
+	if !passwordHash.Valid {
+		return wraperrors.UnauthorizedErr("This account has no password, set one through the password reset email")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash.String), []byte(password)) != nil {
+		return wraperrors.UnauthorizedErr("Invalid email or password")
+	}
+	return nil
+}
+
+func hashPassword(password string) (pgtype.Text, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return pgtype.Text{}, wraperrors.InternalErr("Failed to hash password", err)
+	}
+	return pgtype.Text{String: string(hash), Valid: true}, nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const (
+	emailVerificationPurpose = "email_verification"
+	passwordResetPurpose     = "password_reset"
+	workspaceInvitePurpose   = "workspace_invitation"
+	emailVerificationTTL     = 24 * time.Hour
+	passwordResetTTL         = 1 * time.Hour
+	workspaceInvitationTTL   = 7 * 24 * time.Hour
+	defaultAppBaseURL        = "http://localhost:8080"
+)
+
+// issueUserToken stores a single-use token row and returns it signed as a JWT,
+// so the row ID can only be presented back together with a valid signature
+func issueUserToken(ctx context.Context, db *pgstore.Queries, userId uuid.UUID, email, purpose string, ttl time.Duration) (string, error) {
+	expiresAt := time.Now().Add(ttl)
+
+	dbToken, err := db.CreateUserToken(ctx, pgstore.CreateUserTokenParams{
+		UserID:    userId,
+		Purpose:   purpose,
+		Email:     email,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		return "", wraperrors.InternalErr("Failed to create token", err)
+	}
+
+	return signPurposeToken(dbToken.ID, userId, purpose, expiresAt)
+}
+
+// consumeUserToken checks the signature and marks the token as used,
+// returning the user and email it was issued for
+func consumeUserToken(ctx context.Context, db *pgstore.Queries, tokenStr, purpose string) (*pgstore.ConsumeUserTokenRow, error) {
+	tokenId, err := parsePurposeToken(tokenStr, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := db.ConsumeUserToken(ctx, pgstore.ConsumeUserTokenParams{
+		ID:      tokenId,
+		Purpose: purpose,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.UnauthorizedErr("Invalid or expired token")
+		}
+		return nil, wraperrors.InternalErr("Failed to consume token", err)
+	}
+
+	return &row, nil
+}
+
+// signPurposeToken signs the ID of a single-use row so it can travel in emails,
+// the purpose claim keeps JWTAuth from accepting it as a session token
+func signPurposeToken(id, userId uuid.UUID, purpose string, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"jti":     id.String(),
+		"user_id": userId.String(),
+		"purpose": purpose,
+		"exp":     expiresAt.Unix(),
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(tokenSecret())
+	if err != nil {
+		return "", wraperrors.InternalErr("Failed to sign token", err)
+	}
+
+	return signed, nil
+}
+
+// parsePurposeToken checks the signature and purpose and returns the row ID
+func parsePurposeToken(tokenStr, purpose string) (uuid.UUID, error) {
+	token, err := jwt.Parse(tokenStr, func(t *jwt.Token) (interface{}, error) {
+		return tokenSecret(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil || !token.Valid {
+		return uuid.Nil, wraperrors.UnauthorizedErr("Invalid or expired token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != purpose {
+		return uuid.Nil, wraperrors.UnauthorizedErr("Invalid or expired token")
+	}
+
+	rawId, _ := claims["jti"].(string)
+	id, err := uuid.Parse(rawId)
+	if err != nil {
+		return uuid.Nil, wraperrors.UnauthorizedErr("Invalid or expired token")
+	}
+
+	return id, nil
+}
+
+func verificationEmail(to, token string) models.Email {
+	return models.Email{
+		To:      to,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf(
+			"Confirm your email address by opening the link below:\n\n%s/api/users/verify_email?token=%s\n\nThe link expires in %s.\n",
+			appBaseURL(), token, emailVerificationTTL,
+		),
+	}
+}
+
+func passwordResetEmail(to, token string) models.Email {
+	return models.Email{
+		To:      to,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Someone asked to reset the password of your account.\n\n"+
+				"Send the token below with your new password to POST %s/api/users/password/reset:\n\n%s\n\n"+
+				"The token expires in %s. If it was not you, ignore this email.\n",
+			appBaseURL(), token, passwordResetTTL,
+		),
+	}
+}
+
+func workspaceInvitationEmail(to, workspaceName, token string) models.Email {
+	return models.Email{
+		To:      to,
+		Subject: fmt.Sprintf("You were invited to %s", workspaceName),
+		Body: fmt.Sprintf(
+			"You were invited to join the workspace %q.\n\n"+
+				"Sign in with this email address and send the token below to POST %s/api/workspaces/invitations/accept:\n\n%s\n\n"+
+				"The invitation expires in %s.\n",
+			workspaceName, appBaseURL(), token, workspaceInvitationTTL,
+		),
+	}
+}
+
+func tokenSecret() []byte {
+	return []byte(os.Getenv("MY_SECRET_KEY"))
+}
+
+func appBaseURL() string {
+	if baseURL := os.Getenv("APP_BASE_URL"); baseURL != "" {
+		return baseURL
+	}
+	return defaultAppBaseURL
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+type webhookService struct {
+	db *pgstore.Queries
+}
+
+func NewWebhookService(queries *pgstore.Queries) ports.WebhookUseCase {
+	return &webhookService{
+		db: queries,
+	}
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context, rawUserId string) ([]*models.Webhook, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbWebhooks, err := s.db.ListWebhooksByUserId(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list webhooks", err)
+	}
+
+	webhooks := make([]*models.Webhook, 0, len(dbWebhooks))
+	for _, dbWebhook := range dbWebhooks {
+		webhooks = append(webhooks, toWebhookModel(dbWebhook))
+	}
+
+	return webhooks, nil
+}
+
+// CreateWebhook registers an endpoint for the events of the user's links,
+// the signing secret is only shown in this response
+func (s *webhookService) CreateWebhook(ctx context.Context, rawUserId string, input *models.CreateWebhookInput) (*models.Webhook, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	u, err := url.ParseRequestURI(input.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, wraperrors.ValidationErr("Invalid webhook url")
+	}
+
+	existing, err := s.db.ListWebhooksByUserId(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list webhooks", err)
+	}
+	if len(existing) >= models.MaxWebhooksPerUser {
+		return nil, wraperrors.ValidationErr(fmt.Sprintf("A user can have at most %d webhooks", models.MaxWebhooksPerUser))
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to generate webhook secret", err)
+	}
+
+	dbWebhook, err := s.db.CreateWebhook(ctx, pgstore.CreateWebhookParams{
+		UserID: userId,
+		Url:    input.Url,
+		Secret: secret,
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to create webhook", err)
+	}
+
+	webhook := toWebhookModel(dbWebhook)
+	webhook.Secret = dbWebhook.Secret
+
+	return webhook, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, rawUserId string, id string) error {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	webhookId, err := uuid.Parse(id)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid webhook ID format")
+	}
+
+	deleted, err := s.db.DeleteWebhook(ctx, pgstore.DeleteWebhookParams{ID: webhookId, UserID: userId})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to delete webhook", err)
+	}
+	if deleted == 0 {
+		return wraperrors.NotFoundErr("Webhook not found")
+	}
+
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+func toWebhookModel(dbWebhook pgstore.Webhook) *models.Webhook {
+	return &models.Webhook{
+		ID:        dbWebhook.ID.String(),
+		Url:       dbWebhook.Url,
+		CreatedAt: dbWebhook.CreatedAt.Time,
+	}
+}
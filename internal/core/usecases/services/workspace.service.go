@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	"github.com/jhonVitor-rs/url-shortener/internal/data/db/pgstore"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+type workspaceService struct {
+	db     *pgstore.Queries
+	mailer ports.Mailer
+}
+
+func NewWorkspaceService(queries *pgstore.Queries, mailer ports.Mailer) ports.WorkspaceUseCase {
+	return &workspaceService{
+		db:     queries,
+		mailer: mailer,
+	}
+}
+
+func (s *workspaceService) ListWorkspaces(ctx context.Context, rawUserId string) ([]*models.Workspace, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbWorkspaces, err := s.db.ListWorkspacesByUserId(ctx, userId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list workspaces", err)
+	}
+
+	workspaces := make([]*models.Workspace, 0, len(dbWorkspaces))
+	for _, dbWorkspace := range dbWorkspaces {
+		workspaces = append(workspaces, &models.Workspace{
+			ID:        dbWorkspace.ID.String(),
+			Name:      dbWorkspace.Name,
+			Role:      dbWorkspace.Role,
+			CreatedAt: dbWorkspace.CreatedAt.Time,
+		})
+	}
+
+	return workspaces, nil
+}
+
+func (s *workspaceService) GetWorkspace(ctx context.Context, rawUserId string, id string) (*models.Workspace, error) {
+	userId, workspaceId, err := parseWorkspaceIds(rawUserId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := requireWorkspaceRole(ctx, s.db, workspaceId, userId, models.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	dbWorkspace, err := s.db.GetWorkspaceById(ctx, workspaceId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("Workspace not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get workspace", err)
+	}
+
+	dbMembers, err := s.db.ListWorkspaceMembers(ctx, workspaceId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to list workspace members", err)
+	}
+
+	members := make([]*models.WorkspaceMember, 0, len(dbMembers))
+	for _, dbMember := range dbMembers {
+		members = append(members, &models.WorkspaceMember{
+			UserID:    dbMember.UserID.String(),
+			Name:      dbMember.Name,
+			Email:     dbMember.Email,
+			Role:      dbMember.Role,
+			CreatedAt: dbMember.CreatedAt.Time,
+		})
+	}
+
+	return &models.Workspace{
+		ID:        dbWorkspace.ID.String(),
+		Name:      dbWorkspace.Name,
+		Role:      role,
+		CreatedAt: dbWorkspace.CreatedAt.Time,
+		Members:   members,
+	}, nil
+}
+
+// CreateWorkspace makes the creator the first owner of the workspace
+func (s *workspaceService) CreateWorkspace(ctx context.Context, rawUserId string, input *models.CreateWorkspaceInput) (*models.Workspace, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	dbWorkspace, err := s.db.CreateWorkspace(ctx, input.Name)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to create workspace", err)
+	}
+
+	err = s.db.AddWorkspaceMember(ctx, pgstore.AddWorkspaceMemberParams{
+		WorkspaceID: dbWorkspace.ID,
+		UserID:      userId,
+		Role:        models.WorkspaceRoleOwner,
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to add workspace owner", err)
+	}
+
+	return &models.Workspace{
+		ID:        dbWorkspace.ID.String(),
+		Name:      dbWorkspace.Name,
+		Role:      models.WorkspaceRoleOwner,
+		CreatedAt: dbWorkspace.CreatedAt.Time,
+	}, nil
+}
+
+func (s *workspaceService) UpdateWorkspace(ctx context.Context, rawUserId string, id string, input *models.UpdateWorkspaceInput) (*models.Workspace, error) {
+	userId, workspaceId, err := parseWorkspaceIds(rawUserId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := requireWorkspaceRole(ctx, s.db, workspaceId, userId, models.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	dbWorkspace, err := s.db.UpdateWorkspace(ctx, pgstore.UpdateWorkspaceParams{
+		ID:   workspaceId,
+		Name: input.Name,
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to update workspace", err)
+	}
+
+	return &models.Workspace{
+		ID:        dbWorkspace.ID.String(),
+		Name:      dbWorkspace.Name,
+		Role:      models.WorkspaceRoleOwner,
+		CreatedAt: dbWorkspace.CreatedAt.Time,
+	}, nil
+}
+
+// DeleteWorkspace refuses to drop a workspace that still owns links, they
+// must be transferred or deleted first
+func (s *workspaceService) DeleteWorkspace(ctx context.Context, rawUserId string, id string) error {
+	userId, workspaceId, err := parseWorkspaceIds(rawUserId, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := requireWorkspaceRole(ctx, s.db, workspaceId, userId, models.WorkspaceRoleOwner); err != nil {
+		return err
+	}
+
+	shortUrls, err := s.db.GetShortUrlsByWorkspaceId(ctx, pgtype.UUID{Bytes: workspaceId, Valid: true})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to list workspace short URLs", err)
+	}
+	if len(shortUrls) > 0 {
+		return wraperrors.AlreadyExistsErr("Workspace still owns short URLs")
+	}
+
+	if err := s.db.DeleteWorkspace(ctx, workspaceId); err != nil {
+		return wraperrors.InternalErr("Failed to delete workspace", err)
+	}
+
+	return nil
+}
+
+func (s *workspaceService) InviteMember(ctx context.Context, rawUserId string, id string, input *models.InviteMemberInput) (*models.WorkspaceInvitation, error) {
+	userId, workspaceId, err := parseWorkspaceIds(rawUserId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := requireWorkspaceRole(ctx, s.db, workspaceId, userId, models.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	dbWorkspace, err := s.db.GetWorkspaceById(ctx, workspaceId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to get workspace", err)
+	}
+
+	expiresAt := time.Now().Add(workspaceInvitationTTL)
+	dbInvitation, err := s.db.CreateWorkspaceInvitation(ctx, pgstore.CreateWorkspaceInvitationParams{
+		WorkspaceID: workspaceId,
+		Email:       input.Email,
+		Role:        input.Role,
+		InvitedBy:   pgtype.UUID{Bytes: userId, Valid: true},
+		ExpiresAt:   pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to create invitation", err)
+	}
+
+	token, err := signPurposeToken(dbInvitation.ID, userId, workspaceInvitePurpose, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mailer.Send(ctx, workspaceInvitationEmail(input.Email, dbWorkspace.Name, token)); err != nil {
+		return nil, wraperrors.InternalErr("Failed to send invitation email", err)
+	}
+
+	return &models.WorkspaceInvitation{
+		ID:          dbInvitation.ID.String(),
+		WorkspaceID: workspaceId.String(),
+		Email:       dbInvitation.Email,
+		Role:        dbInvitation.Role,
+		ExpiresAt:   dbInvitation.ExpiresAt.Time,
+	}, nil
+}
+
+// AcceptInvitation adds the authenticated user to the workspace, the
+// invitation only works for the verified address it was sent to
+func (s *workspaceService) AcceptInvitation(ctx context.Context, rawUserId string, token string) (*models.Workspace, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	invitationId, err := parsePurposeToken(token, workspaceInvitePurpose)
+	if err != nil {
+		return nil, err
+	}
+
+	dbInvitation, err := s.db.GetWorkspaceInvitation(ctx, invitationId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.UnauthorizedErr("Invalid or expired token")
+		}
+		return nil, wraperrors.InternalErr("Failed to get invitation", err)
+	}
+
+	dbUser, err := s.db.GetUser(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wraperrors.NotFoundErr("User not found")
+		}
+		return nil, wraperrors.InternalErr("Failed to get user", err)
+	}
+
+	if !strings.EqualFold(dbUser.Email, dbInvitation.Email) {
+		return nil, wraperrors.ForbiddenErr("Invitation was sent to a different email address")
+	}
+	if !dbUser.EmailVerifiedAt.Valid {
+		return nil, wraperrors.ForbiddenErr("Email address must be verified before joining a workspace")
+	}
+
+	accepted, err := s.db.AcceptWorkspaceInvitation(ctx, invitationId)
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to accept invitation", err)
+	}
+	if accepted == 0 {
+		return nil, wraperrors.UnauthorizedErr("Invalid or expired token")
+	}
+
+	err = s.db.AddWorkspaceMember(ctx, pgstore.AddWorkspaceMemberParams{
+		WorkspaceID: dbInvitation.WorkspaceID,
+		UserID:      userId,
+		Role:        dbInvitation.Role,
+	})
+	if err != nil {
+		return nil, wraperrors.InternalErr("Failed to add workspace member", err)
+	}
+
+	return s.GetWorkspace(ctx, rawUserId, dbInvitation.WorkspaceID.String())
+}
+
+func (s *workspaceService) UpdateMemberRole(ctx context.Context, rawUserId string, id string, rawMemberId string, input *models.UpdateMemberRoleInput) error {
+	userId, workspaceId, err := parseWorkspaceIds(rawUserId, id)
+	if err != nil {
+		return err
+	}
+
+	memberId, err := uuid.Parse(rawMemberId)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid member ID format")
+	}
+
+	if _, err := requireWorkspaceRole(ctx, s.db, workspaceId, userId, models.WorkspaceRoleOwner); err != nil {
+		return err
+	}
+
+	if input.Role != models.WorkspaceRoleOwner {
+		if err := s.checkKeepsOwner(ctx, workspaceId, memberId); err != nil {
+			return err
+		}
+	}
+
+	updated, err := s.db.UpdateWorkspaceMemberRole(ctx, pgstore.UpdateWorkspaceMemberRoleParams{
+		WorkspaceID: workspaceId,
+		UserID:      memberId,
+		Role:        input.Role,
+	})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to update member role", err)
+	}
+	if updated == 0 {
+		return wraperrors.NotFoundErr("Member not found")
+	}
+
+	return nil
+}
+
+// RemoveMember lets owners remove anyone and any member leave on their own
+func (s *workspaceService) RemoveMember(ctx context.Context, rawUserId string, id string, rawMemberId string) error {
+	userId, workspaceId, err := parseWorkspaceIds(rawUserId, id)
+	if err != nil {
+		return err
+	}
+
+	memberId, err := uuid.Parse(rawMemberId)
+	if err != nil {
+		return wraperrors.ValidationErr("Invalid member ID format")
+	}
+
+	minRole := models.WorkspaceRoleOwner
+	if memberId == userId {
+		minRole = models.WorkspaceRoleViewer
+	}
+	if _, err := requireWorkspaceRole(ctx, s.db, workspaceId, userId, minRole); err != nil {
+		return err
+	}
+
+	if err := s.checkKeepsOwner(ctx, workspaceId, memberId); err != nil {
+		return err
+	}
+
+	removed, err := s.db.RemoveWorkspaceMember(ctx, pgstore.RemoveWorkspaceMemberParams{
+		WorkspaceID: workspaceId,
+		UserID:      memberId,
+	})
+	if err != nil {
+		return wraperrors.InternalErr("Failed to remove member", err)
+	}
+	if removed == 0 {
+		return wraperrors.NotFoundErr("Member not found")
+	}
+
+	return nil
+}
+
+// checkKeepsOwner fails when memberId is the last owner of the workspace
+func (s *workspaceService) checkKeepsOwner(ctx context.Context, workspaceId, memberId uuid.UUID) error {
+	role, err := s.db.GetWorkspaceMemberRole(ctx, pgstore.GetWorkspaceMemberRoleParams{
+		WorkspaceID: workspaceId,
+		UserID:      memberId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return wraperrors.NotFoundErr("Member not found")
+		}
+		return wraperrors.InternalErr("Failed to get member role", err)
+	}
+	if role != models.WorkspaceRoleOwner {
+		return nil
+	}
+
+	owners, err := s.db.CountWorkspaceOwners(ctx, workspaceId)
+	if err != nil {
+		return wraperrors.InternalErr("Failed to count workspace owners", err)
+	}
+	if owners <= 1 {
+		return wraperrors.ValidationErr("Workspace must keep at least one owner")
+	}
+
+	return nil
+}
+
+// requireWorkspaceRole returns the user's role in the workspace. Non-members
+// get a not found error so workspace IDs can't be probed
+func requireWorkspaceRole(ctx context.Context, db *pgstore.Queries, workspaceId, userId uuid.UUID, minRole string) (string, error) {
+	role, err := db.GetWorkspaceMemberRole(ctx, pgstore.GetWorkspaceMemberRoleParams{
+		WorkspaceID: workspaceId,
+		UserID:      userId,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", wraperrors.NotFoundErr("Workspace not found")
+		}
+		return "", wraperrors.InternalErr("Failed to get workspace role", err)
+	}
+
+	if models.WorkspaceRoleRank(role) < models.WorkspaceRoleRank(minRole) {
+		return "", wraperrors.ForbiddenErr("Insufficient workspace role")
+	}
+
+	return role, nil
+}
+
+func parseWorkspaceIds(rawUserId, rawWorkspaceId string) (uuid.UUID, uuid.UUID, error) {
+	userId, err := uuid.Parse(rawUserId)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, wraperrors.ValidationErr("Invalid user ID format")
+	}
+
+	workspaceId, err := uuid.Parse(rawWorkspaceId)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, wraperrors.ValidationErr("Invalid workspace ID format")
+	}
+
+	return userId, workspaceId, nil
+}
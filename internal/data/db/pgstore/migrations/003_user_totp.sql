@@ -0,0 +1,22 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS "totp_secret" TEXT,
+  ADD COLUMN IF NOT EXISTS "totp_enabled" BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "code_hash" TEXT NOT NULL,
+  "used_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON
+  DELETE
+    CASCADE
+);
+---- create above / drop below ----
+DROP TABLE IF EXISTS user_recovery_codes;
+ALTER TABLE users
+  DROP COLUMN IF EXISTS "totp_enabled",
+  DROP COLUMN IF EXISTS "totp_secret";
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
@@ -0,0 +1,32 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS "email_verified_at" TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN IF NOT EXISTS "password_hash" TEXT;
+
+-- Accounts created before verification existed keep working
+UPDATE
+  users
+SET
+  email_verified_at = created_at
+WHERE
+  email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "purpose" TEXT NOT NULL,
+  "email" VARCHAR(100) NOT NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "used_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON
+  DELETE
+    CASCADE
+);
+---- create above / drop below ----
+DROP TABLE IF EXISTS user_tokens;
+ALTER TABLE users
+  DROP COLUMN IF EXISTS "password_hash",
+  DROP COLUMN IF EXISTS "email_verified_at";
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
@@ -0,0 +1,74 @@
+-- Write your migrate up statements here
+CREATE TABLE IF NOT EXISTS workspaces (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "name" VARCHAR(100) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS workspace_members (
+  "workspace_id" uuid NOT NULL,
+  "user_id" uuid NOT NULL,
+  "role" TEXT NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  PRIMARY KEY (workspace_id, user_id),
+  FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON
+  DELETE
+    CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "workspace_id" uuid NOT NULL,
+  "email" VARCHAR(100) NOT NULL,
+  "role" TEXT NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+  "invited_by" uuid,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "accepted_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+  FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON
+  DELETE
+    CASCADE,
+    FOREIGN KEY (invited_by) REFERENCES users(id) ON
+  DELETE
+    SET
+      NULL
+);
+
+-- Links owned by a workspace survive their creator: user_id becomes the
+-- (optional) creator and only personal links are removed with the user
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "workspace_id" uuid REFERENCES workspaces(id) ON DELETE RESTRICT,
+  ALTER COLUMN "user_id" DROP NOT NULL,
+  DROP CONSTRAINT IF EXISTS short_urls_user_id_fkey,
+  ADD CONSTRAINT short_urls_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL,
+  ADD CONSTRAINT short_urls_owner_check CHECK (user_id IS NOT NULL OR workspace_id IS NOT NULL);
+
+CREATE INDEX IF NOT EXISTS short_urls_workspace_id_idx ON short_urls (workspace_id);
+
+CREATE OR REPLACE FUNCTION delete_personal_short_urls() RETURNS TRIGGER AS $$
+BEGIN
+  DELETE FROM short_urls WHERE user_id = OLD.id AND workspace_id IS NULL;
+  RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_delete_personal_short_urls BEFORE DELETE ON users
+  FOR EACH ROW EXECUTE FUNCTION delete_personal_short_urls();
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS users_delete_personal_short_urls ON users;
+DROP FUNCTION IF EXISTS delete_personal_short_urls();
+DELETE FROM short_urls WHERE user_id IS NULL;
+ALTER TABLE short_urls
+  DROP CONSTRAINT IF EXISTS short_urls_owner_check,
+  DROP CONSTRAINT IF EXISTS short_urls_user_id_fkey,
+  ADD CONSTRAINT short_urls_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+  ALTER COLUMN "user_id" SET NOT NULL,
+  DROP COLUMN IF EXISTS "workspace_id";
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
@@ -0,0 +1,25 @@
+-- Write your migrate up statements here
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "deleted_at" TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users (deleted_at)
+WHERE
+  deleted_at IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS short_urls_deleted_at_idx ON short_urls (deleted_at)
+WHERE
+  deleted_at IS NOT NULL;
+---- create above / drop below ----
+DELETE FROM short_urls WHERE deleted_at IS NOT NULL;
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS short_urls_deleted_at_idx;
+DROP INDEX IF EXISTS users_deleted_at_idx;
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE users
+  DROP COLUMN IF EXISTS "deleted_at";
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
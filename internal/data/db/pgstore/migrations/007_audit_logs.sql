@@ -0,0 +1,33 @@
+-- Write your migrate up statements here
+-- actor_id and entity_id have no foreign keys on purpose: the trail must
+-- outlive the users and links it talks about
+CREATE TABLE IF NOT EXISTS audit_logs (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "actor_id" uuid,
+  "entity_type" TEXT NOT NULL,
+  "entity_id" uuid NOT NULL,
+  "action" TEXT NOT NULL,
+  "changes" JSONB NOT NULL DEFAULT '{}',
+  "request_id" TEXT,
+  "ip_address" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_logs_entity_idx ON audit_logs (entity_type, entity_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS audit_logs_actor_id_idx ON audit_logs (actor_id, created_at DESC);
+
+CREATE OR REPLACE FUNCTION prevent_audit_log_changes() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_logs_append_only BEFORE UPDATE OR DELETE ON audit_logs
+  FOR EACH ROW EXECUTE FUNCTION prevent_audit_log_changes();
+---- create above / drop below ----
+DROP TRIGGER IF EXISTS audit_logs_append_only ON audit_logs;
+DROP FUNCTION IF EXISTS prevent_audit_log_changes();
+DROP TABLE IF EXISTS audit_logs;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
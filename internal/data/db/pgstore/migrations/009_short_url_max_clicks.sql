@@ -0,0 +1,8 @@
+-- Write your migrate up statements here
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "max_clicks" INTEGER CHECK (max_clicks > 0);
+---- create above / drop below ----
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "max_clicks";
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- Write your migrate up statements here
+-- inactive_fallback decides what a link shows before starts_at: a 404, a
+-- redirect to the holding page in inactive_url or a countdown page
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "starts_at" TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN IF NOT EXISTS "inactive_fallback" TEXT NOT NULL DEFAULT 'not_found' CHECK (
+    inactive_fallback IN ('not_found', 'holding_page', 'countdown')
+  ),
+  ADD COLUMN IF NOT EXISTS "inactive_url" TEXT;
+---- create above / drop below ----
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "inactive_url",
+  DROP COLUMN IF EXISTS "inactive_fallback",
+  DROP COLUMN IF EXISTS "starts_at";
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
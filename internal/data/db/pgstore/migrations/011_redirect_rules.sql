@@ -0,0 +1,20 @@
+-- Write your migrate up statements here
+-- conditions is a JSON object evaluated by the application, rules are tried
+-- by ascending position and the link's original_url is the fallback
+CREATE TABLE IF NOT EXISTS redirect_rules (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "short_url_id" uuid NOT NULL,
+  "position" INTEGER NOT NULL DEFAULT 0,
+  "conditions" JSONB NOT NULL DEFAULT '{}',
+  "target_url" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS redirect_rules_short_url_id_idx ON redirect_rules (short_url_id, position);
+---- create above / drop below ----
+DROP TABLE IF EXISTS redirect_rules;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
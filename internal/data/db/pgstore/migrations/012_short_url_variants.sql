@@ -0,0 +1,30 @@
+-- Write your migrate up statements here
+-- A link with variants splits its traffic between them by weight instead of
+-- always redirecting to original_url, sticky_variants keeps returning
+-- visitors on the variant they got first
+CREATE TABLE IF NOT EXISTS short_url_variants (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "short_url_id" uuid NOT NULL,
+  "url" TEXT NOT NULL,
+  "weight" INTEGER NOT NULL CHECK (
+    weight > 0
+    AND weight <= 1000
+  ),
+  "access_count" INTEGER NOT NULL DEFAULT 0,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS short_url_variants_short_url_id_idx ON short_url_variants (short_url_id);
+
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "sticky_variants" BOOLEAN NOT NULL DEFAULT FALSE;
+---- create above / drop below ----
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "sticky_variants";
+
+DROP TABLE IF EXISTS short_url_variants;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
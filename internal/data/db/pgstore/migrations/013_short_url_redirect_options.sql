@@ -0,0 +1,16 @@
+-- Write your migrate up statements here
+-- forward_query appends the visitor's query string to the destination,
+-- query_merge decides which value wins when both have the same parameter
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "redirect_status" INTEGER NOT NULL DEFAULT 302 CHECK (redirect_status IN (301, 302, 307, 308)),
+  ADD COLUMN IF NOT EXISTS "forward_query" BOOLEAN NOT NULL DEFAULT FALSE,
+  ADD COLUMN IF NOT EXISTS "query_merge" TEXT NOT NULL DEFAULT 'keep_destination' CHECK (
+    query_merge IN ('keep_destination', 'override', 'append')
+  );
+---- create above / drop below ----
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "query_merge",
+  DROP COLUMN IF EXISTS "forward_query",
+  DROP COLUMN IF EXISTS "redirect_status";
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
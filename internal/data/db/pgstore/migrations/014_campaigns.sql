@@ -0,0 +1,32 @@
+-- Write your migrate up statements here
+-- Campaigns hold default UTM values a user reuses when creating links, the
+-- links keep their campaign_id so clicks can be aggregated per campaign
+CREATE TABLE IF NOT EXISTS campaigns (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "name" TEXT NOT NULL,
+  "utm_source" TEXT,
+  "utm_medium" TEXT,
+  "utm_campaign" TEXT,
+  "utm_term" TEXT,
+  "utm_content" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  UNIQUE (user_id, name),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON
+  DELETE
+    CASCADE
+);
+
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "campaign_id" uuid REFERENCES campaigns(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS short_urls_campaign_id_idx ON short_urls (campaign_id);
+---- create above / drop below ----
+DROP INDEX IF EXISTS short_urls_campaign_id_idx;
+
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "campaign_id";
+
+DROP TABLE IF EXISTS campaigns;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
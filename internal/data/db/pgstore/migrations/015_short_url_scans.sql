@@ -0,0 +1,9 @@
+-- Write your migrate up statements here
+-- scan_count is the part of access_count that came from QR code scans
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "scan_count" INTEGER NOT NULL DEFAULT 0;
+---- create above / drop below ----
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "scan_count";
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
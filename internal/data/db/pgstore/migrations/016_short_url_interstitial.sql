@@ -0,0 +1,10 @@
+-- Write your migrate up statements here
+-- interstitial makes every visit go through the preview page with a safety
+-- warning before reaching the destination
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "interstitial" BOOLEAN NOT NULL DEFAULT FALSE;
+---- create above / drop below ----
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "interstitial";
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
@@ -0,0 +1,29 @@
+-- Write your migrate up statements here
+-- Metadata of the destination of each link, fetched in the background. url is
+-- the destination it was requested for, so a result for an old original_url
+-- is discarded. Pending rows are retried at next_attempt_at and marked as
+-- failed once they run out of attempts
+CREATE TABLE IF NOT EXISTS short_url_metadata (
+  "short_url_id" uuid PRIMARY KEY NOT NULL,
+  "url" TEXT NOT NULL,
+  "status" TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'fetched', 'failed')),
+  "title" TEXT,
+  "description" TEXT,
+  "image_url" TEXT,
+  "favicon_url" TEXT,
+  "attempts" INTEGER NOT NULL DEFAULT 0,
+  "last_error" TEXT,
+  "next_attempt_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  "fetched_at" TIMESTAMP WITH TIME ZONE,
+  FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS short_url_metadata_pending_idx ON short_url_metadata (next_attempt_at)
+WHERE
+  status = 'pending';
+---- create above / drop below ----
+DROP TABLE IF EXISTS short_url_metadata;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
@@ -0,0 +1,45 @@
+-- Write your migrate up statements here
+-- Result of the latest periodic check of each link destination. url is the
+-- destination that was checked, rows are removed when original_url changes.
+-- consecutive_failures drives the broken link webhooks, notified_at is set
+-- once the owner was told and cleared when the destination recovers
+CREATE TABLE IF NOT EXISTS short_url_health (
+  "short_url_id" uuid PRIMARY KEY NOT NULL,
+  "url" TEXT NOT NULL,
+  "healthy" BOOLEAN NOT NULL,
+  "status_code" INTEGER,
+  "final_url" TEXT,
+  "latency_ms" INTEGER,
+  "last_error" TEXT,
+  "consecutive_failures" INTEGER NOT NULL DEFAULT 0,
+  "checked_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  "last_success_at" TIMESTAMP WITH TIME ZONE,
+  "next_check_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "notified_at" TIMESTAMP WITH TIME ZONE,
+  FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS short_url_health_next_check_idx ON short_url_health (next_check_at);
+
+-- Endpoints a user registers to be told about their links, payloads are
+-- signed with the secret
+CREATE TABLE IF NOT EXISTS webhooks (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "url" TEXT NOT NULL,
+  "secret" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_user_id_idx ON webhooks (user_id);
+---- create above / drop below ----
+DROP TABLE IF EXISTS webhooks;
+
+DROP TABLE IF EXISTS short_url_health;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
@@ -0,0 +1,58 @@
+-- Write your migrate up statements here
+-- Tags and folders let a user organize their links. A link has any number of
+-- tags and at most one folder, folders nest through parent_id and a folder
+-- name is unique among its siblings, top level folders included
+CREATE TABLE IF NOT EXISTS tags (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "name" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  UNIQUE (user_id, name),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS short_url_tags (
+  "short_url_id" uuid NOT NULL,
+  "tag_id" uuid NOT NULL,
+  PRIMARY KEY (short_url_id, tag_id),
+  FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON
+  DELETE
+    CASCADE,
+  FOREIGN KEY (tag_id) REFERENCES tags(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS short_url_tags_tag_id_idx ON short_url_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS folders (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "parent_id" uuid REFERENCES folders(id) ON DELETE CASCADE,
+  "name" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  UNIQUE NULLS NOT DISTINCT (user_id, parent_id, name),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON
+  DELETE
+    CASCADE
+);
+
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "folder_id" uuid REFERENCES folders(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS short_urls_folder_id_idx ON short_urls (folder_id);
+---- create above / drop below ----
+DROP INDEX IF EXISTS short_urls_folder_id_idx;
+
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "folder_id";
+
+DROP TABLE IF EXISTS folders;
+
+DROP TABLE IF EXISTS short_url_tags;
+
+DROP TABLE IF EXISTS tags;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
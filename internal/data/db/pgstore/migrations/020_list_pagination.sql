@@ -0,0 +1,37 @@
+-- Write your migrate up statements here
+-- Link lists are paged with a cursor on (created_at, id) and searched with
+-- ILIKE over the slug, the destination and the fetched title, which the
+-- trigram indexes serve for any substring
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS short_urls_user_id_created_at_idx ON short_urls (user_id, created_at, id)
+WHERE
+  deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS short_urls_workspace_id_created_at_idx ON short_urls (workspace_id, created_at, id)
+WHERE
+  deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS short_urls_slug_trgm_idx ON short_urls USING gin (slug gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS short_urls_original_url_trgm_idx ON short_urls USING gin (original_url gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS short_url_metadata_title_trgm_idx ON short_url_metadata USING gin (title gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS users_created_at_idx ON users (created_at, id)
+WHERE
+  deleted_at IS NULL;
+---- create above / drop below ----
+DROP INDEX IF EXISTS users_created_at_idx;
+
+DROP INDEX IF EXISTS short_url_metadata_title_trgm_idx;
+
+DROP INDEX IF EXISTS short_urls_original_url_trgm_idx;
+
+DROP INDEX IF EXISTS short_urls_slug_trgm_idx;
+
+DROP INDEX IF EXISTS short_urls_workspace_id_created_at_idx;
+
+DROP INDEX IF EXISTS short_urls_user_id_created_at_idx;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
@@ -0,0 +1,44 @@
+-- Write your migrate up statements here
+-- Bulk uploads too large to answer right away run as jobs. Each row keeps
+-- its input until it is processed, then the link it created or its errors,
+-- so an interrupted job resumes where it stopped once its lock expires
+CREATE TABLE IF NOT EXISTS bulk_jobs (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "status" TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+  "total_rows" INTEGER NOT NULL,
+  "processed_rows" INTEGER NOT NULL DEFAULT 0,
+  "created_rows" INTEGER NOT NULL DEFAULT 0,
+  "failed_rows" INTEGER NOT NULL DEFAULT 0,
+  "error" TEXT,
+  "locked_until" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  "started_at" TIMESTAMP WITH TIME ZONE,
+  "finished_at" TIMESTAMP WITH TIME ZONE,
+  FOREIGN KEY (user_id) REFERENCES users(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS bulk_jobs_unfinished_idx ON bulk_jobs (created_at)
+WHERE
+  status IN ('pending', 'running');
+
+CREATE TABLE IF NOT EXISTS bulk_job_rows (
+  "job_id" uuid NOT NULL,
+  "row_number" INTEGER NOT NULL,
+  "input" JSONB NOT NULL,
+  "short_url_id" uuid,
+  "slug" TEXT,
+  "errors" JSONB,
+  PRIMARY KEY (job_id, row_number),
+  FOREIGN KEY (job_id) REFERENCES bulk_jobs(id) ON
+  DELETE
+    CASCADE
+);
+---- create above / drop below ----
+DROP TABLE IF EXISTS bulk_job_rows;
+
+DROP TABLE IF EXISTS bulk_jobs;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
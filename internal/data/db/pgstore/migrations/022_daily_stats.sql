@@ -0,0 +1,18 @@
+-- Write your migrate up statements here
+-- Clicks and QR scans of a link per UTC day, counted when the access counters
+-- are synced from Redis. Imported links bring only their click total, kept in
+-- access_count, other shorteners do not export their daily clicks
+CREATE TABLE IF NOT EXISTS short_url_daily_stats (
+  "short_url_id" uuid NOT NULL,
+  "day" DATE NOT NULL,
+  "clicks" INTEGER NOT NULL DEFAULT 0,
+  "scans" INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (short_url_id, day),
+  FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON
+  DELETE
+    CASCADE
+);
+---- create above / drop below ----
+DROP TABLE IF EXISTS short_url_daily_stats;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
@@ -0,0 +1,35 @@
+-- Write your migrate up statements here
+-- Every edit of a link stores the state it left the link in, numbered from 1
+-- per link. A link edited for the first time also gets its state before the
+-- edit as version 1, dated to the link creation. author_id is empty for that
+-- first state and once the author account is deleted
+CREATE TABLE IF NOT EXISTS short_url_versions (
+  "short_url_id" uuid NOT NULL,
+  "version" INTEGER NOT NULL,
+  "slug" TEXT NOT NULL,
+  "original_url" TEXT NOT NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE,
+  "password_hash" TEXT,
+  "max_clicks" INTEGER,
+  "starts_at" TIMESTAMP WITH TIME ZONE,
+  "inactive_fallback" TEXT NOT NULL,
+  "inactive_url" TEXT,
+  "sticky_variants" BOOLEAN NOT NULL,
+  "redirect_status" INTEGER NOT NULL,
+  "forward_query" BOOLEAN NOT NULL,
+  "query_merge" TEXT NOT NULL,
+  "campaign_id" uuid REFERENCES campaigns(id) ON DELETE SET NULL,
+  "interstitial" BOOLEAN NOT NULL,
+  "folder_id" uuid REFERENCES folders(id) ON DELETE SET NULL,
+  "author_id" uuid REFERENCES users(id) ON DELETE SET NULL,
+  "restored_from" INTEGER,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (short_url_id, version),
+  FOREIGN KEY (short_url_id) REFERENCES short_urls(id) ON
+  DELETE
+    CASCADE
+);
+---- create above / drop below ----
+DROP TABLE IF EXISTS short_url_versions;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
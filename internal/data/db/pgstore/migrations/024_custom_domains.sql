@@ -0,0 +1,61 @@
+-- Write your migrate up statements here
+-- Custom domains serve links under a branded host. A domain is verified once
+-- a DNS TXT record holds its token, only verified domains resolve links.
+-- not_found_url is where a visitor is sent for a slug the domain does not have.
+-- Several users may claim a host, only one of them can have it verified
+CREATE TABLE IF NOT EXISTS domains (
+  "id" uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+  "user_id" uuid NOT NULL,
+  "host" TEXT NOT NULL,
+  "verification_token" TEXT NOT NULL,
+  "verified_at" TIMESTAMP WITH TIME ZONE,
+  "not_found_url" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  UNIQUE (user_id, host),
+  FOREIGN KEY (user_id) REFERENCES users(id) ON
+  DELETE
+    CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS domains_verified_host_key ON domains (host)
+WHERE
+  verified_at IS NOT NULL;
+
+-- Links without a domain are served by the shortener host. Slugs are unique
+-- per domain, the links of the shortener host sharing one namespace, and
+-- looked up through the plain slug index whatever the domain
+ALTER TABLE short_urls
+  ADD COLUMN IF NOT EXISTS "domain_id" uuid REFERENCES domains(id) ON
+  DELETE
+    CASCADE;
+
+ALTER TABLE short_urls
+  DROP CONSTRAINT IF EXISTS short_urls_slug_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS short_urls_slug_key ON short_urls (slug)
+WHERE
+  domain_id IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS short_urls_domain_id_slug_key ON short_urls (domain_id, slug)
+WHERE
+  domain_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS short_urls_slug_idx ON short_urls (slug);
+---- create above / drop below ----
+DROP INDEX IF EXISTS short_urls_slug_idx;
+
+DROP INDEX IF EXISTS short_urls_domain_id_slug_key;
+
+DROP INDEX IF EXISTS short_urls_slug_key;
+
+DELETE FROM short_urls WHERE domain_id IS NOT NULL;
+
+ALTER TABLE short_urls
+  DROP COLUMN IF EXISTS "domain_id",
+  ADD CONSTRAINT short_urls_slug_key UNIQUE (slug);
+
+DROP INDEX IF EXISTS domains_verified_host_key;
+
+DROP TABLE IF EXISTS domains;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
\ No newline at end of file
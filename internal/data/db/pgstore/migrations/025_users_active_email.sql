@@ -0,0 +1,34 @@
+-- Write your migrate up statements here
+-- An email only has to be unique among the accounts that are not deleted, so
+-- the address of a deleted account can sign up again. Restoring that account
+-- is refused while the address is taken
+ALTER TABLE users
+  DROP CONSTRAINT IF EXISTS users_email_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_key ON users (email)
+WHERE
+  deleted_at IS NULL;
+---- create above / drop below ----
+DROP INDEX IF EXISTS users_email_key;
+
+DELETE FROM users u
+WHERE
+  u.deleted_at IS NOT NULL
+  AND EXISTS (
+    SELECT
+      1
+    FROM
+      users other
+    WHERE
+      other.email = u.email
+      AND other.id <> u.id
+      AND (
+        other.deleted_at IS NULL
+        OR other.deleted_at > u.deleted_at
+      )
+  );
+
+ALTER TABLE users
+  ADD CONSTRAINT users_email_key UNIQUE (email);
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -0,0 +1,13 @@
+-- Write your migrate up statements here
+-- A domain is removed with its owner, the links it serves must not go with it
+-- unnoticed. Deleting a domain that still serves links is refused, the
+-- service clears the links in the trash before deleting a domain
+ALTER TABLE short_urls
+  DROP CONSTRAINT IF EXISTS short_urls_domain_id_fkey,
+  ADD CONSTRAINT short_urls_domain_id_fkey FOREIGN KEY (domain_id) REFERENCES domains(id) ON DELETE RESTRICT;
+---- create above / drop below ----
+ALTER TABLE short_urls
+  DROP CONSTRAINT IF EXISTS short_urls_domain_id_fkey,
+  ADD CONSTRAINT short_urls_domain_id_fkey FOREIGN KEY (domain_id) REFERENCES domains(id) ON DELETE CASCADE;
+-- Write your migrate down statements here. If this migration is irreversible
+-- Then delete the separator line above.
@@ -9,19 +9,245 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type ShortUrl struct {
+type AuditLog struct {
+	ID         uuid.UUID          `json:"id"`
+	ActorID    pgtype.UUID        `json:"actor_id"`
+	EntityType string             `json:"entity_type"`
+	EntityID   uuid.UUID          `json:"entity_id"`
+	Action     string             `json:"action"`
+	Changes    []byte             `json:"changes"`
+	RequestID  pgtype.Text        `json:"request_id"`
+	IpAddress  pgtype.Text        `json:"ip_address"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type BulkJob struct {
+	ID            uuid.UUID          `json:"id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	Status        string             `json:"status"`
+	TotalRows     int32              `json:"total_rows"`
+	ProcessedRows int32              `json:"processed_rows"`
+	CreatedRows   int32              `json:"created_rows"`
+	FailedRows    int32              `json:"failed_rows"`
+	Error         pgtype.Text        `json:"error"`
+	LockedUntil   pgtype.Timestamptz `json:"locked_until"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	StartedAt     pgtype.Timestamptz `json:"started_at"`
+	FinishedAt    pgtype.Timestamptz `json:"finished_at"`
+}
+
+type BulkJobRow struct {
+	JobID      uuid.UUID   `json:"job_id"`
+	RowNumber  int32       `json:"row_number"`
+	Input      []byte      `json:"input"`
+	ShortUrlID pgtype.UUID `json:"short_url_id"`
+	Slug       pgtype.Text `json:"slug"`
+	Errors     []byte      `json:"errors"`
+}
+
+type Campaign struct {
 	ID          uuid.UUID          `json:"id"`
 	UserID      uuid.UUID          `json:"user_id"`
-	Slug        string             `json:"slug"`
-	OriginalUrl string             `json:"original_url"`
+	Name        string             `json:"name"`
+	UtmSource   pgtype.Text        `json:"utm_source"`
+	UtmMedium   pgtype.Text        `json:"utm_medium"`
+	UtmCampaign pgtype.Text        `json:"utm_campaign"`
+	UtmTerm     pgtype.Text        `json:"utm_term"`
+	UtmContent  pgtype.Text        `json:"utm_content"`
 	CreatedAt   pgtype.Timestamptz `json:"created_at"`
-	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
-	AccessCount pgtype.Int4        `json:"access_count"`
 }
 
-type User struct {
+type Domain struct {
+	ID                uuid.UUID          `json:"id"`
+	UserID            uuid.UUID          `json:"user_id"`
+	Host              string             `json:"host"`
+	VerificationToken string             `json:"verification_token"`
+	VerifiedAt        pgtype.Timestamptz `json:"verified_at"`
+	NotFoundUrl       pgtype.Text        `json:"not_found_url"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+}
+
+type Folder struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	ParentID  pgtype.UUID        `json:"parent_id"`
+	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RedirectRule struct {
+	ID         uuid.UUID          `json:"id"`
+	ShortUrlID uuid.UUID          `json:"short_url_id"`
+	Position   int32              `json:"position"`
+	Conditions []byte             `json:"conditions"`
+	TargetUrl  string             `json:"target_url"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type ShortUrl struct {
+	ID               uuid.UUID          `json:"id"`
+	UserID           pgtype.UUID        `json:"user_id"`
+	Slug             string             `json:"slug"`
+	OriginalUrl      string             `json:"original_url"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	AccessCount      pgtype.Int4        `json:"access_count"`
+	WorkspaceID      pgtype.UUID        `json:"workspace_id"`
+	DeletedAt        pgtype.Timestamptz `json:"deleted_at"`
+	PasswordHash     pgtype.Text        `json:"password_hash"`
+	MaxClicks        pgtype.Int4        `json:"max_clicks"`
+	StartsAt         pgtype.Timestamptz `json:"starts_at"`
+	InactiveFallback string             `json:"inactive_fallback"`
+	InactiveUrl      pgtype.Text        `json:"inactive_url"`
+	StickyVariants   bool               `json:"sticky_variants"`
+	RedirectStatus   int32              `json:"redirect_status"`
+	ForwardQuery     bool               `json:"forward_query"`
+	QueryMerge       string             `json:"query_merge"`
+	CampaignID       pgtype.UUID        `json:"campaign_id"`
+	ScanCount        int32              `json:"scan_count"`
+	Interstitial     bool               `json:"interstitial"`
+	FolderID         pgtype.UUID        `json:"folder_id"`
+	DomainID         pgtype.UUID        `json:"domain_id"`
+}
+
+type ShortUrlDailyStat struct {
+	ShortUrlID uuid.UUID   `json:"short_url_id"`
+	Day        pgtype.Date `json:"day"`
+	Clicks     int32       `json:"clicks"`
+	Scans      int32       `json:"scans"`
+}
+
+type ShortUrlHealth struct {
+	ShortUrlID          uuid.UUID          `json:"short_url_id"`
+	Url                 string             `json:"url"`
+	Healthy             bool               `json:"healthy"`
+	StatusCode          pgtype.Int4        `json:"status_code"`
+	FinalUrl            pgtype.Text        `json:"final_url"`
+	LatencyMs           pgtype.Int4        `json:"latency_ms"`
+	LastError           pgtype.Text        `json:"last_error"`
+	ConsecutiveFailures int32              `json:"consecutive_failures"`
+	CheckedAt           pgtype.Timestamptz `json:"checked_at"`
+	LastSuccessAt       pgtype.Timestamptz `json:"last_success_at"`
+	NextCheckAt         pgtype.Timestamptz `json:"next_check_at"`
+	NotifiedAt          pgtype.Timestamptz `json:"notified_at"`
+}
+
+type ShortUrlMetadatum struct {
+	ShortUrlID    uuid.UUID          `json:"short_url_id"`
+	Url           string             `json:"url"`
+	Status        string             `json:"status"`
+	Title         pgtype.Text        `json:"title"`
+	Description   pgtype.Text        `json:"description"`
+	ImageUrl      pgtype.Text        `json:"image_url"`
+	FaviconUrl    pgtype.Text        `json:"favicon_url"`
+	Attempts      int32              `json:"attempts"`
+	LastError     pgtype.Text        `json:"last_error"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	FetchedAt     pgtype.Timestamptz `json:"fetched_at"`
+}
+
+type ShortUrlTag struct {
+	ShortUrlID uuid.UUID `json:"short_url_id"`
+	TagID      uuid.UUID `json:"tag_id"`
+}
+
+type ShortUrlVariant struct {
+	ID          uuid.UUID          `json:"id"`
+	ShortUrlID  uuid.UUID          `json:"short_url_id"`
+	Url         string             `json:"url"`
+	Weight      int32              `json:"weight"`
+	AccessCount int32              `json:"access_count"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type ShortUrlVersion struct {
+	ShortUrlID       uuid.UUID          `json:"short_url_id"`
+	Version          int32              `json:"version"`
+	Slug             string             `json:"slug"`
+	OriginalUrl      string             `json:"original_url"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	PasswordHash     pgtype.Text        `json:"password_hash"`
+	MaxClicks        pgtype.Int4        `json:"max_clicks"`
+	StartsAt         pgtype.Timestamptz `json:"starts_at"`
+	InactiveFallback string             `json:"inactive_fallback"`
+	InactiveUrl      pgtype.Text        `json:"inactive_url"`
+	StickyVariants   bool               `json:"sticky_variants"`
+	RedirectStatus   int32              `json:"redirect_status"`
+	ForwardQuery     bool               `json:"forward_query"`
+	QueryMerge       string             `json:"query_merge"`
+	CampaignID       pgtype.UUID        `json:"campaign_id"`
+	Interstitial     bool               `json:"interstitial"`
+	FolderID         pgtype.UUID        `json:"folder_id"`
+	AuthorID         pgtype.UUID        `json:"author_id"`
+	RestoredFrom     pgtype.Int4        `json:"restored_from"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+}
+
+type Tag struct {
 	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
 	Name      string             `json:"name"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type User struct {
+	ID              uuid.UUID          `json:"id"`
+	Name            string             `json:"name"`
+	Email           string             `json:"email"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	TotpSecret      pgtype.Text        `json:"totp_secret"`
+	TotpEnabled     bool               `json:"totp_enabled"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+	PasswordHash    pgtype.Text        `json:"password_hash"`
+	DeletedAt       pgtype.Timestamptz `json:"deleted_at"`
+}
+
+type UserRecoveryCode struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type UserToken struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Purpose   string             `json:"purpose"`
 	Email     string             `json:"email"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Webhook struct {
+	ID        uuid.UUID          `json:"id"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Url       string             `json:"url"`
+	Secret    string             `json:"secret"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type Workspace struct {
+	ID        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
+
+type WorkspaceInvitation struct {
+	ID          uuid.UUID          `json:"id"`
+	WorkspaceID uuid.UUID          `json:"workspace_id"`
+	Email       string             `json:"email"`
+	Role        string             `json:"role"`
+	InvitedBy   pgtype.UUID        `json:"invited_by"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	AcceptedAt  pgtype.Timestamptz `json:"accepted_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID          `json:"workspace_id"`
+	UserID      uuid.UUID          `json:"user_id"`
+	Role        string             `json:"role"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
@@ -14,106 +14,168 @@ import (
 
 const createShortUrl = `-- name: CreateShortUrl :one
 INSERT INTO
-  short_urls (user_id, slug, original_url, expires_at)
+  short_urls (
+    user_id,
+    workspace_id,
+    slug,
+    original_url,
+    expires_at,
+    password_hash,
+    max_clicks,
+    starts_at,
+    inactive_fallback,
+    inactive_url,
+    sticky_variants,
+    redirect_status,
+    forward_query,
+    query_merge,
+    campaign_id,
+    interstitial,
+    folder_id,
+    domain_id
+  )
 VALUES
-  ($1, $2, $3, $4) RETURNING id,
-  slug,
-  original_url,
-  expires_at,
-  created_at
+  ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18) RETURNING id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
 `
 
 type CreateShortUrlParams struct {
-	UserID      uuid.UUID          `json:"user_id"`
-	Slug        string             `json:"slug"`
-	OriginalUrl string             `json:"original_url"`
-	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
-}
-
-type CreateShortUrlRow struct {
-	ID          uuid.UUID          `json:"id"`
-	Slug        string             `json:"slug"`
-	OriginalUrl string             `json:"original_url"`
-	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UserID           pgtype.UUID        `json:"user_id"`
+	WorkspaceID      pgtype.UUID        `json:"workspace_id"`
+	Slug             string             `json:"slug"`
+	OriginalUrl      string             `json:"original_url"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	PasswordHash     pgtype.Text        `json:"password_hash"`
+	MaxClicks        pgtype.Int4        `json:"max_clicks"`
+	StartsAt         pgtype.Timestamptz `json:"starts_at"`
+	InactiveFallback string             `json:"inactive_fallback"`
+	InactiveUrl      pgtype.Text        `json:"inactive_url"`
+	StickyVariants   bool               `json:"sticky_variants"`
+	RedirectStatus   int32              `json:"redirect_status"`
+	ForwardQuery     bool               `json:"forward_query"`
+	QueryMerge       string             `json:"query_merge"`
+	CampaignID       pgtype.UUID        `json:"campaign_id"`
+	Interstitial     bool               `json:"interstitial"`
+	FolderID         pgtype.UUID        `json:"folder_id"`
+	DomainID         pgtype.UUID        `json:"domain_id"`
 }
 
-func (q *Queries) CreateShortUrl(ctx context.Context, arg CreateShortUrlParams) (CreateShortUrlRow, error) {
+func (q *Queries) CreateShortUrl(ctx context.Context, arg CreateShortUrlParams) (ShortUrl, error) {
 	row := q.db.QueryRow(ctx, createShortUrl,
 		arg.UserID,
+		arg.WorkspaceID,
 		arg.Slug,
 		arg.OriginalUrl,
 		arg.ExpiresAt,
+		arg.PasswordHash,
+		arg.MaxClicks,
+		arg.StartsAt,
+		arg.InactiveFallback,
+		arg.InactiveUrl,
+		arg.StickyVariants,
+		arg.RedirectStatus,
+		arg.ForwardQuery,
+		arg.QueryMerge,
+		arg.CampaignID,
+		arg.Interstitial,
+		arg.FolderID,
+		arg.DomainID,
 	)
-	var i CreateShortUrlRow
+	var i ShortUrl
 	err := row.Scan(
 		&i.ID,
+		&i.UserID,
 		&i.Slug,
 		&i.OriginalUrl,
-		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.AccessCount,
+		&i.WorkspaceID,
+		&i.DeletedAt,
+		&i.PasswordHash,
+		&i.MaxClicks,
+		&i.StartsAt,
+		&i.InactiveFallback,
+		&i.InactiveUrl,
+		&i.StickyVariants,
+		&i.RedirectStatus,
+		&i.ForwardQuery,
+		&i.QueryMerge,
+		&i.CampaignID,
+		&i.ScanCount,
+		&i.Interstitial,
+		&i.FolderID,
+		&i.DomainID,
 	)
 	return i, err
 }
 
+type CreateShortUrlsParams struct {
+	ID               uuid.UUID          `json:"id"`
+	UserID           pgtype.UUID        `json:"user_id"`
+	WorkspaceID      pgtype.UUID        `json:"workspace_id"`
+	Slug             string             `json:"slug"`
+	OriginalUrl      string             `json:"original_url"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	PasswordHash     pgtype.Text        `json:"password_hash"`
+	MaxClicks        pgtype.Int4        `json:"max_clicks"`
+	StartsAt         pgtype.Timestamptz `json:"starts_at"`
+	InactiveFallback string             `json:"inactive_fallback"`
+	InactiveUrl      pgtype.Text        `json:"inactive_url"`
+	StickyVariants   bool               `json:"sticky_variants"`
+	RedirectStatus   int32              `json:"redirect_status"`
+	ForwardQuery     bool               `json:"forward_query"`
+	QueryMerge       string             `json:"query_merge"`
+	CampaignID       pgtype.UUID        `json:"campaign_id"`
+	Interstitial     bool               `json:"interstitial"`
+	FolderID         pgtype.UUID        `json:"folder_id"`
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO
-  users (NAME, email)
+  users (NAME, email, password_hash)
 VALUES
-  ($1, $2) RETURNING id,
+  ($1, $2, $3) RETURNING id,
   NAME,
   email,
-  created_at
+  created_at,
+  email_verified_at
 `
 
 type CreateUserParams struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name         string      `json:"name"`
+	Email        string      `json:"email"`
+	PasswordHash pgtype.Text `json:"password_hash"`
 }
 
-func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Name, arg.Email)
-	var i User
+type CreateUserRow struct {
+	ID              uuid.UUID          `json:"id"`
+	Name            string             `json:"name"`
+	Email           string             `json:"email"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
+	row := q.db.QueryRow(ctx, createUser, arg.Name, arg.Email, arg.PasswordHash)
+	var i CreateUserRow
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Email,
 		&i.CreatedAt,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
-const deleteShortUrl = `-- name: DeleteShortUrl :exec
-DELETE FROM
-  short_urls
-WHERE
-  id = $1
-`
-
-func (q *Queries) DeleteShortUrl(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteShortUrl, id)
-	return err
-}
-
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM
-  users
-WHERE
-  id = $1
-`
-
-func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteUser, id)
-	return err
-}
-
 const getShortUrlById = `-- name: GetShortUrlById :one
 SELECT
-  id, user_id, slug, original_url, created_at, expires_at, access_count
+  id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
 FROM
   short_urls
 WHERE
   id = $1
+  AND deleted_at IS NULL
 `
 
 func (q *Queries) GetShortUrlById(ctx context.Context, id uuid.UUID) (ShortUrl, error) {
@@ -127,21 +189,49 @@ func (q *Queries) GetShortUrlById(ctx context.Context, id uuid.UUID) (ShortUrl,
 		&i.CreatedAt,
 		&i.ExpiresAt,
 		&i.AccessCount,
+		&i.WorkspaceID,
+		&i.DeletedAt,
+		&i.PasswordHash,
+		&i.MaxClicks,
+		&i.StartsAt,
+		&i.InactiveFallback,
+		&i.InactiveUrl,
+		&i.StickyVariants,
+		&i.RedirectStatus,
+		&i.ForwardQuery,
+		&i.QueryMerge,
+		&i.CampaignID,
+		&i.ScanCount,
+		&i.Interstitial,
+		&i.FolderID,
+		&i.DomainID,
 	)
 	return i, err
 }
 
 const getShortUrlBySlug = `-- name: GetShortUrlBySlug :one
 SELECT
-  id, user_id, slug, original_url, created_at, expires_at, access_count
+  id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
 FROM
   short_urls
 WHERE
   slug = $1
+  AND (
+    (
+      $2::uuid IS NULL
+      AND domain_id IS NULL
+    )
+    OR domain_id = $2::uuid
+  )
 `
 
-func (q *Queries) GetShortUrlBySlug(ctx context.Context, slug string) (ShortUrl, error) {
-	row := q.db.QueryRow(ctx, getShortUrlBySlug, slug)
+type GetShortUrlBySlugParams struct {
+	Slug     string      `json:"slug"`
+	DomainID pgtype.UUID `json:"domain_id"`
+}
+
+func (q *Queries) GetShortUrlBySlug(ctx context.Context, arg GetShortUrlBySlugParams) (ShortUrl, error) {
+	row := q.db.QueryRow(ctx, getShortUrlBySlug, arg.Slug, arg.DomainID)
 	var i ShortUrl
 	err := row.Scan(
 		&i.ID,
@@ -151,20 +241,38 @@ func (q *Queries) GetShortUrlBySlug(ctx context.Context, slug string) (ShortUrl,
 		&i.CreatedAt,
 		&i.ExpiresAt,
 		&i.AccessCount,
+		&i.WorkspaceID,
+		&i.DeletedAt,
+		&i.PasswordHash,
+		&i.MaxClicks,
+		&i.StartsAt,
+		&i.InactiveFallback,
+		&i.InactiveUrl,
+		&i.StickyVariants,
+		&i.RedirectStatus,
+		&i.ForwardQuery,
+		&i.QueryMerge,
+		&i.CampaignID,
+		&i.ScanCount,
+		&i.Interstitial,
+		&i.FolderID,
+		&i.DomainID,
 	)
 	return i, err
 }
 
 const getShortUrlsByUserId = `-- name: GetShortUrlsByUserId :many
 SELECT
-  id, user_id, slug, original_url, created_at, expires_at, access_count
+  id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
 FROM
   short_urls
 WHERE
   user_id = $1
+  AND workspace_id IS NULL
+  AND deleted_at IS NULL
 `
 
-func (q *Queries) GetShortUrlsByUserId(ctx context.Context, userID uuid.UUID) ([]ShortUrl, error) {
+func (q *Queries) GetShortUrlsByUserId(ctx context.Context, userID pgtype.UUID) ([]ShortUrl, error) {
 	rows, err := q.db.Query(ctx, getShortUrlsByUserId, userID)
 	if err != nil {
 		return nil, err
@@ -181,6 +289,76 @@ func (q *Queries) GetShortUrlsByUserId(ctx context.Context, userID uuid.UUID) ([
 			&i.CreatedAt,
 			&i.ExpiresAt,
 			&i.AccessCount,
+			&i.WorkspaceID,
+			&i.DeletedAt,
+			&i.PasswordHash,
+			&i.MaxClicks,
+			&i.StartsAt,
+			&i.InactiveFallback,
+			&i.InactiveUrl,
+			&i.StickyVariants,
+			&i.RedirectStatus,
+			&i.ForwardQuery,
+			&i.QueryMerge,
+			&i.CampaignID,
+			&i.ScanCount,
+			&i.Interstitial,
+			&i.FolderID,
+			&i.DomainID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getShortUrlsByWorkspaceId = `-- name: GetShortUrlsByWorkspaceId :many
+SELECT
+  id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
+FROM
+  short_urls
+WHERE
+  workspace_id = $1
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) GetShortUrlsByWorkspaceId(ctx context.Context, workspaceID pgtype.UUID) ([]ShortUrl, error) {
+	rows, err := q.db.Query(ctx, getShortUrlsByWorkspaceId, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShortUrl
+	for rows.Next() {
+		var i ShortUrl
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Slug,
+			&i.OriginalUrl,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.AccessCount,
+			&i.WorkspaceID,
+			&i.DeletedAt,
+			&i.PasswordHash,
+			&i.MaxClicks,
+			&i.StartsAt,
+			&i.InactiveFallback,
+			&i.InactiveUrl,
+			&i.StickyVariants,
+			&i.RedirectStatus,
+			&i.ForwardQuery,
+			&i.QueryMerge,
+			&i.CampaignID,
+			&i.ScanCount,
+			&i.Interstitial,
+			&i.FolderID,
+			&i.DomainID,
 		); err != nil {
 			return nil, err
 		}
@@ -194,11 +372,12 @@ func (q *Queries) GetShortUrlsByUserId(ctx context.Context, userID uuid.UUID) ([
 
 const getUser = `-- name: GetUser :one
 SELECT
-  id, name, email, created_at
+  id, name, email, created_at, totp_secret, totp_enabled, email_verified_at, password_hash, deleted_at
 FROM
   users
 WHERE
   id = $1
+  AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
@@ -209,17 +388,23 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Name,
 		&i.Email,
 		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.PasswordHash,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
 SELECT
-  id, name, email, created_at
+  id, name, email, created_at, totp_secret, totp_enabled, email_verified_at, password_hash, deleted_at
 FROM
   users
 WHERE
   email = $1
+  AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -230,19 +415,56 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Name,
 		&i.Email,
 		&i.CreatedAt,
+		&i.TotpSecret,
+		&i.TotpEnabled,
+		&i.EmailVerifiedAt,
+		&i.PasswordHash,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
 SELECT
-  id, name, email, created_at
+  id, name, email, created_at, totp_secret, totp_enabled, email_verified_at, password_hash, deleted_at
 FROM
   users
+WHERE
+  deleted_at IS NULL
+  AND (
+    $1::uuid IS NULL
+    OR (
+      $2::boolean
+      AND (created_at, id) < ($3::timestamptz, $1::uuid)
+    )
+    OR (
+      NOT $2::boolean
+      AND (created_at, id) > ($3::timestamptz, $1::uuid)
+    )
+  )
+ORDER BY
+  CASE WHEN $2::boolean THEN created_at END DESC,
+  CASE WHEN $2::boolean THEN id END DESC,
+  created_at,
+  id
+LIMIT
+  $4::integer
 `
 
-func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.Query(ctx, getUsers)
+type GetUsersParams struct {
+	CursorID   pgtype.UUID        `json:"cursor_id"`
+	Descending bool               `json:"descending"`
+	CursorTime pgtype.Timestamptz `json:"cursor_time"`
+	PageSize   int32              `json:"page_size"`
+}
+
+func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error) {
+	rows, err := q.db.Query(ctx, getUsers,
+		arg.CursorID,
+		arg.Descending,
+		arg.CursorTime,
+		arg.PageSize,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -255,6 +477,11 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 			&i.Name,
 			&i.Email,
 			&i.CreatedAt,
+			&i.TotpSecret,
+			&i.TotpEnabled,
+			&i.EmailVerifiedAt,
+			&i.PasswordHash,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -266,69 +493,675 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+type ImportShortUrlsParams struct {
+	ID          uuid.UUID          `json:"id"`
+	UserID      pgtype.UUID        `json:"user_id"`
+	Slug        string             `json:"slug"`
+	OriginalUrl string             `json:"original_url"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	AccessCount pgtype.Int4        `json:"access_count"`
+}
+
 const incrementAccessCount = `-- name: IncrementAccessCount :exec
+WITH updated AS (
+  UPDATE
+    short_urls
+  SET
+    access_count = access_count + $1
+  WHERE
+    slug = $2
+    AND (
+      (
+        $3::uuid IS NULL
+        AND domain_id IS NULL
+      )
+      OR domain_id = $3::uuid
+    ) RETURNING id
+)
+INSERT INTO
+  short_url_daily_stats (short_url_id, day, clicks)
+SELECT
+  id,
+  (NOW() AT TIME ZONE 'UTC')::date,
+  $1
+FROM
+  updated ON CONFLICT (short_url_id, day) DO
 UPDATE
-  short_urls
 SET
-  access_count = access_count + $2
-WHERE
-  slug = $1
+  clicks = short_url_daily_stats.clicks + EXCLUDED.clicks
 `
 
 type IncrementAccessCountParams struct {
-	Slug        string      `json:"slug"`
 	AccessCount pgtype.Int4 `json:"access_count"`
+	Slug        string      `json:"slug"`
+	DomainID    pgtype.UUID `json:"domain_id"`
 }
 
 func (q *Queries) IncrementAccessCount(ctx context.Context, arg IncrementAccessCountParams) error {
-	_, err := q.db.Exec(ctx, incrementAccessCount, arg.Slug, arg.AccessCount)
+	_, err := q.db.Exec(ctx, incrementAccessCount, arg.AccessCount, arg.Slug, arg.DomainID)
 	return err
 }
 
+const incrementScanCount = `-- name: IncrementScanCount :exec
+WITH updated AS (
+  UPDATE
+    short_urls
+  SET
+    scan_count = scan_count + $1
+  WHERE
+    slug = $2
+    AND (
+      (
+        $3::uuid IS NULL
+        AND domain_id IS NULL
+      )
+      OR domain_id = $3::uuid
+    ) RETURNING id
+)
+INSERT INTO
+  short_url_daily_stats (short_url_id, day, scans)
+SELECT
+  id,
+  (NOW() AT TIME ZONE 'UTC')::date,
+  $1
+FROM
+  updated ON CONFLICT (short_url_id, day) DO
+UPDATE
+SET
+  scans = short_url_daily_stats.scans + EXCLUDED.scans
+`
+
+type IncrementScanCountParams struct {
+	ScanCount int32       `json:"scan_count"`
+	Slug      string      `json:"slug"`
+	DomainID  pgtype.UUID `json:"domain_id"`
+}
+
+func (q *Queries) IncrementScanCount(ctx context.Context, arg IncrementScanCountParams) error {
+	_, err := q.db.Exec(ctx, incrementScanCount, arg.ScanCount, arg.Slug, arg.DomainID)
+	return err
+}
+
+const listExistingSlugs = `-- name: ListExistingSlugs :many
+SELECT
+  slug
+FROM
+  short_urls
+WHERE
+  slug = ANY($1::text [])
+  AND domain_id IS NULL
+`
+
+func (q *Queries) ListExistingSlugs(ctx context.Context, slugs []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listExistingSlugs, slugs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var slug string
+		if err := rows.Scan(&slug); err != nil {
+			return nil, err
+		}
+		items = append(items, slug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShortUrls = `-- name: ListShortUrls :many
+SELECT
+  id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
+FROM
+  short_urls
+WHERE
+  deleted_at IS NULL
+  AND (
+    (
+      $1::uuid IS NULL
+      AND user_id = $2::uuid
+      AND workspace_id IS NULL
+    )
+    OR workspace_id = $1::uuid
+  )
+  AND (
+    $3::uuid IS NULL
+    OR campaign_id = $3::uuid
+  )
+  AND (
+    $4::uuid IS NULL
+    OR folder_id = $4::uuid
+  )
+  AND (
+    $5::uuid IS NULL
+    OR EXISTS (
+      SELECT
+        1
+      FROM
+        short_url_tags st
+      WHERE
+        st.short_url_id = short_urls.id
+        AND st.tag_id = $5::uuid
+    )
+  )
+  AND (
+    $6::text IS NULL
+    OR (
+      $6::text = 'active'
+      AND (
+        starts_at IS NULL
+        OR starts_at <= NOW()
+      )
+      AND (
+        expires_at IS NULL
+        OR expires_at > NOW()
+      )
+      AND (
+        max_clicks IS NULL
+        OR COALESCE(access_count, 0) < max_clicks
+      )
+    )
+    OR (
+      $6::text = 'expired'
+      AND (
+        expires_at <= NOW()
+        OR COALESCE(access_count, 0) >= max_clicks
+      )
+    )
+    OR (
+      $6::text = 'scheduled'
+      AND starts_at > NOW()
+    )
+  )
+  AND (
+    $7::timestamptz IS NULL
+    OR created_at >= $7::timestamptz
+  )
+  AND (
+    $8::timestamptz IS NULL
+    OR created_at < $8::timestamptz
+  )
+  AND (
+    $9::text IS NULL
+    OR slug ILIKE $9::text
+    OR original_url ILIKE $9::text
+    OR EXISTS (
+      SELECT
+        1
+      FROM
+        short_url_metadata m
+      WHERE
+        m.short_url_id = short_urls.id
+        AND m.title ILIKE $9::text
+    )
+  )
+  AND (
+    $10::uuid IS NULL
+    OR (
+      $11::text = 'created_at'
+      AND $12::boolean
+      AND (created_at, id) < ($13::timestamptz, $10::uuid)
+    )
+    OR (
+      $11::text = 'created_at'
+      AND NOT $12::boolean
+      AND (created_at, id) > ($13::timestamptz, $10::uuid)
+    )
+    OR (
+      $11::text = 'clicks'
+      AND $12::boolean
+      AND (COALESCE(access_count, 0), id) < ($14::integer, $10::uuid)
+    )
+    OR (
+      $11::text = 'clicks'
+      AND NOT $12::boolean
+      AND (COALESCE(access_count, 0), id) > ($14::integer, $10::uuid)
+    )
+    OR (
+      $11::text = 'expires_at'
+      AND $12::boolean
+      AND (COALESCE(expires_at, 'infinity'), id) < ($13::timestamptz, $10::uuid)
+    )
+    OR (
+      $11::text = 'expires_at'
+      AND NOT $12::boolean
+      AND (COALESCE(expires_at, 'infinity'), id) > ($13::timestamptz, $10::uuid)
+    )
+  )
+ORDER BY
+  CASE WHEN $11::text = 'created_at' AND $12::boolean THEN created_at END DESC,
+  CASE WHEN $11::text = 'created_at' AND NOT $12::boolean THEN created_at END,
+  CASE WHEN $11::text = 'clicks' AND $12::boolean THEN COALESCE(access_count, 0) END DESC,
+  CASE WHEN $11::text = 'clicks' AND NOT $12::boolean THEN COALESCE(access_count, 0) END,
+  CASE WHEN $11::text = 'expires_at' AND $12::boolean THEN COALESCE(expires_at, 'infinity') END DESC,
+  CASE WHEN $11::text = 'expires_at' AND NOT $12::boolean THEN COALESCE(expires_at, 'infinity') END,
+  CASE WHEN $12::boolean THEN id END DESC,
+  id
+LIMIT
+  $15::integer
+`
+
+type ListShortUrlsParams struct {
+	WorkspaceID   pgtype.UUID        `json:"workspace_id"`
+	UserID        uuid.UUID          `json:"user_id"`
+	CampaignID    pgtype.UUID        `json:"campaign_id"`
+	FolderID      pgtype.UUID        `json:"folder_id"`
+	TagID         pgtype.UUID        `json:"tag_id"`
+	Status        pgtype.Text        `json:"status"`
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+	Search        pgtype.Text        `json:"search"`
+	CursorID      pgtype.UUID        `json:"cursor_id"`
+	SortBy        string             `json:"sort_by"`
+	Descending    bool               `json:"descending"`
+	CursorTime    pgtype.Timestamptz `json:"cursor_time"`
+	CursorClicks  pgtype.Int4        `json:"cursor_clicks"`
+	PageSize      int32              `json:"page_size"`
+}
+
+func (q *Queries) ListShortUrls(ctx context.Context, arg ListShortUrlsParams) ([]ShortUrl, error) {
+	rows, err := q.db.Query(ctx, listShortUrls,
+		arg.WorkspaceID,
+		arg.UserID,
+		arg.CampaignID,
+		arg.FolderID,
+		arg.TagID,
+		arg.Status,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Search,
+		arg.CursorID,
+		arg.SortBy,
+		arg.Descending,
+		arg.CursorTime,
+		arg.CursorClicks,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShortUrl
+	for rows.Next() {
+		var i ShortUrl
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Slug,
+			&i.OriginalUrl,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.AccessCount,
+			&i.WorkspaceID,
+			&i.DeletedAt,
+			&i.PasswordHash,
+			&i.MaxClicks,
+			&i.StartsAt,
+			&i.InactiveFallback,
+			&i.InactiveUrl,
+			&i.StickyVariants,
+			&i.RedirectStatus,
+			&i.ForwardQuery,
+			&i.QueryMerge,
+			&i.CampaignID,
+			&i.ScanCount,
+			&i.Interstitial,
+			&i.FolderID,
+			&i.DomainID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShortUrlsByDestinationHost = `-- name: ListShortUrlsByDestinationHost :many
+SELECT
+  id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
+FROM
+  short_urls
+WHERE
+  deleted_at IS NULL
+  AND (
+    (
+      $1::uuid IS NULL
+      AND user_id = $2::uuid
+      AND workspace_id IS NULL
+    )
+    OR workspace_id = $1::uuid
+  )
+  AND lower(substring(original_url FROM '^[^:]+://([^/?#:]+)')) = $3::text
+  AND (
+    starts_at IS NULL
+    OR starts_at <= NOW()
+  )
+  AND (
+    expires_at IS NULL
+    OR expires_at > NOW()
+  )
+  AND (
+    max_clicks IS NULL
+    OR COALESCE(access_count, 0) < max_clicks
+  )
+ORDER BY
+  created_at,
+  id
+`
+
+type ListShortUrlsByDestinationHostParams struct {
+	WorkspaceID pgtype.UUID `json:"workspace_id"`
+	UserID      uuid.UUID   `json:"user_id"`
+	Host        string      `json:"host"`
+}
+
+func (q *Queries) ListShortUrlsByDestinationHost(ctx context.Context, arg ListShortUrlsByDestinationHostParams) ([]ShortUrl, error) {
+	rows, err := q.db.Query(ctx, listShortUrlsByDestinationHost, arg.WorkspaceID, arg.UserID, arg.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShortUrl
+	for rows.Next() {
+		var i ShortUrl
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Slug,
+			&i.OriginalUrl,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.AccessCount,
+			&i.WorkspaceID,
+			&i.DeletedAt,
+			&i.PasswordHash,
+			&i.MaxClicks,
+			&i.StartsAt,
+			&i.InactiveFallback,
+			&i.InactiveUrl,
+			&i.StickyVariants,
+			&i.RedirectStatus,
+			&i.ForwardQuery,
+			&i.QueryMerge,
+			&i.CampaignID,
+			&i.ScanCount,
+			&i.Interstitial,
+			&i.FolderID,
+			&i.DomainID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShortUrlsByIds = `-- name: ListShortUrlsByIds :many
+SELECT
+  id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
+FROM
+  short_urls
+WHERE
+  id = ANY($1::uuid [])
+`
+
+func (q *Queries) ListShortUrlsByIds(ctx context.Context, ids []uuid.UUID) ([]ShortUrl, error) {
+	rows, err := q.db.Query(ctx, listShortUrlsByIds, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShortUrl
+	for rows.Next() {
+		var i ShortUrl
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Slug,
+			&i.OriginalUrl,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.AccessCount,
+			&i.WorkspaceID,
+			&i.DeletedAt,
+			&i.PasswordHash,
+			&i.MaxClicks,
+			&i.StartsAt,
+			&i.InactiveFallback,
+			&i.InactiveUrl,
+			&i.StickyVariants,
+			&i.RedirectStatus,
+			&i.ForwardQuery,
+			&i.QueryMerge,
+			&i.CampaignID,
+			&i.ScanCount,
+			&i.Interstitial,
+			&i.FolderID,
+			&i.DomainID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserShortUrls = `-- name: ListUserShortUrls :many
+SELECT
+  id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
+FROM
+  short_urls
+WHERE
+  user_id = $1
+  AND deleted_at IS NULL
+  AND id > $2
+ORDER BY
+  id
+LIMIT
+  $3
+`
+
+type ListUserShortUrlsParams struct {
+	UserID pgtype.UUID `json:"user_id"`
+	ID     uuid.UUID   `json:"id"`
+	Limit  int32       `json:"limit"`
+}
+
+func (q *Queries) ListUserShortUrls(ctx context.Context, arg ListUserShortUrlsParams) ([]ShortUrl, error) {
+	rows, err := q.db.Query(ctx, listUserShortUrls, arg.UserID, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShortUrl
+	for rows.Next() {
+		var i ShortUrl
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Slug,
+			&i.OriginalUrl,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.AccessCount,
+			&i.WorkspaceID,
+			&i.DeletedAt,
+			&i.PasswordHash,
+			&i.MaxClicks,
+			&i.StartsAt,
+			&i.InactiveFallback,
+			&i.InactiveUrl,
+			&i.StickyVariants,
+			&i.RedirectStatus,
+			&i.ForwardQuery,
+			&i.QueryMerge,
+			&i.CampaignID,
+			&i.ScanCount,
+			&i.Interstitial,
+			&i.FolderID,
+			&i.DomainID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const transferShortUrl = `-- name: TransferShortUrl :one
+UPDATE
+  short_urls
+SET
+  user_id = $2,
+  workspace_id = $3,
+  folder_id = CASE
+    WHEN user_id = $2 THEN folder_id
+  END,
+  campaign_id = CASE
+    WHEN user_id = $2 THEN campaign_id
+  END
+WHERE
+  id = $1 RETURNING id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
+`
+
+type TransferShortUrlParams struct {
+	ID          uuid.UUID   `json:"id"`
+	UserID      pgtype.UUID `json:"user_id"`
+	WorkspaceID pgtype.UUID `json:"workspace_id"`
+}
+
+func (q *Queries) TransferShortUrl(ctx context.Context, arg TransferShortUrlParams) (ShortUrl, error) {
+	row := q.db.QueryRow(ctx, transferShortUrl, arg.ID, arg.UserID, arg.WorkspaceID)
+	var i ShortUrl
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Slug,
+		&i.OriginalUrl,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.AccessCount,
+		&i.WorkspaceID,
+		&i.DeletedAt,
+		&i.PasswordHash,
+		&i.MaxClicks,
+		&i.StartsAt,
+		&i.InactiveFallback,
+		&i.InactiveUrl,
+		&i.StickyVariants,
+		&i.RedirectStatus,
+		&i.ForwardQuery,
+		&i.QueryMerge,
+		&i.CampaignID,
+		&i.ScanCount,
+		&i.Interstitial,
+		&i.FolderID,
+		&i.DomainID,
+	)
+	return i, err
+}
+
 const updateShortUrl = `-- name: UpdateShortUrl :one
 UPDATE
   short_urls
 SET
   slug = $2,
   original_url = $3,
-  expires_at = $4
+  expires_at = $4,
+  password_hash = $5,
+  max_clicks = $6,
+  starts_at = $7,
+  inactive_fallback = $8,
+  inactive_url = $9,
+  sticky_variants = $10,
+  redirect_status = $11,
+  forward_query = $12,
+  query_merge = $13,
+  campaign_id = $14,
+  interstitial = $15,
+  folder_id = $16
 WHERE
-  id = $1 RETURNING id,
-  slug,
-  original_url,
-  expires_at,
-  created_at
+  id = $1 RETURNING id, user_id, slug, original_url, created_at, expires_at, access_count, workspace_id, deleted_at, password_hash, max_clicks, starts_at, inactive_fallback, inactive_url, sticky_variants, redirect_status, forward_query, query_merge, campaign_id, scan_count, interstitial, folder_id, domain_id
 `
 
 type UpdateShortUrlParams struct {
-	ID          uuid.UUID          `json:"id"`
-	Slug        string             `json:"slug"`
-	OriginalUrl string             `json:"original_url"`
-	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
-}
-
-type UpdateShortUrlRow struct {
-	ID          uuid.UUID          `json:"id"`
-	Slug        string             `json:"slug"`
-	OriginalUrl string             `json:"original_url"`
-	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	ID               uuid.UUID          `json:"id"`
+	Slug             string             `json:"slug"`
+	OriginalUrl      string             `json:"original_url"`
+	ExpiresAt        pgtype.Timestamptz `json:"expires_at"`
+	PasswordHash     pgtype.Text        `json:"password_hash"`
+	MaxClicks        pgtype.Int4        `json:"max_clicks"`
+	StartsAt         pgtype.Timestamptz `json:"starts_at"`
+	InactiveFallback string             `json:"inactive_fallback"`
+	InactiveUrl      pgtype.Text        `json:"inactive_url"`
+	StickyVariants   bool               `json:"sticky_variants"`
+	RedirectStatus   int32              `json:"redirect_status"`
+	ForwardQuery     bool               `json:"forward_query"`
+	QueryMerge       string             `json:"query_merge"`
+	CampaignID       pgtype.UUID        `json:"campaign_id"`
+	Interstitial     bool               `json:"interstitial"`
+	FolderID         pgtype.UUID        `json:"folder_id"`
 }
 
-func (q *Queries) UpdateShortUrl(ctx context.Context, arg UpdateShortUrlParams) (UpdateShortUrlRow, error) {
+func (q *Queries) UpdateShortUrl(ctx context.Context, arg UpdateShortUrlParams) (ShortUrl, error) {
 	row := q.db.QueryRow(ctx, updateShortUrl,
 		arg.ID,
 		arg.Slug,
 		arg.OriginalUrl,
 		arg.ExpiresAt,
+		arg.PasswordHash,
+		arg.MaxClicks,
+		arg.StartsAt,
+		arg.InactiveFallback,
+		arg.InactiveUrl,
+		arg.StickyVariants,
+		arg.RedirectStatus,
+		arg.ForwardQuery,
+		arg.QueryMerge,
+		arg.CampaignID,
+		arg.Interstitial,
+		arg.FolderID,
 	)
-	var i UpdateShortUrlRow
+	var i ShortUrl
 	err := row.Scan(
 		&i.ID,
+		&i.UserID,
 		&i.Slug,
 		&i.OriginalUrl,
-		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.AccessCount,
+		&i.WorkspaceID,
+		&i.DeletedAt,
+		&i.PasswordHash,
+		&i.MaxClicks,
+		&i.StartsAt,
+		&i.InactiveFallback,
+		&i.InactiveUrl,
+		&i.StickyVariants,
+		&i.RedirectStatus,
+		&i.ForwardQuery,
+		&i.QueryMerge,
+		&i.CampaignID,
+		&i.ScanCount,
+		&i.Interstitial,
+		&i.FolderID,
+		&i.DomainID,
 	)
 	return i, err
 }
@@ -338,12 +1171,17 @@ UPDATE
   users
 SET
   NAME = $2,
-  email = $3
+  email = $3,
+  email_verified_at = CASE
+    WHEN email = $3 THEN email_verified_at
+    ELSE NULL
+  END
 WHERE
   id = $1 RETURNING id,
   NAME,
   email,
-  created_at
+  created_at,
+  email_verified_at
 `
 
 type UpdateUserParams struct {
@@ -352,14 +1190,23 @@ type UpdateUserParams struct {
 	Email string    `json:"email"`
 }
 
-func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+type UpdateUserRow struct {
+	ID              uuid.UUID          `json:"id"`
+	Name            string             `json:"name"`
+	Email           string             `json:"email"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	EmailVerifiedAt pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
 	row := q.db.QueryRow(ctx, updateUser, arg.ID, arg.Name, arg.Email)
-	var i User
+	var i UpdateUserRow
 	err := row.Scan(
 		&i.ID,
 		&i.Name,
 		&i.Email,
 		&i.CreatedAt,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,55 @@
+-- name: CreateAuditLog :exec
+INSERT INTO
+  audit_logs (
+    actor_id,
+    entity_type,
+    entity_id,
+    action,
+    changes,
+    request_id,
+    ip_address
+  )
+VALUES
+  ($1, $2, $3, $4, $5, $6, $7);
+-- name: CreateAuditLogs :copyfrom
+INSERT INTO
+  audit_logs (
+    actor_id,
+    entity_type,
+    entity_id,
+    action,
+    changes,
+    request_id,
+    ip_address
+  )
+VALUES
+  ($1, $2, $3, $4, $5, $6, $7);
+-- name: ListAuditLogsByEntity :many
+SELECT
+  *
+FROM
+  audit_logs
+WHERE
+  entity_type = $1
+  AND entity_id = $2
+ORDER BY
+  created_at DESC,
+  id DESC
+LIMIT
+  $3 OFFSET $4;
+-- name: ListAuditLogsByUser :many
+SELECT
+  *
+FROM
+  audit_logs
+WHERE
+  actor_id = $1
+  OR (
+    entity_type = 'user'
+    AND entity_id = $1
+  )
+ORDER BY
+  created_at DESC,
+  id DESC
+LIMIT
+  $2 OFFSET $3;
\ No newline at end of file
@@ -0,0 +1,110 @@
+-- name: ClaimBulkJob :one
+UPDATE
+  bulk_jobs
+SET
+  status = 'running',
+  started_at = COALESCE(started_at, NOW()),
+  locked_until = sqlc.arg(locked_until)::timestamptz
+WHERE
+  id = (
+    SELECT
+      id
+    FROM
+      bulk_jobs
+    WHERE
+      status IN ('pending', 'running')
+      AND (
+        locked_until IS NULL
+        OR locked_until < NOW()
+      )
+    ORDER BY
+      created_at
+    LIMIT
+      1 FOR UPDATE SKIP LOCKED
+  ) RETURNING *;
+-- name: CreateBulkJob :one
+INSERT INTO
+  bulk_jobs (user_id, total_rows, processed_rows, failed_rows)
+VALUES
+  ($1, $2, $3, $4) RETURNING *;
+-- name: CreateBulkJobRows :copyfrom
+INSERT INTO
+  bulk_job_rows (job_id, row_number, input, errors)
+VALUES
+  ($1, $2, $3, $4);
+-- name: FinishBulkJob :exec
+UPDATE
+  bulk_jobs
+SET
+  status = $2,
+  error = $3,
+  locked_until = NULL,
+  finished_at = NOW()
+WHERE
+  id = $1;
+-- name: GetBulkJob :one
+SELECT
+  *
+FROM
+  bulk_jobs
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: ListBulkJobResults :many
+SELECT
+  row_number,
+  COALESCE(input ->> 'original_url', '')::text AS original_url,
+  short_url_id,
+  slug,
+  errors
+FROM
+  bulk_job_rows
+WHERE
+  job_id = $1
+  AND row_number > $2
+ORDER BY
+  row_number
+LIMIT
+  $3;
+-- name: ListPendingBulkJobRows :many
+SELECT
+  row_number,
+  input
+FROM
+  bulk_job_rows
+WHERE
+  job_id = $1
+  AND short_url_id IS NULL
+  AND errors IS NULL
+ORDER BY
+  row_number
+LIMIT
+  $2;
+-- name: SaveBulkJobRows :exec
+UPDATE
+  bulk_job_rows r
+SET
+  input = r.input - 'password',
+  short_url_id = NULLIF(u.short_url_id, '00000000-0000-0000-0000-000000000000'),
+  slug = NULLIF(u.slug, ''),
+  errors = NULLIF(u.errors, '')::jsonb
+FROM
+  unnest(
+    sqlc.arg(row_numbers)::integer [],
+    sqlc.arg(short_url_ids)::uuid [],
+    sqlc.arg(slugs)::text [],
+    sqlc.arg(errors)::text []
+  ) AS u(row_number, short_url_id, slug, errors)
+WHERE
+  r.job_id = sqlc.arg(job_id)
+  AND r.row_number = u.row_number;
+-- name: UpdateBulkJobProgress :exec
+UPDATE
+  bulk_jobs
+SET
+  processed_rows = processed_rows + $2,
+  created_rows = created_rows + $3,
+  failed_rows = failed_rows + $4,
+  locked_until = $5
+WHERE
+  id = $1;
\ No newline at end of file
@@ -0,0 +1,62 @@
+-- name: CreateCampaign :one
+INSERT INTO
+  campaigns (
+    user_id,
+    name,
+    utm_source,
+    utm_medium,
+    utm_campaign,
+    utm_term,
+    utm_content
+  )
+VALUES
+  ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+-- name: DeleteCampaign :execrows
+DELETE FROM
+  campaigns
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: GetCampaign :one
+SELECT
+  *
+FROM
+  campaigns
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: ListCampaignStats :many
+SELECT
+  s.campaign_id,
+  COUNT(*) AS link_count,
+  COALESCE(SUM(s.access_count), 0)::bigint AS clicks
+FROM
+  short_urls s
+  JOIN campaigns c ON c.id = s.campaign_id
+WHERE
+  c.user_id = $1
+  AND s.deleted_at IS NULL
+GROUP BY
+  s.campaign_id;
+-- name: ListCampaigns :many
+SELECT
+  *
+FROM
+  campaigns
+WHERE
+  user_id = $1
+ORDER BY
+  name;
+-- name: UpdateCampaign :one
+UPDATE
+  campaigns
+SET
+  name = $3,
+  utm_source = $4,
+  utm_medium = $5,
+  utm_campaign = $6,
+  utm_term = $7,
+  utm_content = $8
+WHERE
+  id = $1
+  AND user_id = $2 RETURNING *;
\ No newline at end of file
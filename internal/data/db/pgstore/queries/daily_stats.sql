@@ -0,0 +1,22 @@
+-- name: ListUserDailyStats :many
+SELECT
+  d.short_url_id,
+  s.slug,
+  d.day,
+  d.clicks,
+  d.scans
+FROM
+  short_url_daily_stats d
+  JOIN short_urls s ON s.id = d.short_url_id
+WHERE
+  s.user_id = sqlc.arg(user_id)
+  AND s.deleted_at IS NULL
+  AND (
+    sqlc.narg(after_id)::uuid IS NULL
+    OR (d.short_url_id, d.day) > (sqlc.narg(after_id)::uuid, sqlc.narg(after_day)::date)
+  )
+ORDER BY
+  d.short_url_id,
+  d.day
+LIMIT
+  sqlc.arg(page_size)::integer;
\ No newline at end of file
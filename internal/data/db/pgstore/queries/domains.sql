@@ -0,0 +1,87 @@
+-- name: CountDomainShortUrls :one
+SELECT
+  COUNT(*)
+FROM
+  short_urls
+WHERE
+  domain_id = $1
+  AND deleted_at IS NULL;
+-- name: CountUserDomainWorkspaceShortUrls :one
+SELECT
+  COUNT(*)
+FROM
+  short_urls s
+  JOIN domains d ON d.id = s.domain_id
+WHERE
+  d.user_id = $1
+  AND s.workspace_id IS NOT NULL;
+-- name: CreateDomain :one
+INSERT INTO
+  domains (user_id, host, verification_token, not_found_url)
+VALUES
+  ($1, $2, $3, $4) RETURNING *;
+-- name: DeleteDomain :execrows
+DELETE FROM
+  domains
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: DeleteTrashedDomainShortUrls :exec
+DELETE FROM
+  short_urls
+WHERE
+  domain_id = $1
+  AND deleted_at IS NOT NULL;
+-- name: GetDomain :one
+SELECT
+  *
+FROM
+  domains
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: GetDomainById :one
+SELECT
+  *
+FROM
+  domains
+WHERE
+  id = $1;
+-- name: GetVerifiedDomainByHost :one
+SELECT
+  *
+FROM
+  domains
+WHERE
+  host = $1
+  AND verified_at IS NOT NULL;
+-- name: ListDomainsByUserId :many
+SELECT
+  *
+FROM
+  domains
+WHERE
+  user_id = $1
+ORDER BY
+  created_at;
+-- name: ListVerifiedDomainHosts :many
+SELECT
+  host
+FROM
+  domains
+WHERE
+  verified_at IS NOT NULL;
+-- name: UpdateDomainNotFoundUrl :one
+UPDATE
+  domains
+SET
+  not_found_url = $2
+WHERE
+  id = $1 RETURNING *;
+-- name: VerifyDomain :one
+UPDATE
+  domains
+SET
+  verified_at = COALESCE(verified_at, NOW())
+WHERE
+  id = $1 RETURNING *;
\ No newline at end of file
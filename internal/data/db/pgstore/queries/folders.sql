@@ -0,0 +1,90 @@
+-- name: CreateFolder :one
+INSERT INTO
+  folders (user_id, parent_id, name)
+VALUES
+  ($1, $2, $3) RETURNING *;
+-- name: DeleteFolder :execrows
+DELETE FROM
+  folders
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: GetFolder :one
+SELECT
+  *
+FROM
+  folders
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: ListFolderStats :many
+SELECT
+  s.folder_id,
+  COUNT(*) AS link_count
+FROM
+  short_urls s
+  JOIN folders f ON f.id = s.folder_id
+WHERE
+  f.user_id = $1
+  AND s.deleted_at IS NULL
+GROUP BY
+  s.folder_id;
+-- name: ListFolderStatsInScope :many
+SELECT
+  s.folder_id,
+  COUNT(*) AS link_count
+FROM
+  short_urls s
+  JOIN folders f ON f.id = s.folder_id
+WHERE
+  f.user_id = sqlc.arg(user_id)
+  AND s.deleted_at IS NULL
+  AND (
+    (
+      sqlc.narg(workspace_id)::uuid IS NULL
+      AND s.user_id = sqlc.arg(user_id)
+      AND s.workspace_id IS NULL
+    )
+    OR s.workspace_id = sqlc.narg(workspace_id)::uuid
+  )
+GROUP BY
+  s.folder_id;
+-- name: ListFolders :many
+SELECT
+  *
+FROM
+  folders
+WHERE
+  user_id = $1
+ORDER BY
+  name;
+-- name: MoveFolderChildren :exec
+UPDATE
+  folders
+SET
+  parent_id = sqlc.narg(target_id)
+WHERE
+  parent_id = sqlc.arg(folder_id);
+-- name: MoveFolderLinks :exec
+UPDATE
+  short_urls
+SET
+  folder_id = sqlc.narg(target_id)
+WHERE
+  folder_id = sqlc.arg(folder_id);
+-- name: SetShortUrlsFolder :execrows
+UPDATE
+  short_urls
+SET
+  folder_id = sqlc.narg(folder_id)
+WHERE
+  id = ANY(sqlc.arg(short_url_ids)::uuid []);
+-- name: UpdateFolder :one
+UPDATE
+  folders
+SET
+  name = $3,
+  parent_id = $4
+WHERE
+  id = $1
+  AND user_id = $2 RETURNING *;
\ No newline at end of file
@@ -2,97 +2,442 @@
 SELECT
   *
 FROM
-  users;
+  users
+WHERE
+  deleted_at IS NULL
+  AND (
+    sqlc.narg(cursor_id)::uuid IS NULL
+    OR (
+      sqlc.arg(descending)::boolean
+      AND (created_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::uuid)
+    )
+    OR (
+      NOT sqlc.arg(descending)::boolean
+      AND (created_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::uuid)
+    )
+  )
+ORDER BY
+  CASE WHEN sqlc.arg(descending)::boolean THEN created_at END DESC,
+  CASE WHEN sqlc.arg(descending)::boolean THEN id END DESC,
+  created_at,
+  id
+LIMIT
+  sqlc.arg(page_size)::integer;
 -- name: GetUser :one
 SELECT
   *
 FROM
   users
 WHERE
-  id = $1;
+  id = $1
+  AND deleted_at IS NULL;
 -- name: GetUserByEmail :one
 SELECT
   *
 FROM
   users
 WHERE
-  email = $1;
+  email = $1
+  AND deleted_at IS NULL;
 -- name: CreateUser :one
 INSERT INTO
-  users (NAME, email)
+  users (NAME, email, password_hash)
 VALUES
-  ($1, $2) RETURNING id,
+  ($1, $2, $3) RETURNING id,
   NAME,
   email,
-  created_at;
+  created_at,
+  email_verified_at;
 -- name: UpdateUser :one
 UPDATE
   users
 SET
   NAME = $2,
-  email = $3
+  email = $3,
+  email_verified_at = CASE
+    WHEN email = $3 THEN email_verified_at
+    ELSE NULL
+  END
 WHERE
   id = $1 RETURNING id,
   NAME,
   email,
-  created_at;
--- name: DeleteUser :exec
-DELETE FROM
-  users
-WHERE
-  id = $1;
+  created_at,
+  email_verified_at;
 -- name: GetShortUrlBySlug :one
 SELECT
   *
 FROM
   short_urls
 WHERE
-  slug = $1;
+  slug = sqlc.arg(slug)
+  AND (
+    (
+      sqlc.narg(domain_id)::uuid IS NULL
+      AND domain_id IS NULL
+    )
+    OR domain_id = sqlc.narg(domain_id)::uuid
+  );
 -- name: GetShortUrlsByUserId :many
 SELECT
   *
 FROM
   short_urls
 WHERE
-  user_id = $1;
+  user_id = $1
+  AND workspace_id IS NULL
+  AND deleted_at IS NULL;
+-- name: GetShortUrlsByWorkspaceId :many
+SELECT
+  *
+FROM
+  short_urls
+WHERE
+  workspace_id = $1
+  AND deleted_at IS NULL;
+-- name: ListShortUrls :many
+SELECT
+  *
+FROM
+  short_urls
+WHERE
+  deleted_at IS NULL
+  AND (
+    (
+      sqlc.narg(workspace_id)::uuid IS NULL
+      AND user_id = sqlc.arg(user_id)::uuid
+      AND workspace_id IS NULL
+    )
+    OR workspace_id = sqlc.narg(workspace_id)::uuid
+  )
+  AND (
+    sqlc.narg(campaign_id)::uuid IS NULL
+    OR campaign_id = sqlc.narg(campaign_id)::uuid
+  )
+  AND (
+    sqlc.narg(folder_id)::uuid IS NULL
+    OR folder_id = sqlc.narg(folder_id)::uuid
+  )
+  AND (
+    sqlc.narg(tag_id)::uuid IS NULL
+    OR EXISTS (
+      SELECT
+        1
+      FROM
+        short_url_tags st
+      WHERE
+        st.short_url_id = short_urls.id
+        AND st.tag_id = sqlc.narg(tag_id)::uuid
+    )
+  )
+  AND (
+    sqlc.narg(status)::text IS NULL
+    OR (
+      sqlc.narg(status)::text = 'active'
+      AND (
+        starts_at IS NULL
+        OR starts_at <= NOW()
+      )
+      AND (
+        expires_at IS NULL
+        OR expires_at > NOW()
+      )
+      AND (
+        max_clicks IS NULL
+        OR COALESCE(access_count, 0) < max_clicks
+      )
+    )
+    OR (
+      sqlc.narg(status)::text = 'expired'
+      AND (
+        expires_at <= NOW()
+        OR COALESCE(access_count, 0) >= max_clicks
+      )
+    )
+    OR (
+      sqlc.narg(status)::text = 'scheduled'
+      AND starts_at > NOW()
+    )
+  )
+  AND (
+    sqlc.narg(created_after)::timestamptz IS NULL
+    OR created_at >= sqlc.narg(created_after)::timestamptz
+  )
+  AND (
+    sqlc.narg(created_before)::timestamptz IS NULL
+    OR created_at < sqlc.narg(created_before)::timestamptz
+  )
+  AND (
+    sqlc.narg(search)::text IS NULL
+    OR slug ILIKE sqlc.narg(search)::text
+    OR original_url ILIKE sqlc.narg(search)::text
+    OR EXISTS (
+      SELECT
+        1
+      FROM
+        short_url_metadata m
+      WHERE
+        m.short_url_id = short_urls.id
+        AND m.title ILIKE sqlc.narg(search)::text
+    )
+  )
+  AND (
+    sqlc.narg(cursor_id)::uuid IS NULL
+    OR (
+      sqlc.arg(sort_by)::text = 'created_at'
+      AND sqlc.arg(descending)::boolean
+      AND (created_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::uuid)
+    )
+    OR (
+      sqlc.arg(sort_by)::text = 'created_at'
+      AND NOT sqlc.arg(descending)::boolean
+      AND (created_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::uuid)
+    )
+    OR (
+      sqlc.arg(sort_by)::text = 'clicks'
+      AND sqlc.arg(descending)::boolean
+      AND (COALESCE(access_count, 0), id) < (sqlc.narg(cursor_clicks)::integer, sqlc.narg(cursor_id)::uuid)
+    )
+    OR (
+      sqlc.arg(sort_by)::text = 'clicks'
+      AND NOT sqlc.arg(descending)::boolean
+      AND (COALESCE(access_count, 0), id) > (sqlc.narg(cursor_clicks)::integer, sqlc.narg(cursor_id)::uuid)
+    )
+    OR (
+      sqlc.arg(sort_by)::text = 'expires_at'
+      AND sqlc.arg(descending)::boolean
+      AND (COALESCE(expires_at, 'infinity'), id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::uuid)
+    )
+    OR (
+      sqlc.arg(sort_by)::text = 'expires_at'
+      AND NOT sqlc.arg(descending)::boolean
+      AND (COALESCE(expires_at, 'infinity'), id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.narg(cursor_id)::uuid)
+    )
+  )
+ORDER BY
+  CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND sqlc.arg(descending)::boolean THEN created_at END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'created_at' AND NOT sqlc.arg(descending)::boolean THEN created_at END,
+  CASE WHEN sqlc.arg(sort_by)::text = 'clicks' AND sqlc.arg(descending)::boolean THEN COALESCE(access_count, 0) END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'clicks' AND NOT sqlc.arg(descending)::boolean THEN COALESCE(access_count, 0) END,
+  CASE WHEN sqlc.arg(sort_by)::text = 'expires_at' AND sqlc.arg(descending)::boolean THEN COALESCE(expires_at, 'infinity') END DESC,
+  CASE WHEN sqlc.arg(sort_by)::text = 'expires_at' AND NOT sqlc.arg(descending)::boolean THEN COALESCE(expires_at, 'infinity') END,
+  CASE WHEN sqlc.arg(descending)::boolean THEN id END DESC,
+  id
+LIMIT
+  sqlc.arg(page_size)::integer;
+-- name: ListShortUrlsByIds :many
+SELECT
+  *
+FROM
+  short_urls
+WHERE
+  id = ANY(sqlc.arg(ids)::uuid []);
+-- name: ListUserShortUrls :many
+SELECT
+  *
+FROM
+  short_urls
+WHERE
+  user_id = $1
+  AND deleted_at IS NULL
+  AND id > $2
+ORDER BY
+  id
+LIMIT
+  $3;
+-- name: ListShortUrlsByDestinationHost :many
+SELECT
+  *
+FROM
+  short_urls
+WHERE
+  deleted_at IS NULL
+  AND (
+    (
+      sqlc.narg(workspace_id)::uuid IS NULL
+      AND user_id = sqlc.arg(user_id)::uuid
+      AND workspace_id IS NULL
+    )
+    OR workspace_id = sqlc.narg(workspace_id)::uuid
+  )
+  AND lower(substring(original_url FROM '^[^:]+://([^/?#:]+)')) = sqlc.arg(host)::text
+  AND (
+    starts_at IS NULL
+    OR starts_at <= NOW()
+  )
+  AND (
+    expires_at IS NULL
+    OR expires_at > NOW()
+  )
+  AND (
+    max_clicks IS NULL
+    OR COALESCE(access_count, 0) < max_clicks
+  )
+ORDER BY
+  created_at,
+  id;
+-- name: ListExistingSlugs :many
+SELECT
+  slug
+FROM
+  short_urls
+WHERE
+  slug = ANY(sqlc.arg(slugs)::text [])
+  AND domain_id IS NULL;
 -- name: GetShortUrlById :one
 SELECT
   *
 FROM
   short_urls
 WHERE
-  id = $1;
+  id = $1
+  AND deleted_at IS NULL;
 -- name: CreateShortUrl :one
 INSERT INTO
-  short_urls (user_id, slug, original_url, expires_at)
+  short_urls (
+    user_id,
+    workspace_id,
+    slug,
+    original_url,
+    expires_at,
+    password_hash,
+    max_clicks,
+    starts_at,
+    inactive_fallback,
+    inactive_url,
+    sticky_variants,
+    redirect_status,
+    forward_query,
+    query_merge,
+    campaign_id,
+    interstitial,
+    folder_id,
+    domain_id
+  )
 VALUES
-  ($1, $2, $3, $4) RETURNING id,
-  slug,
-  original_url,
-  expires_at,
-  created_at;
+  ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18) RETURNING *;
+-- name: CreateShortUrls :copyfrom
+INSERT INTO
+  short_urls (
+    id,
+    user_id,
+    workspace_id,
+    slug,
+    original_url,
+    expires_at,
+    password_hash,
+    max_clicks,
+    starts_at,
+    inactive_fallback,
+    inactive_url,
+    sticky_variants,
+    redirect_status,
+    forward_query,
+    query_merge,
+    campaign_id,
+    interstitial,
+    folder_id
+  )
+VALUES
+  ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18);
+-- name: ImportShortUrls :copyfrom
+INSERT INTO
+  short_urls (
+    id,
+    user_id,
+    slug,
+    original_url,
+    created_at,
+    access_count
+  )
+VALUES
+  ($1, $2, $3, $4, $5, $6);
 -- name: UpdateShortUrl :one
 UPDATE
   short_urls
 SET
   slug = $2,
   original_url = $3,
-  expires_at = $4
+  expires_at = $4,
+  password_hash = $5,
+  max_clicks = $6,
+  starts_at = $7,
+  inactive_fallback = $8,
+  inactive_url = $9,
+  sticky_variants = $10,
+  redirect_status = $11,
+  forward_query = $12,
+  query_merge = $13,
+  campaign_id = $14,
+  interstitial = $15,
+  folder_id = $16
 WHERE
-  id = $1 RETURNING id,
-  slug,
-  original_url,
-  expires_at,
-  created_at;
--- name: IncrementAccessCount :exec
+  id = $1 RETURNING *;
+-- name: TransferShortUrl :one
 UPDATE
   short_urls
 SET
-  access_count = access_count + $2
+  user_id = $2,
+  workspace_id = $3,
+  folder_id = CASE
+    WHEN user_id = $2 THEN folder_id
+  END,
+  campaign_id = CASE
+    WHEN user_id = $2 THEN campaign_id
+  END
 WHERE
-  slug = $1;
--- name: DeleteShortUrl :exec
-DELETE FROM
-  short_urls
-WHERE
-  id = $1;
\ No newline at end of file
+  id = $1 RETURNING *;
+-- name: IncrementAccessCount :exec
+WITH updated AS (
+  UPDATE
+    short_urls
+  SET
+    access_count = access_count + sqlc.arg(access_count)
+  WHERE
+    slug = sqlc.arg(slug)
+    AND (
+      (
+        sqlc.narg(domain_id)::uuid IS NULL
+        AND domain_id IS NULL
+      )
+      OR domain_id = sqlc.narg(domain_id)::uuid
+    ) RETURNING id
+)
+INSERT INTO
+  short_url_daily_stats (short_url_id, day, clicks)
+SELECT
+  id,
+  (NOW() AT TIME ZONE 'UTC')::date,
+  sqlc.arg(access_count)
+FROM
+  updated ON CONFLICT (short_url_id, day) DO
+UPDATE
+SET
+  clicks = short_url_daily_stats.clicks + EXCLUDED.clicks;
+-- name: IncrementScanCount :exec
+WITH updated AS (
+  UPDATE
+    short_urls
+  SET
+    scan_count = scan_count + sqlc.arg(scan_count)
+  WHERE
+    slug = sqlc.arg(slug)
+    AND (
+      (
+        sqlc.narg(domain_id)::uuid IS NULL
+        AND domain_id IS NULL
+      )
+      OR domain_id = sqlc.narg(domain_id)::uuid
+    ) RETURNING id
+)
+INSERT INTO
+  short_url_daily_stats (short_url_id, day, scans)
+SELECT
+  id,
+  (NOW() AT TIME ZONE 'UTC')::date,
+  sqlc.arg(scan_count)
+FROM
+  updated ON CONFLICT (short_url_id, day) DO
+UPDATE
+SET
+  scans = short_url_daily_stats.scans + EXCLUDED.scans;
\ No newline at end of file
@@ -0,0 +1,53 @@
+-- name: CountRedirectRules :one
+SELECT
+  COUNT(*)
+FROM
+  redirect_rules
+WHERE
+  short_url_id = $1;
+-- name: CreateRedirectRule :one
+INSERT INTO
+  redirect_rules (short_url_id, position, conditions, target_url)
+VALUES
+  ($1, $2, $3, $4) RETURNING *;
+-- name: DeleteRedirectRule :execrows
+DELETE FROM
+  redirect_rules
+WHERE
+  id = $1
+  AND short_url_id = $2;
+-- name: GetRedirectRule :one
+SELECT
+  *
+FROM
+  redirect_rules
+WHERE
+  id = $1
+  AND short_url_id = $2;
+-- name: ListRedirectRules :many
+SELECT
+  *
+FROM
+  redirect_rules
+WHERE
+  short_url_id = $1
+ORDER BY
+  position,
+  created_at;
+-- name: NextRedirectRulePosition :one
+SELECT
+  (COALESCE(MAX(position), -1) + 1)::int
+FROM
+  redirect_rules
+WHERE
+  short_url_id = $1;
+-- name: UpdateRedirectRule :one
+UPDATE
+  redirect_rules
+SET
+  position = $3,
+  conditions = $4,
+  target_url = $5
+WHERE
+  id = $1
+  AND short_url_id = $2 RETURNING *;
\ No newline at end of file
@@ -0,0 +1,95 @@
+-- name: DeleteShortUrlHealth :exec
+DELETE FROM
+  short_url_health
+WHERE
+  short_url_id = $1;
+-- name: ListDueHealthChecks :many
+SELECT
+  s.id,
+  s.user_id,
+  s.slug,
+  s.original_url,
+  h.notified_at
+FROM
+  short_urls s
+  LEFT JOIN short_url_health h ON h.short_url_id = s.id
+WHERE
+  s.deleted_at IS NULL
+  AND (
+    s.starts_at IS NULL
+    OR s.starts_at <= NOW()
+  )
+  AND (
+    s.expires_at IS NULL
+    OR s.expires_at > NOW()
+  )
+  AND (
+    s.max_clicks IS NULL
+    OR s.access_count < s.max_clicks
+  )
+  AND (
+    h.next_check_at IS NULL
+    OR h.next_check_at <= NOW()
+  )
+ORDER BY
+  h.next_check_at NULLS FIRST,
+  s.created_at DESC
+LIMIT
+  $1;
+-- name: ListShortUrlHealth :many
+SELECT
+  *
+FROM
+  short_url_health
+WHERE
+  short_url_id = ANY(sqlc.arg(short_url_ids)::uuid []);
+-- name: MarkShortUrlHealthNotified :exec
+UPDATE
+  short_url_health
+SET
+  notified_at = NOW()
+WHERE
+  short_url_id = $1;
+-- name: SaveShortUrlHealth :one
+INSERT INTO
+  short_url_health (
+    short_url_id,
+    url,
+    healthy,
+    status_code,
+    final_url,
+    latency_ms,
+    last_error,
+    consecutive_failures,
+    last_success_at,
+    next_check_at
+  )
+SELECT
+  s.id,
+  s.original_url,
+  sqlc.arg(healthy)::boolean,
+  sqlc.narg(status_code)::integer,
+  sqlc.narg(final_url)::text,
+  sqlc.narg(latency_ms)::integer,
+  sqlc.narg(last_error)::text,
+  CASE WHEN sqlc.arg(healthy)::boolean THEN 0 ELSE 1 END,
+  CASE WHEN sqlc.arg(healthy)::boolean THEN NOW() END,
+  sqlc.arg(next_check_at)::timestamptz
+FROM
+  short_urls s
+WHERE
+  s.id = sqlc.arg(short_url_id)
+  AND s.original_url = sqlc.arg(url) ON CONFLICT (short_url_id) DO
+UPDATE
+SET
+  url = EXCLUDED.url,
+  healthy = EXCLUDED.healthy,
+  status_code = EXCLUDED.status_code,
+  final_url = EXCLUDED.final_url,
+  latency_ms = EXCLUDED.latency_ms,
+  last_error = EXCLUDED.last_error,
+  consecutive_failures = CASE WHEN EXCLUDED.healthy THEN 0 ELSE short_url_health.consecutive_failures + 1 END,
+  checked_at = NOW(),
+  last_success_at = COALESCE(EXCLUDED.last_success_at, short_url_health.last_success_at),
+  next_check_at = EXCLUDED.next_check_at,
+  notified_at = CASE WHEN EXCLUDED.healthy THEN NULL ELSE short_url_health.notified_at END RETURNING *;
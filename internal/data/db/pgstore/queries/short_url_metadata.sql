@@ -0,0 +1,75 @@
+-- name: FailShortUrlMetadata :exec
+UPDATE
+  short_url_metadata
+SET
+  status = $3,
+  attempts = attempts + 1,
+  last_error = $4,
+  next_attempt_at = $5
+WHERE
+  short_url_id = $1
+  AND url = $2;
+-- name: ListPendingShortUrlMetadata :many
+SELECT
+  *
+FROM
+  short_url_metadata
+WHERE
+  status = 'pending'
+  AND next_attempt_at <= NOW()
+  AND short_url_id IN (
+    SELECT
+      id
+    FROM
+      short_urls
+    WHERE
+      deleted_at IS NULL
+  )
+ORDER BY
+  next_attempt_at
+LIMIT
+  $1;
+-- name: ListShortUrlMetadata :many
+SELECT
+  *
+FROM
+  short_url_metadata
+WHERE
+  short_url_id = ANY(sqlc.arg(short_url_ids)::uuid []);
+-- name: RequestShortUrlMetadata :exec
+INSERT INTO
+  short_url_metadata (short_url_id, url)
+VALUES
+  ($1, $2) ON CONFLICT (short_url_id) DO
+UPDATE
+SET
+  url = EXCLUDED.url,
+  status = 'pending',
+  title = NULL,
+  description = NULL,
+  image_url = NULL,
+  favicon_url = NULL,
+  attempts = 0,
+  last_error = NULL,
+  next_attempt_at = NOW(),
+  fetched_at = NULL;
+-- name: RequestShortUrlsMetadata :copyfrom
+INSERT INTO
+  short_url_metadata (short_url_id, url)
+VALUES
+  ($1, $2);
+-- name: SaveShortUrlMetadata :exec
+UPDATE
+  short_url_metadata
+SET
+  status = 'fetched',
+  title = $3,
+  description = $4,
+  image_url = $5,
+  favicon_url = $6,
+  attempts = attempts + 1,
+  last_error = NULL,
+  fetched_at = NOW()
+WHERE
+  short_url_id = $1
+  AND url = $2;
\ No newline at end of file
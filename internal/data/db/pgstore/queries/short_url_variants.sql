@@ -0,0 +1,34 @@
+-- name: CreateShortUrlVariant :one
+INSERT INTO
+  short_url_variants (short_url_id, url, weight)
+VALUES
+  ($1, $2, $3) RETURNING *;
+-- name: DeleteShortUrlVariant :exec
+DELETE FROM
+  short_url_variants
+WHERE
+  id = $1;
+-- name: IncrementVariantAccessCount :exec
+UPDATE
+  short_url_variants
+SET
+  access_count = access_count + $2
+WHERE
+  id = $1;
+-- name: ListShortUrlVariants :many
+SELECT
+  *
+FROM
+  short_url_variants
+WHERE
+  short_url_id = ANY(sqlc.arg(short_url_ids)::uuid [])
+ORDER BY
+  created_at,
+  id;
+-- name: UpdateShortUrlVariantWeight :exec
+UPDATE
+  short_url_variants
+SET
+  weight = $2
+WHERE
+  id = $1;
\ No newline at end of file
@@ -0,0 +1,152 @@
+-- name: CreateInitialShortUrlVersion :exec
+INSERT INTO
+  short_url_versions (
+    short_url_id,
+    version,
+    slug,
+    original_url,
+    expires_at,
+    password_hash,
+    max_clicks,
+    starts_at,
+    inactive_fallback,
+    inactive_url,
+    sticky_variants,
+    redirect_status,
+    forward_query,
+    query_merge,
+    campaign_id,
+    interstitial,
+    folder_id,
+    created_at
+  )
+SELECT
+  s.id,
+  1,
+  s.slug,
+  s.original_url,
+  s.expires_at,
+  s.password_hash,
+  s.max_clicks,
+  s.starts_at,
+  s.inactive_fallback,
+  s.inactive_url,
+  s.sticky_variants,
+  s.redirect_status,
+  s.forward_query,
+  s.query_merge,
+  s.campaign_id,
+  s.interstitial,
+  s.folder_id,
+  COALESCE(s.created_at, NOW())
+FROM
+  short_urls s
+WHERE
+  s.id = $1
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      short_url_versions v
+    WHERE
+      v.short_url_id = s.id
+  ) ON CONFLICT DO NOTHING;
+-- name: CreateShortUrlVersion :one
+INSERT INTO
+  short_url_versions (
+    short_url_id,
+    version,
+    slug,
+    original_url,
+    expires_at,
+    password_hash,
+    max_clicks,
+    starts_at,
+    inactive_fallback,
+    inactive_url,
+    sticky_variants,
+    redirect_status,
+    forward_query,
+    query_merge,
+    campaign_id,
+    interstitial,
+    folder_id,
+    author_id,
+    restored_from
+  )
+SELECT
+  s.id,
+  (
+    SELECT
+      COALESCE(MAX(v.version), 0) + 1
+    FROM
+      short_url_versions v
+    WHERE
+      v.short_url_id = s.id
+  ),
+  s.slug,
+  s.original_url,
+  s.expires_at,
+  s.password_hash,
+  s.max_clicks,
+  s.starts_at,
+  s.inactive_fallback,
+  s.inactive_url,
+  s.sticky_variants,
+  s.redirect_status,
+  s.forward_query,
+  s.query_merge,
+  s.campaign_id,
+  s.interstitial,
+  s.folder_id,
+  sqlc.narg(author_id)::uuid,
+  sqlc.narg(restored_from)::integer
+FROM
+  short_urls s
+WHERE
+  s.id = sqlc.arg(short_url_id) RETURNING *;
+-- name: GetShortUrlVersion :one
+SELECT
+  *
+FROM
+  short_url_versions
+WHERE
+  short_url_id = $1
+  AND version = $2;
+-- name: ListShortUrlVersions :many
+SELECT
+  v.*,
+  u.name AS author_name,
+  u.email AS author_email
+FROM
+  short_url_versions v
+  LEFT JOIN users u ON u.id = v.author_id
+WHERE
+  v.short_url_id = $1
+ORDER BY
+  v.version DESC;
+-- name: RestoreShortUrlVersion :one
+UPDATE
+  short_urls s
+SET
+  slug = sqlc.arg(slug),
+  original_url = v.original_url,
+  expires_at = v.expires_at,
+  password_hash = v.password_hash,
+  max_clicks = v.max_clicks,
+  starts_at = v.starts_at,
+  inactive_fallback = v.inactive_fallback,
+  inactive_url = v.inactive_url,
+  sticky_variants = v.sticky_variants,
+  redirect_status = v.redirect_status,
+  forward_query = v.forward_query,
+  query_merge = v.query_merge,
+  campaign_id = sqlc.narg(campaign_id),
+  interstitial = v.interstitial,
+  folder_id = sqlc.narg(folder_id)
+FROM
+  short_url_versions v
+WHERE
+  s.id = sqlc.arg(id)
+  AND v.short_url_id = s.id
+  AND v.version = sqlc.arg(version) RETURNING s.*;
\ No newline at end of file
@@ -0,0 +1,79 @@
+-- name: SoftDeleteUser :execrows
+UPDATE
+  users
+SET
+  deleted_at = $2
+WHERE
+  id = $1
+  AND deleted_at IS NULL;
+-- name: GetDeletedUserByEmail :one
+SELECT
+  *
+FROM
+  users
+WHERE
+  email = $1
+  AND deleted_at IS NOT NULL
+ORDER BY
+  deleted_at DESC
+LIMIT
+  1;
+-- name: RestoreUser :exec
+UPDATE
+  users
+SET
+  deleted_at = NULL
+WHERE
+  id = $1;
+-- name: SoftDeleteShortUrl :execrows
+UPDATE
+  short_urls
+SET
+  deleted_at = NOW()
+WHERE
+  id = $1
+  AND deleted_at IS NULL;
+-- name: SoftDeletePersonalShortUrls :many
+UPDATE
+  short_urls
+SET
+  deleted_at = $2
+WHERE
+  user_id = $1
+  AND workspace_id IS NULL
+  AND deleted_at IS NULL RETURNING slug,
+  domain_id;
+-- name: GetDeletedShortUrlById :one
+SELECT
+  *
+FROM
+  short_urls
+WHERE
+  id = $1
+  AND deleted_at IS NOT NULL;
+-- name: RestoreShortUrl :one
+UPDATE
+  short_urls
+SET
+  deleted_at = NULL
+WHERE
+  id = $1 RETURNING *;
+-- name: RestorePersonalShortUrls :exec
+UPDATE
+  short_urls
+SET
+  deleted_at = NULL
+WHERE
+  user_id = $1
+  AND workspace_id IS NULL
+  AND deleted_at = $2;
+-- name: PurgeDeletedShortUrls :execrows
+DELETE FROM
+  short_urls
+WHERE
+  deleted_at < $1;
+-- name: PurgeDeletedUsers :execrows
+DELETE FROM
+  users
+WHERE
+  deleted_at < $1;
\ No newline at end of file
@@ -0,0 +1,125 @@
+-- name: AddShortUrlTags :exec
+INSERT INTO
+  short_url_tags (short_url_id, tag_id)
+SELECT
+  unnest(sqlc.arg(short_url_ids)::uuid []),
+  sqlc.arg(tag_id)::uuid ON CONFLICT DO NOTHING;
+-- name: ClearShortUrlTags :exec
+DELETE FROM
+  short_url_tags
+WHERE
+  short_url_id = $1;
+-- name: CreateTag :one
+INSERT INTO
+  tags (user_id, name)
+VALUES
+  ($1, $2) RETURNING *;
+-- name: DeleteShortUrlTags :exec
+DELETE FROM
+  short_url_tags st USING tags t
+WHERE
+  t.id = st.tag_id
+  AND st.short_url_id = $1
+  AND t.user_id = $2;
+-- name: DeleteTag :execrows
+DELETE FROM
+  tags
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: GetTag :one
+SELECT
+  *
+FROM
+  tags
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: ListShortUrlTags :many
+SELECT
+  st.short_url_id,
+  t.id,
+  t.name
+FROM
+  short_url_tags st
+  JOIN tags t ON t.id = st.tag_id
+WHERE
+  st.short_url_id = ANY(sqlc.arg(short_url_ids)::uuid [])
+ORDER BY
+  t.name;
+-- name: ListTagStats :many
+SELECT
+  st.tag_id,
+  COUNT(*) AS link_count
+FROM
+  short_url_tags st
+  JOIN tags t ON t.id = st.tag_id
+  JOIN short_urls s ON s.id = st.short_url_id
+WHERE
+  t.user_id = $1
+  AND s.deleted_at IS NULL
+GROUP BY
+  st.tag_id;
+-- name: ListTagStatsInScope :many
+SELECT
+  st.tag_id,
+  COUNT(*) AS link_count
+FROM
+  short_url_tags st
+  JOIN tags t ON t.id = st.tag_id
+  JOIN short_urls s ON s.id = st.short_url_id
+WHERE
+  t.user_id = sqlc.arg(user_id)
+  AND s.deleted_at IS NULL
+  AND (
+    (
+      sqlc.narg(workspace_id)::uuid IS NULL
+      AND s.user_id = sqlc.arg(user_id)
+      AND s.workspace_id IS NULL
+    )
+    OR s.workspace_id = sqlc.narg(workspace_id)::uuid
+  )
+GROUP BY
+  st.tag_id;
+-- name: ListTags :many
+SELECT
+  *
+FROM
+  tags
+WHERE
+  user_id = $1
+ORDER BY
+  name;
+-- name: MoveShortUrlTags :exec
+INSERT INTO
+  short_url_tags (short_url_id, tag_id)
+SELECT
+  short_url_id,
+  sqlc.arg(target_id)::uuid
+FROM
+  short_url_tags
+WHERE
+  tag_id = sqlc.arg(tag_id) ON CONFLICT DO NOTHING;
+-- name: RemoveShortUrlTags :execrows
+DELETE FROM
+  short_url_tags
+WHERE
+  tag_id = sqlc.arg(tag_id)
+  AND short_url_id = ANY(sqlc.arg(short_url_ids)::uuid []);
+-- name: UpdateTag :one
+UPDATE
+  tags
+SET
+  name = $3
+WHERE
+  id = $1
+  AND user_id = $2 RETURNING *;
+-- name: UpsertTagsByName :many
+INSERT INTO
+  tags (user_id, name)
+SELECT
+  sqlc.arg(user_id)::uuid,
+  unnest(sqlc.arg(names)::text []) ON CONFLICT (user_id, name) DO
+UPDATE
+SET
+  name = EXCLUDED.name RETURNING *;
\ No newline at end of file
@@ -0,0 +1,42 @@
+-- name: SetUserTotpSecret :exec
+UPDATE
+  users
+SET
+  totp_secret = $2,
+  totp_enabled = FALSE
+WHERE
+  id = $1;
+-- name: EnableUserTotp :exec
+UPDATE
+  users
+SET
+  totp_enabled = TRUE
+WHERE
+  id = $1;
+-- name: DisableUserTotp :exec
+UPDATE
+  users
+SET
+  totp_secret = NULL,
+  totp_enabled = FALSE
+WHERE
+  id = $1;
+-- name: CreateRecoveryCode :exec
+INSERT INTO
+  user_recovery_codes (user_id, code_hash)
+VALUES
+  ($1, $2);
+-- name: DeleteRecoveryCodesByUserId :exec
+DELETE FROM
+  user_recovery_codes
+WHERE
+  user_id = $1;
+-- name: UseRecoveryCode :execrows
+UPDATE
+  user_recovery_codes
+SET
+  used_at = NOW()
+WHERE
+  user_id = $1
+  AND code_hash = $2
+  AND used_at IS NULL;
\ No newline at end of file
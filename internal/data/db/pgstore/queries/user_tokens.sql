@@ -0,0 +1,40 @@
+-- name: CreateUserToken :one
+INSERT INTO
+  user_tokens (user_id, purpose, email, expires_at)
+VALUES
+  ($1, $2, $3, $4) RETURNING *;
+-- name: ConsumeUserToken :one
+UPDATE
+  user_tokens
+SET
+  used_at = NOW()
+WHERE
+  id = $1
+  AND purpose = $2
+  AND used_at IS NULL
+  AND expires_at > NOW() RETURNING user_id,
+  email;
+-- name: InvalidateUserTokens :exec
+UPDATE
+  user_tokens
+SET
+  used_at = NOW()
+WHERE
+  user_id = $1
+  AND purpose = $2
+  AND used_at IS NULL;
+-- name: MarkUserEmailVerified :execrows
+UPDATE
+  users
+SET
+  email_verified_at = NOW()
+WHERE
+  id = $1
+  AND email = $2;
+-- name: UpdateUserPassword :exec
+UPDATE
+  users
+SET
+  password_hash = $2
+WHERE
+  id = $1;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- name: CreateWebhook :one
+INSERT INTO
+  webhooks (user_id, url, secret)
+VALUES
+  ($1, $2, $3) RETURNING *;
+-- name: DeleteWebhook :execrows
+DELETE FROM
+  webhooks
+WHERE
+  id = $1
+  AND user_id = $2;
+-- name: ListWebhooksByUserId :many
+SELECT
+  *
+FROM
+  webhooks
+WHERE
+  user_id = $1
+ORDER BY
+  created_at;
@@ -0,0 +1,132 @@
+-- name: CreateWorkspace :one
+INSERT INTO
+  workspaces (NAME)
+VALUES
+  ($1) RETURNING *;
+-- name: GetWorkspaceById :one
+SELECT
+  *
+FROM
+  workspaces
+WHERE
+  id = $1;
+-- name: UpdateWorkspace :one
+UPDATE
+  workspaces
+SET
+  NAME = $2
+WHERE
+  id = $1 RETURNING *;
+-- name: DeleteWorkspace :exec
+DELETE FROM
+  workspaces
+WHERE
+  id = $1;
+-- name: ListWorkspacesByUserId :many
+SELECT
+  w.id,
+  w.name,
+  w.created_at,
+  m.role
+FROM
+  workspaces w
+  JOIN workspace_members m ON m.workspace_id = w.id
+WHERE
+  m.user_id = $1
+ORDER BY
+  w.created_at;
+-- name: AddWorkspaceMember :exec
+INSERT INTO
+  workspace_members (workspace_id, user_id, role)
+VALUES
+  ($1, $2, $3) ON CONFLICT (workspace_id, user_id) DO NOTHING;
+-- name: GetWorkspaceMemberRole :one
+SELECT
+  m.role
+FROM
+  workspace_members m
+  JOIN users u ON u.id = m.user_id
+WHERE
+  m.workspace_id = $1
+  AND m.user_id = $2
+  AND u.deleted_at IS NULL;
+-- name: ListWorkspaceMembers :many
+SELECT
+  m.user_id,
+  u.name,
+  u.email,
+  m.role,
+  m.created_at
+FROM
+  workspace_members m
+  JOIN users u ON u.id = m.user_id
+WHERE
+  m.workspace_id = $1
+  AND u.deleted_at IS NULL
+ORDER BY
+  m.created_at;
+-- name: UpdateWorkspaceMemberRole :execrows
+UPDATE
+  workspace_members
+SET
+  role = $3
+WHERE
+  workspace_id = $1
+  AND user_id = $2;
+-- name: RemoveWorkspaceMember :execrows
+DELETE FROM
+  workspace_members
+WHERE
+  workspace_id = $1
+  AND user_id = $2;
+-- name: CountWorkspaceOwners :one
+SELECT
+  COUNT(*)
+FROM
+  workspace_members m
+  JOIN users u ON u.id = m.user_id
+WHERE
+  m.workspace_id = $1
+  AND m.role = 'owner'
+  AND u.deleted_at IS NULL;
+-- name: CountSoleOwnedWorkspaces :one
+SELECT
+  COUNT(*)
+FROM
+  workspace_members m
+WHERE
+  m.user_id = $1
+  AND m.role = 'owner'
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      workspace_members o
+      JOIN users u ON u.id = o.user_id
+    WHERE
+      o.workspace_id = m.workspace_id
+      AND o.role = 'owner'
+      AND o.user_id <> m.user_id
+      AND u.deleted_at IS NULL
+  );
+-- name: CreateWorkspaceInvitation :one
+INSERT INTO
+  workspace_invitations (workspace_id, email, role, invited_by, expires_at)
+VALUES
+  ($1, $2, $3, $4, $5) RETURNING *;
+-- name: GetWorkspaceInvitation :one
+SELECT
+  *
+FROM
+  workspace_invitations
+WHERE
+  id = $1;
+-- name: AcceptWorkspaceInvitation :execrows
+UPDATE
+  workspace_invitations
+SET
+  accepted_at = NOW()
+WHERE
+  id = $1
+  AND accepted_at IS NULL
+  AND expires_at > NOW();
\ No newline at end of file
@@ -3,6 +3,7 @@ package infra
 import (
 	"context"
 	"log/slog"
+	"strconv"
 	"time"
 
 	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
@@ -10,11 +11,53 @@ import (
 )
 
 const (
-	accessKeyPrefix = "access:"
-	defaultReties   = 3
-	retryDelay      = 100 * time.Millisecond
+	accessKeyPrefix        = "access:"
+	variantAccessKeyPrefix = "variant_access:" // Cliques pendentes por variante, chave variant_access:<id>
+	clicksKeyPrefix        = "clicks:"         // Hash com limite (max) e cliques usados (used) de links limitados
+	scanAccessKeyPrefix    = "scan_access:"    // Leituras de QR code pendentes por link, chave scan_access:<slug>
+	defaultReties          = 3
+	retryDelay             = 100 * time.Millisecond
+	clicksTTL              = 30 * 24 * time.Hour // Precisa durar mais que o TTL do URLCache
 )
 
+// registerClickScript conta um redirecionamento de forma atômica. Para links
+// com limite de cliques o hash clicks:<slug> é inicializado na primeira vez com
+// o limite e os cliques já contados (banco + pendentes em access:<slug>), já
+// com TTL para não ficar sem expirar quando o link nasce esgotado, e o
+// clique só é contado enquanto houver saldo, assim acessos concorrentes não
+// ultrapassam o limite.
+// KEYS[1] = access:<slug>, KEYS[2] = clicks:<slug>
+// ARGV[1] = limite ("" quando desconhecido), ARGV[2] = cliques salvos no banco, ARGV[3] = TTL em segundos
+// Retorna os cliques restantes, -1 quando o limite foi atingido e -2 para links sem limite
+var registerClickScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	if ARGV[1] == '' then
+		redis.call('INCR', KEYS[1])
+		return -2
+	end
+	local pending = tonumber(redis.call('GET', KEYS[1]) or '0')
+	redis.call('HSET', KEYS[2], 'max', ARGV[1], 'used', tonumber(ARGV[2]) + pending)
+	redis.call('EXPIRE', KEYS[2], ARGV[3])
+end
+
+local max = tonumber(redis.call('HGET', KEYS[2], 'max'))
+local used = tonumber(redis.call('HGET', KEYS[2], 'used'))
+if used >= max then
+	return -1
+end
+
+redis.call('HINCRBY', KEYS[2], 'used', 1)
+redis.call('EXPIRE', KEYS[2], ARGV[3])
+redis.call('INCR', KEYS[1])
+return max - used - 1
+`)
+
+// ClickLimit descreve o limite de cliques de um link carregado do banco
+type ClickLimit struct {
+	MaxClicks int
+	Used      int
+}
+
 type AccessCounter struct {
 	client *redis.Client
 	logger *slog.Logger
@@ -59,7 +102,115 @@ func (ac *AccessCounter) IncrementAccess(ctx context.Context, slug string) (int6
 	return 0, wraperrors.InternalErr("Failed to incremente access counter", err)
 }
 
+// RegisterClick substitui IncrementAccess no redirecionamento, aplicando o
+// limite de cliques. limit é nil quando o link veio do cache, nesse caso vale o
+// limite já registrado no Redis. Retorna erro 410 quando o limite foi atingido
+func (ac *AccessCounter) RegisterClick(ctx context.Context, slug string, limit *ClickLimit) error {
+	if slug == "" {
+		return wraperrors.ValidationErr("Slug cannot be empty")
+	}
+
+	maxClicks, used := "", 0
+	if limit != nil {
+		maxClicks, used = strconv.Itoa(limit.MaxClicks), limit.Used
+	}
+
+	keys := []string{accessKeyPrefix + slug, clicksKeyPrefix + slug}
+	remaining, err := registerClickScript.Run(ctx, ac.client, keys, maxClicks, used, int(clicksTTL.Seconds())).Int()
+	if err != nil {
+		ac.logger.Error("failed to register click", "slug", slug, "error", err)
+		return wraperrors.InternalErr("Failed to register click", err)
+	}
+
+	if remaining == -1 {
+		return wraperrors.GoneErr("Short URL has reached its click limit")
+	}
+
+	return nil
+}
+
+// PendingAccess retorna os cliques do link ainda não sincronizados com o banco
+func (ac *AccessCounter) PendingAccess(ctx context.Context, slug string) int {
+	count, err := ac.client.Get(ctx, accessKeyPrefix+slug).Int()
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// RemainingClicks retorna o saldo atual de cliques de um link limitado, ok é
+// falso quando o Redis ainda não tem o limite do link
+func (ac *AccessCounter) RemainingClicks(ctx context.Context, slug string) (int, bool) {
+	values, err := ac.client.HMGet(ctx, clicksKeyPrefix+slug, "max", "used").Result()
+	if err != nil || values[0] == nil || values[1] == nil {
+		return 0, false
+	}
+
+	maxClicks, err1 := strconv.Atoi(values[0].(string))
+	used, err2 := strconv.Atoi(values[1].(string))
+	if err1 != nil || err2 != nil {
+		return 0, false
+	}
+
+	return max(maxClicks-used, 0), true
+}
+
+// ResetClickLimit descarta o limite registrado, usado quando o link é alterado
+// para que o próximo clique leia o novo limite do banco
+func (ac *AccessCounter) ResetClickLimit(ctx context.Context, slug string) error {
+	if err := ac.client.Del(ctx, clicksKeyPrefix+slug).Err(); err != nil {
+		ac.logger.Error("failed to reset click limit", "slug", slug, "error", err)
+		return wraperrors.InternalErr("Failed to reset click limit", err)
+	}
+
+	return nil
+}
+
+// IncrementVariantAccess conta um redirecionamento para uma variante, os
+// contadores são sincronizados com o banco junto com os dos links
+func (ac *AccessCounter) IncrementVariantAccess(ctx context.Context, variantId string) error {
+	if variantId == "" {
+		return wraperrors.ValidationErr("Variant ID cannot be empty")
+	}
+
+	if err := ac.client.Incr(ctx, variantAccessKeyPrefix+variantId).Err(); err != nil {
+		ac.logger.Warn("failed to increment variant access count", "variant_id", variantId, "error", err)
+		return wraperrors.InternalErr("Failed to increment variant access counter", err)
+	}
+
+	return nil
+}
+
+// IncrementScanAccess conta um redirecionamento vindo de um QR code, ele já
+// foi contado como clique e aqui é separado apenas para as estatísticas
+func (ac *AccessCounter) IncrementScanAccess(ctx context.Context, slug string) error {
+	if slug == "" {
+		return wraperrors.ValidationErr("Slug cannot be empty")
+	}
+
+	if err := ac.client.Incr(ctx, scanAccessKeyPrefix+slug).Err(); err != nil {
+		ac.logger.Warn("failed to increment scan count", "slug", slug, "error", err)
+		return wraperrors.InternalErr("Failed to increment scan counter", err)
+	}
+
+	return nil
+}
+
 func (ac *AccessCounter) GetAllAccessKeys(ctx context.Context) ([]string, error) {
+	return ac.scanKeys(ctx, accessKeyPrefix)
+}
+
+// GetAllVariantAccessKeys retorna as chaves dos contadores de variantes
+func (ac *AccessCounter) GetAllVariantAccessKeys(ctx context.Context) ([]string, error) {
+	return ac.scanKeys(ctx, variantAccessKeyPrefix)
+}
+
+// GetAllScanAccessKeys retorna as chaves dos contadores de leituras de QR code
+func (ac *AccessCounter) GetAllScanAccessKeys(ctx context.Context) ([]string, error) {
+	return ac.scanKeys(ctx, scanAccessKeyPrefix)
+}
+
+func (ac *AccessCounter) scanKeys(ctx context.Context, prefix string) ([]string, error) {
 	var keys []string
 	var cursor uint64
 
@@ -67,9 +218,9 @@ func (ac *AccessCounter) GetAllAccessKeys(ctx context.Context) ([]string, error)
 		var scanKeys []string
 		var err error
 
-		scanKeys, cursor, err = ac.client.Scan(ctx, cursor, accessKeyPrefix+"*", 0).Result()
+		scanKeys, cursor, err = ac.client.Scan(ctx, cursor, prefix+"*", 0).Result()
 		if err != nil {
-			ac.logger.Error("failed to scan Redis for access keys", "error", err)
+			ac.logger.Error("failed to scan Redis for access keys", "prefix", prefix, "error", err)
 			return nil, wraperrors.InternalErr("Failed to retrieve access keys", err)
 		}
 
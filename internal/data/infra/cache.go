@@ -3,6 +3,7 @@ package infra
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"time"
 
@@ -17,6 +18,9 @@ const (
 	urlPrefix  = "url:"          // Prefixo para as chaves de URL no Redis
 	defaultTTL = 24 * time.Hour  // TTL padrão para URLs sem data de expiração
 	minTTL     = 5 * time.Minute // TTL mínimo para evitar expiração imediata
+
+	domainPrefix = "domain:"       // Prefixo para os domínios personalizados por host
+	domainTTL    = 5 * time.Minute // TTL dos domínios, inclusive dos hosts que não são um
 )
 
 // URLCache encapsula funcionalidades de cache para URLs
@@ -33,60 +37,81 @@ func NewURLCache(client *redis.Client) *URLCache {
 	}
 }
 
-// LogRecentAccess armazena a URL original em cache e atualiza a lista de acessos recentes
+// LogRecentAccess armazena em cache o redirecionamento do link (URL original,
+// regras e variantes) pela chave do link (ver models.LinkKey) e atualiza a
+// lista de acessos recentes
 // Esta função executa assincronamente e não bloqueia o chamador
-func (c *URLCache) LogRecentAccess(ctx context.Context, shortUrl *models.ShortUrl) error {
-	if shortUrl == nil {
+func (c *URLCache) LogRecentAccess(ctx context.Context, shortUrl *models.ShortUrl, redirect *models.CachedRedirect) error {
+	if shortUrl == nil || redirect == nil {
 		return wraperrors.ValidationErr("short url cannot be nil")
 	}
 
+	// Links protegidos por senha nunca são cacheados, o redirecionamento pelo
+	// cache pularia a verificação da senha
+	if shortUrl.HasPassword {
+		return nil
+	}
+
+	key := shortUrl.Key()
 	go func() {
-		c.logger.Info("log recent access started", "slug", shortUrl.Slug)
+		c.logger.Info("log recent access started", "key", key)
 
 		// Usar contexto Background para operação em goroutine separada
 		timeoutCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		urlKey := urlPrefix + shortUrl.Slug
+		urlKey := urlPrefix + key
 
 		// Determinar TTL com base na data de expiração
 		ttl := c.calculateTTL(shortUrl)
 		if ttl <= 0 {
-			c.logger.Debug("url already expired, not caching", "slug", shortUrl.Slug)
+			c.logger.Debug("url expired or not active yet, not caching", "key", key)
 			return
 		}
 
-		// Armazenar URL em cache
-		if _, err := c.client.Set(timeoutCtx, urlKey, shortUrl.OriginalUrl, ttl).Result(); err != nil {
-			c.logger.Error("failed to save url in cache", "error", err, "slug", shortUrl.Slug)
+		// Armazenar URL, regras e variantes em cache
+		value, err := json.Marshal(redirect)
+		if err != nil {
+			c.logger.Error("failed to encode cached redirect", "error", err, "key", key)
+			return
+		}
+		if _, err := c.client.Set(timeoutCtx, urlKey, value, ttl).Result(); err != nil {
+			c.logger.Error("failed to save url in cache", "error", err, "key", key)
 			return
 		}
 
-		c.logger.Info("url cached successfully", "slug", shortUrl.Slug, "ttl", ttl)
+		c.logger.Info("url cached successfully", "key", key, "ttl", ttl)
 
 		// Atualizar lista de URLs recentes
-		c.updateListURLs(timeoutCtx, shortUrl.Slug)
+		c.updateListURLs(timeoutCtx, key)
 	}()
 
 	return nil
 }
 
-// GetUrl recupera uma URL do cache pelo slug
-func (c *URLCache) GetURL(ctx context.Context, slug string) (string, error) {
+// GetRedirect recupera do cache a URL original, as regras e as variantes de um
+// link pela sua chave
+func (c *URLCache) GetRedirect(ctx context.Context, slug string) (*models.CachedRedirect, error) {
 	if slug == "" {
-		return "", wraperrors.ValidationErr("slug cannot be empty")
+		return nil, wraperrors.ValidationErr("slug cannot be empty")
 	}
 
 	urlKey := urlPrefix + slug
-	url, err := c.client.Get(ctx, urlKey).Result()
+	value, err := c.client.Get(ctx, urlKey).Result()
 	if err != nil {
 		// Melhorar diferenciação entre erros
 		if err == redis.Nil {
-			return "", wraperrors.NotFoundErr("url not found in cache")
+			return nil, wraperrors.NotFoundErr("url not found in cache")
 		}
 
 		c.logger.Error("failed to retrieve url from cache", "slug", slug, "error", err)
-		return "", wraperrors.InternalErr("cache retrieval error", err)
+		return nil, wraperrors.InternalErr("cache retrieval error", err)
+	}
+
+	// Entradas antigas guardam apenas a URL original
+	redirect := &models.CachedRedirect{}
+	if err := json.Unmarshal([]byte(value), redirect); err != nil {
+		redirect = &models.CachedRedirect{OriginalUrl: value}
 	}
 
 	// Atualizar lista de URLs recentes de forma assíncrona
@@ -96,7 +121,26 @@ func (c *URLCache) GetURL(ctx context.Context, slug string) (string, error) {
 		c.updateListURLs(timeoutCtx, slug)
 	}()
 
-	return url, nil
+	return redirect, nil
+}
+
+// Invalidate remove a URL do cache e da lista de recentes, usado quando o link
+// deixa de redirecionar (por exemplo, ao ser excluído)
+func (c *URLCache) Invalidate(ctx context.Context, slug string) error {
+	if slug == "" {
+		return wraperrors.ValidationErr("slug cannot be empty")
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.Del(ctx, urlPrefix+slug)
+	pipe.LRem(ctx, listKey, 0, slug)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Error("failed to invalidate cached url", "slug", slug, "error", err)
+		return wraperrors.InternalErr("failed to invalidate cached url", err)
+	}
+
+	return nil
 }
 
 // updateListURLs atualiza a lista de URLs recentes
@@ -127,6 +171,12 @@ func (c *URLCache) updateListURLs(ctx context.Context, slug string) {
 
 // calculateTTL calcula o TTL apropriado para o cache
 func (c *URLCache) calculateTTL(shortUrl *models.ShortUrl) time.Duration {
+	// Links agendados não são cacheados antes de starts_at, o redirecionamento
+	// pelo cache ignoraria a janela de ativação
+	if shortUrl.StartsAt != nil && time.Now().Before(*shortUrl.StartsAt) {
+		return 0
+	}
+
 	if shortUrl.ExpiresAt != nil && !shortUrl.ExpiresAt.IsZero() {
 		ttl := time.Until(*shortUrl.ExpiresAt)
 
@@ -143,6 +193,55 @@ func (c *URLCache) calculateTTL(shortUrl *models.ShortUrl) time.Duration {
 	return defaultTTL
 }
 
+// GetDomain recupera do cache o domínio personalizado de um host. found indica
+// que o host está no cache, com domain nil quando ele não é um domínio
+// verificado
+func (c *URLCache) GetDomain(ctx context.Context, host string) (domain *models.Domain, found bool) {
+	value, err := c.client.Get(ctx, domainPrefix+host).Result()
+	if err != nil {
+		if err != redis.Nil {
+			c.logger.Error("failed to retrieve domain from cache", "host", host, "error", err)
+		}
+		return nil, false
+	}
+
+	if value == "" {
+		return nil, true
+	}
+	domain = &models.Domain{}
+	if err := json.Unmarshal([]byte(value), domain); err != nil {
+		return nil, false
+	}
+	return domain, true
+}
+
+// SetDomain guarda o domínio personalizado de um host, nil guarda que o host
+// não é um domínio verificado para não consultar o banco a cada acesso
+func (c *URLCache) SetDomain(ctx context.Context, host string, domain *models.Domain) {
+	var value []byte
+	if domain != nil {
+		var err error
+		if value, err = json.Marshal(domain); err != nil {
+			c.logger.Error("failed to encode cached domain", "host", host, "error", err)
+			return
+		}
+	}
+
+	if err := c.client.Set(ctx, domainPrefix+host, value, domainTTL).Err(); err != nil {
+		c.logger.Error("failed to save domain in cache", "host", host, "error", err)
+	}
+}
+
+// InvalidateDomain remove o host do cache, usado quando o domínio é
+// verificado, alterado ou excluído
+func (c *URLCache) InvalidateDomain(ctx context.Context, host string) error {
+	if err := c.client.Del(ctx, domainPrefix+host).Err(); err != nil {
+		c.logger.Error("failed to invalidate cached domain", "host", host, "error", err)
+		return wraperrors.InternalErr("failed to invalidate cached domain", err)
+	}
+	return nil
+}
+
 // GetRecentURLs retorna a lista de slugs de URLs recentes
 func (c *URLCache) GetRecentURLs(ctx context.Context, limit int) ([]string, error) {
 	if limit <= 0 || limit > maxLength {
@@ -0,0 +1,280 @@
+package infra
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+)
+
+const (
+	defaultBlocklistRefresh  = 5 * time.Minute
+	destinationLookupTimeout = 2 * time.Second
+	customDomainsRefresh     = time.Minute
+)
+
+// knownShorteners são encurtadores públicos, encadear um link neles esconde o
+// destino real e foge das verificações
+var knownShorteners = []string{
+	"bit.ly", "bit.do", "buff.ly", "cutt.ly", "goo.gl", "is.gd", "ow.ly",
+	"rb.gy", "rebrand.ly", "s.id", "shorturl.at", "t.co", "t.ly", "tiny.cc",
+	"tinyurl.com", "v.gd",
+}
+
+// DestinationPolicyOptions configura a política de destinos. Os domínios
+// aceitam curingas: "example.com" casa apenas o próprio domínio, "*.example.com"
+// casa os subdomínios e "*" casa qualquer host
+type DestinationPolicyOptions struct {
+	// AllowDomains são destinos confiáveis que dispensam as demais verificações,
+	// combinado com DenyDomains "*" vira uma lista exclusiva
+	AllowDomains []string
+	DenyDomains  []string
+	// ShortenerDomains se somam aos encurtadores conhecidos, subdomínios
+	// também são recusados
+	ShortenerDomains []string
+	// SelfDomains são os domínios deste encurtador, subdomínios também são
+	// recusados
+	SelfDomains      []string
+	BlocklistPath    string
+	BlocklistRefresh time.Duration
+}
+
+// DestinationPolicy decide para onde um link pode redirecionar
+type DestinationPolicy struct {
+	allow      []string
+	deny       []string
+	shorteners []string
+	self       []string
+	blocklist  *HashBlocklist
+	resolver   *net.Resolver
+
+	// customDomains são os domínios personalizados verificados, relidos por
+	// loadCustomDomains no máximo uma vez por customDomainsRefresh
+	customMu          sync.RWMutex
+	loadCustomDomains CustomDomainsLoader
+	customDomains     []string
+	customLoadedAt    time.Time
+}
+
+// CustomDomainsLoader lista os domínios personalizados verificados, que
+// também são do próprio encurtador
+type CustomDomainsLoader func(ctx context.Context) ([]string, error)
+
+// NewDestinationPolicy monta a política a partir das opções
+func NewDestinationPolicy(options DestinationPolicyOptions) *DestinationPolicy {
+	p := &DestinationPolicy{
+		allow:    normalizeDomains(options.AllowDomains),
+		deny:     normalizeDomains(options.DenyDomains),
+		resolver: net.DefaultResolver,
+	}
+	for _, domain := range normalizeDomains(slices.Concat(knownShorteners, options.ShortenerDomains)) {
+		p.shorteners = append(p.shorteners, domain, "*."+domain)
+	}
+	for _, domain := range normalizeDomains(options.SelfDomains) {
+		p.self = append(p.self, domain, "*."+domain)
+	}
+	if options.BlocklistPath != "" {
+		refresh := options.BlocklistRefresh
+		if refresh <= 0 {
+			refresh = defaultBlocklistRefresh
+		}
+		p.blocklist = NewHashBlocklist(options.BlocklistPath, refresh)
+	}
+
+	return p
+}
+
+// NewDestinationPolicyFromEnv lê DESTINATION_ALLOW_DOMAINS,
+// DESTINATION_DENY_DOMAINS e DESTINATION_SHORTENER_DOMAINS, separados por
+// vírgula, DESTINATION_BLOCKLIST_PATH e DESTINATION_BLOCKLIST_REFRESH. O host
+// de APP_BASE_URL é o domínio do próprio encurtador
+func NewDestinationPolicyFromEnv() *DestinationPolicy {
+	options := DestinationPolicyOptions{
+		AllowDomains:     splitList(os.Getenv("DESTINATION_ALLOW_DOMAINS")),
+		DenyDomains:      splitList(os.Getenv("DESTINATION_DENY_DOMAINS")),
+		ShortenerDomains: splitList(os.Getenv("DESTINATION_SHORTENER_DOMAINS")),
+		BlocklistPath:    os.Getenv("DESTINATION_BLOCKLIST_PATH"),
+	}
+	if raw := os.Getenv("DESTINATION_BLOCKLIST_REFRESH"); raw != "" {
+		if refresh, err := time.ParseDuration(raw); err == nil {
+			options.BlocklistRefresh = refresh
+		}
+	}
+	if baseURL, err := url.Parse(os.Getenv("APP_BASE_URL")); err == nil && baseURL.Hostname() != "" {
+		options.SelfDomains = []string{baseURL.Hostname()}
+	}
+
+	return NewDestinationPolicy(options)
+}
+
+// WithCustomDomains faz a política recusar também os domínios personalizados
+// listados por loader, exatamente como escritos, sem subdomínios
+func (p *DestinationPolicy) WithCustomDomains(loader CustomDomainsLoader) *DestinationPolicy {
+	p.customMu.Lock()
+	defer p.customMu.Unlock()
+
+	p.loadCustomDomains = loader
+	p.customDomains = nil
+	p.customLoadedAt = time.Time{}
+	return p
+}
+
+// Check confere a URL na ordem: formato, credenciais, o próprio encurtador,
+// lista de permitidos, lista de bloqueados, outros encurtadores, blocklist de
+// hashes e por fim endereços privados, resolvendo o host quando não é um IP.
+// Uma falha de DNS não recusa o destino, o acesso em si continua protegido
+// pelo cliente HTTP seguro
+func (p *DestinationPolicy) Check(ctx context.Context, rawUrl string) *models.DestinationViolation {
+	u, err := url.ParseRequestURI(rawUrl)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return &models.DestinationViolation{Reason: models.DestinationInvalidUrl}
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	violation := func(reason string) *models.DestinationViolation {
+		return &models.DestinationViolation{Reason: reason, Host: host}
+	}
+
+	if u.User != nil {
+		return violation(models.DestinationCredentials)
+	}
+	if matchesDomain(host, p.self) || p.isCustomDomain(ctx, host) {
+		return violation(models.DestinationSelfReference)
+	}
+	if matchesDomain(host, p.allow) {
+		return nil
+	}
+	if matchesDomain(host, p.deny) {
+		return violation(models.DestinationDeniedDomain)
+	}
+	if matchesDomain(host, p.shorteners) {
+		return violation(models.DestinationChainedShortener)
+	}
+	if p.blocklist.Contains(u) {
+		return violation(models.DestinationBlocklisted)
+	}
+	if p.isPrivateHost(ctx, host) {
+		return violation(models.DestinationPrivateAddress)
+	}
+
+	return nil
+}
+
+// ReloadBlocklist relê a blocklist de hashes sem esperar a próxima conferência
+func (p *DestinationPolicy) ReloadBlocklist() error {
+	if p.blocklist == nil {
+		return nil
+	}
+	return p.blocklist.Reload()
+}
+
+// ReloadCustomDomains relê os domínios personalizados sem esperar o intervalo
+// de atualização, em caso de erro a lista anterior é mantida
+func (p *DestinationPolicy) ReloadCustomDomains(ctx context.Context) error {
+	p.customMu.Lock()
+	defer p.customMu.Unlock()
+
+	return p.reloadCustomDomainsLocked(ctx)
+}
+
+func (p *DestinationPolicy) reloadCustomDomainsLocked(ctx context.Context) error {
+	if p.loadCustomDomains == nil {
+		return nil
+	}
+
+	// a próxima tentativa espera o intervalo mesmo depois de uma falha
+	p.customLoadedAt = time.Now()
+	domains, err := p.loadCustomDomains(ctx)
+	if err != nil {
+		return err
+	}
+	p.customDomains = normalizeDomains(domains)
+	return nil
+}
+
+func (p *DestinationPolicy) isCustomDomain(ctx context.Context, host string) bool {
+	p.customMu.RLock()
+	stale := p.loadCustomDomains != nil && time.Since(p.customLoadedAt) > customDomainsRefresh
+	p.customMu.RUnlock()
+
+	if stale {
+		p.customMu.Lock()
+		if time.Since(p.customLoadedAt) > customDomainsRefresh {
+			// uma falha mantém a lista anterior, o erro não recusa o destino
+			_ = p.reloadCustomDomainsLocked(ctx)
+		}
+		p.customMu.Unlock()
+	}
+
+	p.customMu.RLock()
+	defer p.customMu.RUnlock()
+
+	return slices.Contains(p.customDomains, host)
+}
+
+func (p *DestinationPolicy) isPrivateHost(ctx context.Context, host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return utils.IsPrivateIP(ip)
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, destinationLookupTimeout)
+	defer cancel()
+
+	addrs, err := p.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if utils.IsPrivateIP(addr.IP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesDomain(host string, patterns []string) bool {
+	for _, pattern := range patterns {
+		switch {
+		case pattern == "*":
+			return true
+		case strings.HasPrefix(pattern, "*."):
+			if strings.HasSuffix(host, pattern[1:]) {
+				return true
+			}
+		case host == pattern:
+			return true
+		}
+	}
+
+	return false
+}
+
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain != "" {
+			normalized = append(normalized, domain)
+		}
+	}
+
+	return normalized
+}
+
+func splitList(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	return strings.Split(raw, ",")
+}
@@ -0,0 +1,60 @@
+package infra
+
+import (
+	"log/slog"
+	"net"
+	"os"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// GeoIP resolve o país de um IP usando uma base local no formato MaxMind
+// (GeoLite2-Country ou GeoIP2-Country). Sem base configurada nenhum país é
+// resolvido e as regras por país nunca casam
+type GeoIP struct {
+	reader *geoip2.Reader
+	logger *slog.Logger
+}
+
+// NewGeoIPFromEnv abre a base indicada em GEOIP_DB_PATH
+func NewGeoIPFromEnv() *GeoIP {
+	g := &GeoIP{logger: slog.Default().With("component", "geoip")}
+
+	path := os.Getenv("GEOIP_DB_PATH")
+	if path == "" {
+		return g
+	}
+
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		g.logger.Error("failed to open geoip database, country rules are disabled", "path", path, "error", err)
+		return g
+	}
+	g.reader = reader
+
+	return g
+}
+
+// Country retorna o código ISO 3166-1 alfa-2 do IP ou "" quando desconhecido
+func (g *GeoIP) Country(rawIP string) string {
+	if g == nil || g.reader == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(rawIP)
+	if err != nil {
+		host = rawIP
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+
+	record, err := g.reader.Country(ip)
+	if err != nil {
+		g.logger.Debug("failed to resolve country", "ip", host, "error", err)
+		return ""
+	}
+
+	return record.Country.IsoCode
+}
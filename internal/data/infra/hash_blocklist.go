@@ -0,0 +1,195 @@
+package infra
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	minBlocklistPrefix = 8
+	maxHostSuffixes    = 5
+	maxPathPrefixes    = 4
+)
+
+// HashBlocklist guarda prefixos de SHA-256 de URLs maliciosas no mesmo espírito
+// das listas do Safe Browsing: cada linha do arquivo é um prefixo hexadecimal
+// de 8 a 64 caracteres do hash de uma expressão "host/caminho", linhas vazias
+// e iniciadas por # são ignoradas. O arquivo é relido quando muda, conferido
+// no máximo uma vez por intervalo de atualização
+type HashBlocklist struct {
+	path    string
+	refresh time.Duration
+	logger  *slog.Logger
+
+	mu        sync.RWMutex
+	prefixes  map[int]map[string]struct{}
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewHashBlocklist carrega o arquivo indicado, um arquivo ausente ou inválido
+// resulta em uma lista vazia até a próxima atualização bem sucedida
+func NewHashBlocklist(path string, refresh time.Duration) *HashBlocklist {
+	b := &HashBlocklist{
+		path:     path,
+		refresh:  refresh,
+		logger:   slog.Default().With("component", "hash_blocklist"),
+		prefixes: map[int]map[string]struct{}{},
+	}
+	if err := b.Reload(); err != nil {
+		b.logger.Error("failed to load blocklist", "path", path, "error", err)
+	}
+
+	return b
+}
+
+// Reload relê o arquivo na hora, mantendo a lista anterior em caso de erro
+func (b *HashBlocklist) Reload() error {
+	file, err := os.Open(b.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	prefixes := map[int]map[string]struct{}{}
+	count := 0
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		prefix := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if prefix == "" || strings.HasPrefix(prefix, "#") {
+			continue
+		}
+		if len(prefix) < minBlocklistPrefix || len(prefix) > sha256.Size*2 {
+			return fmt.Errorf("line %d: prefix must have between %d and %d hex characters", line, minBlocklistPrefix, sha256.Size*2)
+		}
+		if _, err := hex.DecodeString(prefix); err != nil {
+			return fmt.Errorf("line %d: invalid hex prefix", line)
+		}
+		if prefixes[len(prefix)] == nil {
+			prefixes[len(prefix)] = map[string]struct{}{}
+		}
+		prefixes[len(prefix)][prefix] = struct{}{}
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.prefixes = prefixes
+	b.modTime = info.ModTime()
+	b.checkedAt = time.Now()
+	b.mu.Unlock()
+
+	b.logger.Info("blocklist loaded", "path", b.path, "prefixes", count)
+	return nil
+}
+
+// Contains informa se alguma expressão da URL tem o hash na lista
+func (b *HashBlocklist) Contains(u *url.URL) bool {
+	if b == nil {
+		return false
+	}
+	b.refreshIfChanged()
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if len(b.prefixes) == 0 {
+		return false
+	}
+
+	for _, expression := range urlExpressions(u) {
+		sum := sha256.Sum256([]byte(expression))
+		digest := hex.EncodeToString(sum[:])
+		for length, prefixes := range b.prefixes {
+			if _, ok := prefixes[digest[:length]]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (b *HashBlocklist) refreshIfChanged() {
+	b.mu.RLock()
+	due := time.Since(b.checkedAt) >= b.refresh
+	modTime := b.modTime
+	b.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	b.mu.Lock()
+	b.checkedAt = time.Now()
+	b.mu.Unlock()
+
+	info, err := os.Stat(b.path)
+	if err != nil || info.ModTime().Equal(modTime) {
+		return
+	}
+	if err := b.Reload(); err != nil {
+		b.logger.Error("failed to refresh blocklist, keeping the previous one", "path", b.path, "error", err)
+	}
+}
+
+// urlExpressions gera as combinações de sufixos do host e prefixos do caminho
+// que são procuradas na lista, do mais específico ao mais genérico, por
+// exemplo "a.b.example.com/1/2.html?x", "a.b.example.com/1/", "example.com/"
+func urlExpressions(u *url.URL) []string {
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return nil
+	}
+
+	hosts := []string{host}
+	if net.ParseIP(host) == nil {
+		labels := strings.Split(host, ".")
+		for i := max(1, len(labels)-maxHostSuffixes); i < len(labels)-1; i++ {
+			hosts = append(hosts, strings.Join(labels[i:], "."))
+		}
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	paths := []string{}
+	if u.RawQuery != "" {
+		paths = append(paths, path+"?"+u.RawQuery)
+	}
+	paths = append(paths, path)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := min(len(segments)-1, maxPathPrefixes-1); i >= 0; i-- {
+		prefix := "/" + strings.Join(segments[:i], "/")
+		if i > 0 {
+			prefix += "/"
+		}
+		if prefix != path {
+			paths = append(paths, prefix)
+		}
+	}
+
+	expressions := make([]string, 0, len(hosts)*len(paths))
+	for _, h := range hosts {
+		for _, p := range paths {
+			expressions = append(expressions, h+p)
+		}
+	}
+
+	return expressions
+}
@@ -0,0 +1,70 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+const (
+	healthCheckTimeout   = 10 * time.Second
+	healthCheckMaxBody   = 64 << 10
+	healthCheckUserAgent = "Mozilla/5.0 (compatible; url-shortener-healthcheck/1.0)"
+)
+
+// HealthChecker confere se o destino de um link ainda responde, com timeout e
+// proteção contra SSRF
+type HealthChecker struct {
+	client *http.Client
+}
+
+func NewHealthChecker(options SafeHTTPOptions) *HealthChecker {
+	if options.Timeout == 0 {
+		options.Timeout = healthCheckTimeout
+	}
+
+	return &HealthChecker{client: NewSafeHTTPClient(options)}
+}
+
+// Check faz um HEAD no destino e, como muitos servidores não implementam HEAD
+// ou respondem a ele de forma diferente, repete com GET quando o status é de
+// erro. Status, URL final e latência são os da última requisição
+func (c *HealthChecker) Check(ctx context.Context, rawUrl string) models.HealthCheckResult {
+	result := c.do(ctx, http.MethodHead, rawUrl)
+	if result.Err == nil && !result.Healthy() {
+		result = c.do(ctx, http.MethodGet, rawUrl)
+	}
+
+	return result
+}
+
+func (c *HealthChecker) do(ctx context.Context, method string, rawUrl string) models.HealthCheckResult {
+	req, err := http.NewRequestWithContext(ctx, method, rawUrl, nil)
+	if err != nil {
+		return models.HealthCheckResult{Err: err}
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return models.HealthCheckResult{Err: fmt.Errorf("unsupported scheme %q", req.URL.Scheme)}
+	}
+	req.Header.Set("User-Agent", healthCheckUserAgent)
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return models.HealthCheckResult{Latency: time.Since(start), Err: err}
+	}
+	defer resp.Body.Close()
+
+	// descarta um pedaço do corpo para que a conexão possa ser reaproveitada
+	io.Copy(io.Discard, io.LimitReader(resp.Body, healthCheckMaxBody))
+
+	return models.HealthCheckResult{
+		StatusCode: resp.StatusCode,
+		FinalUrl:   resp.Request.URL.String(),
+		Latency:    time.Since(start),
+	}
+}
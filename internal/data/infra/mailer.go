@@ -0,0 +1,155 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"github.com/jhonVitor-rs/url-shortener/internal/core/usecases/ports"
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+)
+
+const defaultMailDir = "./tmp/mail"
+
+// NewMailerFromEnv escolhe a implementação de Mailer a partir da variável MAILER
+// (smtp, file ou memory). Sem configuração as mensagens são gravadas em disco
+func NewMailerFromEnv() ports.Mailer {
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		return NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("MAIL_FROM"),
+		)
+	case "memory":
+		return NewMemoryMailer()
+	default:
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = defaultMailDir
+		}
+		return NewFileMailer(dir, os.Getenv("MAIL_FROM"))
+	}
+}
+
+// SMTPMailer envia emails através de um servidor SMTP
+type SMTPMailer struct {
+	addr   string
+	auth   smtp.Auth
+	from   string
+	logger *slog.Logger
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr:   host + ":" + port,
+		auth:   auth,
+		from:   from,
+		logger: slog.Default().With("component", "smtp_mailer"),
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, email models.Email) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{email.To}, buildMessage(m.from, email)); err != nil {
+		m.logger.Error("failed to send email", "to", email.To, "error", err)
+		return wraperrors.InternalErr("Failed to send email", err)
+	}
+
+	return nil
+}
+
+// FileMailer grava cada email como um arquivo .eml, útil em desenvolvimento
+type FileMailer struct {
+	dir  string
+	from string
+	mu   sync.Mutex
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+func (m *FileMailer) Send(ctx context.Context, email models.Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return wraperrors.InternalErr("Failed to create mail directory", err)
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.ReplaceAll(email.To, "@", "_at_"))
+	if err := os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, email), 0o644); err != nil {
+		return wraperrors.InternalErr("Failed to write email file", err)
+	}
+
+	return nil
+}
+
+// MemoryMailer mantém os emails em memória para os testes
+type MemoryMailer struct {
+	mu       sync.Mutex
+	messages []models.Email
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, email models.Email) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, email)
+	return nil
+}
+
+// Messages retorna uma cópia dos emails enviados
+func (m *MemoryMailer) Messages() []models.Email {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]models.Email(nil), m.messages...)
+}
+
+// LastMessageTo retorna o email mais recente enviado para o endereço
+func (m *MemoryMailer) LastMessageTo(address string) (models.Email, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].To == address {
+			return m.messages[i], true
+		}
+	}
+	return models.Email{}, false
+}
+
+func buildMessage(from string, email models.Email) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", email.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", email.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(email.Body)
+	return []byte(b.String())
+}
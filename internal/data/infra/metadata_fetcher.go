@@ -0,0 +1,205 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	metadataFetchTimeout  = 5 * time.Second
+	metadataMaxBodyBytes  = 512 << 10 // Título e meta tags ficam no começo da página
+	metadataMaxTextLength = 500
+	metadataMaxUrlLength  = 2048
+	metadataUserAgent     = "Mozilla/5.0 (compatible; url-shortener-preview/1.0)"
+)
+
+// MetadataFetcher lê título, descrição, og:image e favicon do destino de um
+// link, com timeout, limite de tamanho e proteção contra SSRF
+type MetadataFetcher struct {
+	client   *http.Client
+	maxBytes int64
+	logger   *slog.Logger
+}
+
+func NewMetadataFetcher(options SafeHTTPOptions) *MetadataFetcher {
+	if options.Timeout == 0 {
+		options.Timeout = metadataFetchTimeout
+	}
+
+	return &MetadataFetcher{
+		client:   NewSafeHTTPClient(options),
+		maxBytes: metadataMaxBodyBytes,
+		logger:   slog.Default().With("component", "metadata_fetcher"),
+	}
+}
+
+// Fetch baixa a página e extrai os metadados. Respostas que não são HTML
+// retornam apenas o favicon padrão do host, erros de rede e status >= 400
+// retornam erro para que a busca seja tentada de novo
+func (f *MetadataFetcher) Fetch(ctx context.Context, rawUrl string) (*models.PageMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q", req.URL.Scheme)
+	}
+	req.Header.Set("User-Agent", metadataUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("destination answered with status %d", resp.StatusCode)
+	}
+
+	// URLs relativas são resolvidas a partir da página final, após redirecionamentos
+	base := resp.Request.URL
+	metadata := &models.PageMetadata{}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "text/html" || mediaType == "application/xhtml+xml" {
+		body, err := charset.NewReader(io.LimitReader(resp.Body, f.maxBytes), contentType)
+		if err != nil {
+			return nil, err
+		}
+		metadata = parsePageMetadata(body, base)
+	}
+
+	if metadata.FaviconUrl == "" {
+		metadata.FaviconUrl = (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/favicon.ico"}).String()
+	}
+
+	return metadata, nil
+}
+
+// parsePageMetadata percorre o <head> com o tokenizer, sem montar a árvore,
+// e para no <body>. O <title> tem preferência sobre og:title e a meta
+// description sobre og:description
+func parsePageMetadata(body io.Reader, base *url.URL) *models.PageMetadata {
+	var title, ogTitle, description, ogDescription, image, favicon string
+
+	tokenizer := html.NewTokenizer(body)
+	inTitle := false
+
+loop:
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			break loop
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "body":
+				break loop
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				key := strings.ToLower(attr(token, "name"))
+				if key == "" {
+					key = strings.ToLower(attr(token, "property"))
+				}
+				content := attr(token, "content")
+				switch key {
+				case "description":
+					description = content
+				case "og:title":
+					ogTitle = content
+				case "og:description":
+					ogDescription = content
+				case "og:image", "og:image:url", "og:image:secure_url":
+					if image == "" {
+						image = content
+					}
+				}
+			case "link":
+				rels := strings.Fields(strings.ToLower(attr(token, "rel")))
+				for _, rel := range rels {
+					// rel="icon" vence shortcut icon e apple-touch-icon
+					if rel == "icon" || (favicon == "" && rel == "apple-touch-icon") {
+						favicon = attr(token, "href")
+					}
+				}
+			}
+
+		case html.TextToken:
+			if inTitle {
+				title += string(tokenizer.Text())
+			}
+
+		case html.EndTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "title" {
+				inTitle = false
+			}
+		}
+	}
+
+	return &models.PageMetadata{
+		Title:       cleanText(firstNonEmpty(title, ogTitle)),
+		Description: cleanText(firstNonEmpty(description, ogDescription)),
+		ImageUrl:    resolveUrl(base, image),
+		FaviconUrl:  resolveUrl(base, favicon),
+	}
+}
+
+func attr(token html.Token, name string) string {
+	for _, a := range token.Attr {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// cleanText junta espaços e quebras de linha e corta textos longos
+func cleanText(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > metadataMaxTextLength {
+		text = string(runes[:metadataMaxTextLength])
+	}
+	return text
+}
+
+// resolveUrl torna href absoluto e descarta o que não for http(s), como
+// data: e javascript:
+func resolveUrl(base *url.URL, href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" {
+		return ""
+	}
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	resolved := u.String()
+	if len(resolved) > metadataMaxUrlLength {
+		return ""
+	}
+	return resolved
+}
@@ -0,0 +1,101 @@
+package infra
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	passwordAttemptsPrefix  = "pwd_attempts:"  // Prefixo das chaves de tentativas por slug e por slug e IP
+	maxPasswordAttempts     = 5                // Tentativas erradas permitidas por IP em cada janela
+	maxSlugPasswordAttempts = 20               // Tentativas erradas permitidas por slug, de qualquer IP, em cada janela
+	passwordAttemptsWindow  = 15 * time.Minute // Janela de contagem e duração do bloqueio
+)
+
+// registerPasswordAttemptScript conta uma tentativa por IP e por slug antes de
+// a senha ser conferida, assim requisições em paralelo não escapam da
+// contagem. A janela começa na primeira tentativa. Um IP já bloqueado não
+// conta mais para o slug, assim um único cliente não bloqueia o link para
+// todos os visitantes
+// KEYS[1] = pwd_attempts:<slug>:<ip>, KEYS[2] = pwd_attempts:<slug>
+// ARGV[1] = limite por IP, ARGV[2] = limite por slug, ARGV[3] = janela em segundos
+// Retorna 0 quando a tentativa é permitida, senão o TTL em segundos do bloqueio
+var registerPasswordAttemptScript = redis.NewScript(`
+for i, key in ipairs(KEYS) do
+	local count = redis.call('INCR', key)
+	if count == 1 then
+		redis.call('EXPIRE', key, ARGV[3])
+	end
+	if count > tonumber(ARGV[i]) then
+		local ttl = redis.call('TTL', key)
+		if ttl < 0 then
+			ttl = tonumber(ARGV[3])
+		end
+		return ttl
+	end
+end
+return 0
+`)
+
+// forgetPasswordAttemptScript desconta do slug uma tentativa que acertou a
+// senha e limpa as tentativas do IP, o slug só acumula os erros
+// KEYS[1] = pwd_attempts:<slug>:<ip>, KEYS[2] = pwd_attempts:<slug>
+var forgetPasswordAttemptScript = redis.NewScript(`
+redis.call('DEL', KEYS[1])
+if tonumber(redis.call('GET', KEYS[2]) or '0') > 0 then
+	redis.call('DECR', KEYS[2])
+end
+return 1
+`)
+
+// PasswordAttempts limita tentativas de senha em links protegidos por slug e
+// IP, e por slug para os ataques distribuídos entre vários IPs
+type PasswordAttempts struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+// NewPasswordAttempts cria uma nova instância do limitador de tentativas
+func NewPasswordAttempts(client *redis.Client) *PasswordAttempts {
+	return &PasswordAttempts{
+		client: client,
+		logger: slog.Default().With("component", "password_attempts"),
+	}
+}
+
+// Register conta a tentativa antes da senha ser conferida e retorna erro 429
+// com o tempo restante de bloqueio quando o limite do IP ou do slug foi passado
+func (p *PasswordAttempts) Register(ctx context.Context, slug, ip string) (time.Duration, error) {
+	blocked, err := registerPasswordAttemptScript.Run(ctx, p.client,
+		[]string{passwordAttemptsKey(slug, ip), passwordAttemptsPrefix + slug},
+		maxPasswordAttempts, maxSlugPasswordAttempts, int(passwordAttemptsWindow.Seconds()),
+	).Int()
+	if err != nil {
+		p.logger.Error("failed to register password attempt", "slug", slug, "error", err)
+		return 0, wraperrors.InternalErr("Failed to check password attempts", err)
+	}
+
+	if blocked == 0 {
+		return 0, nil
+	}
+	return time.Duration(blocked) * time.Second, wraperrors.TooManyRequestsErr("Too many password attempts, try again later")
+}
+
+// Reset limpa as tentativas do IP após uma senha correta e tira a tentativa
+// certa da contagem do slug
+func (p *PasswordAttempts) Reset(ctx context.Context, slug, ip string) {
+	err := forgetPasswordAttemptScript.Run(ctx, p.client,
+		[]string{passwordAttemptsKey(slug, ip), passwordAttemptsPrefix + slug},
+	).Err()
+	if err != nil {
+		p.logger.Error("failed to reset password attempts", "slug", slug, "error", err)
+	}
+}
+
+func passwordAttemptsKey(slug, ip string) string {
+	return passwordAttemptsPrefix + slug + ":" + ip
+}
@@ -0,0 +1,36 @@
+package infra
+
+import (
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"log/slog"
+	"os"
+)
+
+// LoadQrLogoFromEnv carrega o logo PNG ou JPEG indicado em QR_LOGO_PATH, que é
+// desenhado no centro dos QR codes que pedirem logo. Sem logo configurado
+// retorna nil
+func LoadQrLogoFromEnv() image.Image {
+	logger := slog.Default().With("component", "qr_logo")
+
+	path := os.Getenv("QR_LOGO_PATH")
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		logger.Error("failed to open qr code logo, logos are disabled", "path", path, "error", err)
+		return nil
+	}
+	defer file.Close()
+
+	logo, _, err := image.Decode(file)
+	if err != nil {
+		logger.Error("failed to decode qr code logo, logos are disabled", "path", path, "error", err)
+		return nil
+	}
+
+	return logo
+}
@@ -0,0 +1,75 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/pkg/utils"
+)
+
+const maxSafeRedirects = 5
+
+// ErrPrivateAddress é retornado quando a conexão iria para um endereço que não
+// é público
+var ErrPrivateAddress = errors.New("destination resolves to a private address")
+
+// SafeHTTPOptions configura o cliente usado para acessar URLs de terceiros
+type SafeHTTPOptions struct {
+	Timeout time.Duration
+	// AllowPrivateNetworks desliga a proteção contra SSRF, apenas para testes
+	// com servidores locais
+	AllowPrivateNetworks bool
+}
+
+// NewSafeHTTPClient cria um cliente HTTP para URLs informadas por usuários. O
+// IP é conferido no momento da conexão, depois da resolução DNS, assim nem um
+// redirecionamento nem um DNS que muda de resposta levam a endereços internos.
+// Proxies do ambiente são ignorados pelo mesmo motivo
+func NewSafeHTTPClient(options SafeHTTPOptions) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   options.Timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if !options.AllowPrivateNetworks {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if utils.IsPrivateIP(net.ParseIP(host)) {
+				return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+			}
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		Proxy: nil,
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, address)
+		},
+		TLSHandshakeTimeout:   options.Timeout,
+		ResponseHeaderTimeout: options.Timeout,
+		MaxIdleConnsPerHost:   2,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &http.Client{
+		Timeout:   options.Timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxSafeRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxSafeRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package infra
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	wraperrors "github.com/jhonVitor-rs/url-shortener/pkg/wrap_errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	totpChallengePrefix = "totp_challenge:" // Desafios de login ainda não usados, chave totp_challenge:<jti>
+	totpAttemptsPrefix  = "totp_attempts:"  // Tentativas de código por usuário, chave totp_attempts:<user_id>
+	totpStepPrefix      = "totp_step:"      // Último passo de tempo TOTP aceito por usuário
+	maxTotpAttempts     = 5                 // Tentativas permitidas por janela
+	totpAttemptsWindow  = 15 * time.Minute  // Janela de contagem e duração do bloqueio
+	totpStepTTL         = 5 * time.Minute   // Mais que a tolerância de validação dos códigos
+)
+
+// useTotpStepScript guarda o passo de tempo de um código aceito e recusa
+// passos iguais ou anteriores, assim um código não é aceito duas vezes
+// KEYS[1] = totp_step:<user_id>
+// ARGV[1] = passo de tempo, ARGV[2] = TTL em segundos
+// Retorna 1 quando o passo é novo e 0 quando já foi usado
+var useTotpStepScript = redis.NewScript(`
+local last = tonumber(redis.call('GET', KEYS[1]) or '-1')
+if tonumber(ARGV[1]) <= last then
+	return 0
+end
+
+redis.call('SET', KEYS[1], ARGV[1], 'EX', ARGV[2])
+return 1
+`)
+
+// TotpGuard protege o segundo fator do login: desafios de uso único, limite
+// de tentativas por usuário e recusa de códigos já usados
+type TotpGuard struct {
+	client *redis.Client
+	logger *slog.Logger
+}
+
+// NewTotpGuard cria uma nova instância da proteção do login TOTP
+func NewTotpGuard(client *redis.Client) *TotpGuard {
+	return &TotpGuard{
+		client: client,
+		logger: slog.Default().With("component", "totp_guard"),
+	}
+}
+
+// IssueChallenge registra um desafio emitido, ele vale até ser usado ou expirar
+func (g *TotpGuard) IssueChallenge(ctx context.Context, jti, userId string, ttl time.Duration) error {
+	if err := g.client.Set(ctx, totpChallengePrefix+jti, userId, ttl).Err(); err != nil {
+		g.logger.Error("failed to save totp challenge", "user_id", userId, "error", err)
+		return wraperrors.InternalErr("Failed to save challenge", err)
+	}
+	return nil
+}
+
+// CheckChallenge confere se o desafio ainda não foi usado
+func (g *TotpGuard) CheckChallenge(ctx context.Context, jti, userId string) error {
+	owner, err := g.client.Get(ctx, totpChallengePrefix+jti).Result()
+	if err == redis.Nil || (err == nil && owner != userId) {
+		return wraperrors.UnauthorizedErr("Challenge token already used or expired")
+	}
+	if err != nil {
+		g.logger.Error("failed to read totp challenge", "user_id", userId, "error", err)
+		return wraperrors.InternalErr("Failed to check challenge", err)
+	}
+	return nil
+}
+
+// ConsumeChallenge apaga o desafio após um código aceito, só uma requisição
+// consegue apagá-lo
+func (g *TotpGuard) ConsumeChallenge(ctx context.Context, jti string) error {
+	deleted, err := g.client.Del(ctx, totpChallengePrefix+jti).Result()
+	if err != nil {
+		g.logger.Error("failed to consume totp challenge", "error", err)
+		return wraperrors.InternalErr("Failed to consume challenge", err)
+	}
+	if deleted == 0 {
+		return wraperrors.UnauthorizedErr("Challenge token already used or expired")
+	}
+	return nil
+}
+
+// RegisterAttempt conta a tentativa antes de o código ser conferido, com
+// INCR e EXPIRE NX atômicos, e retorna erro 429 com o tempo restante de
+// bloqueio quando o limite foi passado. Requisições em paralelo não escapam
+// da contagem
+func (g *TotpGuard) RegisterAttempt(ctx context.Context, userId string) (time.Duration, error) {
+	key := totpAttemptsPrefix + userId
+
+	pipe := g.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, totpAttemptsWindow)
+	ttl := pipe.TTL(ctx, key)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		g.logger.Error("failed to register totp attempt", "user_id", userId, "error", err)
+		return 0, wraperrors.InternalErr("Failed to check TOTP attempts", err)
+	}
+
+	if incr.Val() <= maxTotpAttempts {
+		return 0, nil
+	}
+
+	retryAfter := ttl.Val()
+	if retryAfter < 0 {
+		retryAfter = totpAttemptsWindow
+	}
+	return retryAfter, wraperrors.TooManyRequestsErr("Too many TOTP attempts, try again later")
+}
+
+// ResetAttempts limpa as tentativas após um código aceito
+func (g *TotpGuard) ResetAttempts(ctx context.Context, userId string) {
+	if err := g.client.Del(ctx, totpAttemptsPrefix+userId).Err(); err != nil {
+		g.logger.Error("failed to reset totp attempts", "user_id", userId, "error", err)
+	}
+}
+
+// UseStep marca o passo de tempo de um código TOTP aceito, retorna false
+// quando ele ou um passo posterior já foi usado
+func (g *TotpGuard) UseStep(ctx context.Context, userId string, step int64) (bool, error) {
+	used, err := useTotpStepScript.Run(ctx, g.client,
+		[]string{totpStepPrefix + userId},
+		strconv.FormatInt(step, 10), int(totpStepTTL.Seconds()),
+	).Int()
+	if err != nil {
+		g.logger.Error("failed to save totp step", "user_id", userId, "error", err)
+		return false, wraperrors.InternalErr("Failed to check TOTP code", err)
+	}
+
+	return used == 1, nil
+}
@@ -0,0 +1,47 @@
+package infra
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+)
+
+// MemoryTxtResolver responde consultas TXT a partir de registros em memória
+// para os testes, nomes sem registros resultam em NXDOMAIN
+type MemoryTxtResolver struct {
+	mu      sync.RWMutex
+	records map[string][]string
+}
+
+func NewMemoryTxtResolver() *MemoryTxtResolver {
+	return &MemoryTxtResolver{records: map[string][]string{}}
+}
+
+// Set substitui os registros TXT do nome, sem valores o nome deixa de existir
+func (r *MemoryTxtResolver) Set(name string, values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name = normalizeTxtName(name)
+	if len(values) == 0 {
+		delete(r.records, name)
+		return
+	}
+	r.records[name] = append([]string(nil), values...)
+}
+
+func (r *MemoryTxtResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	values, ok := r.records[normalizeTxtName(name)]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return append([]string(nil), values...), nil
+}
+
+func normalizeTxtName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
@@ -0,0 +1,63 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/jhonVitor-rs/url-shortener/internal/core/domain/models"
+)
+
+const (
+	webhookTimeout   = 10 * time.Second
+	webhookUserAgent = "url-shortener-webhooks/1.0"
+)
+
+// WebhookSender entrega eventos para os webhooks cadastrados pelos usuários.
+// As URLs são de terceiros, por isso usa o cliente HTTP seguro
+type WebhookSender struct {
+	client *http.Client
+}
+
+func NewWebhookSender(options SafeHTTPOptions) *WebhookSender {
+	if options.Timeout == 0 {
+		options.Timeout = webhookTimeout
+	}
+
+	return &WebhookSender{client: NewSafeHTTPClient(options)}
+}
+
+// Send faz o POST do evento em JSON assinado com o segredo do webhook.
+// Qualquer status fora da faixa 2xx é considerado falha
+func (s *WebhookSender) Send(ctx context.Context, url string, secret string, event string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(models.WebhookEventHeader, event)
+	req.Header.Set(models.WebhookSignatureHeader, models.SignWebhookPayload(secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
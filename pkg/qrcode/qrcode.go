@@ -0,0 +1,262 @@
+// Package qrcode renders QR codes as PNG or SVG on top of a pure Go encoder
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"github.com/boombuler/barcode/qr"
+)
+
+const (
+	MinSize = 64
+	MaxSize = 2048
+	// MaxMargin is the quiet zone limit in modules, scanners need at least 4
+	MaxMargin = 16
+	// logoRatio is the share of the code width the logo may cover, level H
+	// recovers up to 30% of the modules so a centered logo stays scannable
+	logoRatio = 0.22
+)
+
+type Level string
+
+const (
+	LevelLow      Level = "L"
+	LevelMedium   Level = "M"
+	LevelQuartile Level = "Q"
+	LevelHigh     Level = "H"
+)
+
+type Options struct {
+	Size       int
+	Level      Level
+	Margin     int
+	Foreground color.RGBA
+	Background color.RGBA
+	// Logo is drawn in the center on a background plate, it forces level H
+	Logo image.Image
+}
+
+// DefaultOptions renders a 256px black on white code with the standard quiet zone
+func DefaultOptions() Options {
+	return Options{
+		Size:       256,
+		Level:      LevelMedium,
+		Margin:     4,
+		Foreground: color.RGBA{A: 0xff},
+		Background: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+}
+
+// ParseLevel accepts L, M, Q and H in any case
+func ParseLevel(raw string) (Level, error) {
+	level := Level(strings.ToUpper(raw))
+	switch level {
+	case LevelLow, LevelMedium, LevelQuartile, LevelHigh:
+		return level, nil
+	}
+	return "", fmt.Errorf("invalid error correction level %q", raw)
+}
+
+// ParseColor accepts #rrggbb, rrggbb and the short #rgb form
+func ParseColor(raw string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(raw, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	var c color.RGBA
+	if len(hex) != 6 {
+		return c, fmt.Errorf("invalid color %q", raw)
+	}
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return c, fmt.Errorf("invalid color %q", raw)
+	}
+	c.A = 0xff
+
+	return c, nil
+}
+
+func (o Options) validate() error {
+	if o.Size < MinSize || o.Size > MaxSize {
+		return fmt.Errorf("size must be between %d and %d", MinSize, MaxSize)
+	}
+	if o.Margin < 0 || o.Margin > MaxMargin {
+		return fmt.Errorf("margin must be between 0 and %d", MaxMargin)
+	}
+	if o.Foreground == o.Background {
+		return errors.New("foreground and background colors must differ")
+	}
+	return nil
+}
+
+// matrix encodes content and returns the modules, true meaning a dark one
+func matrix(content string, o Options) ([][]bool, error) {
+	levels := map[Level]qr.ErrorCorrectionLevel{
+		LevelLow:      qr.L,
+		LevelMedium:   qr.M,
+		LevelQuartile: qr.Q,
+		LevelHigh:     qr.H,
+	}
+	level, ok := levels[o.Level]
+	if !ok {
+		return nil, fmt.Errorf("invalid error correction level %q", o.Level)
+	}
+	if o.Logo != nil {
+		level = qr.H
+	}
+
+	code, err := qr.Encode(content, level, qr.Auto)
+	if err != nil {
+		return nil, err
+	}
+
+	dim := code.Bounds().Dx()
+	modules := make([][]bool, dim)
+	for y := range dim {
+		modules[y] = make([]bool, dim)
+		for x := range dim {
+			r, _, _, _ := code.At(x, y).RGBA()
+			modules[y][x] = r == 0
+		}
+	}
+
+	return modules, nil
+}
+
+// PNG renders content in a Size x Size image. Modules are scaled to whole
+// pixels and the leftover is split around the quiet zone
+func PNG(content string, o Options) ([]byte, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	modules, err := matrix(content, o)
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(modules) + 2*o.Margin
+	scale := o.Size / total
+	if scale == 0 {
+		return nil, fmt.Errorf("size %d is too small for this content, it needs at least %d", o.Size, total)
+	}
+	offset := (o.Size - len(modules)*scale) / 2
+
+	img := image.NewRGBA(image.Rect(0, 0, o.Size, o.Size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{o.Background}, image.Point{}, draw.Src)
+	fg := &image.Uniform{o.Foreground}
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				rect := image.Rect(offset+x*scale, offset+y*scale, offset+(x+1)*scale, offset+(y+1)*scale)
+				draw.Draw(img, rect, fg, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	if o.Logo != nil {
+		drawLogo(img, o.Logo, o.Background, len(modules)*scale)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders content with one path for all dark modules, the viewBox is in
+// modules so the image scales without blurring
+func SVG(content string, o Options) ([]byte, error) {
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	modules, err := matrix(content, o)
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(modules) + 2*o.Margin
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, o.Size, o.Size, total, total)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, total, total, hexColor(o.Background))
+
+	buf.WriteString(`<path fill="` + hexColor(o.Foreground) + `" d="`)
+	for y, row := range modules {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+			// Runs of dark modules become a single rectangle
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start+o.Margin, y+o.Margin, x-start, x-start)
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if o.Logo != nil {
+		var logo bytes.Buffer
+		if err := png.Encode(&logo, o.Logo); err != nil {
+			return nil, err
+		}
+		side := float64(len(modules)) * logoRatio
+		pos := float64(total)/2 - side/2
+		pad := side * 0.1
+		fmt.Fprintf(&buf, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`, pos-pad, pos-pad, side+2*pad, side+2*pad, hexColor(o.Background))
+		fmt.Fprintf(&buf, `<image x="%.2f" y="%.2f" width="%.2f" height="%.2f" preserveAspectRatio="xMidYMid meet" href="data:image/png;base64,%s"/>`,
+			pos, pos, side, side, base64.StdEncoding.EncodeToString(logo.Bytes()))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+// drawLogo scales the logo to fit the center of a code codeSize pixels wide
+// and draws it over a background plate
+func drawLogo(img *image.RGBA, logo image.Image, background color.RGBA, codeSize int) {
+	side := int(float64(codeSize) * logoRatio)
+	if side == 0 {
+		return
+	}
+	center := img.Bounds().Dx() / 2
+	pad := side / 10
+	plate := image.Rect(center-side/2-pad, center-side/2-pad, center+side/2+pad, center+side/2+pad)
+	draw.Draw(img, plate, &image.Uniform{background}, image.Point{}, draw.Src)
+
+	bounds := logo.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return
+	}
+	// Keep the aspect ratio inside a side x side square
+	dw, dh := side, side
+	if w > h {
+		dh = side * h / w
+	} else {
+		dw = side * w / h
+	}
+	dst := image.Rect(center-dw/2, center-dh/2, center-dw/2+dw, center-dh/2+dh)
+
+	// Nearest neighbour is enough for a logo of a few hundred pixels
+	scaled := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := range dh {
+		for x := range dw {
+			scaled.Set(x, y, logo.At(bounds.Min.X+x*w/dw, bounds.Min.Y+y*h/dh))
+		}
+	}
+	draw.Draw(img, dst, scaled, image.Point{}, draw.Over)
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -27,27 +27,21 @@ func ParseAndValidate[T any](w http.ResponseWriter, r *http.Request) (*T, bool)
 		return nil, false
 	}
 
-	if err := validate.Struct(body); err != nil {
-		slog.Error("Failed to validate struct", "error", err)
-		if validationErrors, ok := err.(validator.ValidationErrors); ok {
-			var errs []models.ValidationError
-			for _, verr := range validationErrors {
-				errs = append(errs, models.ValidationError{
-					Field: verr.Field(),
-					Tag:   verr.Tag(),
-					Value: fmt.Sprintf("%v", verr.Value()),
-				})
-			}
-			hooks.WriteJSON(w, http.StatusBadRequest, models.Response{
-				Success: false,
-				Error: &models.ErrorData{
-					Message: "Invalid input",
-					Errors:  errs,
-				},
-			})
-			return nil, false
-		}
-
+	errs, err := ValidateStruct(body)
+	if len(errs) > 0 || err != nil {
+		slog.Error("Failed to validate struct", "errors", errs, "error", err)
+	}
+	if len(errs) > 0 {
+		hooks.WriteJSON(w, http.StatusBadRequest, models.Response{
+			Success: false,
+			Error: &models.ErrorData{
+				Message: "Invalid input",
+				Errors:  errs,
+			},
+		})
+		return nil, false
+	}
+	if err != nil {
 		hooks.WriteJSON(w, http.StatusBadRequest, models.Response{
 			Success: false,
 			Error: &models.ErrorData{
@@ -60,3 +54,27 @@ func ParseAndValidate[T any](w http.ResponseWriter, r *http.Request) (*T, bool)
 
 	return &body, true
 }
+
+// ValidateStruct checks the validate tags of value, the fields breaking them
+// are listed as validation errors. Other errors mean value can't be validated
+func ValidateStruct(value any) ([]models.ValidationError, error) {
+	err := validate.Struct(value)
+	if err == nil {
+		return nil, nil
+	}
+
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil, err
+	}
+
+	errs := make([]models.ValidationError, 0, len(validationErrors))
+	for _, verr := range validationErrors {
+		errs = append(errs, models.ValidationError{
+			Field: verr.Field(),
+			Tag:   verr.Tag(),
+			Value: fmt.Sprintf("%v", verr.Value()),
+		})
+	}
+	return errs, nil
+}
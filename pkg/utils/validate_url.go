@@ -9,10 +9,15 @@ import (
 )
 
 func ValidateUrl(w http.ResponseWriter, urlStr string) bool {
-	u, err := url.ParseRequestURI(urlStr)
-	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+	if !IsValidUrl(urlStr) {
 		hooks.SendResponse(w, http.StatusBadRequest, nil, wraperrors.ValidationErr("Invalid URL"))
 		return false
 	}
 	return true
 }
+
+// IsValidUrl accepts absolute http and https URLs
+func IsValidUrl(urlStr string) bool {
+	u, err := url.ParseRequestURI(urlStr)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
@@ -10,6 +10,9 @@ var (
 	ErrAlreadyExists   = errors.New("already exists")
 	ErrValidation      = errors.New("validation failed")
 	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrGone            = errors.New("gone")
+	ErrTooManyRequests = errors.New("too many requests")
 	ErrInternal        = errors.New("internal error")
 	ErrUniqueViolation = errors.New("unique constraint violation")
 )
@@ -19,6 +22,15 @@ type AppError struct {
 	Message string
 	Type    error
 	Err     error
+	Fields  []FieldError
+}
+
+// FieldError points a validation failure at one input field, Tag names the
+// rule that failed
+type FieldError struct {
+	Field string
+	Tag   string
+	Value string
 }
 
 func (e *AppError) Error() string {
@@ -63,10 +75,28 @@ func ValidationErr(msg string) *AppError {
 	return New(msg, ErrValidation, 400, nil)
 }
 
+func FieldValidationErr(msg string, fields ...FieldError) *AppError {
+	err := ValidationErr(msg)
+	err.Fields = fields
+	return err
+}
+
 func UnauthorizedErr(msg string) *AppError {
 	return New(msg, ErrUnauthorized, 401, nil)
 }
 
+func ForbiddenErr(msg string) *AppError {
+	return New(msg, ErrForbidden, 403, nil)
+}
+
+func GoneErr(msg string) *AppError {
+	return New(msg, ErrGone, 410, nil)
+}
+
+func TooManyRequestsErr(msg string) *AppError {
+	return New(msg, ErrTooManyRequests, 429, nil)
+}
+
 func InternalErr(msg string, err error) *AppError {
 	return New(msg, ErrInternal, 500, err)
 }
@@ -95,6 +125,18 @@ func IsUnauthorizedError(err error) bool {
 	return errors.Is(err, ErrUnauthorized)
 }
 
+func IsForbiddenError(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+func IsGoneError(err error) bool {
+	return errors.Is(err, ErrGone)
+}
+
+func IsTooManyRequestsError(err error) bool {
+	return errors.Is(err, ErrTooManyRequests)
+}
+
 func IsInternalError(err error) bool {
 	return errors.Is(err, ErrInternal)
 }